@@ -521,6 +521,8 @@ create_ovn_kube_manifests() {
     --ovn-loglevel-nbctld="${OVN_LOG_LEVEL_NBCTLD}" \
     --egress-ip-enable=true \
     --egress-firewall-enable=true \
+    --egress-qos-enable=true \
+    --admin-network-policy-enable=true \
     --v4-join-subnet="${JOIN_SUBNET_IPV4}" \
     --v6-join-subnet="${JOIN_SUBNET_IPV6}" \
     --ex-gw-network-interface="${OVN_EX_GW_NETWORK_INTERFACE}"
@@ -541,7 +543,10 @@ install_ovn_image() {
 install_ovn() {
   pushd ../dist/yaml
   run_kubectl apply -f k8s.ovn.org_egressfirewalls.yaml
+  run_kubectl apply -f k8s.ovn.org_clusteregressfirewalls.yaml
   run_kubectl apply -f k8s.ovn.org_egressips.yaml
+  run_kubectl apply -f k8s.ovn.org_egressqoses.yaml
+  run_kubectl apply -f k8s.ovn.org_adminnetworkpolicies.yaml
   run_kubectl apply -f ovn-setup.yaml
   MASTER_NODES=$(kind get nodes --name "${KIND_CLUSTER_NAME}" | sort | head -n "${KIND_NUM_MASTER}")
   # We want OVN HA not Kubernetes HA
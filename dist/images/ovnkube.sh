@@ -69,7 +69,11 @@ fi
 # OVN_SSL_ENABLE - use SSL transport to NB/SB db and northd (default: no)
 # OVN_REMOTE_PROBE_INTERVAL - ovn remote probe interval in ms (default 100000)
 # OVN_EGRESSIP_ENABLE - enable egress IP for ovn-kubernetes
+# OVN_EGRESSIP_HEALTHCHECK_PORT - port on which ovnkube-node answers egress IP reachability probes (default: 0, disabled)
 # OVN_EGRESSFIREWALL_ENABLE - enable egressFirewall for ovn-kubernetes
+# OVN_EGRESSQOS_ENABLE - enable egress QoS for ovn-kubernetes
+# OVN_ADMIN_NETWORK_POLICY_ENABLE - enable admin network policies for ovn-kubernetes
+# OVN_MULTI_NETWORK_ENABLE - enable secondary OVN networks attached via net-attach-defs
 # OVN_UNPRIVILEGED_MODE - execute CNI ovs/netns commands from host (default no)
 # OVNKUBE_NODE_MODE - ovnkube node mode of operation, one of: full, smart-nic, smart-nic-host (default: full)
 # OVNKUBE_NODE_MGMT_PORT_NETDEV - ovnkube node management port netdev. valid when ovnkube node mode is: smart-nic, smart-nic-host
@@ -199,8 +203,16 @@ ovn_remote_probe_interval=${OVN_REMOTE_PROBE_INTERVAL:-100000}
 ovn_multicast_enable=${OVN_MULTICAST_ENABLE:-}
 #OVN_EGRESSIP_ENABLE - enable egress IP for ovn-kubernetes
 ovn_egressip_enable=${OVN_EGRESSIP_ENABLE:-false}
+#OVN_EGRESSIP_HEALTHCHECK_PORT - port on which ovnkube-node answers egress IP reachability probes
+ovn_egressip_healthcheck_port=${OVN_EGRESSIP_HEALTHCHECK_PORT:-}
 #OVN_EGRESSFIREWALL_ENABLE - enable egressFirewall for ovn-kubernetes
 ovn_egressfirewall_enable=${OVN_EGRESSFIREWALL_ENABLE:-false}
+#OVN_EGRESSQOS_ENABLE - enable egress QoS for ovn-kubernetes
+ovn_egressqos_enable=${OVN_EGRESSQOS_ENABLE:-false}
+#OVN_ADMIN_NETWORK_POLICY_ENABLE - enable admin network policies for ovn-kubernetes
+ovn_admin_network_policy_enable=${OVN_ADMIN_NETWORK_POLICY_ENABLE:-false}
+#OVN_MULTI_NETWORK_ENABLE - enable secondary OVN networks attached via net-attach-defs
+ovn_multi_network_enable=${OVN_MULTI_NETWORK_ENABLE:-false}
 ovn_acl_logging_rate_limit=${OVN_ACL_LOGGING_RATE_LIMIT:-"20"}
 ovn_netflow_targets=${OVN_NETFLOW_TARGETS:-}
 ovn_sflow_targets=${OVN_SFLOW_TARGETS:-}
@@ -915,6 +927,18 @@ ovn-master() {
 	  egressfirewall_enabled_flag="--enable-egress-firewall"
   fi
   echo "egressfirewall_enabled_flag=${egressfirewall_enabled_flag}"
+  egressqos_enabled_flag=
+  if [[ ${ovn_egressqos_enable} == "true" ]]; then
+    egressqos_enabled_flag="--enable-egress-qos"
+  fi
+  admin_network_policy_enabled_flag=
+  if [[ ${ovn_admin_network_policy_enable} == "true" ]]; then
+    admin_network_policy_enabled_flag="--enable-admin-network-policy"
+  fi
+  multi_network_enabled_flag=
+  if [[ ${ovn_multi_network_enable} == "true" ]]; then
+    multi_network_enabled_flag="--enable-multi-network"
+  fi
 
   ovnkube_master_metrics_bind_address="${metrics_endpoint_ip}:9409"
 
@@ -941,6 +965,9 @@ ovn-master() {
     ${ovn_acl_logging_rate_limit_flag} \
     ${egressip_enabled_flag} \
     ${egressfirewall_enabled_flag} \
+    ${egressqos_enabled_flag} \
+    ${admin_network_policy_enabled_flag} \
+    ${multi_network_enabled_flag} \
     --metrics-bind-address ${ovnkube_master_metrics_bind_address} \
     --host-network-namespace ${ovn_host_network_namespace} &
 
@@ -1042,6 +1069,11 @@ ovn-node() {
       egressip_enabled_flag="--enable-egress-ip"
   fi
 
+  egressip_healthcheck_port_flag=
+  if [[ -n ${ovn_egressip_healthcheck_port} ]]; then
+      egressip_healthcheck_port_flag="--egressip-node-healthcheck-port=${ovn_egressip_healthcheck_port}"
+  fi
+
   netflow_targets=
   if [[ -n ${ovn_netflow_targets} ]]; then
       netflow_targets="--netflow-targets ${ovn_netflow_targets}"
@@ -1139,6 +1171,7 @@ ovn-node() {
     --inactivity-probe=${ovn_remote_probe_interval} \
     ${multicast_enabled_flag} \
     ${egressip_enabled_flag} \
+    ${egressip_healthcheck_port_flag} \
     ${netflow_targets} \
     ${sflow_targets} \
     ${ipfix_targets} \
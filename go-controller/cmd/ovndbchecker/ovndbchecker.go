@@ -192,6 +192,7 @@ func runOvnKubeDBChecker(ctx *cli.Context) error {
 			KClient:              ovnClientset.KubeClient,
 			EIPClient:            ovnClientset.EgressIPClient,
 			EgressFirewallClient: ovnClientset.EgressFirewallClient,
+			EgressQoSClient:      ovnClientset.EgressQoSClient,
 		},
 		stopChan)
 	// run until cancelled
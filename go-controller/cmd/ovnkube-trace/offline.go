@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	klog "k8s.io/klog"
+)
+
+// offline is set when tracing against database snapshots instead of a live
+// cluster: the commands then run locally against local ovsdb-servers, and
+// only the logical (ovn-trace) part of the traces can be run
+var offline bool
+
+// offlineDir holds the copies of the database snapshots and the sockets of
+// the local ovsdb-servers
+var offlineDir string
+
+// offlineServers are the local ovsdb-servers
+var offlineServers []*exec.Cmd
+
+// loadKubernetesObjects reads the pods, services, endpoints and nodes of a
+// dump made with "kubectl get -o yaml", either a List or a multi-document
+// YAML file, and returns a client serving them
+func loadKubernetesObjects(path string) (corev1client.CoreV1Interface, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []runtime.Object
+	decoder := scheme.Codecs.UniversalDeserializer()
+	var addObject func(raw []byte) error
+	addObject = func(raw []byte) error {
+		obj, _, err := decoder.Decode(raw, nil, nil)
+		if err != nil {
+			if runtime.IsNotRegisteredError(err) {
+				klog.V(5).Infof("Skipping object of unknown kind in %s: %v", path, err)
+				return nil
+			}
+			return fmt.Errorf("failed to decode object in %s: %v", path, err)
+		}
+		switch obj := obj.(type) {
+		case *kapi.List:
+			for _, item := range obj.Items {
+				if err := addObject(item.Raw); err != nil {
+					return err
+				}
+			}
+		case *kapi.Pod, *kapi.Service, *kapi.Endpoints, *kapi.Node:
+			objects = append(objects, obj)
+		default:
+			klog.V(5).Infof("Skipping %T in %s", obj, path)
+		}
+		return nil
+	}
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		if err := addObject(doc); err != nil {
+			return nil, err
+		}
+	}
+	klog.V(5).Infof("Loaded %d objects from %s", len(objects), path)
+	return fake.NewSimpleClientset(objects...).CoreV1(), nil
+}
+
+// startOfflineDatabase starts a local ovsdb-server serving a copy of a
+// database snapshot, and returns the URI of the server. Snapshots of
+// clustered databases are converted to standalone ones first.
+func startOfflineDatabase(name, dbFile string) (string, error) {
+	db := filepath.Join(offlineDir, name+".db")
+	// ovsdb-tool db-is-clustered exits with status 0 for clustered databases
+	if err := exec.Command("ovsdb-tool", "db-is-clustered", dbFile).Run(); err == nil {
+		out, err := exec.Command("ovsdb-tool", "cluster-to-standalone", db, dbFile).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("failed to convert clustered database %s: %v: %s", dbFile, err, out)
+		}
+	} else {
+		data, err := ioutil.ReadFile(dbFile)
+		if err != nil {
+			return "", err
+		}
+		if err := ioutil.WriteFile(db, data, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	sock := filepath.Join(offlineDir, name+".sock")
+	cmd := exec.Command("ovsdb-server", "--no-chdir",
+		"--remote=punix:"+sock,
+		"--unixctl="+filepath.Join(offlineDir, name+".ctl"),
+		db)
+	// the server must not outlive ovnkube-trace
+	cmd.SysProcAttr = childProcAttr()
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start ovsdb-server for %s: %v", dbFile, err)
+	}
+	offlineServers = append(offlineServers, cmd)
+	err := wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		_, err := os.Stat(sock)
+		return err == nil, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("ovsdb-server for %s did not start: %v", dbFile, err)
+	}
+	klog.V(5).Infof("Serving %s on %s", dbFile, sock)
+	return "unix:" + sock, nil
+}
+
+// startOfflineDatabases starts the local ovsdb-servers of the NB and SB
+// database snapshots and returns their URIs
+func startOfflineDatabases(nbFile, sbFile string) (string, string, error) {
+	var err error
+	offlineDir, err = ioutil.TempDir("", "ovnkube-trace")
+	if err != nil {
+		return "", "", err
+	}
+	nbUri, err := startOfflineDatabase("ovnnb_db", nbFile)
+	if err != nil {
+		return "", "", err
+	}
+	sbUri, err := startOfflineDatabase("ovnsb_db", sbFile)
+	if err != nil {
+		return "", "", err
+	}
+	return nbUri, sbUri, nil
+}
+
+// cleanupOffline stops the local ovsdb-servers and removes the copies of the
+// database snapshots
+func cleanupOffline() {
+	for _, cmd := range offlineServers {
+		if err := cmd.Process.Kill(); err != nil {
+			klog.V(5).Infof("Failed to stop ovsdb-server %d: %v", cmd.Process.Pid, err)
+		}
+		cmd.Wait()
+	}
+	offlineServers = nil
+	if offlineDir != "" {
+		os.RemoveAll(offlineDir)
+		offlineDir = ""
+	}
+}
+
+// fatalExit cleans up the offline databases, if any, and exits with a failure
+// status; it must be used instead of os.Exit once they may have been started
+func fatalExit() {
+	cleanupOffline()
+	os.Exit(-1)
+}
+
+// execLocal runs a command locally instead of in a pod, in offline mode
+func execLocal(cmd string, in string) (string, string, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	c := exec.Command("bash", "-c", cmd)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if in != "" {
+		c.Stdin = strings.NewReader(in)
+	}
+	err := c.Run()
+	return stdout.String(), stderr.String(), err
+}
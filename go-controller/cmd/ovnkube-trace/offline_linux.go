@@ -0,0 +1,10 @@
+// +build linux
+
+package main
+
+import "syscall"
+
+// childProcAttr makes the child processes terminate with ovnkube-trace
+func childProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Pdeathsig: syscall.SIGTERM}
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package main
+
+import "syscall"
+
+// childProcAttr is a no-op, the child processes have to be terminated
+// by hand on this platform
+func childProcAttr() *syscall.SysProcAttr {
+	return nil
+}
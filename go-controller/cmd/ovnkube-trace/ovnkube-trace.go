@@ -22,6 +22,7 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 	klog "k8s.io/klog"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	types "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	util "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 )
@@ -40,6 +41,9 @@ type IpAddrReq struct {
 
 type SvcInfo struct {
 	IP           string
+	Port         string
+	NodePort     string
+	ExternalIPs  []string
 	PodName      string
 	PodNamespace string
 	PodIP        string
@@ -62,6 +66,20 @@ type PodInfo struct {
 	HostNetwork             bool
 }
 
+// NodeInfo holds the gateway information of a node, used to trace traffic
+// leaving the cluster through the node or entering the cluster from outside
+type NodeInfo struct {
+	NodeName                string
+	IP                      string
+	OvnKubeContainerPodName string
+	GatewayMode             string
+	GatewayMAC              string
+	LocalnetPort            string
+	BridgeName              string
+	UplinkPort              string
+	LocalNum                string
+}
+
 func (si SvcInfo) getL3Ver() string {
 	if net.ParseIP(si.IP).To4() != nil {
 		return "ip4"
@@ -76,12 +94,22 @@ func (si PodInfo) getL3Ver() string {
 	return "ip6"
 }
 
-func execInPod(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, namespace string, podName string, containerName string, cmd string, in string) (string, string, error) {
+func getL3Ver(ip string) string {
+	if net.ParseIP(ip).To4() != nil {
+		return "ip4"
+	}
+	return "ip6"
+}
+
+func execInPod(coreclient corev1client.CoreV1Interface, restconfig *rest.Config, namespace string, podName string, containerName string, cmd string, in string) (string, string, error) {
+	if offline {
+		return execLocal(cmd, in)
+	}
 
 	scheme := runtime.NewScheme()
 	if err := kapi.AddToScheme(scheme); err != nil {
 		fmt.Printf("error adding to scheme: %v", err)
-		os.Exit(-1)
+		fatalExit()
 	}
 	parameterCodec := runtime.NewParameterCodec(scheme)
 
@@ -133,7 +161,7 @@ func execInPod(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, n
 	return stdout.String(), stderr.String(), err
 }
 
-func getPodMAC(client *corev1client.CoreV1Client, pod *kapi.Pod) (podMAC string, err error) {
+func getPodMAC(client corev1client.CoreV1Interface, pod *kapi.Pod) (podMAC string, err error) {
 
 	if pod.Spec.HostNetwork {
 		node, err := client.Nodes().Get(context.TODO(), pod.Spec.NodeName, metav1.GetOptions{})
@@ -161,7 +189,7 @@ func getPodMAC(client *corev1client.CoreV1Client, pod *kapi.Pod) (podMAC string,
 	return podMAC, nil
 }
 
-func getSvcInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, svcName string, ovnNamespace string, namespace string, cmd string) (svcInfo *SvcInfo, err error) {
+func getSvcInfo(coreclient corev1client.CoreV1Interface, restconfig *rest.Config, svcName string, ovnNamespace string, namespace string, cmd string, dstPort string) (svcInfo *SvcInfo, err error) {
 
 	// Get service with the name supplied by svcName
 	svc, err := coreclient.Services(namespace).Get(context.TODO(), svcName, metav1.GetOptions{})
@@ -179,8 +207,27 @@ func getSvcInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 	klog.V(5).Infof("==>Got service %s ClusterIP is %s\n", svcName, clusterIP)
 
 	svcInfo = &SvcInfo{
-		IP: svc.Spec.ClusterIP,
+		IP:          svc.Spec.ClusterIP,
+		Port:        dstPort,
+		ExternalIPs: svc.Spec.ExternalIPs,
 	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			svcInfo.ExternalIPs = append(svcInfo.ExternalIPs, ingress.IP)
+		}
+	}
+	// Use the service port matching dstPort, or the first one
+	for i, port := range svc.Spec.Ports {
+		if i == 0 || strconv.Itoa(int(port.Port)) == dstPort {
+			svcInfo.Port = strconv.Itoa(int(port.Port))
+			if port.NodePort != 0 {
+				svcInfo.NodePort = strconv.Itoa(int(port.NodePort))
+			} else {
+				svcInfo.NodePort = ""
+			}
+		}
+	}
+	klog.V(5).Infof("==>Got service %s port %s NodePort %s external IPs %v\n", svcName, svcInfo.Port, svcInfo.NodePort, svcInfo.ExternalIPs)
 
 	ep, err := coreclient.Endpoints(namespace).Get(context.TODO(), svcName, metav1.GetOptions{})
 	if err != nil {
@@ -220,7 +267,7 @@ func getSvcInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 	return svcInfo, err
 }
 
-func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, podName string, ovnNamespace string, namespace string, cmd string) (podInfo *PodInfo, err error) {
+func getPodInfo(coreclient corev1client.CoreV1Interface, restconfig *rest.Config, podName string, ovnNamespace string, namespace string, cmd string) (podInfo *PodInfo, err error) {
 
 	var ethName string
 
@@ -260,6 +307,11 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 		ethName = util.GetLegacyK8sMgmtIntfName(node.Name)
 		podInfo.HostNetwork = true
 		linkIndex = 0
+	} else if offline {
+		// there is no pod to read the interface index from, nor OVS
+		// to look the interface up in
+		ethName = "eth0"
+		podInfo.HostNetwork = false
 	} else {
 		ethName = "eth0"
 		podInfo.HostNetwork = false
@@ -286,7 +338,7 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 	}
 	klog.V(5).Infof("Using interface name of %s with MAC of %s", ethName, podMAC)
 
-	if !pod.Spec.HostNetwork && linkIndex == 0 {
+	if !pod.Spec.HostNetwork && linkIndex == 0 && !offline {
 		klog.V(0).Infof("Fatal: Pod Network used and linkIndex is zero")
 		return nil, err
 	}
@@ -295,43 +347,23 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 		return nil, err
 	}
 
-	// Get pods in the openshift-ovn-kubernetes namespace
-	podsOvn, errOvn := coreclient.Pods(ovnNamespace).List(context.TODO(), metav1.ListOptions{})
-	if errOvn != nil {
-		klog.V(0).Infof("Cannot find pods in %s namespace", ovnNamespace)
-		return nil, errOvn
-	}
-
-	var ovnkubePod *kapi.Pod
-	// Find ovnkube-node-xxx pod running on the same node as Pod
-	for _, podOvn := range podsOvn.Items {
-		if podOvn.Spec.NodeName == node.Name {
-			if !strings.HasPrefix(podOvn.Name, "ovnkube-node-metrics") {
-				if strings.HasPrefix(podOvn.Name, "ovnkube-node") {
-					klog.V(5).Infof("==> pod %s is running on node %s", podOvn.Name, node.Name)
-					ovnkubePod = &podOvn
-					break
-				}
-			}
+	podInfo.NodeName = node.Name
+	podInfo.StorPort = "stor-" + node.Name
+	if !offline {
+		ovnkubePod, err := getOvnKubeNodePod(coreclient, node.Name, ovnNamespace)
+		if err != nil {
+			return nil, err
 		}
+		podInfo.OvnKubeContainerPodName = ovnkubePod.Name
 	}
-	if ovnkubePod == nil {
-		klog.V(0).Infof("Cannot find ovnkube-node pod on node %s in namespace %s", node.Name, ovnNamespace)
-		err := fmt.Errorf("cannot find ovnkube-node pod on node %s in namespace %s", node.Name, ovnNamespace)
-		return nil, err
-	}
-
-	podInfo.OvnKubeContainerPodName = ovnkubePod.Name
-	podInfo.NodeName = ovnkubePod.Spec.NodeName
-	podInfo.StorPort = "stor-" + ovnkubePod.Spec.NodeName
 
 	// Find stor MAC
-	klog.V(5).Infof("Command is: %s", "ovn-nbctl "+cmd+" lsp-get-addresses "+"stor-"+ovnkubePod.Spec.NodeName)
-	lspCmd := "ovn-nbctl " + cmd + " lsp-get-addresses " + "stor-" + ovnkubePod.Spec.NodeName
-	ipOutput, ipError, err := execInPod(coreclient, restconfig, ovnNamespace, ovnkubePod.Name, "ovnkube-node", lspCmd, "")
+	klog.V(5).Infof("Command is: %s", "ovn-nbctl "+cmd+" lsp-get-addresses "+podInfo.StorPort)
+	lspCmd := "ovn-nbctl " + cmd + " lsp-get-addresses " + podInfo.StorPort
+	ipOutput, ipError, err := execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubeContainerPodName, "ovnkube-node", lspCmd, "")
 	if err != nil {
 		fmt.Printf("execInPod() failed with %s stderr %s stdout %s \n", err, ipError, ipOutput)
-		klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, ovnkubePod.Name)
+		klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, podInfo.OvnKubeContainerPodName)
 		return nil, err
 	}
 
@@ -339,10 +371,14 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 
 	k8sMgmtIntfName := util.GetLegacyK8sMgmtIntfName(podInfo.NodeName)
 	if ethName == k8sMgmtIntfName {
-		podInfo.OVNName = types.K8sPrefix + ovnkubePod.Spec.NodeName
+		podInfo.OVNName = types.K8sPrefix + podInfo.NodeName
 		podInfo.VethName = "ovn-k8s-mp0"
 		klog.V(5).Infof("hostInterface on host stack OVN name is %s\n", podInfo.OVNName)
-	} else {
+	}
+	if offline {
+		return podInfo, nil
+	}
+	if ethName != k8sMgmtIntfName {
 
 		// obnkube-node-xxx uses host network.  Find host end of veth matching pod eth0 index
 
@@ -351,18 +387,18 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 		ipCmd := "ip -j addr show"
 		klog.V(5).Infof("Command is: %s", ipCmd)
 
-		hostOutput, hostError, err := execInPod(coreclient, restconfig, ovnNamespace, ovnkubePod.Name, "ovnkube-node", ipCmd, "")
+		hostOutput, hostError, err := execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubeContainerPodName, "ovnkube-node", ipCmd, "")
 		if err != nil {
 			fmt.Printf("execInPod() failed with %s stderr %s stdout %s \n", err, hostError, hostOutput)
-			klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, ovnkubePod.Name)
+			klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, podInfo.OvnKubeContainerPodName)
 			return nil, err
 		}
 
-		klog.V(5).Infof("==>ovnkubePod %s: ip addr show: %q", ovnkubePod.Name, hostOutput)
+		klog.V(5).Infof("==>ovnkubePod %s: ip addr show: %q", podInfo.OvnKubeContainerPodName, hostOutput)
 
 		var data []IpAddrReq
 		hostOutput = strings.Replace(hostOutput, "\n", "", -1)
-		klog.V(5).Infof("==> host %s NOW: %s", ovnkubePod.Name, hostOutput)
+		klog.V(5).Infof("==> host %s NOW: %s", podInfo.OvnKubeContainerPodName, hostOutput)
 		err = json.Unmarshal([]byte(hostOutput), &data)
 		if err != nil {
 			klog.V(1).Infof("JSON ERR: couldn't get stuff from data %v; json parse error: %v", data, err)
@@ -385,10 +421,10 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 	// ovs-vsctl get Interface [vethname] ofport
 	portCmd := "ovs-vsctl get Interface " + podInfo.VethName + " ofport"
 	klog.V(5).Infof("Command is: %s", portCmd)
-	portOutput, portError, err := execInPod(coreclient, restconfig, ovnNamespace, ovnkubePod.Name, "ovnkube-node", portCmd, "")
+	portOutput, portError, err := execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubeContainerPodName, "ovnkube-node", portCmd, "")
 	if err != nil {
 		fmt.Printf("execInPod() failed with %s stderr %s stdout %s \n", err, portError, portOutput)
-		klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, ovnkubePod.Name)
+		klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, podInfo.OvnKubeContainerPodName)
 		return nil, err
 	}
 	podInfo.PortNum = strings.Replace(portOutput, "\n", "", -1)
@@ -396,10 +432,10 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 	// ovs-vsctl get Interface ovn-k8s-mp0 ofport
 	portCmd = "ovs-vsctl get Interface " + "ovn-k8s-mp0" + " ofport"
 	klog.V(5).Infof("Command is: %s", portCmd)
-	localOutput, localError, err := execInPod(coreclient, restconfig, ovnNamespace, ovnkubePod.Name, "ovnkube-node", portCmd, "")
+	localOutput, localError, err := execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubeContainerPodName, "ovnkube-node", portCmd, "")
 	if err != nil {
 		fmt.Printf("execInPod() failed with %s stderr %s stdout %s \n", err, localError, localOutput)
-		klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, ovnkubePod.Name)
+		klog.V(5).Infof("execInPod() failed err %s - podInfo %v - ovnkubePod Name %s", err, podInfo, podInfo.OvnKubeContainerPodName)
 		return nil, err
 	}
 	podInfo.LocalNum = strings.Replace(localOutput, "\n", "", -1)
@@ -407,7 +443,303 @@ func getPodInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config,
 	return podInfo, err
 }
 
-func getOvnNamespace(coreclient *corev1client.CoreV1Client, override string) (string, error) {
+// getOvnKubeNodePod returns the ovnkube-node pod running on the node
+func getOvnKubeNodePod(coreclient corev1client.CoreV1Interface, nodeName string, ovnNamespace string) (*kapi.Pod, error) {
+	// Get pods in the openshift-ovn-kubernetes namespace
+	podsOvn, errOvn := coreclient.Pods(ovnNamespace).List(context.TODO(), metav1.ListOptions{})
+	if errOvn != nil {
+		klog.V(0).Infof("Cannot find pods in %s namespace", ovnNamespace)
+		return nil, errOvn
+	}
+
+	// Find ovnkube-node-xxx pod running on the node
+	for i, podOvn := range podsOvn.Items {
+		if podOvn.Spec.NodeName == nodeName {
+			if !strings.HasPrefix(podOvn.Name, "ovnkube-node-metrics") {
+				if strings.HasPrefix(podOvn.Name, "ovnkube-node") {
+					klog.V(5).Infof("==> pod %s is running on node %s", podOvn.Name, nodeName)
+					return &podsOvn.Items[i], nil
+				}
+			}
+		}
+	}
+	klog.V(0).Infof("Cannot find ovnkube-node pod on node %s in namespace %s", nodeName, ovnNamespace)
+	return nil, fmt.Errorf("cannot find ovnkube-node pod on node %s in namespace %s", nodeName, ovnNamespace)
+}
+
+// getNodeInfo returns the address and the gateway configuration of a node
+func getNodeInfo(coreclient corev1client.CoreV1Interface, restconfig *rest.Config, nodeName string, ovnNamespace string) (*NodeInfo, error) {
+	node, err := coreclient.Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		klog.V(1).Infof("Node %s not found\n", nodeName)
+		return nil, err
+	}
+
+	nodeIP, err := util.GetNodePrimaryIP(node)
+	if err != nil {
+		return nil, err
+	}
+	l3GatewayConfig, err := util.ParseNodeL3GatewayAnnotation(node)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo := &NodeInfo{
+		NodeName:     nodeName,
+		IP:           nodeIP,
+		GatewayMode:  string(l3GatewayConfig.Mode),
+		GatewayMAC:   l3GatewayConfig.MACAddress.String(),
+		LocalnetPort: l3GatewayConfig.InterfaceID,
+		// the localnet port of the external switch is named <bridge>_<node>
+		BridgeName: strings.TrimSuffix(l3GatewayConfig.InterfaceID, "_"+nodeName),
+	}
+	klog.V(5).Infof("==>Got node %s with IP %s and gateway bridge %s", nodeName, nodeIP, nodeInfo.BridgeName)
+	if offline {
+		// there is neither an ovnkube-node pod nor OVS to query
+		return nodeInfo, nil
+	}
+
+	ovnkubePod, err := getOvnKubeNodePod(coreclient, nodeName, ovnNamespace)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo.OvnKubeContainerPodName = ovnkubePod.Name
+
+	// the uplink of the gateway bridge is its only port which is not a patch port to br-int
+	portsCmd := "ovs-vsctl list-ports " + nodeInfo.BridgeName
+	klog.V(5).Infof("Command is: %s", portsCmd)
+	portsOutput, portsError, err := execInPod(coreclient, restconfig, ovnNamespace, ovnkubePod.Name, "ovnkube-node", portsCmd, "")
+	if err != nil {
+		fmt.Printf("execInPod() failed with %s stderr %s stdout %s \n", err, portsError, portsOutput)
+		return nil, err
+	}
+	for _, port := range strings.Split(portsOutput, "\n") {
+		if port != "" && !strings.HasPrefix(port, "patch-") {
+			nodeInfo.UplinkPort = port
+			break
+		}
+	}
+
+	// ovs-vsctl get Interface ovn-k8s-mp0 ofport
+	portCmd := "ovs-vsctl get Interface " + "ovn-k8s-mp0" + " ofport"
+	klog.V(5).Infof("Command is: %s", portCmd)
+	localOutput, localError, err := execInPod(coreclient, restconfig, ovnNamespace, ovnkubePod.Name, "ovnkube-node", portCmd, "")
+	if err != nil {
+		fmt.Printf("execInPod() failed with %s stderr %s stdout %s \n", err, localError, localOutput)
+		return nil, err
+	}
+	nodeInfo.LocalNum = strings.Replace(localOutput, "\n", "", -1)
+
+	return nodeInfo, nil
+}
+
+// findNodeByIP returns the name of the node owning ip, if any
+func findNodeByIP(coreclient corev1client.CoreV1Interface, ip string) (string, error) {
+	nodes, err := coreclient.Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, node := range nodes.Items {
+		for _, addr := range node.Status.Addresses {
+			if addr.Address == ip {
+				return node.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// findPodByIP returns the namespace and name of the pod network pod owning ip, if any
+func findPodByIP(coreclient corev1client.CoreV1Interface, ip string) (string, string, error) {
+	pods, err := coreclient.Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.HostNetwork {
+			continue
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			if podIP.IP == ip {
+				return pod.Namespace, pod.Name, nil
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// traceContext holds what is needed to run the trace commands in the cluster
+type traceContext struct {
+	coreclient   corev1client.CoreV1Interface
+	restconfig   *rest.Config
+	ovnNamespace string
+	nbcmd        string
+	sbcmd        string
+	protocol     string
+	dstPort      string
+	outputJSON   bool
+	// stdout is where the report is printed in json output mode, all the
+	// other output is then redirected to stderr
+	stdout io.Writer
+	report TraceReport
+	// execCmd replaces the commands run in the pods when set, for the tests
+	execCmd func(ovnkubePodName, cmd string) (string, string, error)
+}
+
+// exec runs a command in the ovnkube-node container of the given pod
+func (tc *traceContext) exec(ovnkubePodName, cmd string) (string, string, error) {
+	if tc.execCmd != nil {
+		return tc.execCmd(ovnkubePodName, cmd)
+	}
+	return execInPod(tc.coreclient, tc.restconfig, tc.ovnNamespace, ovnkubePodName, "ovnkube-node", cmd, "")
+}
+
+// execTrace runs a trace command in the ovnkube-node container of the given pod and returns its output
+func (tc *traceContext) execTrace(ovnkubePodName, desc, cmd, in string) string {
+	klog.V(5).Infof("%s command is %s", desc, cmd)
+	out, stderr, err := execInPod(tc.coreclient, tc.restconfig, tc.ovnNamespace, ovnkubePodName, "ovnkube-node", cmd, in)
+	if err != nil {
+		klog.V(1).Infof("%s error %v stdOut: %s\n stdErr: %s", desc, err, out, stderr)
+		fatalExit()
+	}
+	klog.V(2).Infof("%s Output: %s\n", desc, out)
+	return out
+}
+
+// ofprotoFields returns the protocol, addresses and ports of an ofproto/trace flow
+func (tc *traceContext) ofprotoFields(srcIP, dstIP, srcPort, dstPort string) string {
+	protocol, src, dst := tc.protocol, "nw_src", "nw_dst"
+	if getL3Ver(dstIP) == "ip6" {
+		protocol, src, dst = tc.protocol+"6", "ipv6_src", "ipv6_dst"
+	}
+	fields := " " + protocol + ","
+	fields += " " + dst + "=" + dstIP + ","
+	fields += " " + src + "=" + srcIP + ","
+	fields += " nw_ttl=64" + ","
+	fields += " " + tc.protocol + "_dst=" + dstPort + ","
+	fields += " " + tc.protocol + "_src=" + srcPort
+	return fields
+}
+
+// traceToIP traces traffic from a pod to an IP which is not a pod IP: either
+// the IP of the node of the pod, which OVN delivers to the host through its
+// management port, or the IP of another node or an IP outside of the cluster,
+// which leave through the gateway of the node of the pod. dstNodeName is the
+// node owning dstIP, if any.
+func traceToIP(tc *traceContext, srcPodInfo *PodInfo, srcNamespace string, srcNodeInfo *NodeInfo, dstIP, dstName, dstNodeName string) {
+	if srcPodInfo.HostNetwork {
+		fmt.Printf("Source pod %s is on host network, its traffic to %s does not go through OVN\n", srcPodInfo.PodName, dstName)
+		fatalExit()
+	}
+
+	// ovn-trace from src pod to dst IP
+	fromSrc := " 'inport==\"" + srcNamespace + "_" + srcPodInfo.PodName + "\""
+	fromSrc += " && eth.dst==" + srcPodInfo.StorMAC
+	fromSrc += " && eth.src==" + srcPodInfo.MAC
+	fromSrc += fmt.Sprintf(" && %s.dst==%s", getL3Ver(dstIP), dstIP)
+	fromSrc += fmt.Sprintf(" && %s.src==%s", srcPodInfo.getL3Ver(), srcPodInfo.IP)
+	fromSrc += " && ip.ttl==64"
+	fromSrc += " && " + tc.protocol + ".dst==" + tc.dstPort + " && " + tc.protocol + ".src==52888'"
+
+	fromSrcCmd := "ovn-trace " + tc.sbcmd + " " + srcPodInfo.NodeName + " " + "--ct=new " + fromSrc
+	ovnSrcDstOut := tc.execTrace(srcPodInfo.OvnKubeContainerPodName, "Source to "+dstName+" ovn-trace", fromSrcCmd, "")
+
+	// Traffic to the node of the pod is rerouted to its management port; so is
+	// all the egress traffic in local gateway mode. In shared gateway mode the
+	// traffic to other nodes and outside of the cluster leaves through the
+	// localnet port of the gateway.
+	mgmtPortOutput := "output to \"" + types.K8sPrefix + srcPodInfo.NodeName + "\""
+	toMgmtPort := dstNodeName == srcPodInfo.NodeName || srcNodeInfo.GatewayMode == string(config.GatewayModeLocal)
+	if toMgmtPort {
+		tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovn-trace", srcPodInfo.PodName, dstName, ovnSrcDstOut, mgmtPortOutput)
+	} else {
+		tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovn-trace", srcPodInfo.PodName, dstName, ovnSrcDstOut,
+			"output to \""+srcNodeInfo.LocalnetPort+"\"")
+	}
+
+	if offline {
+		return
+	}
+
+	// ovs-appctl ofproto/trace: src pod to dst IP
+	fromSrc = "ofproto/trace br-int"
+	fromSrc += " \"in_port=" + srcPodInfo.VethName + ","
+	fromSrc += " dl_dst=" + srcPodInfo.StorMAC + ","
+	fromSrc += " dl_src=" + srcPodInfo.MAC + ","
+	fromSrc += tc.ofprotoFields(srcPodInfo.IP, dstIP, "12345", tc.dstPort) + "\""
+
+	fromSrcCmd = "ovs-appctl " + fromSrc
+	appSrcDstOut := tc.execTrace(srcPodInfo.OvnKubeContainerPodName, "Source to "+dstName+" ovs-appctl", fromSrcCmd, "")
+
+	if toMgmtPort {
+		// trace will end at the ovs port number of the management port of the node
+		tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovs-appctl ofproto/trace", srcPodInfo.PodName, dstName, appSrcDstOut,
+			"output:"+srcNodeInfo.LocalNum+"\n\nFinal flow:")
+	} else {
+		// trace will continue on the gateway bridge through the localnet patch port
+		tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovs-appctl ofproto/trace", srcPodInfo.PodName, dstName, appSrcDstOut,
+			"bridge(\""+srcNodeInfo.BridgeName+"\")")
+	}
+}
+
+// traceFromExternal traces traffic from an IP outside of the cluster to a
+// NodePort of the entry node, or to an external IP of the service if it has
+// no NodePort, entering the cluster through the gateway bridge of the node
+func traceFromExternal(tc *traceContext, srcIP string, svcInfo *SvcInfo, svcName string, nodeInfo *NodeInfo) {
+	if nodeInfo.GatewayMode != string(config.GatewayModeShared) {
+		fmt.Printf("Tracing traffic entering the cluster is only supported in shared gateway mode, node %s uses %s gateway mode\n",
+			nodeInfo.NodeName, nodeInfo.GatewayMode)
+		fatalExit()
+	}
+
+	var dstIP, dstPort string
+	if svcInfo.NodePort != "" {
+		dstIP, dstPort = nodeInfo.IP, svcInfo.NodePort
+	} else if len(svcInfo.ExternalIPs) > 0 {
+		dstIP, dstPort = svcInfo.ExternalIPs[0], svcInfo.Port
+	} else {
+		fmt.Printf("Service %s has neither a NodePort nor an external IP\n", svcName)
+		fatalExit()
+	}
+	dst := fmt.Sprintf("%s (%s)", svcName, net.JoinHostPort(dstIP, dstPort))
+	fmt.Printf("using %s on node %s as entry point of service %s\n", net.JoinHostPort(dstIP, dstPort), nodeInfo.NodeName, svcName)
+
+	// ovn-trace from the localnet port of the external switch of the node
+	fromSrc := " 'inport==\"" + nodeInfo.LocalnetPort + "\""
+	fromSrc += " && eth.dst==" + nodeInfo.GatewayMAC
+	fromSrc += " && eth.src==" + externalMAC
+	fromSrc += fmt.Sprintf(" && %s.dst==%s", getL3Ver(dstIP), dstIP)
+	fromSrc += fmt.Sprintf(" && %s.src==%s", getL3Ver(srcIP), srcIP)
+	fromSrc += " && ip.ttl==64"
+	fromSrc += " && " + tc.protocol + ".dst==" + dstPort + " && " + tc.protocol + ".src==52888'"
+	fromSrc += " --lb-dst " + net.JoinHostPort(svcInfo.PodIP, svcInfo.PodPort)
+
+	fromSrcCmd := "ovn-trace " + tc.sbcmd + " " + types.ExternalSwitchPrefix + nodeInfo.NodeName + " " + "--ct=new " + fromSrc
+	ovnSrcDstOut := tc.execTrace(nodeInfo.OvnKubeContainerPodName, "External source to service ovn-trace", fromSrcCmd, "")
+	tc.checkTraceOutput(nodeInfo.OvnKubeContainerPodName, "ovn-trace", srcIP, dst, ovnSrcDstOut,
+		"output to \""+svcInfo.PodNamespace+"_"+svcInfo.PodName+"\"")
+
+	if offline {
+		return
+	}
+
+	// ovs-appctl ofproto/trace: from the uplink of the gateway bridge
+	if nodeInfo.UplinkPort == "" {
+		fmt.Printf("Cannot find the uplink port of bridge %s on node %s\n", nodeInfo.BridgeName, nodeInfo.NodeName)
+		fatalExit()
+	}
+	fromSrc = "ofproto/trace " + nodeInfo.BridgeName
+	fromSrc += " \"in_port=" + nodeInfo.UplinkPort + ","
+	fromSrc += " dl_dst=" + nodeInfo.GatewayMAC + ","
+	fromSrc += " dl_src=" + externalMAC + ","
+	fromSrc += tc.ofprotoFields(srcIP, dstIP, "12345", dstPort) + "\""
+
+	fromSrcCmd = "ovs-appctl " + fromSrc
+	appSrcDstOut := tc.execTrace(nodeInfo.OvnKubeContainerPodName, "External source to service ovs-appctl", fromSrcCmd, "")
+	// trace will continue on br-int through the localnet patch port
+	tc.checkTraceOutput(nodeInfo.OvnKubeContainerPodName, "ovs-appctl ofproto/trace", srcIP, dst, appSrcDstOut, "bridge(\"br-int\")")
+}
+
+func getOvnNamespace(coreclient corev1client.CoreV1Interface, override string) (string, error) {
 	if override != "" {
 		return override, nil
 	}
@@ -426,7 +758,7 @@ func getOvnNamespace(coreclient *corev1client.CoreV1Client, override string) (st
 
 // Get the OVN Database URIs from the first container found in any pod in the ovn-kubernetes namespace with name "ovnkube-master"
 // Returns nbAddress, sbAddress, protocol == "ssl", nil
-func getDatabaseURIs(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ovnNamespace string) (string, string, bool, error) {
+func getDatabaseURIs(coreclient corev1client.CoreV1Interface, restconfig *rest.Config, ovnNamespace string) (string, string, bool, error) {
 	containerName := "ovnkube-master"
 	var err error
 
@@ -482,6 +814,9 @@ func getDatabaseURIs(coreclient *corev1client.CoreV1Client, restconfig *rest.Con
 	return nbAddress, sbAddress, protocol == "ssl", nil
 }
 
+// externalMAC is the source MAC of the traffic traced from outside of the cluster
+const externalMAC = "02:00:00:00:00:01"
+
 var (
 	level klog.Level
 )
@@ -501,12 +836,19 @@ func main() {
 	cliConfig := flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
 
 	srcPodName := flag.String("src", "", "src: source pod name")
+	srcIP := flag.String("src-ip", "", "src-ip: source IP outside of the cluster, entering the cluster on the gateway of -dst-node; requires -service")
 	dstPodName := flag.String("dst", "", "dest: destination pod name")
 	dstSvcName := flag.String("service", "", "service: destination service name")
+	dstIP := flag.String("dst-ip", "", "dst-ip: destination IP of a pod, a node or outside of the cluster")
+	dstNodeName := flag.String("dst-node", "", "dst-node: destination node name; with -service, trace the NodePort of the service on this node")
 	dstPort := flag.String("dst-port", "80", "dst-port: destination port")
 	tcp := flag.Bool("tcp", false, "use tcp transport protocol")
 	udp := flag.Bool("udp", false, "use udp transport protocol")
 	loglevel := flag.String("loglevel", "0", "loglevel: klog level")
+	output := flag.String("output", "text", "output: output format, text or json")
+	nbDBFile := flag.String("nb-db-file", "", "nb-db-file: NB database snapshot to trace against offline, instead of a live cluster")
+	sbDBFile := flag.String("sb-db-file", "", "sb-db-file: SB database snapshot to trace against offline, instead of a live cluster")
+	k8sObjectsFile := flag.String("k8s-objects-file", "", "k8s-objects-file: YAML dump of the pods, services, endpoints and nodes of the cluster, to trace against offline")
 
 	flag.Parse()
 
@@ -515,97 +857,157 @@ func main() {
 	err = level.Set(*loglevel)
 	if err != nil {
 		fmt.Printf("fatal: cannot set logging level\n")
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(0).Infof("Log level set to: %s", *loglevel)
 
+	if *output != "text" && *output != "json" {
+		fmt.Printf("Usage: output must be text or json\n")
+		klog.V(1).Infof("Usage: output must be text or json")
+		fatalExit()
+	}
+	offline = *nbDBFile != "" || *sbDBFile != "" || *k8sObjectsFile != ""
+	if offline && (*nbDBFile == "" || *sbDBFile == "" || *k8sObjectsFile == "") {
+		fmt.Printf("Usage: NB and SB database files and Kubernetes objects file must all be specified to trace offline\n")
+		klog.V(1).Infof("Usage: NB and SB database files and Kubernetes objects file must all be specified to trace offline")
+		fatalExit()
+	}
+	// In json output mode only the report is printed on stdout, the
+	// progress messages go to stderr
+	stdout := os.Stdout
+	if *output == "json" {
+		os.Stdout = os.Stderr
+	}
+
 	srcNamespace := *psrcNamespace
 	dstNamespace := *pdstNamespace
 
-	if *srcPodName == "" {
-		fmt.Printf("Usage: source pod must be specified\n")
-		klog.V(1).Infof("Usage: source pod must be specified")
-		os.Exit(-1)
+	if *srcPodName == "" && *srcIP == "" {
+		fmt.Printf("Usage: source pod or source IP must be specified\n")
+		klog.V(1).Infof("Usage: source pod or source IP must be specified")
+		fatalExit()
+	}
+	if *srcPodName != "" && *srcIP != "" {
+		fmt.Printf("Usage: Both source pod and source IP cannot be specified\n")
+		klog.V(1).Infof("Usage: Both source pod and source IP cannot be specified")
+		fatalExit()
+	}
+	if *srcIP != "" && (*dstSvcName == "" || *dstNodeName == "") {
+		fmt.Printf("Usage: destination service and destination node must be specified for source IP\n")
+		klog.V(1).Infof("Usage: destination service and destination node must be specified for source IP")
+		fatalExit()
+	}
+	if *dstIP != "" && net.ParseIP(*dstIP) == nil {
+		fmt.Printf("Usage: invalid destination IP %s\n", *dstIP)
+		klog.V(1).Infof("Usage: invalid destination IP %s", *dstIP)
+		fatalExit()
+	}
+	if *srcIP != "" && net.ParseIP(*srcIP) == nil {
+		fmt.Printf("Usage: invalid source IP %s\n", *srcIP)
+		klog.V(1).Infof("Usage: invalid source IP %s", *srcIP)
+		fatalExit()
 	}
 	if !*tcp && !*udp {
 		fmt.Printf("Usage: either tcp or udp must be specified\n")
 		klog.V(1).Infof("Usage: either tcp or udp must be specified")
-		os.Exit(-1)
+		fatalExit()
 	}
 	if *udp && *tcp {
 		fmt.Printf("Usage: Both tcp or udp cannot be specified\n")
 		klog.V(1).Infof("Usage: Both tcp or udp cannot be specified")
-		os.Exit(-1)
+		fatalExit()
 	}
 	if *tcp {
-		if *dstSvcName == "" && *dstPodName == "" {
-			fmt.Printf("Usage: destination pod or destination service must be specified for tcp\n")
-			klog.V(1).Infof("Usage: destination pod or destination service must be specified for tcp")
-			os.Exit(-1)
+		if *dstSvcName == "" && *dstPodName == "" && *dstIP == "" && *dstNodeName == "" {
+			fmt.Printf("Usage: destination pod, service, IP or node must be specified for tcp\n")
+			klog.V(1).Infof("Usage: destination pod, service, IP or node must be specified for tcp")
+			fatalExit()
 		} else {
 			protocol = "tcp"
 		}
 	}
 	if *udp {
-		if *dstPodName == "" {
-			fmt.Printf("Usage: destination pod must be specified for udp\n")
-			klog.V(1).Infof("Usage: destination pod must be specified for udp")
-			os.Exit(-1)
+		if *dstSvcName != "" || (*dstPodName == "" && *dstIP == "" && *dstNodeName == "") {
+			fmt.Printf("Usage: destination pod, IP or node must be specified for udp\n")
+			klog.V(1).Infof("Usage: destination pod, IP or node must be specified for udp")
+			fatalExit()
 		} else {
 			protocol = "udp"
 		}
 	}
 
 	var restconfig *rest.Config
+	var coreclient corev1client.CoreV1Interface
+	var nbUri, sbUri string
+	var useSSL bool
 
-	// This might work better?  https://godoc.org/sigs.k8s.io/controller-runtime/pkg/client/config
-
-	// When supplied the kubeconfig supplied via cli takes precedence
-	if *cliConfig != "" {
-
-		// use the current context in kubeconfig
-		restconfig, err = clientcmd.BuildConfigFromFlags("", *cliConfig)
+	if offline {
+		coreclient, err = loadKubernetesObjects(*k8sObjectsFile)
 		if err != nil {
-			klog.V(1).Infof(" Unexpected error: %v", err)
-			os.Exit(-1)
+			fmt.Printf("Failed to load Kubernetes objects: %v\n", err)
+			fatalExit()
+		}
+		nbUri, sbUri, err = startOfflineDatabases(*nbDBFile, *sbDBFile)
+		if err != nil {
+			fmt.Printf("Failed to start the databases: %v\n", err)
+			fatalExit()
 		}
 	} else {
+		// This might work better?  https://godoc.org/sigs.k8s.io/controller-runtime/pkg/client/config
+
+		// When supplied the kubeconfig supplied via cli takes precedence
+		if *cliConfig != "" {
+
+			// use the current context in kubeconfig
+			restconfig, err = clientcmd.BuildConfigFromFlags("", *cliConfig)
+			if err != nil {
+				klog.V(1).Infof(" Unexpected error: %v", err)
+				fatalExit()
+			}
+		} else {
 
-		// Instantiate loader for kubeconfig file.
-		kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			clientcmd.NewDefaultClientConfigLoadingRules(),
-			&clientcmd.ConfigOverrides{},
-		)
+			// Instantiate loader for kubeconfig file.
+			kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+				clientcmd.NewDefaultClientConfigLoadingRules(),
+				&clientcmd.ConfigOverrides{},
+			)
 
-		// Get a rest.Config from the kubeconfig file.  This will be passed into all
-		// the client objects we create.
-		restconfig, err = kubeconfig.ClientConfig()
+			// Get a rest.Config from the kubeconfig file.  This will be passed into all
+			// the client objects we create.
+			restconfig, err = kubeconfig.ClientConfig()
+			if err != nil {
+				klog.V(1).Infof(" Unexpected error: %v", err)
+				fatalExit()
+			}
+		}
+
+		// Create a Kubernetes core/v1 client.
+		coreclient, err = corev1client.NewForConfig(restconfig)
 		if err != nil {
 			klog.V(1).Infof(" Unexpected error: %v", err)
-			os.Exit(-1)
+			fatalExit()
 		}
-	}
 
-	// Create a Kubernetes core/v1 client.
-	coreclient, err := corev1client.NewForConfig(restconfig)
-	if err != nil {
-		klog.V(1).Infof(" Unexpected error: %v", err)
-		os.Exit(-1)
-	}
+		// Get OVN Namespace
+		ovnNamespace, err = getOvnNamespace(coreclient, *pcfgNamespace)
+		if err != nil {
+			klog.V(1).Infof(" Unexpected error: %v", err)
+			fatalExit()
+		}
+		klog.V(5).Infof("OVN Kubernetes namespace is %s", ovnNamespace)
 
-	// Get OVN Namespace
-	ovnNamespace, err = getOvnNamespace(coreclient, *pcfgNamespace)
-	if err != nil {
-		klog.V(1).Infof(" Unexpected error: %v", err)
-		os.Exit(-1)
+		nbUri, sbUri, useSSL, err = getDatabaseURIs(coreclient, restconfig, ovnNamespace)
+		if err != nil {
+			fmt.Printf("Failed to get database URIs: %v\n", err)
+			fatalExit()
+		}
 	}
-	klog.V(5).Infof("OVN Kubernetes namespace is %s", ovnNamespace)
 
 	// List all Nodes.
 	nodes, err := coreclient.Nodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		klog.V(1).Infof(" Unexpected error: %v", err)
-		os.Exit(-1)
+		fatalExit()
 	}
 
 	masters := make(map[string]string)
@@ -642,12 +1044,6 @@ func main() {
 
 	// Common ssl parameters
 	var sslCertKeys string
-	nbUri, sbUri, useSSL, err := getDatabaseURIs(coreclient, restconfig, ovnNamespace)
-	if err != nil {
-		fmt.Printf("Failed to get database URIs: %v\n", err)
-		os.Exit(-1)
-	}
-
 	if useSSL {
 		sslCertKeys = "-p /ovn-cert/tls.key -c /ovn-cert/tls.crt -C /ovn-ca/ca-bundle.crt "
 	} else {
@@ -660,28 +1056,122 @@ func main() {
 	sbcmd := sslCertKeys + "--db " + sbUri
 	klog.V(5).Infof("The sbcmd is %s", sbcmd)
 
+	tc := &traceContext{
+		coreclient:   coreclient,
+		restconfig:   restconfig,
+		ovnNamespace: ovnNamespace,
+		nbcmd:        nbcmd,
+		sbcmd:        sbcmd,
+		protocol:     protocol,
+		dstPort:      *dstPort,
+		outputJSON:   *output == "json",
+		stdout:       stdout,
+	}
+
+	// Trace from outside of the cluster to a service
+	if *srcIP != "" {
+		dstSvcInfo, err := getSvcInfo(coreclient, restconfig, *dstSvcName, ovnNamespace, dstNamespace, nbcmd, *dstPort)
+		if err != nil {
+			fmt.Printf("Failed to get information from service %s: %v\n", *dstSvcName, err)
+			klog.V(1).Infof("Failed to get information from service %s: %v", *dstSvcName, err)
+			fatalExit()
+		}
+		dstNodeInfo, err := getNodeInfo(coreclient, restconfig, *dstNodeName, ovnNamespace)
+		if err != nil {
+			fmt.Printf("Failed to get information from node %s: %v\n", *dstNodeName, err)
+			klog.V(1).Infof("Failed to get information from node %s: %v", *dstNodeName, err)
+			fatalExit()
+		}
+		traceFromExternal(tc, *srcIP, dstSvcInfo, *dstSvcName, dstNodeInfo)
+		tc.complete(true)
+		return
+	}
+
 	// Get info needed for the src Pod
 	srcPodInfo, err := getPodInfo(coreclient, restconfig, *srcPodName, ovnNamespace, srcNamespace, nbcmd)
 	if err != nil {
 		fmt.Printf("Failed to get information from pod %s: %v\n", *srcPodName, err)
 		klog.V(1).Infof("Failed to get information from pod %s: %v", *srcPodName, err)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(5).Infof("srcPodInfo is %v", srcPodInfo)
 
+	// Trace to an IP which is not a pod IP, or to a node
+	dstIPName := *dstIP
+	if *dstIP != "" {
+		podNamespace, podName, err := findPodByIP(coreclient, *dstIP)
+		if err != nil {
+			fmt.Printf("Failed to look up the pod with IP %s: %v\n", *dstIP, err)
+			fatalExit()
+		}
+		if podName != "" {
+			klog.V(1).Infof("Destination IP %s belongs to pod %s/%s", *dstIP, podNamespace, podName)
+			*dstPodName = podName
+			dstNamespace = podNamespace
+		} else {
+			nodeName, err := findNodeByIP(coreclient, *dstIP)
+			if err != nil {
+				fmt.Printf("Failed to look up the node with IP %s: %v\n", *dstIP, err)
+				fatalExit()
+			}
+			if nodeName != "" {
+				klog.V(1).Infof("Destination IP %s belongs to node %s", *dstIP, nodeName)
+				*dstNodeName = nodeName
+				dstIPName = fmt.Sprintf("%s (%s)", nodeName, *dstIP)
+			}
+		}
+	}
+	if *dstPodName == "" && *dstSvcName == "" {
+		srcNodeInfo, err := getNodeInfo(coreclient, restconfig, srcPodInfo.NodeName, ovnNamespace)
+		if err != nil {
+			fmt.Printf("Failed to get information from node %s: %v\n", srcPodInfo.NodeName, err)
+			klog.V(1).Infof("Failed to get information from node %s: %v", srcPodInfo.NodeName, err)
+			fatalExit()
+		}
+		if *dstIP != "" {
+			traceToIP(tc, srcPodInfo, srcNamespace, srcNodeInfo, *dstIP, dstIPName, *dstNodeName)
+		} else {
+			dstNodeInfo, err := getNodeInfo(coreclient, restconfig, *dstNodeName, ovnNamespace)
+			if err != nil {
+				fmt.Printf("Failed to get information from node %s: %v\n", *dstNodeName, err)
+				klog.V(1).Infof("Failed to get information from node %s: %v", *dstNodeName, err)
+				fatalExit()
+			}
+			traceToIP(tc, srcPodInfo, srcNamespace, srcNodeInfo, dstNodeInfo.IP, *dstNodeName, *dstNodeName)
+		}
+		tc.complete(true)
+		return
+	}
+
 	var dstSvcInfo *SvcInfo
 
 	// Get destination service if there is one
 	if *dstSvcName != "" {
 		//Get dst servcie
-		dstSvcInfo, err = getSvcInfo(coreclient, restconfig, *dstSvcName, ovnNamespace, dstNamespace, nbcmd)
+		dstSvcInfo, err = getSvcInfo(coreclient, restconfig, *dstSvcName, ovnNamespace, dstNamespace, nbcmd, *dstPort)
 		if err != nil {
 			fmt.Printf("Failed to get information from service %s: %v\n", *dstSvcName, err)
 			klog.V(1).Infof("Failed to get information from service %s: %v", *dstSvcName, err)
-			os.Exit(-1)
+			fatalExit()
 		}
 
-		// ovn-trace from src pod to clusterIP of ther service
+		// ovn-trace from src pod to clusterIP of ther service, or to the
+		// NodePort of the service on the destination node
+
+		svcIP, svcPort := dstSvcInfo.IP, *dstPort
+		if *dstNodeName != "" {
+			dstNodeInfo, err := getNodeInfo(coreclient, restconfig, *dstNodeName, ovnNamespace)
+			if err != nil {
+				fmt.Printf("Failed to get information from node %s: %v\n", *dstNodeName, err)
+				klog.V(1).Infof("Failed to get information from node %s: %v", *dstNodeName, err)
+				fatalExit()
+			}
+			if dstSvcInfo.NodePort == "" {
+				fmt.Printf("Service %s has no NodePort\n", *dstSvcName)
+				fatalExit()
+			}
+			svcIP, svcPort = dstNodeInfo.IP, dstSvcInfo.NodePort
+		}
 
 		var fromSrc string
 
@@ -692,10 +1182,10 @@ func main() {
 		}
 		fromSrc += " && eth.dst==" + srcPodInfo.StorMAC
 		fromSrc += " && eth.src==" + srcPodInfo.MAC
-		fromSrc += fmt.Sprintf(" && %s.dst==%s", dstSvcInfo.getL3Ver(), dstSvcInfo.IP)
+		fromSrc += fmt.Sprintf(" && %s.dst==%s", getL3Ver(svcIP), svcIP)
 		fromSrc += fmt.Sprintf(" && %s.src==%s", srcPodInfo.getL3Ver(), srcPodInfo.IP)
 		fromSrc += " && ip.ttl==64"
-		fromSrc += " && " + protocol + ".dst==" + *dstPort + " && " + protocol + ".src==52888'"
+		fromSrc += " && " + protocol + ".dst==" + svcPort + " && " + protocol + ".src==52888'"
 		fromSrc += " --lb-dst " + dstSvcInfo.PodIP + ":" + dstSvcInfo.PodPort
 
 		fromSrcCmd := "ovn-trace " + sbcmd + " " + srcPodInfo.NodeName + " " + "--ct=new " + fromSrc
@@ -704,19 +1194,12 @@ func main() {
 		ovnSrcDstOut, ovnSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromSrcCmd, "")
 		if err != nil {
 			klog.V(1).Infof("Source to Destination ovn-trace error %v stdOut: %s\n stdErr: %s", err, ovnSrcDstOut, ovnSrcDstErr)
-			os.Exit(-1)
+			fatalExit()
 		}
 		klog.V(2).Infof("Source to service clusterIP  ovn-trace Output: %s\n", ovnSrcDstOut)
 
 		successString := "output to \"" + dstSvcInfo.PodNamespace + "_" + dstSvcInfo.PodName + "\""
-		if strings.Contains(ovnSrcDstOut, successString) {
-			fmt.Printf("ovn-trace indicates success from %s to %s - matched on %s\n", *srcPodName, *dstSvcName, successString)
-			klog.V(0).Infof("ovn-trace indicates success from %s to %s - matched on %s\n", *srcPodName, *dstSvcName, successString)
-		} else {
-			fmt.Printf("ovn-trace indicates failure from %s to %s - %s not matched\n", *srcPodName, *dstSvcName, successString)
-			klog.V(0).Infof("ovn-trace indicates failure from %s to %s - %s not matched\n", *srcPodName, *dstSvcName, successString)
-			os.Exit(-1)
-		}
+		tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovn-trace", *srcPodName, *dstSvcName, ovnSrcDstOut, successString)
 
 		// set dst pod name, we'll use this to run through pod-pod tests as if use supplied this pod
 		*dstPodName = dstSvcInfo.PodName
@@ -729,7 +1212,7 @@ func main() {
 	if err != nil {
 		fmt.Printf("Failed to get information from pod %s: %v\n", *dstPodName, err)
 		klog.V(1).Infof("Failed to get information from pod %s: %v", *dstPodName, err)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(5).Infof("dstPodInfo is %v\n", dstPodInfo)
 
@@ -741,7 +1224,7 @@ func main() {
 	// At least one pod must not be on the Host Network
 	if srcPodInfo.HostNetwork && dstPodInfo.HostNetwork {
 		fmt.Printf("Both pods cannot be on Host Network; use ping\n")
-		os.Exit(-1)
+		fatalExit()
 	}
 
 	// ovn-trace from src pod to dst pod
@@ -766,7 +1249,7 @@ func main() {
 	ovnSrcDstOut, ovnSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromSrcCmd, "")
 	if err != nil {
 		klog.V(1).Infof("Source to Destination ovn-trace error %v stdOut: %s\n stdErr: %s", err, ovnSrcDstOut, ovnSrcDstErr)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(2).Infof("Source to Destination ovn-trace Output: %s\n", ovnSrcDstOut)
 
@@ -777,14 +1260,7 @@ func main() {
 	} else {
 		successString = "output to \"" + dstNamespace + "_" + *dstPodName + "\""
 	}
-	if strings.Contains(ovnSrcDstOut, successString) {
-		fmt.Printf("ovn-trace indicates success from %s to %s - matched on %s\n", *srcPodName, *dstPodName, successString)
-		klog.V(0).Infof("ovn-trace indicates success from %s to %s - matched on %s\n", *srcPodName, *dstPodName, successString)
-	} else {
-		fmt.Printf("ovn-trace indicates failure from %s to %s - %s not matched\n", *srcPodName, *dstPodName, successString)
-		klog.V(0).Infof("ovn-trace indicates failure from %s to %s - %s not matched\n", *srcPodName, *dstPodName, successString)
-		os.Exit(-1)
-	}
+	tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovn-trace", *srcPodName, *dstPodName, ovnSrcDstOut, successString)
 
 	// Trace from dst pod to src pod
 
@@ -809,7 +1285,7 @@ func main() {
 	ovnDstSrcOut, ovnDstSrcErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromDstCmd, "")
 	if err != nil {
 		klog.V(1).Infof("Source to Destination ovn-trace error %v stdOut: %s\n stdErr: %s", err, ovnDstSrcOut, ovnDstSrcErr)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(2).Infof("Destination to Source ovn-trace Output: %s\n", ovnDstSrcOut)
 
@@ -819,13 +1295,12 @@ func main() {
 	} else {
 		successString = "output to \"" + srcNamespace + "_" + *srcPodName + "\""
 	}
-	if strings.Contains(ovnDstSrcOut, successString) {
-		fmt.Printf("ovn-trace indicates success from %s to %s - matched on %s\n", *dstPodName, *srcPodName, successString)
-		klog.V(0).Infof("ovn-trace indicates success from %s to %s - matched on %s\n", *dstPodName, *srcPodName, successString)
-	} else {
-		fmt.Printf("ovn-trace indicates failure from %s to %s - %s not matched\n", *dstPodName, *srcPodName, successString)
-		klog.V(0).Infof("ovn-trace indicates failure from %s to %s - %s not matched\n", *dstPodName, *srcPodName, successString)
-		os.Exit(-1)
+	tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovn-trace", *dstPodName, *srcPodName, ovnDstSrcOut, successString)
+
+	if offline {
+		// there is no OVS to run ofproto/trace against
+		tc.complete(true)
+		return
 	}
 
 	// ovs-appctl ofproto/trace: src pod to dst pod
@@ -846,7 +1321,7 @@ func main() {
 	appSrcDstOut, appSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromSrcCmd, "")
 	if err != nil {
 		klog.V(1).Infof("Source to Destination ovs-appctl error %v stdOut: %s\n stdErr: %s", err, appSrcDstOut, appSrcDstErr)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(2).Infof("Source to Destination ovs-appctl Output: %s\n", appSrcDstOut)
 
@@ -863,14 +1338,7 @@ func main() {
 		successString = "-> output to kernel tunnel"
 	}
 
-	if strings.Contains(appSrcDstOut, successString) {
-		fmt.Printf("ovs-appctl ofproto/trace indicates success from %s to %s - matched on %s\n", *srcPodName, *dstPodName, successString)
-		klog.V(0).Infof("ovs-appctl ofproto/trace indicates success from %s to %s - matched on %s\n", *srcPodName, *dstPodName, successString)
-	} else {
-		fmt.Printf("ovs-appctl ofproto/trace indicates failure from %s to %s - %s not matched\n", *srcPodName, *dstPodName, successString)
-		klog.V(0).Infof("ovs-appctl ofproto/trace indicates failure from %s to %s - %s not matched\n", *srcPodName, *dstPodName, successString)
-		os.Exit(-1)
-	}
+	tc.checkTraceOutput(srcPodInfo.OvnKubeContainerPodName, "ovs-appctl ofproto/trace", *srcPodName, *dstPodName, appSrcDstOut, successString)
 
 	// ovs-appctl ofproto/trace: dst pod to src pod
 
@@ -890,7 +1358,7 @@ func main() {
 	appDstSrcOut, appDstSrcErr, err := execInPod(coreclient, restconfig, ovnNamespace, dstPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromDstCmd, "")
 	if err != nil {
 		klog.V(1).Infof("Destination to Source ovs-appctl error %v stdOut: %s\n stdErr: %s", err, appDstSrcOut, appDstSrcErr)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(2).Infof("Destination to Source ovs-appctl Output: %s\n", appDstSrcOut)
 
@@ -907,14 +1375,7 @@ func main() {
 		successString = "-> output to kernel tunnel"
 	}
 
-	if strings.Contains(appDstSrcOut, successString) {
-		fmt.Printf("ovs-appctl ofproto/trace indicates success from %s to %s - matched on %s\n", *dstPodName, *srcPodName, successString)
-		klog.V(0).Infof("ovs-appctl ofproto/trace indicates success from %s to %s - matched on %s\n", *dstPodName, *srcPodName, successString)
-	} else {
-		fmt.Printf("ovs-appctl ofproto/trace indicates failure from %s to %s - %s not matched\n", *dstPodName, *srcPodName, successString)
-		klog.V(0).Infof("ovs-appctl ofproto/trace indicates failure from %s to %s - %s not matched\n", *dstPodName, *srcPodName, successString)
-		os.Exit(-1)
-	}
+	tc.checkTraceOutput(dstPodInfo.OvnKubeContainerPodName, "ovs-appctl ofproto/trace", *dstPodName, *srcPodName, appDstSrcOut, successString)
 
 	// Install dependencies with pip3 in case they are missing (for older images)
 	podList := []string{
@@ -930,7 +1391,7 @@ func main() {
 			depVerifyOut, depVerifyErr, err := execInPod(coreclient, restconfig, ovnNamespace, podName, "ovnkube-node", dependencyCmd, "")
 			if err != nil {
 				klog.V(0).Infof("Dependency verification error in pod %s, container %s. Error '%v', stdOut: '%s'\n stdErr: %s", podName, "ovnkube-node", err, depVerifyOut, depVerifyErr)
-				os.Exit(-1)
+				fatalExit()
 			}
 			trueFalse := strings.TrimSuffix(depVerifyOut, "\n")
 			klog.V(10).Infof("Dependency check '%s' in pod '%s', container '%s' yielded '%s'", dependencyCmd, podName, "ovnkube-node", trueFalse)
@@ -939,7 +1400,7 @@ func main() {
 				depInstallOut, depInstallErr, err := execInPod(coreclient, restconfig, ovnNamespace, podName, "ovnkube-node", installCmd, "")
 				if err != nil {
 					klog.V(0).Infof("ovn-detrace error in pod %s, container %s. Error '%v', stdOut: '%s'\n stdErr: %s", podName, "ovnkube-node", err, depInstallOut, depInstallErr)
-					os.Exit(-1)
+					fatalExit()
 				}
 				fmt.Printf("install ovn-detrace Output: %s\n", depInstallOut)
 			}
@@ -959,7 +1420,7 @@ func main() {
 	dtraceSrcDstOut, dtraceSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromSrcCmd, appSrcDstOut)
 	if err != nil {
 		klog.V(1).Infof("Source to Destination ovn-detrace error %v stdOut: %s\n stdErr: %s", err, dtraceSrcDstOut, dtraceSrcDstErr)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(2).Infof("Source to Destination ovn-detrace Completed - Output: %s\n", dtraceSrcDstOut)
 
@@ -975,9 +1436,9 @@ func main() {
 	dtraceDstSrcOut, dtraceDstSrcErr, err := execInPod(coreclient, restconfig, ovnNamespace, dstPodInfo.OvnKubeContainerPodName, "ovnkube-node", fromDstCmd, appDstSrcOut)
 	if err != nil {
 		klog.V(1).Infof("Destination to Source ovn-detrace error %v stdOut: %s\n stdErr: %s", err, dtraceDstSrcOut, dtraceDstSrcErr)
-		os.Exit(-1)
+		fatalExit()
 	}
 	klog.V(2).Infof("Destination to Source detrace Completed - Output: %s\n", appDstSrcOut)
 
-	fmt.Println("ovn-trace command Completed normally")
+	tc.complete(true)
 }
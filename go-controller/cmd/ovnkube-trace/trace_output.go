@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	klog "k8s.io/klog"
+
+	types "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+)
+
+// TraceReport is the result of all the traces run, printed with -output json
+type TraceReport struct {
+	Success bool          `json:"success"`
+	Traces  []TraceResult `json:"traces"`
+}
+
+// TraceResult is the result of a single ovn-trace or ofproto/trace command
+type TraceResult struct {
+	Command     string         `json:"command"`
+	Source      string         `json:"source"`
+	Destination string         `json:"destination"`
+	Success     bool           `json:"success"`
+	MatchedOn   string         `json:"matchedOn,omitempty"`
+	Expected    []string       `json:"expected,omitempty"`
+	Hops        []TraceHop     `json:"hops,omitempty"`
+	Verdict     *PolicyVerdict `json:"verdict,omitempty"`
+}
+
+// TraceHop is a flow hit by a trace: a logical flow of a datapath for
+// ovn-trace, an OpenFlow flow of a bridge for ofproto/trace
+type TraceHop struct {
+	Datapath string   `json:"datapath"`
+	Pipeline string   `json:"pipeline,omitempty"`
+	Table    int      `json:"table"`
+	Stage    string   `json:"stage,omitempty"`
+	Match    string   `json:"match"`
+	Priority int      `json:"priority"`
+	UUID     string   `json:"uuid,omitempty"`
+	Actions  []string `json:"actions,omitempty"`
+}
+
+// PolicyVerdict explains why a trace was dropped: the ACL which dropped the
+// traffic and the Kubernetes object it was created for
+type PolicyVerdict struct {
+	Action      string `json:"action"`
+	Stage       string `json:"stage"`
+	Datapath    string `json:"datapath"`
+	ACLUUID     string `json:"aclUUID,omitempty"`
+	ACLName     string `json:"aclName,omitempty"`
+	ACLPriority int    `json:"aclPriority,omitempty"`
+	ACLMatch    string `json:"aclMatch,omitempty"`
+	Kind        string `json:"kind,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Rule        string `json:"rule,omitempty"`
+	Description string `json:"description"`
+}
+
+// The kinds of objects ACLs are mapped back to
+const (
+	kindNetworkPolicy            = "NetworkPolicy"
+	kindNetworkPolicyDefaultDeny = "NetworkPolicyDefaultDeny"
+	kindEgressFirewall           = "EgressFirewall"
+	kindAdminNetworkPolicy       = "AdminNetworkPolicy"
+	kindMulticastDefaultDeny     = "MulticastDefaultDeny"
+	kindMulticastDefaultAllow    = "MulticastDefaultAllow"
+)
+
+var (
+	// ingress(dp="ovn-worker", inport="default_pod1", outport="stor-ovn-worker")
+	ovnTraceDatapathRe = regexp.MustCompile(`^(ingress|egress)\(dp="([^"]*)"`)
+	//  9. ls_in_acl (northd.c:6001): ip4.src == 10.244.0.5, priority 2001, uuid 5d5e5c13
+	ovnTraceHopRe = regexp.MustCompile(`^\s*(\d+)\. (\S+) \([^)]*\): (.*), priority (\d+), uuid ([0-9a-f]+)$`)
+	// bridge("br-int")
+	ofprotoBridgeRe = regexp.MustCompile(`^bridge\("([^"]*)"\)`)
+	//  0. in_port=7, priority 100, cookie 0x5d5e5c13
+	ofprotoHopRe = regexp.MustCompile(`^\s*(\d+)\. (.*), priority (\d+)(, cookie 0x([0-9a-f]+))?$`)
+)
+
+// parseOvnTraceHops returns the logical flows hit by an ovn-trace, in order
+func parseOvnTraceHops(output string) []TraceHop {
+	var hops []TraceHop
+	var datapath, pipeline string
+	var hop *TraceHop
+	for _, line := range strings.Split(output, "\n") {
+		if m := ovnTraceDatapathRe.FindStringSubmatch(line); m != nil {
+			pipeline, datapath = m[1], m[2]
+			hop = nil
+			continue
+		}
+		if m := ovnTraceHopRe.FindStringSubmatch(line); m != nil {
+			table, _ := strconv.Atoi(m[1])
+			priority, _ := strconv.Atoi(m[4])
+			hops = append(hops, TraceHop{
+				Datapath: datapath,
+				Pipeline: pipeline,
+				Table:    table,
+				Stage:    m[2],
+				Match:    m[3],
+				Priority: priority,
+				UUID:     m[5],
+			})
+			hop = &hops[len(hops)-1]
+			continue
+		}
+		// the actions of a flow are indented below it, up to the next blank line
+		action := strings.TrimSpace(line)
+		if action == "" || strings.HasPrefix(action, "---") {
+			hop = nil
+			continue
+		}
+		if hop != nil && strings.HasPrefix(line, "    ") {
+			hop.Actions = append(hop.Actions, action)
+		}
+	}
+	return hops
+}
+
+// parseOfprotoTraceHops returns the OpenFlow flows hit by an ofproto/trace, in order
+func parseOfprotoTraceHops(output string) []TraceHop {
+	var hops []TraceHop
+	var bridge string
+	var hop *TraceHop
+	for _, line := range strings.Split(output, "\n") {
+		if m := ofprotoBridgeRe.FindStringSubmatch(line); m != nil {
+			bridge = m[1]
+			hop = nil
+			continue
+		}
+		if bridge == "" {
+			continue
+		}
+		if m := ofprotoHopRe.FindStringSubmatch(line); m != nil {
+			table, _ := strconv.Atoi(m[1])
+			priority, _ := strconv.Atoi(m[3])
+			hops = append(hops, TraceHop{
+				Datapath: bridge,
+				Table:    table,
+				Match:    m[2],
+				Priority: priority,
+				UUID:     m[5],
+			})
+			hop = &hops[len(hops)-1]
+			continue
+		}
+		action := strings.TrimSpace(line)
+		if action == "" || strings.HasPrefix(action, "Final flow:") {
+			hop = nil
+			bridge = ""
+			continue
+		}
+		if hop != nil && !strings.HasPrefix(action, "-") {
+			hop.Actions = append(hop.Actions, action)
+		}
+	}
+	return hops
+}
+
+// findDropHop returns the last logical flow of the trace dropping or
+// rejecting the traffic, nil if the traffic was not dropped
+func findDropHop(hops []TraceHop) (*TraceHop, string) {
+	for i := len(hops) - 1; i >= 0; i-- {
+		for _, action := range hops[i].Actions {
+			if action == "drop;" || strings.HasPrefix(action, "reject") {
+				return &hops[i], strings.TrimSuffix(strings.Fields(action)[0], ";")
+			}
+		}
+	}
+	return nil, ""
+}
+
+// describeACL maps an ACL back to the Kubernetes object which produced it,
+// from the name and the external IDs set on the ACLs by ovnkube-master
+func describeACL(verdict *PolicyVerdict, externalIDs map[string]string) {
+	if name, ok := externalIDs["admin-network-policy"]; ok {
+		verdict.Kind = kindAdminNetworkPolicy
+		verdict.Name = name
+		verdict.Rule = externalIDs["rule"]
+		verdict.Description = fmt.Sprintf("AdminNetworkPolicy %s rule %s", name, verdict.Rule)
+		return
+	}
+	if namespace, ok := externalIDs["egressFirewall"]; ok {
+		verdict.Kind = kindEgressFirewall
+		verdict.Namespace = namespace
+		verdict.Name = "default"
+		startPriority, _ := strconv.Atoi(types.EgressFirewallStartPriority)
+		if verdict.ACLPriority > 0 {
+			verdict.Rule = strconv.Itoa(startPriority - verdict.ACLPriority)
+		}
+		verdict.Description = fmt.Sprintf("EgressFirewall %s/%s rule %s", namespace, verdict.Name, verdict.Rule)
+		return
+	}
+	if policy, ok := externalIDs["policy"]; ok {
+		verdict.Kind = kindNetworkPolicy
+		verdict.Namespace = externalIDs["namespace"]
+		verdict.Name = policy
+		policyType := externalIDs["policy_type"]
+		verdict.Rule = policyType + " " + externalIDs[policyType+"_num"]
+		verdict.Description = fmt.Sprintf("NetworkPolicy %s/%s %s rule %s", verdict.Namespace, policy,
+			policyType, externalIDs[policyType+"_num"])
+		return
+	}
+	if policyType, ok := externalIDs["default-deny-policy-type"]; ok {
+		switch {
+		case strings.HasSuffix(verdict.ACLName, "DefaultDenyMulticastEgress"),
+			strings.HasSuffix(verdict.ACLName, "DefaultDenyMulticastIngress"):
+			verdict.Kind = kindMulticastDefaultDeny
+			verdict.Description = fmt.Sprintf("multicast %s default deny, multicast is not enabled on the namespace", policyType)
+		case strings.HasSuffix(verdict.ACLName, "DefaultAllowMulticastEgress"),
+			strings.HasSuffix(verdict.ACLName, "DefaultAllowMulticastIngress"):
+			verdict.Kind = kindMulticastDefaultAllow
+			verdict.Description = fmt.Sprintf("multicast %s default allow", policyType)
+		default:
+			// default deny ACLs are named <namespace>_<first policy isolating the namespace>
+			verdict.Kind = kindNetworkPolicyDefaultDeny
+			parts := strings.SplitN(verdict.ACLName, "_", 2)
+			verdict.Namespace = parts[0]
+			if len(parts) == 2 {
+				verdict.Name = parts[1]
+			}
+			verdict.Rule = policyType
+			verdict.Description = fmt.Sprintf("NetworkPolicy %s default deny of namespace %s: no NetworkPolicy allows the traffic",
+				policyType, verdict.Namespace)
+		}
+		return
+	}
+	if kind, ok := externalIDs[types.OvnK8sPrefix+"/kind"]; ok {
+		owner := externalIDs[types.OvnK8sPrefix+"/owner"]
+		verdict.Kind = kind
+		parts := strings.SplitN(owner, "/", 2)
+		if len(parts) == 2 {
+			verdict.Namespace, verdict.Name = parts[0], parts[1]
+		} else {
+			verdict.Name = owner
+		}
+		verdict.Description = fmt.Sprintf("%s %s", kind, owner)
+		return
+	}
+	verdict.Description = fmt.Sprintf("ACL %s is not owned by a known Kubernetes object", verdict.ACLName)
+}
+
+// parseExternalIDs parses the "key=value key=value" bare output of an ovsdb map
+func parseExternalIDs(bare string) map[string]string {
+	externalIDs := make(map[string]string)
+	for _, field := range strings.Fields(bare) {
+		keyVal := strings.SplitN(field, "=", 2)
+		if len(keyVal) == 2 {
+			externalIDs[keyVal[0]] = strings.Trim(keyVal[1], "\"")
+		}
+	}
+	return externalIDs
+}
+
+// getPolicyVerdict returns the ACL which dropped the traffic of an ovn-trace,
+// and the Kubernetes object which produced it. It returns nil if the traffic
+// was not dropped.
+func (tc *traceContext) getPolicyVerdict(ovnkubePodName string, hops []TraceHop) *PolicyVerdict {
+	hop, action := findDropHop(hops)
+	if hop == nil {
+		return nil
+	}
+	verdict := &PolicyVerdict{
+		Action:      action,
+		Stage:       hop.Stage,
+		Datapath:    hop.Datapath,
+		Description: fmt.Sprintf("dropped by logical flow %s of stage %s", hop.UUID, hop.Stage),
+	}
+	if !strings.Contains(hop.Stage, "_acl") {
+		return verdict
+	}
+
+	// the stage hint of the logical flows of ACLs is the prefix of the UUID of the ACL
+	lflowCmd := "ovn-sbctl " + tc.sbcmd + " --bare --columns=external_ids list Logical_Flow " + hop.UUID
+	lflowOut, lflowErr, err := tc.exec(ovnkubePodName, lflowCmd)
+	if err != nil {
+		klog.V(1).Infof("Failed to get logical flow %s: %v stdErr: %s", hop.UUID, err, lflowErr)
+		return verdict
+	}
+	stageHint := parseExternalIDs(lflowOut)["stage-hint"]
+	if stageHint == "" {
+		return verdict
+	}
+
+	aclCmd := "ovn-nbctl " + tc.nbcmd + " --format=csv --data=bare --no-heading" +
+		" --columns=_uuid,name,priority,match,external_ids list ACL " + stageHint
+	aclOut, aclErr, err := tc.exec(ovnkubePodName, aclCmd)
+	if err != nil {
+		klog.V(1).Infof("Failed to get ACL %s: %v stdErr: %s", stageHint, err, aclErr)
+		return verdict
+	}
+	records, err := csv.NewReader(strings.NewReader(aclOut)).ReadAll()
+	if err != nil || len(records) == 0 || len(records[0]) < 5 {
+		klog.V(1).Infof("Failed to parse ACL %s: %q", stageHint, aclOut)
+		return verdict
+	}
+	verdict.ACLUUID = records[0][0]
+	verdict.ACLName = records[0][1]
+	verdict.ACLPriority, _ = strconv.Atoi(records[0][2])
+	verdict.ACLMatch = records[0][3]
+	describeACL(verdict, parseExternalIDs(records[0][4]))
+	return verdict
+}
+
+// checkTraceOutput reports whether the output of a trace from src to dst
+// contains any of the success strings, and exits on failure
+func (tc *traceContext) checkTraceOutput(ovnkubePodName, traceName, src, dst, output string, successStrings ...string) {
+	result := TraceResult{
+		Command:     traceName,
+		Source:      src,
+		Destination: dst,
+		Expected:    successStrings,
+	}
+	if traceName == "ovn-trace" {
+		result.Hops = parseOvnTraceHops(output)
+	} else {
+		result.Hops = parseOfprotoTraceHops(output)
+	}
+	for _, successString := range successStrings {
+		if strings.Contains(output, successString) {
+			result.Success = true
+			result.MatchedOn = successString
+			break
+		}
+	}
+	if !result.Success && traceName == "ovn-trace" {
+		result.Verdict = tc.getPolicyVerdict(ovnkubePodName, result.Hops)
+	}
+	tc.report.Traces = append(tc.report.Traces, result)
+
+	if result.Success {
+		fmt.Printf("%s indicates success from %s to %s - matched on %s\n", traceName, src, dst, result.MatchedOn)
+		klog.V(0).Infof("%s indicates success from %s to %s - matched on %s\n", traceName, src, dst, result.MatchedOn)
+		return
+	}
+	fmt.Printf("%s indicates failure from %s to %s - %s not matched\n", traceName, src, dst, strings.Join(successStrings, " or "))
+	klog.V(0).Infof("%s indicates failure from %s to %s - %s not matched\n", traceName, src, dst, strings.Join(successStrings, " or "))
+	if result.Verdict != nil {
+		fmt.Printf("%s: traffic denied (%s) by %s\n", traceName, result.Verdict.Action, result.Verdict.Description)
+	}
+	tc.complete(false)
+}
+
+// complete prints the report in json output mode, and exits on failure
+func (tc *traceContext) complete(success bool) {
+	cleanupOffline()
+	tc.report.Success = success
+	if tc.outputJSON {
+		out, err := json.MarshalIndent(tc.report, "", "  ")
+		if err != nil {
+			klog.Errorf("Failed to marshal the trace report: %v", err)
+			os.Exit(-1)
+		}
+		fmt.Fprintln(tc.stdout, string(out))
+	} else if success {
+		fmt.Println("ovn-trace command Completed normally")
+	}
+	if !success {
+		os.Exit(-1)
+	}
+}
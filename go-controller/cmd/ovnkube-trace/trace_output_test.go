@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the outputs below follow the ovn-trace --detailed format, for a trace from the
+// pod default/client to the pod default/server on the same node, trimmed of the
+// logical flows not needed by the tests
+
+const ovnTraceAllowOutput = `# tcp,reg14=0x4,vlan_tci=0x0000,dl_src=0a:58:0a:f4:01:05,dl_dst=0a:58:0a:f4:01:06,nw_src=10.244.1.5,nw_dst=10.244.1.6,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=52888,tp_dst=80,tcp_flags=0
+
+ingress(dp="ovn-worker", inport="default_client")
+-------------------------------------------------
+ 0. ls_in_port_sec_l2 (northd.c:5007): inport == "default_client" && eth.src == {0a:58:0a:f4:01:05}, priority 50, uuid 6a5f3c0b
+    next;
+ 1. ls_in_port_sec_ip (northd.c:4735): inport == "default_client" && eth.src == 0a:58:0a:f4:01:05 && ip4.src == {10.244.1.5}, priority 90, uuid 9f0e2b14
+    next;
+ 5. ls_in_pre_acl (northd.c:5324): ip, priority 100, uuid 0e4a8c2d
+    reg0[0] = 1;
+    next;
+ 6. ls_in_pre_lb (northd.c:5456): ip, priority 100, uuid 4c1b7d93
+    reg0[2] = 1;
+    next;
+22. ls_in_l2_lkup (northd.c:7340): eth.dst == 0a:58:0a:f4:01:06, priority 50, uuid 8e2d4a71
+    outport = "default_server";
+    output;
+
+egress(dp="ovn-worker", inport="default_client", outport="default_server")
+--------------------------------------------------------------------------
+ 0. ls_out_pre_lb (northd.c:5497): ip, priority 100, uuid 1c7d0e52
+    reg0[2] = 1;
+    next;
+ 9. ls_out_port_sec_l2 (northd.c:5254): outport == "default_server" && eth.dst == {0a:58:0a:f4:01:06}, priority 50, uuid 2d6e1f39
+    output;
+    /* output to "default_server", type "" */
+`
+
+const ovnTraceACLDropOutput = `# tcp,reg14=0x4,vlan_tci=0x0000,dl_src=0a:58:0a:f4:01:05,dl_dst=0a:58:0a:f4:01:06,nw_src=10.244.1.5,nw_dst=10.244.1.6,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=52888,tp_dst=80,tcp_flags=0
+
+ingress(dp="ovn-worker", inport="default_client")
+-------------------------------------------------
+ 0. ls_in_port_sec_l2 (northd.c:5007): inport == "default_client" && eth.src == {0a:58:0a:f4:01:05}, priority 50, uuid 6a5f3c0b
+    next;
+22. ls_in_l2_lkup (northd.c:7340): eth.dst == 0a:58:0a:f4:01:06, priority 50, uuid 8e2d4a71
+    outport = "default_server";
+    output;
+
+egress(dp="ovn-worker", inport="default_client", outport="default_server")
+--------------------------------------------------------------------------
+ 2. ls_out_pre_acl (northd.c:5333): ip, priority 100, uuid 7a9b2e40
+    reg0[0] = 1;
+    next;
+ 3. ls_out_pre_stateful (northd.c:5571): reg0[0] == 1, priority 100, uuid 51c3d0a8
+    ct_next;
+
+ct_next(ct_state=new|trk)
+-------------------------
+ 4. ls_out_acl (northd.c:5756): outport == @a16982411286042166782_ingressDefaultDeny && ip, priority 1000, uuid 3b8a9f61
+    drop;
+`
+
+const ovnTraceCtInvDropOutput = `# tcp,reg14=0x4,vlan_tci=0x0000,dl_src=0a:58:0a:f4:01:05,dl_dst=0a:58:0a:f4:01:06,nw_src=10.244.1.5,nw_dst=10.244.1.6,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=52888,tp_dst=80,tcp_flags=0
+
+egress(dp="ovn-worker", inport="default_client", outport="default_server")
+--------------------------------------------------------------------------
+ 3. ls_out_pre_stateful (northd.c:5571): reg0[0] == 1, priority 100, uuid 51c3d0a8
+    ct_next;
+
+ct_next(ct_state=inv|trk)
+-------------------------
+ 4. ls_out_acl (northd.c:5618): ct.inv || (ct.est && ct.rpl && ct_label.blocked == 1), priority 65535, uuid 0f3e9d27
+    drop;
+`
+
+func Test_parseOvnTraceHops(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		hops     int
+		first    TraceHop
+		last     TraceHop
+		dropType string
+	}{
+		{
+			name:   "allowed traffic",
+			output: ovnTraceAllowOutput,
+			hops:   7,
+			first: TraceHop{
+				Datapath: "ovn-worker",
+				Pipeline: "ingress",
+				Table:    0,
+				Stage:    "ls_in_port_sec_l2",
+				Match:    `inport == "default_client" && eth.src == {0a:58:0a:f4:01:05}`,
+				Priority: 50,
+				UUID:     "6a5f3c0b",
+				Actions:  []string{"next;"},
+			},
+			last: TraceHop{
+				Datapath: "ovn-worker",
+				Pipeline: "egress",
+				Table:    9,
+				Stage:    "ls_out_port_sec_l2",
+				Match:    `outport == "default_server" && eth.dst == {0a:58:0a:f4:01:06}`,
+				Priority: 50,
+				UUID:     "2d6e1f39",
+				Actions:  []string{"output;", `/* output to "default_server", type "" */`},
+			},
+		},
+		{
+			name:   "traffic dropped by a NetworkPolicy ACL",
+			output: ovnTraceACLDropOutput,
+			hops:   5,
+			first: TraceHop{
+				Datapath: "ovn-worker",
+				Pipeline: "ingress",
+				Table:    0,
+				Stage:    "ls_in_port_sec_l2",
+				Match:    `inport == "default_client" && eth.src == {0a:58:0a:f4:01:05}`,
+				Priority: 50,
+				UUID:     "6a5f3c0b",
+				Actions:  []string{"next;"},
+			},
+			last: TraceHop{
+				Datapath: "ovn-worker",
+				Pipeline: "egress",
+				Table:    4,
+				Stage:    "ls_out_acl",
+				Match:    "outport == @a16982411286042166782_ingressDefaultDeny && ip",
+				Priority: 1000,
+				UUID:     "3b8a9f61",
+				Actions:  []string{"drop;"},
+			},
+			dropType: "drop",
+		},
+		{
+			name:   "traffic dropped by an ACL stage flow without stage hint",
+			output: ovnTraceCtInvDropOutput,
+			hops:   2,
+			first: TraceHop{
+				Datapath: "ovn-worker",
+				Pipeline: "egress",
+				Table:    3,
+				Stage:    "ls_out_pre_stateful",
+				Match:    "reg0[0] == 1",
+				Priority: 100,
+				UUID:     "51c3d0a8",
+				Actions:  []string{"ct_next;"},
+			},
+			last: TraceHop{
+				Datapath: "ovn-worker",
+				Pipeline: "egress",
+				Table:    4,
+				Stage:    "ls_out_acl",
+				Match:    "ct.inv || (ct.est && ct.rpl && ct_label.blocked == 1)",
+				Priority: 65535,
+				UUID:     "0f3e9d27",
+				Actions:  []string{"drop;"},
+			},
+			dropType: "drop",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hops := parseOvnTraceHops(tt.output)
+			if !assert.Len(t, hops, tt.hops) {
+				return
+			}
+			assert.Equal(t, tt.first, hops[0])
+			assert.Equal(t, tt.last, hops[len(hops)-1])
+
+			dropHop, dropType := findDropHop(hops)
+			assert.Equal(t, tt.dropType, dropType)
+			if tt.dropType == "" {
+				assert.Nil(t, dropHop)
+			} else {
+				assert.Equal(t, &hops[len(hops)-1], dropHop)
+			}
+		})
+	}
+}
+
+func Test_getPolicyVerdict(t *testing.T) {
+	const (
+		lflowCmd = "ovn-sbctl  --bare --columns=external_ids list Logical_Flow "
+		aclCmd   = "ovn-nbctl  --format=csv --data=bare --no-heading --columns=_uuid,name,priority,match,external_ids list ACL "
+	)
+	tests := []struct {
+		name     string
+		output   string
+		cmds     map[string]string
+		verdict  *PolicyVerdict
+		expected []string
+	}{
+		{
+			name:    "allowed traffic",
+			output:  ovnTraceAllowOutput,
+			verdict: nil,
+		},
+		{
+			name:   "traffic dropped by a NetworkPolicy ACL",
+			output: ovnTraceACLDropOutput,
+			cmds: map[string]string{
+				lflowCmd + "3b8a9f61": "source=northd.c:5756 stage-hint=3b8a9f61 stage-name=ls_out_acl\n",
+				aclCmd + "3b8a9f61": "3b8a9f61-3d2c-4d6e-9a4e-6d1c4b2f9e10,default_deny-all,1000," +
+					"\"outport == @a16982411286042166782_ingressDefaultDeny && ip\",default-deny-policy-type=Ingress\n",
+			},
+			verdict: &PolicyVerdict{
+				Action:      "drop",
+				Stage:       "ls_out_acl",
+				Datapath:    "ovn-worker",
+				ACLUUID:     "3b8a9f61-3d2c-4d6e-9a4e-6d1c4b2f9e10",
+				ACLName:     "default_deny-all",
+				ACLPriority: 1000,
+				ACLMatch:    "outport == @a16982411286042166782_ingressDefaultDeny && ip",
+				Kind:        kindNetworkPolicyDefaultDeny,
+				Namespace:   "default",
+				Name:        "deny-all",
+				Rule:        "Ingress",
+				Description: "NetworkPolicy Ingress default deny of namespace default: no NetworkPolicy allows the traffic",
+			},
+			expected: []string{lflowCmd + "3b8a9f61", aclCmd + "3b8a9f61"},
+		},
+		{
+			name:   "traffic dropped by an ACL stage flow without stage hint",
+			output: ovnTraceCtInvDropOutput,
+			cmds: map[string]string{
+				lflowCmd + "0f3e9d27": "source=northd.c:5618 stage-name=ls_out_acl\n",
+			},
+			verdict: &PolicyVerdict{
+				Action:      "drop",
+				Stage:       "ls_out_acl",
+				Datapath:    "ovn-worker",
+				Description: "dropped by logical flow 0f3e9d27 of stage ls_out_acl",
+			},
+			expected: []string{lflowCmd + "0f3e9d27"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed []string
+			tc := &traceContext{
+				execCmd: func(ovnkubePodName, cmd string) (string, string, error) {
+					executed = append(executed, cmd)
+					if out, ok := tt.cmds[cmd]; ok {
+						return out, "", nil
+					}
+					return "", "", fmt.Errorf("unexpected command %q", cmd)
+				},
+			}
+			verdict := tc.getPolicyVerdict("ovnkube-node-abcde", parseOvnTraceHops(tt.output))
+			assert.Equal(t, tt.verdict, verdict)
+			assert.Equal(t, tt.expected, executed)
+		})
+	}
+}
+
+func Test_describeACL(t *testing.T) {
+	tests := []struct {
+		name        string
+		aclName     string
+		aclPriority int
+		externalIDs string
+		want        PolicyVerdict
+	}{
+		{
+			name:        "NetworkPolicy rule",
+			aclName:     "allow-web",
+			aclPriority: 1001,
+			externalIDs: "Ingress_num=0 ipblock_cidr=false l4Match=None namespace=default policy=allow-web policy_type=Ingress",
+			want: PolicyVerdict{
+				Kind:        kindNetworkPolicy,
+				Namespace:   "default",
+				Name:        "allow-web",
+				Rule:        "Ingress 0",
+				Description: "NetworkPolicy default/allow-web Ingress rule 0",
+			},
+		},
+		{
+			name:        "NetworkPolicy default deny",
+			aclName:     "default_deny-all",
+			aclPriority: 1000,
+			externalIDs: "default-deny-policy-type=Egress",
+			want: PolicyVerdict{
+				Kind:        kindNetworkPolicyDefaultDeny,
+				Namespace:   "default",
+				Name:        "deny-all",
+				Rule:        "Egress",
+				Description: "NetworkPolicy Egress default deny of namespace default: no NetworkPolicy allows the traffic",
+			},
+		},
+		{
+			name:        "multicast default deny",
+			aclName:     "clusterPortGroup_DefaultDenyMulticastEgress",
+			aclPriority: 1011,
+			externalIDs: "default-deny-policy-type=Egress",
+			want: PolicyVerdict{
+				Kind:        kindMulticastDefaultDeny,
+				Description: "multicast Egress default deny, multicast is not enabled on the namespace",
+			},
+		},
+		{
+			name:        "EgressFirewall rule",
+			aclPriority: 9998,
+			externalIDs: "egressFirewall=default",
+			want: PolicyVerdict{
+				Kind:        kindEgressFirewall,
+				Namespace:   "default",
+				Name:        "default",
+				Rule:        "2",
+				Description: "EgressFirewall default/default rule 2",
+			},
+		},
+		{
+			name:        "AdminNetworkPolicy rule",
+			aclName:     "anp_cluster-control",
+			aclPriority: 1500,
+			externalIDs: "admin-network-policy=cluster-control rule=ingress-1",
+			want: PolicyVerdict{
+				Kind:        kindAdminNetworkPolicy,
+				Name:        "cluster-control",
+				Rule:        "ingress-1",
+				Description: "AdminNetworkPolicy cluster-control rule ingress-1",
+			},
+		},
+		{
+			name:        "ACL of an unknown owner",
+			aclName:     "custom",
+			aclPriority: 100,
+			externalIDs: "owner=admin",
+			want: PolicyVerdict{
+				Description: "ACL custom is not owned by a known Kubernetes object",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := &PolicyVerdict{ACLName: tt.aclName, ACLPriority: tt.aclPriority}
+			describeACL(verdict, parseExternalIDs(tt.externalIDs))
+			tt.want.ACLName = tt.aclName
+			tt.want.ACLPriority = tt.aclPriority
+			assert.Equal(t, tt.want, *verdict)
+		})
+	}
+}
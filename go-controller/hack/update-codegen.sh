@@ -38,34 +38,37 @@ if  ! ( command -v controller-gen > /dev/null ); then
 fi
 
 for crd in ${crds}; do
-  echo "Generating deepcopy funcs for $crd"
-  deepcopy-gen \
-    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    -O zz_generated.deepcopy \
-    --bounding-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd
+  # every API version of a CRD has its own clientset, listers and informers
+  for version in $(ls pkg/crd/$crd); do
+    echo "Generating deepcopy funcs for $crd $version"
+    deepcopy-gen \
+      --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+      -O zz_generated.deepcopy \
+      --bounding-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd
 
 
-  echo "Generating clientset for $crd"
-  client-gen \
-    --clientset-name "${CLIENTSET_NAME_VERSIONED:-versioned}" \
-    --input-base "" \
-    --input github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/clientset \
-    "$@"
+    echo "Generating clientset for $crd $version"
+    client-gen \
+      --clientset-name "${CLIENTSET_NAME_VERSIONED:-versioned}" \
+      --input-base "" \
+      --input github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+      --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/clientset \
+      "$@"
 
-  echo "Generating listers for $crd"
-  lister-gen \
-    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/listers \
-    "$@"
+    echo "Generating listers for $crd $version"
+    lister-gen \
+      --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+      --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/listers \
+      "$@"
 
-  echo "Generating informers for $crd"
-  informer-gen \
-    --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1 \
-    --versioned-clientset-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/clientset/versioned \
-    --listers-package  github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/listers \
-    --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/v1/apis/informers \
-    "$@"
+    echo "Generating informers for $crd $version"
+    informer-gen \
+      --input-dirs github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version \
+      --versioned-clientset-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/clientset/versioned \
+      --listers-package  github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/listers \
+      --output-package github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/$crd/$version/apis/informers \
+      "$@"
+  done
 done
 
 echo "Generating CRDs"
@@ -78,7 +81,17 @@ echo "Editing egressFirewall CRD"
 sed -i -e':begin;$!N;s/.*metadata:\n.*type: object/&\n            properties:\n              name:\n                type: string\n                pattern: ^default$/;P;D' \
 	_output/crds/k8s.ovn.org_egressfirewalls.yaml
 ## It is also required that we restrict the number of properties on the 'to' section of the egressfirewall
-## so that either 'dnsName' or 'cidrSelector is set in the crd and currently kubebuilder does not support
-## adding validation to objects only to the fields
-sed -i -e ':begin;$!N;s/                          type: string\n.*type: object/&\n                      minProperties: 1\n                      maxProperties: 1/;P;D' \
+## so that only one of 'dnsName', 'cidrSelector' or 'nodeSelector' is set in the crd and currently kubebuilder
+## does not support adding validation to objects only to the fields
+sed -i -e ':begin;$!N;s/                          type: object\n                      type: object/&\n                      minProperties: 1\n                      maxProperties: 1/;P;D' \
 	_output/crds/k8s.ovn.org_egressfirewalls.yaml
+echo "Editing ClusterEgressFirewall CRD"
+## The ClusterEgressFirewall shares the rules of the egressFirewall and is a singleton named "default"
+sed -i -e':begin;$!N;s/.*metadata:\n.*type: object/&\n            properties:\n              name:\n                type: string\n                pattern: ^default$/;P;D' \
+	_output/crds/k8s.ovn.org_clusteregressfirewalls.yaml
+sed -i -e ':begin;$!N;s/                          type: object\n                      type: object/&\n                      minProperties: 1\n                      maxProperties: 1/;P;D' \
+	_output/crds/k8s.ovn.org_clusteregressfirewalls.yaml
+echo "Editing EgressQoS CRD"
+## We desire that only EgressQoS with the name "default" are accepted by the apiserver.
+sed -i -e':begin;$!N;s/.*metadata:\n.*type: object/&\n            properties:\n              name:\n                type: string\n                pattern: ^default$/;P;D' \
+	_output/crds/k8s.ovn.org_egressqoses.yaml
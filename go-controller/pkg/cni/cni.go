@@ -101,7 +101,14 @@ func (pr *PodRequest) cmdAdd(kubeAuth *KubeAPIAuth, podLister corev1listers.PodL
 	}
 
 	kubecli := &kube.Kube{KClient: kclient}
+	nadName := pr.nadName()
 	annotCondFn := isOvnReady
+	if !isDefaultNetwork(nadName) {
+		if pr.IsSmartNIC {
+			return nil, fmt.Errorf("secondary network %s is not supported on smart-nic hosts", nadName)
+		}
+		annotCondFn = isOvnReadyForNetwork(nadName)
+	}
 
 	if pr.IsSmartNIC {
 		// Add Smart-NIC connection-details annotation so ovnkube-node running on smart-NIC
@@ -121,7 +128,7 @@ func (pr *PodRequest) cmdAdd(kubeAuth *KubeAPIAuth, podLister corev1listers.PodL
 		return nil, err
 	}
 
-	podInterfaceInfo, err := PodAnnotation2PodInfo(annotations, useOVSExternalIDs, pr.IsSmartNIC)
+	podInterfaceInfo, err := PodAnnotation2PodInfo(annotations, nadName, pr.CNIConf.MTU, useOVSExternalIDs, pr.IsSmartNIC)
 	if err != nil {
 		return nil, err
 	}
@@ -164,8 +171,11 @@ func (pr *PodRequest) cmdCheck(podLister corev1listers.PodLister, useOVSExternal
 	}
 
 	// Get the IP address and MAC address of the pod
+	nadName := pr.nadName()
 	annotCondFn := isOvnReady
-	if pr.IsSmartNIC {
+	if !isDefaultNetwork(nadName) {
+		annotCondFn = isOvnReadyForNetwork(nadName)
+	} else if pr.IsSmartNIC {
 		annotCondFn = isSmartNICReady
 	}
 	podUID, annotations, err := GetPodAnnotations(pr.ctx, podLister, kclient, pr.PodNamespace, pr.PodName, annotCondFn)
@@ -191,7 +201,7 @@ func (pr *PodRequest) cmdCheck(podLister corev1listers.PodLister, useOVSExternal
 		if len(hostIfaceName) == 0 {
 			return nil, fmt.Errorf("could not find host interface in the prevResult: %v", result)
 		}
-		ifaceID := fmt.Sprintf("%s_%s", namespace, podName)
+		ifaceID := getIfaceID(namespace, podName, nadName)
 		ofPort, err := getIfaceOFPort(hostIfaceName)
 		if err != nil {
 			return nil, err
@@ -204,6 +214,10 @@ func (pr *PodRequest) cmdCheck(podLister corev1listers.PodLister, useOVSExternal
 			}
 		}
 
+		if !isDefaultNetwork(nadName) {
+			// bandwidth is only enforced on the default network interface
+			return []byte{}, nil
+		}
 		for _, direction := range []direction{Ingress, Egress} {
 			annotationBandwith, annotationErr := extractPodBandwidth(annotations, direction)
 			ovnBandwith, ovnErr := getOvsPortBandwidth(hostIfaceName, direction)
@@ -147,7 +147,7 @@ func setupInterface(netns ns.NetNS, containerID, ifName string, ifInfo *PodInter
 	}
 
 	// rename the host end of veth pair
-	hostIface.Name = containerID[:15]
+	hostIface.Name = getHostIfaceName(containerID, ifName, ifInfo.NADName)
 	if err := renameLink(oldHostVethName, hostIface.Name); err != nil {
 		return nil, nil, fmt.Errorf("failed to rename %s to %s: %v", oldHostVethName, hostIface.Name, err)
 	}
@@ -257,7 +257,7 @@ func ConfigureOVS(ctx context.Context, namespace, podName, hostIfaceName string,
 	ifInfo *PodInterfaceInfo, sandboxID string, podLister corev1listers.PodLister,
 	kclient kubernetes.Interface, initialPodUID string) error {
 	klog.Infof("ConfigureOVS: namespace: %s, podName: %s", namespace, podName)
-	ifaceID := util.GetIfaceId(namespace, podName)
+	ifaceID := getIfaceID(namespace, podName, ifInfo.NADName)
 
 	// Find and remove any existing OVS port with this iface-id. Pods can
 	// have multiple sandboxes if some are waiting for garbage collection,
@@ -287,8 +287,11 @@ func ConfigureOVS(ctx context.Context, namespace, podName, hostIfaceName string,
 		return fmt.Errorf("failure in plugging pod interface: %v\n  %q", err, out)
 	}
 
-	if err := clearPodBandwidth(sandboxID); err != nil {
-		return err
+	// the pod bandwidth of the sandbox is only applied to the interface of the default network
+	if isDefaultNetwork(ifInfo.NADName) {
+		if err := clearPodBandwidth(sandboxID); err != nil {
+			return err
+		}
 	}
 
 	if ifInfo.Ingress > 0 || ifInfo.Egress > 0 {
@@ -412,7 +415,8 @@ func (pr *PodRequest) deletePodConntrack() {
 
 // PlatformSpecificCleanup deletes the OVS port
 func (pr *PodRequest) PlatformSpecificCleanup() error {
-	ifaceName := pr.SandboxID[:15]
+	nadName := pr.nadName()
+	ifaceName := getHostIfaceName(pr.SandboxID, pr.IfName, nadName)
 	ovsArgs := []string{
 		"del-port", "br-int", ifaceName,
 	}
@@ -422,7 +426,9 @@ func (pr *PodRequest) PlatformSpecificCleanup() error {
 		klog.Warningf("Failed to delete OVS port %s: %v\n  %q", ifaceName, err, string(out))
 	}
 
-	_ = clearPodBandwidth(pr.SandboxID)
+	if isDefaultNetwork(nadName) {
+		_ = clearPodBandwidth(pr.SandboxID)
+	}
 	pr.deletePodConntrack()
 
 	return nil
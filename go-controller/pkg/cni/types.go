@@ -40,6 +40,9 @@ type PodInterfaceInfo struct {
 	Egress      int64 `json:"egress"`
 	CheckExtIDs bool  `json:"check-external-ids"`
 	IsSmartNic  bool  `json:"smartnic"`
+	// NADName is the net-attach-def of the network of the interface, either empty or
+	// util.OvnPodDefaultNetwork for the default network
+	NADName string `json:"nad-name,omitempty"`
 }
 
 // Explicit type for CNI commands the server handles
@@ -108,6 +111,15 @@ type PodRequest struct {
 	IsSmartNIC bool
 }
 
+// nadName returns the network the request is for: the net-attach-def of a
+// secondary network, or util.OvnPodDefaultNetwork for the default network
+func (pr *PodRequest) nadName() string {
+	if pr.CNIConf != nil && pr.CNIConf.Topology != "" {
+		return pr.CNIConf.NADName
+	}
+	return util.OvnPodDefaultNetwork
+}
+
 type cniRequestFunc func(request *PodRequest, podLister corev1listers.PodLister, useOVSExternalIDs bool, kclient kubernetes.Interface, kubeAuth *KubeAPIAuth) ([]byte, error)
 
 // Server object that listens for JSON-marshaled Request objects
@@ -23,6 +23,19 @@ type NetConf struct {
 	// LogFileMaxAge represents the maximum number
 	// of days to retain old log files
 	LogFileMaxAge int `json:"logfile-maxage"`
+
+	// Topology of a secondary network, "layer2" or "layer3", unset for the
+	// default network
+	Topology string `json:"topology,omitempty"`
+	// NADName is the namespace/name of the NetworkAttachmentDefinition of a
+	// secondary network
+	NADName string `json:"netAttachDefName,omitempty"`
+	// Subnets is the comma-separated list of the subnets of a secondary
+	// network, in the format of the cluster subnets of the default network
+	Subnets string `json:"subnets,omitempty"`
+	// MTU of the secondary network interface, the MTU of the default network
+	// is used when unset
+	MTU int `json:"mtu,omitempty"`
 }
 
 // NetworkSelectionElement represents one element of the JSON format
@@ -37,6 +50,9 @@ type NetworkSelectionElement struct {
 	// MacRequest contains an optional requested MAC address for this
 	// network attachment
 	MacRequest string `json:"mac,omitempty"`
+	// IPRequest contains an optional requested list of IP addresses for
+	// this network attachment, one in each of the subnets of the node
+	IPRequest []string `json:"ips,omitempty"`
 	// GatewayRequest contains default route IP address for the pod
 	GatewayRequest []net.IP `json:"default-route,omitempty"`
 }
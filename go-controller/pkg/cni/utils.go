@@ -27,6 +27,15 @@ func isOvnReady(podAnnotation map[string]string) bool {
 	return false
 }
 
+// isOvnReadyForNetwork returns a wait condition for OVN master to set the
+// entry of a secondary network in the pod-networks annotation
+func isOvnReadyForNetwork(nadName string) podAnnotWaitCond {
+	return func(podAnnotation map[string]string) bool {
+		_, err := util.UnmarshalPodAnnotationForNetwork(podAnnotation, nadName)
+		return err == nil
+	}
+}
+
 // isSmartNICReady is a wait condition smart-NIC: wait for OVN master to set pod-networks annotation and
 // ovnkube running on Smart-NIC to set connection-status pod annotation and its status is Ready
 func isSmartNICReady(podAnnotation map[string]string) bool {
@@ -101,29 +110,65 @@ func GetPodAnnotations(ctx context.Context, podLister corev1listers.PodLister, k
 	}
 }
 
-// PodAnnotation2PodInfo creates PodInterfaceInfo from Pod annotations and additional attributes
-func PodAnnotation2PodInfo(podAnnotation map[string]string, checkExtIDs bool, isSmartNic bool) (
-	*PodInterfaceInfo, error) {
-	podAnnotSt, err := util.UnmarshalPodAnnotation(podAnnotation)
+// PodAnnotation2PodInfo creates PodInterfaceInfo from Pod annotations and additional attributes.
+// nadName selects the network of the interface, util.OvnPodDefaultNetwork for the default
+// network. The pod bandwidth annotations only apply to the interface of the default network.
+func PodAnnotation2PodInfo(podAnnotation map[string]string, nadName string, mtu int, checkExtIDs bool,
+	isSmartNic bool) (*PodInterfaceInfo, error) {
+	podAnnotSt, err := util.UnmarshalPodAnnotationForNetwork(podAnnotation, nadName)
 	if err != nil {
 		return nil, err
 	}
-	ingress, err := extractPodBandwidth(podAnnotation, Ingress)
-	if err != nil && !errors.Is(err, BandwidthNotFound) {
-		return nil, err
+
+	var ingress, egress int64
+	if isDefaultNetwork(nadName) {
+		ingress, err = extractPodBandwidth(podAnnotation, Ingress)
+		if err != nil && !errors.Is(err, BandwidthNotFound) {
+			return nil, err
+		}
+		egress, err = extractPodBandwidth(podAnnotation, Egress)
+		if err != nil && !errors.Is(err, BandwidthNotFound) {
+			return nil, err
+		}
 	}
-	egress, err := extractPodBandwidth(podAnnotation, Egress)
-	if err != nil && !errors.Is(err, BandwidthNotFound) {
-		return nil, err
+	if mtu == 0 {
+		mtu = config.Default.MTU
 	}
 
 	podInterfaceInfo := &PodInterfaceInfo{
 		PodAnnotation: *podAnnotSt,
-		MTU:           config.Default.MTU,
+		MTU:           mtu,
 		Ingress:       ingress,
 		Egress:        egress,
 		CheckExtIDs:   checkExtIDs,
 		IsSmartNic:    isSmartNic,
+		NADName:       nadName,
 	}
 	return podInterfaceInfo, nil
 }
+
+// isDefaultNetwork returns whether nadName refers to the default network
+func isDefaultNetwork(nadName string) bool {
+	return nadName == "" || nadName == util.OvnPodDefaultNetwork
+}
+
+// getIfaceID returns the iface-id of the OVS interface of a pod on the given network
+func getIfaceID(podNamespace, podName, nadName string) string {
+	if isDefaultNetwork(nadName) {
+		return util.GetIfaceId(podNamespace, podName)
+	}
+	return util.GetSecondaryNetworkLogicalPortName(podNamespace, podName, nadName)
+}
+
+// getHostIfaceName returns the name of the host side interface of a pod on
+// the given network. Interfaces of secondary networks are suffixed with the
+// name of the interface in the pod, so that they are unique per sandbox.
+func getHostIfaceName(sandboxID, ifName, nadName string) string {
+	if isDefaultNetwork(nadName) {
+		return sandboxID[:15]
+	}
+	if len(ifName) > 10 {
+		ifName = ifName[:10]
+	}
+	return sandboxID[:15-len(ifName)-1] + "_" + ifName
+}
@@ -216,27 +216,66 @@ var _ = Describe("CNI Utils tests", func() {
 "gateway_ip":"192.168.2.1"}}`,
 		}
 		It("Creates PodInterfaceInfo with IsSmartNIC false", func() {
-			pif, err := PodAnnotation2PodInfo(podAnnot, false, false)
+			pif, err := PodAnnotation2PodInfo(podAnnot, util.OvnPodDefaultNetwork, 0, false, false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(pif.IsSmartNic).To(BeFalse())
 		})
 
 		It("Creates PodInterfaceInfo with IsSmartNIC true", func() {
-			pif, err := PodAnnotation2PodInfo(podAnnot, false, true)
+			pif, err := PodAnnotation2PodInfo(podAnnot, util.OvnPodDefaultNetwork, 0, false, true)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(pif.IsSmartNic).To(BeTrue())
 		})
 
 		It("Creates PodInterfaceInfo with checkExtIDs false", func() {
-			pif, err := PodAnnotation2PodInfo(podAnnot, false, false)
+			pif, err := PodAnnotation2PodInfo(podAnnot, util.OvnPodDefaultNetwork, 0, false, false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(pif.CheckExtIDs).To(BeFalse())
 		})
 
 		It("Creates PodInterfaceInfo with checkExtIDs true", func() {
-			pif, err := PodAnnotation2PodInfo(podAnnot, true, false)
+			pif, err := PodAnnotation2PodInfo(podAnnot, util.OvnPodDefaultNetwork, 0, true, false)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(pif.CheckExtIDs).To(BeTrue())
 		})
+
+		It("Creates PodInterfaceInfo of a secondary network", func() {
+			annot := map[string]string{
+				util.OvnPodAnnotationName: `{
+"default":{"ip_addresses":["192.168.2.3/24"],
+"mac_address":"0a:58:c0:a8:02:03",
+"gateway_ips":["192.168.2.1"]},
+"ns1/l2-net":{"ip_addresses":["10.100.0.5/24"],
+"mac_address":"0a:58:0a:64:00:05"}}`,
+				"kubernetes.io/ingress-bandwidth": "1M",
+			}
+			pif, err := PodAnnotation2PodInfo(annot, "ns1/l2-net", 1400, false, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pif.NADName).To(Equal("ns1/l2-net"))
+			Expect(pif.MTU).To(Equal(1400))
+			Expect(pif.Ingress).To(BeZero())
+			Expect(pif.MAC.String()).To(Equal("0a:58:0a:64:00:05"))
+			Expect(pif.IPs).To(HaveLen(1))
+			Expect(pif.IPs[0].String()).To(Equal("10.100.0.5/24"))
+		})
+
+		It("Fails to create PodInterfaceInfo of a secondary network missing from the annotation", func() {
+			_, err := PodAnnotation2PodInfo(podAnnot, "ns1/l2-net", 0, false, false)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("getHostIfaceName", func() {
+		const sandboxID = "6e7c2a1f9b3d4c5e8f0a1b2c3d4e5f60"
+
+		It("Names the default network interface after the sandbox", func() {
+			Expect(getHostIfaceName(sandboxID, "eth0", util.OvnPodDefaultNetwork)).To(Equal("6e7c2a1f9b3d4c5"))
+		})
+
+		It("Suffixes secondary network interfaces with the pod interface name", func() {
+			name := getHostIfaceName(sandboxID, "net1", "ns1/l2-net")
+			Expect(name).To(Equal("6e7c2a1f9b_net1"))
+			Expect(len(name)).To(BeNumerically("<=", 15))
+		})
 	})
 })
@@ -101,7 +101,15 @@ var (
 	}
 
 	// OVNKubernetesFeatureConfig holds OVN-Kubernetes feature enhancement config file parameters and command-line overrides
-	OVNKubernetesFeature OVNKubernetesFeatureConfig
+	OVNKubernetesFeature = OVNKubernetesFeatureConfig{
+		EgressIPReachabilityCheckInterval: 5, // in Seconds
+		EgressIPReachabilityCheckTimeout:  1, // in Seconds
+		EgressFirewallDNSService:          "kube-system/kube-dns",
+		EgressFirewallDNSWorkers:          5,
+		EgressFirewallDNSMinTTL:           5,    // in Seconds
+		EgressFirewallDNSMaxTTL:           1800, // in Seconds
+		EgressFirewallDNSNegativeTTL:      30,   // in Seconds
+	}
 
 	// OvnNorth holds northbound OVN database client and server authentication and location details
 	OvnNorth OvnAuthConfig
@@ -266,6 +274,39 @@ type KubernetesConfig struct {
 type OVNKubernetesFeatureConfig struct {
 	EnableEgressIP       bool `gcfg:"enable-egress-ip"`
 	EnableEgressFirewall bool `gcfg:"enable-egress-firewall"`
+	EnableEgressQoS      bool `gcfg:"enable-egress-qos"`
+	// EnableAdminNetworkPolicy enables the cluster-scoped AdminNetworkPolicy CRD
+	EnableAdminNetworkPolicy bool `gcfg:"enable-admin-network-policy"`
+	// EnableMultiNetwork enables the secondary OVN networks attached to pods
+	// through NetworkAttachmentDefinitions
+	EnableMultiNetwork bool `gcfg:"enable-multi-network"`
+	// EgressIPNodeHealthCheckPort is the port on which ovnkube-node answers the egress IP
+	// reachability probes of ovnkube-master. 0 disables the health check server, in which
+	// case the master falls back to dialing the discard port of the node.
+	EgressIPNodeHealthCheckPort int `gcfg:"egressip-node-healthcheck-port"`
+	// EgressIPReachabilityCheckInterval is the interval, in seconds, at which the master
+	// checks the reachability of egress nodes
+	EgressIPReachabilityCheckInterval int `gcfg:"egressip-reachability-check-interval"`
+	// EgressIPReachabilityCheckTimeout is the timeout, in seconds, of a single egress node
+	// reachability probe
+	EgressIPReachabilityCheckTimeout int `gcfg:"egressip-reachability-check-timeout"`
+	// EgressFirewallDNSService is the namespace/name of the cluster DNS service whose
+	// responses to the pods are snooped by ovnkube-node to learn the IPs of the wildcard
+	// dnsNames of EgressFirewall rules
+	EgressFirewallDNSService string `gcfg:"egress-firewall-dns-service"`
+	// EgressFirewallDNSWorkers is the number of dnsNames of EgressFirewall rules the
+	// master resolves concurrently
+	EgressFirewallDNSWorkers int `gcfg:"egress-firewall-dns-workers"`
+	// EgressFirewallDNSMinTTL and EgressFirewallDNSMaxTTL bound, in seconds, the TTL
+	// after which the dnsNames of EgressFirewall rules are resolved again
+	EgressFirewallDNSMinTTL int `gcfg:"egress-firewall-dns-min-ttl"`
+	EgressFirewallDNSMaxTTL int `gcfg:"egress-firewall-dns-max-ttl"`
+	// EgressFirewallDNSNegativeTTL is the time, in seconds, after which a dnsName that
+	// failed to resolve is retried, when the response does not carry a negative TTL
+	EgressFirewallDNSNegativeTTL int `gcfg:"egress-firewall-dns-negative-ttl"`
+	// EnableLBHealthCheck allows services to opt in to OVN load balancer health checks.
+	// It reserves the last address of each node's IPv4 subnet as the source of the probes.
+	EnableLBHealthCheck bool `gcfg:"enable-lb-health-check"`
 }
 
 // GatewayMode holds the node gateway mode
@@ -751,6 +792,82 @@ var OVNK8sFeatureFlags = []cli.Flag{
 		Destination: &cliConfig.OVNKubernetesFeature.EnableEgressFirewall,
 		Value:       OVNKubernetesFeature.EnableEgressFirewall,
 	},
+	&cli.BoolFlag{
+		Name:        "enable-egress-qos",
+		Usage:       "Configure to use EgressQoS CRD feature with ovn-kubernetes.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableEgressQoS,
+		Value:       OVNKubernetesFeature.EnableEgressQoS,
+	},
+	&cli.BoolFlag{
+		Name:        "enable-admin-network-policy",
+		Usage:       "Configure to use AdminNetworkPolicy CRD feature with ovn-kubernetes.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableAdminNetworkPolicy,
+		Value:       OVNKubernetesFeature.EnableAdminNetworkPolicy,
+	},
+	&cli.BoolFlag{
+		Name:        "enable-multi-network",
+		Usage:       "Configure to use secondary OVN networks attached to pods through NetworkAttachmentDefinitions with ovn-kubernetes.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableMultiNetwork,
+		Value:       OVNKubernetesFeature.EnableMultiNetwork,
+	},
+	&cli.IntFlag{
+		Name: "egressip-node-healthcheck-port",
+		Usage: "Configure the port on which ovnkube-node answers EgressIP reachability probes. " +
+			"0 disables the health check server and the master falls back to dialing the discard port (default: 0)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
+		Value:       OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-reachability-check-interval",
+		Usage:       "Interval in seconds at which the master checks the reachability of egress nodes (default: 5)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPReachabilityCheckInterval,
+		Value:       OVNKubernetesFeature.EgressIPReachabilityCheckInterval,
+	},
+	&cli.IntFlag{
+		Name:        "egressip-reachability-check-timeout",
+		Usage:       "Timeout in seconds of a single egress node reachability probe (default: 1)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressIPReachabilityCheckTimeout,
+		Value:       OVNKubernetesFeature.EgressIPReachabilityCheckTimeout,
+	},
+	&cli.StringFlag{
+		Name: "egress-firewall-dns-service",
+		Usage: "The namespace/name of the cluster DNS service whose responses to the pods are snooped " +
+			"to learn the IPs of the wildcard dnsNames of EgressFirewall rules (default: kube-system/kube-dns)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSService,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSService,
+	},
+	&cli.IntFlag{
+		Name:        "egress-firewall-dns-workers",
+		Usage:       "The number of dnsNames of EgressFirewall rules resolved concurrently (default: 5)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSWorkers,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSWorkers,
+	},
+	&cli.IntFlag{
+		Name:        "egress-firewall-dns-min-ttl",
+		Usage:       "The minimum time in seconds after which the dnsNames of EgressFirewall rules are resolved again (default: 5)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSMinTTL,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSMinTTL,
+	},
+	&cli.IntFlag{
+		Name:        "egress-firewall-dns-max-ttl",
+		Usage:       "The maximum time in seconds after which the dnsNames of EgressFirewall rules are resolved again (default: 1800)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSMaxTTL,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSMaxTTL,
+	},
+	&cli.IntFlag{
+		Name: "egress-firewall-dns-negative-ttl",
+		Usage: "The time in seconds after which a dnsName of an EgressFirewall rule that failed to resolve " +
+			"is retried, unless the DNS response carries a negative TTL (default: 30)",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSNegativeTTL,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSNegativeTTL,
+	},
+	&cli.BoolFlag{
+		Name: "enable-lb-health-check",
+		Usage: "Configure OVN load balancer health checks for the backends of services annotated with " +
+			"k8s.ovn.org/lb-health-check. Reserves the last address of each node subnet as the probe source.",
+		Destination: &cliConfig.OVNKubernetesFeature.EnableLBHealthCheck,
+		Value:       OVNKubernetesFeature.EnableLBHealthCheck,
+	},
 }
 
 // K8sFlags capture Kubernetes-related options
@@ -1354,6 +1471,35 @@ func buildOVNKubernetesFeatureConfig(ctx *cli.Context, cli, file *config) error
 	if err := overrideFields(&OVNKubernetesFeature, &cli.OVNKubernetesFeature, &savedOVNKubernetesFeature); err != nil {
 		return err
 	}
+	if OVNKubernetesFeature.EgressIPNodeHealthCheckPort < 0 || OVNKubernetesFeature.EgressIPNodeHealthCheckPort > 65535 {
+		return fmt.Errorf("invalid egressip-node-healthcheck-port %d", OVNKubernetesFeature.EgressIPNodeHealthCheckPort)
+	}
+	if OVNKubernetesFeature.EgressIPReachabilityCheckInterval <= 0 {
+		return fmt.Errorf("egressip-reachability-check-interval must be positive, got %d",
+			OVNKubernetesFeature.EgressIPReachabilityCheckInterval)
+	}
+	if OVNKubernetesFeature.EgressIPReachabilityCheckTimeout <= 0 {
+		return fmt.Errorf("egressip-reachability-check-timeout must be positive, got %d",
+			OVNKubernetesFeature.EgressIPReachabilityCheckTimeout)
+	}
+	if parts := strings.Split(OVNKubernetesFeature.EgressFirewallDNSService, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("egress-firewall-dns-service must be namespace/name, got %q",
+			OVNKubernetesFeature.EgressFirewallDNSService)
+	}
+	if OVNKubernetesFeature.EgressFirewallDNSWorkers <= 0 {
+		return fmt.Errorf("egress-firewall-dns-workers must be positive, got %d",
+			OVNKubernetesFeature.EgressFirewallDNSWorkers)
+	}
+	if OVNKubernetesFeature.EgressFirewallDNSMinTTL <= 0 ||
+		OVNKubernetesFeature.EgressFirewallDNSMaxTTL < OVNKubernetesFeature.EgressFirewallDNSMinTTL {
+		return fmt.Errorf("egress-firewall-dns-min-ttl %d must be positive and not greater than egress-firewall-dns-max-ttl %d",
+			OVNKubernetesFeature.EgressFirewallDNSMinTTL, OVNKubernetesFeature.EgressFirewallDNSMaxTTL)
+	}
+	if OVNKubernetesFeature.EgressFirewallDNSNegativeTTL <= 0 ||
+		OVNKubernetesFeature.EgressFirewallDNSNegativeTTL > OVNKubernetesFeature.EgressFirewallDNSMaxTTL {
+		return fmt.Errorf("egress-firewall-dns-negative-ttl %d must be positive and not greater than egress-firewall-dns-max-ttl %d",
+			OVNKubernetesFeature.EgressFirewallDNSNegativeTTL, OVNKubernetesFeature.EgressFirewallDNSMaxTTL)
+	}
 	return nil
 }
 
@@ -837,6 +837,21 @@ mode=shared
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	})
 
+	It("returns an error when the egress firewall DNS negative TTL is greater than the maximum TTL", func() {
+		app.Action = func(ctx *cli.Context) error {
+			_, err := InitConfig(ctx, kexec.New(), nil)
+			gomega.Expect(err).To(gomega.MatchError("egress-firewall-dns-negative-ttl 600 must be positive and not greater than egress-firewall-dns-max-ttl 300"))
+			return nil
+		}
+		cliArgs := []string{
+			app.Name,
+			"-egress-firewall-dns-max-ttl=300",
+			"-egress-firewall-dns-negative-ttl=600",
+		}
+		err := app.Run(cliArgs)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
 	It("returns an error when the vlan-id is specified for mode other than shared gateway mode", func() {
 		app.Action = func(ctx *cli.Context) error {
 			_, err := InitConfig(ctx, kexec.New(), nil)
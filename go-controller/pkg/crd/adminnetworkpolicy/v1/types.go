@@ -0,0 +1,134 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +resource:path=adminnetworkpolicy
+// +kubebuilder:resource:shortName=anp,scope=Cluster
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=".spec.priority"
+// AdminNetworkPolicy is a cluster-scoped network policy set by the cluster
+// administrator. The rules of AdminNetworkPolicies are evaluated before those
+// of the namespaced NetworkPolicies and cannot be overridden by them.
+type AdminNetworkPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of AdminNetworkPolicy.
+	Spec AdminNetworkPolicySpec `json:"spec"`
+}
+
+// AdminNetworkPolicySpec is a desired state description of AdminNetworkPolicy.
+type AdminNetworkPolicySpec struct {
+	// priority orders the AdminNetworkPolicies, the rules of a policy with a
+	// lower value are evaluated before those of a policy with a higher value.
+	// Two policies should not share the same priority.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=99
+	Priority int32 `json:"priority"`
+	// subject selects the pods the policy applies to.
+	Subject AdminNetworkPolicySubject `json:"subject"`
+	// ingress is the ordered list of rules applied to the traffic to the
+	// subject pods, the first matching rule decides the fate of the traffic.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Ingress []AdminNetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// egress is the ordered list of rules applied to the traffic from the
+	// subject pods, the first matching rule decides the fate of the traffic.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Egress []AdminNetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// AdminNetworkPolicySubject selects pods by their namespace and their labels
+type AdminNetworkPolicySubject struct {
+	// namespaceSelector selects the namespaces of the pods, an empty selector
+	// selects all the namespaces.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// podSelector selects the pods within the selected namespaces, an empty
+	// selector selects all the pods.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// AdminNetworkPolicyRuleAction is the action taken on the traffic matching a rule
+// +kubebuilder:validation:Enum=Allow;Deny;Pass
+type AdminNetworkPolicyRuleAction string
+
+const (
+	// AdminNetworkPolicyRuleActionAllow allows the traffic, whatever the
+	// NetworkPolicies of the subject pods
+	AdminNetworkPolicyRuleActionAllow AdminNetworkPolicyRuleAction = "Allow"
+	// AdminNetworkPolicyRuleActionDeny drops the traffic, whatever the
+	// NetworkPolicies of the subject pods
+	AdminNetworkPolicyRuleActionDeny AdminNetworkPolicyRuleAction = "Deny"
+	// AdminNetworkPolicyRuleActionPass skips the rules of the lower priority
+	// AdminNetworkPolicies and leaves the traffic to the NetworkPolicies of
+	// the subject pods
+	AdminNetworkPolicyRuleActionPass AdminNetworkPolicyRuleAction = "Pass"
+)
+
+// AdminNetworkPolicyIngressRule matches the traffic to the subject pods
+type AdminNetworkPolicyIngressRule struct {
+	// action is the action taken on the matching traffic.
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	// from is the list of the sources of the traffic.
+	// +kubebuilder:validation:MinItems=1
+	From []AdminNetworkPolicyPeer `json:"from"`
+	// ports restricts the rule to the traffic to the listed ports, the rule
+	// matches all the traffic when the list is empty.
+	// +optional
+	Ports []AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyEgressRule matches the traffic from the subject pods
+type AdminNetworkPolicyEgressRule struct {
+	// action is the action taken on the matching traffic.
+	Action AdminNetworkPolicyRuleAction `json:"action"`
+	// to is the list of the destinations of the traffic.
+	// +kubebuilder:validation:MinItems=1
+	To []AdminNetworkPolicyPeer `json:"to"`
+	// ports restricts the rule to the traffic to the listed ports, the rule
+	// matches all the traffic when the list is empty.
+	// +optional
+	Ports []AdminNetworkPolicyPort `json:"ports,omitempty"`
+}
+
+// AdminNetworkPolicyPeer selects the pods at the other end of the traffic
+type AdminNetworkPolicyPeer struct {
+	// namespaceSelector selects the namespaces of the pods, an empty selector
+	// selects all the namespaces.
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	// podSelector selects the pods within the selected namespaces, an empty
+	// selector selects all the pods.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// AdminNetworkPolicyPort is a destination port of the traffic
+type AdminNetworkPolicyPort struct {
+	// protocol is the protocol of the traffic.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	Protocol string `json:"protocol"`
+	// port is the destination port of the traffic, all the ports of the
+	// protocol are matched when it is unset.
+	// +optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	Port int32 `json:"port,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=adminnetworkpolicy
+// AdminNetworkPolicyList is the list of AdminNetworkPolicies.
+type AdminNetworkPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of AdminNetworkPolicies.
+	Items []AdminNetworkPolicy `json:"items"`
+}
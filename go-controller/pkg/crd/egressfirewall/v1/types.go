@@ -34,13 +34,33 @@ type EgressFirewall struct {
 }
 
 type EgressFirewallStatus struct {
+	// status is a summary of the programming result of the whole EgressFirewall
 	Status string `json:"status,omitempty"`
+	// rules holds the programming result of each rule in spec.egress
+	// +optional
+	Rules []EgressFirewallRuleStatus `json:"rules,omitempty"`
+}
+
+// EgressFirewallRuleStatus is the programming result of a single egressfirewall rule
+type EgressFirewallRuleStatus struct {
+	// index is the position of the rule in spec.egress
+	Index int `json:"index"`
+	// status is one of "Applied", "Failed" or "NotApplied"
+	Status string `json:"status"`
+	// message is a human readable description of why the rule is not fully effective,
+	// e.g. an invalid cidrSelector or a dnsName that failed to resolve
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // EgressFirewallSpec is a desired state description of EgressFirewall.
 type EgressFirewallSpec struct {
 	// a collection of egress firewall rule objects
 	Egress []EgressFirewallRule `json:"egress"`
+	// podSelector restricts the rules to the pods of the namespace matching the selector,
+	// the rules apply to all the pods of the namespace when it is unset or empty
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
 }
 
 // EgressFirewallRule is a single egressfirewall rule object
@@ -52,6 +72,10 @@ type EgressFirewallRule struct {
 	Ports []EgressFirewallPort `json:"ports,omitempty"`
 	// to is the target that traffic is allowed/denied to
 	To EgressFirewallDestination `json:"to"`
+	// podSelector restricts the rule to the pods of the namespace matching the selector, and
+	// the podSelector of the spec if it is set
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
 }
 
 // EgressFirewallPort specifies the port to allow or deny traffic to
@@ -67,11 +91,17 @@ type EgressFirewallPort struct {
 
 // EgressFirewallDestination is the endpoint that traffic is either allowed or denied to
 type EgressFirewallDestination struct {
-	// cidrSelector is the CIDR range to allow/deny traffic to. If this is set, dnsName must be unset.
+	// cidrSelector is the CIDR range to allow/deny traffic to. If this is set, dnsName and nodeSelector must be unset.
 	CIDRSelector string `json:"cidrSelector,omitempty"`
-	// dnsName is the domain name to allow/deny traffic to. If this is set, cidrSelector must be unset.
-	// +kubebuilder:validation:Pattern=^([A-Za-z0-9-]+\.)*[A-Za-z0-9-]+\.?$
+	// dnsName is the domain name to allow/deny traffic to. If this is set, cidrSelector and nodeSelector must be unset.
+	// A wildcard such as *.example.com matches all the subdomains of the domain, their IPs are learned from the
+	// DNS responses received by the pods.
+	// +kubebuilder:validation:Pattern=^(\*\.)?([A-Za-z0-9-]+\.)*[A-Za-z0-9-]+\.?$
 	DNSName string `json:"dnsName,omitempty"`
+	// nodeSelector will allow/deny traffic to the Kubernetes node IP of selected nodes. If this is set,
+	// cidrSelector and dnsName must be unset.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
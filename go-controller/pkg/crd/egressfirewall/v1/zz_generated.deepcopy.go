@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,7 +31,7 @@ func (in *EgressFirewall) DeepCopyInto(out *EgressFirewall) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -55,6 +56,11 @@ func (in *EgressFirewall) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallDestination) DeepCopyInto(out *EgressFirewallDestination) {
 	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -125,7 +131,12 @@ func (in *EgressFirewallRule) DeepCopyInto(out *EgressFirewallRule) {
 		*out = make([]EgressFirewallPort, len(*in))
 		copy(*out, *in)
 	}
-	out.To = in.To
+	in.To.DeepCopyInto(&out.To)
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -139,6 +150,22 @@ func (in *EgressFirewallRule) DeepCopy() *EgressFirewallRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressFirewallRuleStatus) DeepCopyInto(out *EgressFirewallRuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressFirewallRuleStatus.
+func (in *EgressFirewallRuleStatus) DeepCopy() *EgressFirewallRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressFirewallRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallSpec) DeepCopyInto(out *EgressFirewallSpec) {
 	*out = *in
@@ -149,6 +176,11 @@ func (in *EgressFirewallSpec) DeepCopyInto(out *EgressFirewallSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -165,6 +197,11 @@ func (in *EgressFirewallSpec) DeepCopy() *EgressFirewallSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallStatus) DeepCopyInto(out *EgressFirewallStatus) {
 	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]EgressFirewallRuleStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
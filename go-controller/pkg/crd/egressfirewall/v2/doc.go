@@ -0,0 +1,4 @@
+// Package v2 contains API Schema definitions for the network v2 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=k8s.ovn.org
+package v2
@@ -0,0 +1,185 @@
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EgressNetworkFirewallRuleType indicates whether an EgressNetworkFirewallRule allows or denies traffic
+// +kubebuilder:validation:Pattern=^Allow|Deny$
+type EgressFirewallRuleType string
+
+const (
+	EgressFirewallRuleAllow EgressFirewallRuleType = "Allow"
+	EgressFirewallRuleDeny  EgressFirewallRuleType = "Deny"
+)
+
+const (
+	// ProtocolICMP and ProtocolICMPv6 are the protocols of the EgressFirewallPorts matching
+	// ICMP messages, in addition to the TCP, UDP and SCTP protocols of v1
+	ProtocolICMP   = "ICMP"
+	ProtocolICMPv6 = "ICMPv6"
+)
+
+// +genclient
+// +resource:path=egressfirewall
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="EgressFirewall Status",type=string,JSONPath=".status.status"
+// EgressFirewall describes the current egress firewall for a Namespace.
+// Traffic from a pod to an IP address outside the cluster will be checked against
+// each EgressFirewallRule in the pod's namespace's EgressFirewall, in
+// order. If no rule matches (or no EgressFirewall is present) then the traffic
+// will be allowed by default.
+type EgressFirewall struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of EgressFirewall.
+	Spec EgressFirewallSpec `json:"spec"`
+	// Observed status of EgressFirewall
+	// +optional
+	Status EgressFirewallStatus `json:"status,omitempty"`
+}
+
+type EgressFirewallStatus struct {
+	// status is a summary of the programming result of the whole EgressFirewall
+	Status string `json:"status,omitempty"`
+	// rules holds the programming result of each rule in spec.egress
+	// +optional
+	Rules []EgressFirewallRuleStatus `json:"rules,omitempty"`
+}
+
+// EgressFirewallRuleStatus is the programming result of a single egressfirewall rule
+type EgressFirewallRuleStatus struct {
+	// index is the position of the rule in spec.egress
+	Index int `json:"index"`
+	// status is one of "Applied", "Failed" or "NotApplied"
+	Status string `json:"status"`
+	// message is a human readable description of why the rule is not fully effective,
+	// e.g. an invalid cidrSelector or a dnsName that failed to resolve
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// EgressFirewallSpec is a desired state description of EgressFirewall.
+type EgressFirewallSpec struct {
+	// a collection of egress firewall rule objects
+	Egress []EgressFirewallRule `json:"egress"`
+	// podSelector restricts the rules to the pods of the namespace matching the selector,
+	// the rules apply to all the pods of the namespace when it is unset or empty
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// EgressFirewallRule is a single egressfirewall rule object
+type EgressFirewallRule struct {
+	// type marks this as an "Allow" or "Deny" rule
+	Type EgressFirewallRuleType `json:"type"`
+	// ports specify what ports and protocols the rule applies to
+	// +optional
+	Ports []EgressFirewallPort `json:"ports,omitempty"`
+	// to is the target that traffic is allowed/denied to
+	To EgressFirewallDestination `json:"to"`
+	// podSelector restricts the rule to the pods of the namespace matching the selector, and
+	// the podSelector of the spec if it is set
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// EgressFirewallPort specifies the ports or the ICMP messages to allow or deny traffic to
+type EgressFirewallPort struct {
+	// protocol (TCP, UDP, SCTP, ICMP, ICMPv6) that the traffic must match.
+	// +kubebuilder:validation:Pattern=^TCP|UDP|SCTP|ICMP|ICMPv6$
+	Protocol string `json:"protocol"`
+	// port that the traffic must match, all the ports of the protocol when unset.
+	// Only valid for TCP, UDP and SCTP.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// endPort makes the rule match the range of ports from port to endPort, inclusive.
+	// Requires port, and must be greater than or equal to port.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +optional
+	EndPort int32 `json:"endPort,omitempty"`
+	// icmpType is the ICMP type that the traffic must match, all the types when unset.
+	// Only valid for ICMP and ICMPv6.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=255
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty"`
+	// icmpCode is the ICMP code that the traffic must match, all the codes when unset.
+	// Requires icmpType.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=255
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty"`
+}
+
+// EgressFirewallDestination is the endpoint that traffic is either allowed or denied to
+type EgressFirewallDestination struct {
+	// cidrSelector is the CIDR range to allow/deny traffic to. If this is set, dnsName and nodeSelector must be unset.
+	CIDRSelector string `json:"cidrSelector,omitempty"`
+	// dnsName is the domain name to allow/deny traffic to. If this is set, cidrSelector and nodeSelector must be unset.
+	// A wildcard such as *.example.com matches all the subdomains of the domain, their IPs are learned from the
+	// DNS responses received by the pods.
+	// +kubebuilder:validation:Pattern=^(\*\.)?([A-Za-z0-9-]+\.)*[A-Za-z0-9-]+\.?$
+	DNSName string `json:"dnsName,omitempty"`
+	// nodeSelector will allow/deny traffic to the Kubernetes node IP of selected nodes. If this is set,
+	// cidrSelector and dnsName must be unset.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=egressfirewall
+// EgressFirewallList is the list of EgressFirewalls.
+type EgressFirewallList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of EgressFirewalls.
+	Items []EgressFirewall `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +resource:path=clusteregressfirewall
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="ClusterEgressFirewall Status",type=string,JSONPath=".status.status"
+// ClusterEgressFirewall describes the default egress firewall of the cluster.
+// Traffic from a pod to an IP address outside the cluster that does not match any
+// EgressFirewallRule of the EgressFirewall of the pod's namespace, or whose namespace
+// has no EgressFirewall, will be checked against each EgressFirewallRule of the
+// ClusterEgressFirewall, in order. If no rule matches then the traffic will be allowed
+// by default.
+type ClusterEgressFirewall struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of ClusterEgressFirewall.
+	Spec ClusterEgressFirewallSpec `json:"spec"`
+	// Observed status of ClusterEgressFirewall
+	// +optional
+	Status EgressFirewallStatus `json:"status,omitempty"`
+}
+
+// ClusterEgressFirewallSpec is a desired state description of ClusterEgressFirewall.
+type ClusterEgressFirewallSpec struct {
+	// a collection of egress firewall rule objects, the podSelector of a rule selects
+	// the pods of all the namespaces
+	Egress []EgressFirewallRule `json:"egress"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=clusteregressfirewall
+// ClusterEgressFirewallList is the list of ClusterEgressFirewalls.
+type ClusterEgressFirewallList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of ClusterEgressFirewalls.
+	Items []ClusterEgressFirewall `json:"items"`
+}
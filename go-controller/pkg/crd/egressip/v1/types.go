@@ -16,6 +16,7 @@ import (
 // +kubebuilder:printcolumn:name="EgressIPs",type=string,JSONPath=".spec.egressIPs[*]"
 // +kubebuilder:printcolumn:name="Assigned Node",type=string,JSONPath=".status.items[*].node"
 // +kubebuilder:printcolumn:name="Assigned EgressIPs",type=string,JSONPath=".status.items[*].egressIP"
+// +kubebuilder:printcolumn:name="Assigned",type=string,JSONPath=".status.conditions[?(@.type==\"Assigned\")].status"
 // EgressIP is a CRD allowing the user to define a fixed
 // source IP for all egress traffic originating from any pods which
 // match the EgressIP resource according to its spec definition.
@@ -33,8 +34,40 @@ type EgressIP struct {
 type EgressIPStatus struct {
 	// The list of assigned egress IPs and their corresponding node assignment.
 	Items []EgressIPStatusItem `json:"items"`
+	// The list of requested egress IPs which could not be assigned to any node, and why.
+	// +optional
+	UnassignedItems []EgressIPUnassignedItem `json:"unassignedItems,omitempty"`
+	// Conditions describe the assignment of the egress IPs: "Assigned",
+	// "PartiallyAssigned" and "Reachable".
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// EgressIPUnassignedNoMatchingNode is the reason given when no egress node's
+	// subnet can host the egress IP
+	EgressIPUnassignedNoMatchingNode = "NoMatchingNode"
+	// EgressIPUnassignedCapacityExceeded is the reason given when every egress
+	// node which could host the egress IP has reached its egress IP capacity
+	EgressIPUnassignedCapacityExceeded = "CapacityExceeded"
+	// EgressIPUnassignedNoReachableNode is the reason given when every egress
+	// node which could host the egress IP is unreachable
+	EgressIPUnassignedNoReachableNode = "NoReachableNode"
+)
+
+const (
+	// EgressIPConditionAssigned is true when every requested egress IP is assigned to a node
+	EgressIPConditionAssigned = "Assigned"
+	// EgressIPConditionPartiallyAssigned is true when some, but not all, requested egress IPs
+	// are assigned to a node
+	EgressIPConditionPartiallyAssigned = "PartiallyAssigned"
+	// EgressIPConditionReachable is true when every node hosting an egress IP is reachable, and
+	// false when egress IPs are left unassigned because the nodes which could host them are not
+	EgressIPConditionReachable = "Reachable"
+)
+
 // The per node status, for those egress IPs who have been assigned.
 type EgressIPStatusItem struct {
 	// Assigned node name
@@ -43,6 +76,14 @@ type EgressIPStatusItem struct {
 	EgressIP string `json:"egressIP"`
 }
 
+// The status of an egress IP which could not be assigned to any node.
+type EgressIPUnassignedItem struct {
+	// Unassigned egress IP
+	EgressIP string `json:"egressIP"`
+	// Reason is one of "NoMatchingNode", "NoReachableNode" or "CapacityExceeded"
+	Reason string `json:"reason"`
+}
+
 // EgressIPSpec is a desired state description of EgressIP.
 type EgressIPSpec struct {
 	// EgressIPs is the list of egress IP addresses requested. Can be IPv4 and/or IPv6.
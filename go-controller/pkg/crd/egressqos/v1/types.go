@@ -0,0 +1,64 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +resource:path=egressqos
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=eq,scope=Namespaced
+// +kubebuilder:printcolumn:name="EgressQoS Status",type=string,JSONPath=".status.status"
+// EgressQoS describes the DSCP marking of the egress traffic of the pods of a Namespace.
+// Traffic from a pod will be checked against each EgressQoSRule in the pod's namespace's
+// EgressQoS, in order, and marked with the DSCP value of the first rule matching it.
+// Only the EgressQoS named "default" is honored in each namespace.
+type EgressQoS struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of EgressQoS.
+	Spec EgressQoSSpec `json:"spec"`
+	// Observed status of EgressQoS
+	// +optional
+	Status EgressQoSStatus `json:"status,omitempty"`
+}
+
+type EgressQoSStatus struct {
+	// status is a summary of the programming result of the EgressQoS
+	Status string `json:"status,omitempty"`
+}
+
+// EgressQoSSpec is a desired state description of EgressQoS.
+type EgressQoSSpec struct {
+	// a collection of egress QoS rule objects
+	Egress []EgressQoSRule `json:"egress"`
+}
+
+// EgressQoSRule is a single egressqos rule object
+type EgressQoSRule struct {
+	// dscp is the DSCP value the matching traffic is marked with
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=63
+	DSCP int `json:"dscp"`
+	// dstCIDR is the CIDR range of the destinations of the traffic to mark. If it is unset
+	// all the egress traffic of the selected pods is marked.
+	// +optional
+	// +kubebuilder:validation:Format="cidr"
+	DstCIDR *string `json:"dstCIDR,omitempty"`
+	// podSelector selects the pods of the namespace whose traffic is marked. If it is empty
+	// the traffic of all the pods of the namespace is marked.
+	// +optional
+	PodSelector metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +resource:path=egressqos
+// EgressQoSList is the list of EgressQoSes.
+type EgressQoSList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of EgressQoSes.
+	Items []EgressQoS `json:"items"`
+}
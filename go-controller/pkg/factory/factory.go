@@ -9,14 +9,28 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
-	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
-	egressfirewallscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/scheme"
-	egressfirewallinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/informers/externalversions"
+	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	egressfirewallscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/clientset/versioned/scheme"
+	egressfirewallinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/informers/externalversions"
+	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/listers/egressfirewall/v2"
 
 	egressipapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned/scheme"
 	egressipinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/informers/externalversions"
 
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/scheme"
+	egressqosinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/informers/externalversions"
+	egressqoslister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/listers/egressqos/v1"
+
+	adminnetworkpolicyapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	adminnetworkpolicyscheme "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned/scheme"
+	adminnetworkpolicyinformerfactory "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/informers/externalversions"
+
+	nadapi "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	nadinformerfactory "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/informers/externalversions"
+	nadlister "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+
 	kapi "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +54,9 @@ type WatchFactory struct {
 	iFactory   informerfactory.SharedInformerFactory
 	eipFactory egressipinformerfactory.SharedInformerFactory
 	efFactory  egressfirewallinformerfactory.SharedInformerFactory
+	eqFactory  egressqosinformerfactory.SharedInformerFactory
+	anpFactory adminnetworkpolicyinformerfactory.SharedInformerFactory
+	nadFactory nadinformerfactory.SharedInformerFactory
 	informers  map[reflect.Type]*informer
 
 	stopChan chan struct{}
@@ -64,14 +81,18 @@ const (
 )
 
 var (
-	podType            reflect.Type = reflect.TypeOf(&kapi.Pod{})
-	serviceType        reflect.Type = reflect.TypeOf(&kapi.Service{})
-	endpointsType      reflect.Type = reflect.TypeOf(&kapi.Endpoints{})
-	policyType         reflect.Type = reflect.TypeOf(&knet.NetworkPolicy{})
-	namespaceType      reflect.Type = reflect.TypeOf(&kapi.Namespace{})
-	nodeType           reflect.Type = reflect.TypeOf(&kapi.Node{})
-	egressFirewallType reflect.Type = reflect.TypeOf(&egressfirewallapi.EgressFirewall{})
-	egressIPType       reflect.Type = reflect.TypeOf(&egressipapi.EgressIP{})
+	podType                   reflect.Type = reflect.TypeOf(&kapi.Pod{})
+	serviceType               reflect.Type = reflect.TypeOf(&kapi.Service{})
+	endpointsType             reflect.Type = reflect.TypeOf(&kapi.Endpoints{})
+	policyType                reflect.Type = reflect.TypeOf(&knet.NetworkPolicy{})
+	namespaceType             reflect.Type = reflect.TypeOf(&kapi.Namespace{})
+	nodeType                  reflect.Type = reflect.TypeOf(&kapi.Node{})
+	egressFirewallType        reflect.Type = reflect.TypeOf(&egressfirewallapi.EgressFirewall{})
+	clusterEgressFirewallType reflect.Type = reflect.TypeOf(&egressfirewallapi.ClusterEgressFirewall{})
+	egressIPType              reflect.Type = reflect.TypeOf(&egressipapi.EgressIP{})
+	egressQoSType             reflect.Type = reflect.TypeOf(&egressqosapi.EgressQoS{})
+	adminNetworkPolicyType    reflect.Type = reflect.TypeOf(&adminnetworkpolicyapi.AdminNetworkPolicy{})
+	nadType                   reflect.Type = reflect.TypeOf(&nadapi.NetworkAttachmentDefinition{})
 )
 
 // NewMasterWatchFactory initializes a new watch factory for the master or master+node processes.
@@ -86,6 +107,9 @@ func NewMasterWatchFactory(ovnClientset *util.OVNClientset) (*WatchFactory, erro
 		iFactory:   informerfactory.NewSharedInformerFactory(ovnClientset.KubeClient, resyncInterval),
 		eipFactory: egressipinformerfactory.NewSharedInformerFactory(ovnClientset.EgressIPClient, resyncInterval),
 		efFactory:  egressfirewallinformerfactory.NewSharedInformerFactory(ovnClientset.EgressFirewallClient, resyncInterval),
+		eqFactory:  egressqosinformerfactory.NewSharedInformerFactory(ovnClientset.EgressQoSClient, resyncInterval),
+		anpFactory: adminnetworkpolicyinformerfactory.NewSharedInformerFactory(ovnClientset.AdminNetworkPolicyClient, resyncInterval),
+		nadFactory: nadinformerfactory.NewSharedInformerFactory(ovnClientset.NetworkAttchDefClient, resyncInterval),
 		informers:  make(map[reflect.Type]*informer),
 		stopChan:   make(chan struct{}),
 	}
@@ -96,6 +120,12 @@ func NewMasterWatchFactory(ovnClientset *util.OVNClientset) (*WatchFactory, erro
 	if err := egressfirewallapi.AddToScheme(egressfirewallscheme.Scheme); err != nil {
 		return nil, err
 	}
+	if err := egressqosapi.AddToScheme(egressqosscheme.Scheme); err != nil {
+		return nil, err
+	}
+	if err := adminnetworkpolicyapi.AddToScheme(adminnetworkpolicyscheme.Scheme); err != nil {
+		return nil, err
+	}
 
 	// For Services and Endpoints, pre-populate the shared Informer with one that
 	// has a label selector excluding headless services.
@@ -155,7 +185,29 @@ func NewMasterWatchFactory(ovnClientset *util.OVNClientset) (*WatchFactory, erro
 		}
 	}
 	if config.OVNKubernetesFeature.EnableEgressFirewall {
-		wf.informers[egressFirewallType], err = newInformer(egressFirewallType, wf.efFactory.K8s().V1().EgressFirewalls().Informer())
+		wf.informers[egressFirewallType], err = newInformer(egressFirewallType, wf.efFactory.K8s().V2().EgressFirewalls().Informer())
+		if err != nil {
+			return nil, err
+		}
+		wf.informers[clusterEgressFirewallType], err = newInformer(clusterEgressFirewallType, wf.efFactory.K8s().V2().ClusterEgressFirewalls().Informer())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.OVNKubernetesFeature.EnableEgressQoS {
+		wf.informers[egressQoSType], err = newInformer(egressQoSType, wf.eqFactory.K8s().V1().EgressQoSes().Informer())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.OVNKubernetesFeature.EnableAdminNetworkPolicy {
+		wf.informers[adminNetworkPolicyType], err = newInformer(adminNetworkPolicyType, wf.anpFactory.K8s().V1().AdminNetworkPolicies().Informer())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.OVNKubernetesFeature.EnableMultiNetwork {
+		wf.informers[nadType], err = newInformer(nadType, wf.nadFactory.K8sCniCncfIo().V1().NetworkAttachmentDefinitions().Informer())
 		if err != nil {
 			return nil, err
 		}
@@ -188,6 +240,30 @@ func (wf *WatchFactory) Start() error {
 			}
 		}
 	}
+	if config.OVNKubernetesFeature.EnableEgressQoS && wf.eqFactory != nil {
+		wf.eqFactory.Start(wf.stopChan)
+		for oType, synced := range wf.eqFactory.WaitForCacheSync(wf.stopChan) {
+			if !synced {
+				return fmt.Errorf("error in syncing cache for %v informer", oType)
+			}
+		}
+	}
+	if config.OVNKubernetesFeature.EnableAdminNetworkPolicy && wf.anpFactory != nil {
+		wf.anpFactory.Start(wf.stopChan)
+		for oType, synced := range wf.anpFactory.WaitForCacheSync(wf.stopChan) {
+			if !synced {
+				return fmt.Errorf("error in syncing cache for %v informer", oType)
+			}
+		}
+	}
+	if config.OVNKubernetesFeature.EnableMultiNetwork && wf.nadFactory != nil {
+		wf.nadFactory.Start(wf.stopChan)
+		for oType, synced := range wf.nadFactory.WaitForCacheSync(wf.stopChan) {
+			if !synced {
+				return fmt.Errorf("error in syncing cache for %v informer", oType)
+			}
+		}
+	}
 
 	return nil
 }
@@ -254,6 +330,22 @@ func NewNodeWatchFactory(ovnClientset *util.OVNClientset, nodeName string) (*Wat
 		}
 	}
 
+	// EgressFirewalls and ClusterEgressFirewalls are needed to learn the IPs of their wildcard dnsNames
+	if config.OVNKubernetesFeature.EnableEgressFirewall {
+		if err := egressfirewallapi.AddToScheme(egressfirewallscheme.Scheme); err != nil {
+			return nil, err
+		}
+		wf.efFactory = egressfirewallinformerfactory.NewSharedInformerFactory(ovnClientset.EgressFirewallClient, resyncInterval)
+		wf.informers[egressFirewallType], err = newInformer(egressFirewallType, wf.efFactory.K8s().V2().EgressFirewalls().Informer())
+		if err != nil {
+			return nil, err
+		}
+		wf.informers[clusterEgressFirewallType], err = newInformer(clusterEgressFirewallType, wf.efFactory.K8s().V2().ClusterEgressFirewalls().Informer())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return wf, nil
 }
 
@@ -296,10 +388,26 @@ func getObjectMeta(objType reflect.Type, obj interface{}) (*metav1.ObjectMeta, e
 		if egressFirewall, ok := obj.(*egressfirewallapi.EgressFirewall); ok {
 			return &egressFirewall.ObjectMeta, nil
 		}
+	case clusterEgressFirewallType:
+		if clusterEgressFirewall, ok := obj.(*egressfirewallapi.ClusterEgressFirewall); ok {
+			return &clusterEgressFirewall.ObjectMeta, nil
+		}
 	case egressIPType:
 		if egressIP, ok := obj.(*egressipapi.EgressIP); ok {
 			return &egressIP.ObjectMeta, nil
 		}
+	case egressQoSType:
+		if egressQoS, ok := obj.(*egressqosapi.EgressQoS); ok {
+			return &egressQoS.ObjectMeta, nil
+		}
+	case adminNetworkPolicyType:
+		if adminNetworkPolicy, ok := obj.(*adminnetworkpolicyapi.AdminNetworkPolicy); ok {
+			return &adminNetworkPolicy.ObjectMeta, nil
+		}
+	case nadType:
+		if nad, ok := obj.(*nadapi.NetworkAttachmentDefinition); ok {
+			return &nad.ObjectMeta, nil
+		}
 	}
 	return nil, fmt.Errorf("cannot get ObjectMeta from type %v", objType)
 }
@@ -419,6 +527,46 @@ func (wf *WatchFactory) RemoveEgressFirewallHandler(handler *Handler) {
 	wf.removeHandler(egressFirewallType, handler)
 }
 
+// AddClusterEgressFirewallHandler adds a handler function that will be executed on ClusterEgressFirewall object changes
+func (wf *WatchFactory) AddClusterEgressFirewallHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(clusterEgressFirewallType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveClusterEgressFirewallHandler removes a ClusterEgressFirewall object event handler function
+func (wf *WatchFactory) RemoveClusterEgressFirewallHandler(handler *Handler) {
+	wf.removeHandler(clusterEgressFirewallType, handler)
+}
+
+// AddEgressQoSHandler adds a handler function that will be executed on EgressQoS object changes
+func (wf *WatchFactory) AddEgressQoSHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(egressQoSType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveEgressQoSHandler removes an EgressQoS object event handler function
+func (wf *WatchFactory) RemoveEgressQoSHandler(handler *Handler) {
+	wf.removeHandler(egressQoSType, handler)
+}
+
+// AddAdminNetworkPolicyHandler adds a handler function that will be executed on AdminNetworkPolicy object changes
+func (wf *WatchFactory) AddAdminNetworkPolicyHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(adminNetworkPolicyType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveAdminNetworkPolicyHandler removes an AdminNetworkPolicy object event handler function
+func (wf *WatchFactory) RemoveAdminNetworkPolicyHandler(handler *Handler) {
+	wf.removeHandler(adminNetworkPolicyType, handler)
+}
+
+// AddNetworkAttachmentDefinitionHandler adds a handler function that will be executed on NetworkAttachmentDefinition object changes
+func (wf *WatchFactory) AddNetworkAttachmentDefinitionHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
+	return wf.addHandler(nadType, "", nil, handlerFuncs, processExisting)
+}
+
+// RemoveNetworkAttachmentDefinitionHandler removes a NetworkAttachmentDefinition object event handler function
+func (wf *WatchFactory) RemoveNetworkAttachmentDefinitionHandler(handler *Handler) {
+	wf.removeHandler(nadType, handler)
+}
+
 // AddEgressIPHandler adds a handler function that will be executed on EgressIP object changes
 func (wf *WatchFactory) AddEgressIPHandler(handlerFuncs cache.ResourceEventHandler, processExisting func([]interface{})) *Handler {
 	return wf.addHandler(egressIPType, "", nil, handlerFuncs, processExisting)
@@ -531,6 +679,30 @@ func (wf *WatchFactory) GetNamespacesBySelector(selector metav1.LabelSelector) (
 	return namespaceLister.List(labels.Set(selector.MatchLabels).AsSelector())
 }
 
+// GetEgressFirewall returns a specific EgressFirewall in a given namespace
+func (wf *WatchFactory) GetEgressFirewall(namespace, name string) (*egressfirewallapi.EgressFirewall, error) {
+	egressFirewallLister := wf.informers[egressFirewallType].lister.(egressfirewalllister.EgressFirewallLister)
+	return egressFirewallLister.EgressFirewalls(namespace).Get(name)
+}
+
+// GetClusterEgressFirewall returns a specific ClusterEgressFirewall
+func (wf *WatchFactory) GetClusterEgressFirewall(name string) (*egressfirewallapi.ClusterEgressFirewall, error) {
+	clusterEgressFirewallLister := wf.informers[clusterEgressFirewallType].lister.(egressfirewalllister.ClusterEgressFirewallLister)
+	return clusterEgressFirewallLister.Get(name)
+}
+
+// GetEgressQoS returns a specific EgressQoS in a given namespace
+func (wf *WatchFactory) GetEgressQoS(namespace, name string) (*egressqosapi.EgressQoS, error) {
+	egressQoSLister := wf.informers[egressQoSType].lister.(egressqoslister.EgressQoSLister)
+	return egressQoSLister.EgressQoSes(namespace).Get(name)
+}
+
+// GetNetworkAttachmentDefinition returns a specific NetworkAttachmentDefinition in a given namespace
+func (wf *WatchFactory) GetNetworkAttachmentDefinition(namespace, name string) (*nadapi.NetworkAttachmentDefinition, error) {
+	nadLister := wf.informers[nadType].lister.(nadlister.NetworkAttachmentDefinitionLister)
+	return nadLister.NetworkAttachmentDefinitions(namespace).Get(name)
+}
+
 func (wf *WatchFactory) NodeInformer() cache.SharedIndexInformer {
 	return wf.informers[nodeType].inf
 }
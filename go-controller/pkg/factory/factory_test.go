@@ -18,8 +18,8 @@ import (
 	core "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
 
-	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
-	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/fake"
+	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/clientset/versioned/fake"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
@@ -111,7 +111,7 @@ func newEgressFirewall(name, namespace string) *egressfirewall.EgressFirewall {
 	return &egressfirewall.EgressFirewall{
 		ObjectMeta: newObjectMeta(name, namespace),
 		Spec: egressfirewall.EgressFirewallSpec{
-			[]egressfirewall.EgressFirewallRule{
+			Egress: []egressfirewall.EgressFirewallRule{
 				{
 					Type: egressfirewall.EgressFirewallRuleAllow,
 					To: egressfirewall.EgressFirewallDestination{
@@ -123,6 +123,22 @@ func newEgressFirewall(name, namespace string) *egressfirewall.EgressFirewall {
 	}
 }
 
+func newClusterEgressFirewall(name string) *egressfirewall.ClusterEgressFirewall {
+	return &egressfirewall.ClusterEgressFirewall{
+		ObjectMeta: newObjectMeta(name, ""),
+		Spec: egressfirewall.ClusterEgressFirewallSpec{
+			Egress: []egressfirewall.EgressFirewallRule{
+				{
+					Type: egressfirewall.EgressFirewallRuleDeny,
+					To: egressfirewall.EgressFirewallDestination{
+						CIDRSelector: "0.0.0.0/0",
+					},
+				},
+			},
+		},
+	}
+}
+
 func newEgressIP(name, namespace string) *egressip.EgressIP {
 	return &egressip.EgressIP{
 		ObjectMeta: newObjectMeta(name, namespace),
@@ -193,6 +209,7 @@ var _ = Describe("Watch Factory Operations", func() {
 		egressIPs                                 []*egressip.EgressIP
 		wf                                        *WatchFactory
 		egressFirewalls                           []*egressfirewall.EgressFirewall
+		clusterEgressFirewalls                    []*egressfirewall.ClusterEgressFirewall
 		err                                       error
 	)
 
@@ -276,6 +293,15 @@ var _ = Describe("Watch Factory Operations", func() {
 			return true, obj, nil
 		})
 
+		clusterEgressFirewalls = make([]*egressfirewall.ClusterEgressFirewall, 0)
+		egressFirewallObjSetup(egressFirewallFakeClient, "clusteregressfirewalls", func(core.Action) (bool, runtime.Object, error) {
+			obj := &egressfirewall.ClusterEgressFirewallList{}
+			for _, p := range clusterEgressFirewalls {
+				obj.Items = append(obj.Items, *p)
+			}
+			return true, obj, nil
+		})
+
 		egressIPs = make([]*egressip.EgressIP, 0)
 		egressIPWatch = egressIPObjSetup(egressIPFakeClient, "egressips", func(core.Action) (bool, runtime.Object, error) {
 			obj := &egressip.EgressIPList{}
@@ -420,6 +446,11 @@ var _ = Describe("Watch Factory Operations", func() {
 			egressFirewalls = append(egressFirewalls, newEgressFirewall("myFirewall1", "default"))
 			testExisting(egressFirewallType)
 		})
+		It("calls ADD for each existing clusterEgressFirewall", func() {
+			clusterEgressFirewalls = append(clusterEgressFirewalls, newClusterEgressFirewall("default"))
+			clusterEgressFirewalls = append(clusterEgressFirewalls, newClusterEgressFirewall("default1"))
+			testExisting(clusterEgressFirewallType)
+		})
 		It("calls ADD for each existing egressIP", func() {
 			egressIPs = append(egressIPs, newEgressIP("myEgressIP", "default"))
 			egressIPs = append(egressIPs, newEgressIP("myEgressIP1", "default"))
@@ -10,10 +10,16 @@ import (
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 
-	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/listers/egressfirewall/v1"
+	egressfirewalllister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/listers/egressfirewall/v2"
 
 	egressiplister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/listers/egressip/v1"
 
+	egressqoslister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/listers/egressqos/v1"
+
+	adminnetworkpolicylister "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/listers/adminnetworkpolicy/v1"
+
+	nadlister "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/listers/k8s.cni.cncf.io/v1"
+
 	ktypes "k8s.io/apimachinery/pkg/types"
 	listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -373,8 +379,16 @@ func newInformerLister(oType reflect.Type, sharedInformer cache.SharedIndexInfor
 		return nil, nil
 	case egressFirewallType:
 		return egressfirewalllister.NewEgressFirewallLister(sharedInformer.GetIndexer()), nil
+	case clusterEgressFirewallType:
+		return egressfirewalllister.NewClusterEgressFirewallLister(sharedInformer.GetIndexer()), nil
 	case egressIPType:
 		return egressiplister.NewEgressIPLister(sharedInformer.GetIndexer()), nil
+	case egressQoSType:
+		return egressqoslister.NewEgressQoSLister(sharedInformer.GetIndexer()), nil
+	case adminNetworkPolicyType:
+		return adminnetworkpolicylister.NewAdminNetworkPolicyLister(sharedInformer.GetIndexer()), nil
+	case nadType:
+		return nadlister.NewNetworkAttachmentDefinitionLister(sharedInformer.GetIndexer()), nil
 	}
 
 	return nil, fmt.Errorf("cannot create lister from type %v", oType)
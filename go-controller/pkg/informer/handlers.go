@@ -21,3 +21,10 @@ type ServiceAndEndpointsEventHandler interface {
 	ServiceEventHandler
 	EndpointsEventHandler
 }
+
+type PodEventHandler interface {
+	AddPod(*kapi.Pod)
+	DeletePod(*kapi.Pod)
+	UpdatePod(old, new *kapi.Pod)
+	SyncPods([]interface{})
+}
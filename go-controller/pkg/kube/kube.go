@@ -6,10 +6,12 @@ import (
 
 	"k8s.io/klog/v2"
 
-	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
-	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
+	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/clientset/versioned"
 	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned"
+	egressqos "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	kapi "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -29,7 +31,9 @@ type Interface interface {
 	RemoveTaintFromNode(nodeName string, taint *kapi.Taint) error
 	PatchNode(old, new *kapi.Node) error
 	UpdateEgressFirewall(egressfirewall *egressfirewall.EgressFirewall) error
+	UpdateClusterEgressFirewall(clusterEgressFirewall *egressfirewall.ClusterEgressFirewall) error
 	UpdateEgressIP(eIP *egressipv1.EgressIP) error
+	UpdateEgressQoS(egressqos *egressqos.EgressQoS) error
 	UpdateNodeStatus(node *kapi.Node) error
 	GetAnnotationsOnPod(namespace, name string) (map[string]string, error)
 	GetNodes() (*kapi.NodeList, error)
@@ -49,6 +53,7 @@ type Kube struct {
 	KClient              kubernetes.Interface
 	EIPClient            egressipclientset.Interface
 	EgressFirewallClient egressfirewallclientset.Interface
+	EgressQoSClient      egressqosclientset.Interface
 }
 
 // SetAnnotationsOnPod takes the pod object and map of key/value string pairs to set as annotations
@@ -223,7 +228,21 @@ func (k *Kube) PatchNode(old, new *kapi.Node) error {
 // UpdateEgressFirewall updates the EgressFirewall with the provided EgressFirewall data
 func (k *Kube) UpdateEgressFirewall(egressfirewall *egressfirewall.EgressFirewall) error {
 	klog.Infof("Updating status on EgressFirewall %s in namespace %s", egressfirewall.Name, egressfirewall.Namespace)
-	_, err := k.EgressFirewallClient.K8sV1().EgressFirewalls(egressfirewall.Namespace).Update(context.TODO(), egressfirewall, metav1.UpdateOptions{})
+	_, err := k.EgressFirewallClient.K8sV2().EgressFirewalls(egressfirewall.Namespace).Update(context.TODO(), egressfirewall, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateClusterEgressFirewall updates the ClusterEgressFirewall with the provided ClusterEgressFirewall data
+func (k *Kube) UpdateClusterEgressFirewall(clusterEgressFirewall *egressfirewall.ClusterEgressFirewall) error {
+	klog.Infof("Updating status on ClusterEgressFirewall %s", clusterEgressFirewall.Name)
+	_, err := k.EgressFirewallClient.K8sV2().ClusterEgressFirewalls().Update(context.TODO(), clusterEgressFirewall, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateEgressQoS updates the EgressQoS with the provided EgressQoS data
+func (k *Kube) UpdateEgressQoS(egressqos *egressqos.EgressQoS) error {
+	klog.Infof("Updating status on EgressQoS %s in namespace %s", egressqos.Name, egressqos.Namespace)
+	_, err := k.EgressQoSClient.K8sV1().EgressQoSes(egressqos.Namespace).Update(context.TODO(), egressqos, metav1.UpdateOptions{})
 	return err
 }
 
@@ -289,7 +308,7 @@ func (k *Kube) GetEgressIPs() (*egressipv1.EgressIPList, error) {
 
 // GetEgressFirewalls returns the list of all EgressFirewall objects from kubernetes
 func (k *Kube) GetEgressFirewalls() (*egressfirewall.EgressFirewallList, error) {
-	return k.EgressFirewallClient.K8sV1().EgressFirewalls(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	return k.EgressFirewallClient.K8sV2().EgressFirewalls(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
 }
 
 // GetEndpoint returns the Endpoints resource
@@ -3,8 +3,9 @@
 package mocks
 
 import (
-	egressfirewallv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressfirewallv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
 	egressipv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
+	egressqosv1 "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -349,6 +350,20 @@ func (_m *KubeInterface) SetTaintOnNode(nodeName string, taint *v1.Taint) error
 	return r0
 }
 
+// UpdateClusterEgressFirewall provides a mock function with given fields: clusterEgressFirewall
+func (_m *KubeInterface) UpdateClusterEgressFirewall(clusterEgressFirewall *egressfirewallv1.ClusterEgressFirewall) error {
+	ret := _m.Called(clusterEgressFirewall)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*egressfirewallv1.ClusterEgressFirewall) error); ok {
+		r0 = rf(clusterEgressFirewall)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateEgressFirewall provides a mock function with given fields: egressfirewall
 func (_m *KubeInterface) UpdateEgressFirewall(egressfirewall *egressfirewallv1.EgressFirewall) error {
 	ret := _m.Called(egressfirewall)
@@ -377,6 +392,20 @@ func (_m *KubeInterface) UpdateEgressIP(eIP *egressipv1.EgressIP) error {
 	return r0
 }
 
+// UpdateEgressQoS provides a mock function with given fields: egressqos
+func (_m *KubeInterface) UpdateEgressQoS(egressqos *egressqosv1.EgressQoS) error {
+	ret := _m.Called(egressqos)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*egressqosv1.EgressQoS) error); ok {
+		r0 = rf(egressqos)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateNodeStatus provides a mock function with given fields: node
 func (_m *KubeInterface) UpdateNodeStatus(node *v1.Node) error {
 	ret := _m.Called(node)
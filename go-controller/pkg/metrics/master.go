@@ -113,6 +113,60 @@ var MetricSyncServiceLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
 	Buckets:   prometheus.ExponentialBuckets(.1, 2, 15)},
 )
 
+// MetricUnidleNeedPodsLatency is the time taken from an OVN controller event reporting a
+// load balancer without backends to the NeedPods event of the matching service.
+var MetricUnidleNeedPodsLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "unidle_need_pods_latency_seconds",
+	Help: "The latency between an empty load balancer backends event being received " +
+		"from the OVN southbound database and the NeedPods event being emitted for the service",
+	Buckets: prometheus.ExponentialBuckets(.001, 2, 15)},
+)
+
+// MetricUnidleEventCount is the number of OVN empty load balancer backends events processed,
+// by the result of their processing.
+var MetricUnidleEventCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "unidle_events_total",
+	Help:      "The number of empty load balancer backends events processed by the unidling controller"},
+	[]string{
+		"result",
+	},
+)
+
+// MetricEgressFirewallDNSResolutionLatency is the time taken to resolve a dnsName of the
+// EgressFirewall rules.
+var MetricEgressFirewallDNSResolutionLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "egress_firewall_dns_resolution_latency_seconds",
+	Help:      "The latency of resolving a dnsName of the EgressFirewall rules",
+	Buckets:   prometheus.ExponentialBuckets(.001, 2, 15)},
+)
+
+// MetricEgressFirewallDNSResolutionFailures is the number of times a dnsName of the
+// EgressFirewall rules failed to resolve.
+var MetricEgressFirewallDNSResolutionFailures = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "egress_firewall_dns_resolution_failures_total",
+	Help:      "The number of times a dnsName of the EgressFirewall rules failed to resolve"},
+)
+
+// MetricEgressFirewallDNSAddressSetChanges is the number of IPs added to and deleted from
+// the address sets of the dnsNames of the EgressFirewall rules.
+var MetricEgressFirewallDNSAddressSetChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: MetricOvnkubeNamespace,
+	Subsystem: MetricOvnkubeSubsystemMaster,
+	Name:      "egress_firewall_dns_address_set_changes_total",
+	Help:      "The number of IPs added to or deleted from the address sets of the dnsNames of the EgressFirewall rules"},
+	[]string{
+		"operation",
+	},
+)
+
 var MetricMasterReadyDuration = prometheus.NewGauge(prometheus.GaugeOpts{
 	Namespace: MetricOvnkubeNamespace,
 	Subsystem: MetricOvnkubeSubsystemMaster,
@@ -208,6 +262,11 @@ func RegisterMasterMetrics(nbClient, sbClient goovn.Client) {
 		prometheus.MustRegister(MetricRequeueServiceCount)
 		prometheus.MustRegister(MetricSyncServiceCount)
 		prometheus.MustRegister(MetricSyncServiceLatency)
+		prometheus.MustRegister(MetricUnidleNeedPodsLatency)
+		prometheus.MustRegister(MetricUnidleEventCount)
+		prometheus.MustRegister(MetricEgressFirewallDNSResolutionLatency)
+		prometheus.MustRegister(MetricEgressFirewallDNSResolutionFailures)
+		prometheus.MustRegister(MetricEgressFirewallDNSAddressSetChanges)
 		prometheus.MustRegister(prometheus.NewGaugeFunc(
 			prometheus.GaugeOpts{
 				Namespace: MetricOvnkubeNamespace,
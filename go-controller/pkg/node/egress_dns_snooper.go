@@ -0,0 +1,347 @@
+package node
+
+import (
+	"encoding/binary"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/kube"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// egressDNSSnoopReportInterval is the minimum interval between two reports of the IPs
+	// learned from the DNS responses to ovnkube-master
+	egressDNSSnoopReportInterval = 30 * time.Second
+	// egressDNSSnoopExpiryInterval is the interval at which the expired IPs are forgotten
+	egressDNSSnoopExpiryInterval = time.Minute
+	// egressDNSSnoopMinTTL is the minimum time the IPs learned from a DNS response are
+	// kept, so that the connections opened just before their TTL expired keep working
+	egressDNSSnoopMinTTL = 5 * time.Minute
+)
+
+// egressDNSSnooper learns the IPs of the wildcard dnsNames of the EgressFirewall rules
+// from the responses of the cluster DNS service to the pods of the node, and reports
+// them to ovnkube-master in a node annotation
+type egressDNSSnooper struct {
+	sync.Mutex
+	nodeName     string
+	kube         kube.Interface
+	watchFactory factory.NodeWatchFactory
+	// dnsService is the namespace and name of the cluster DNS service, only its
+	// responses are trusted
+	dnsServiceNamespace string
+	dnsServiceName      string
+	// wildcardDNSNames holds the wildcard dnsNames of each EgressFirewall, keyed by
+	// namespace/name of the EgressFirewall, or by name of the ClusterEgressFirewall
+	wildcardDNSNames map[string][]string
+	// dnsIPs holds the IPs seen for each wildcard dnsName and the time they expire at
+	dnsIPs map[string]map[string]time.Time
+	// reported is the last value of the node annotation, nil until first reported
+	reported map[string][]string
+	// changed is signaled when new IPs are learned or dnsNames are no longer used
+	changed chan struct{}
+}
+
+func newEgressDNSSnooper(nodeName string, kube kube.Interface, watchFactory factory.NodeWatchFactory,
+	dnsService string) *egressDNSSnooper {
+	s := &egressDNSSnooper{
+		nodeName:         nodeName,
+		kube:             kube,
+		watchFactory:     watchFactory,
+		wildcardDNSNames: make(map[string][]string),
+		dnsIPs:           make(map[string]map[string]time.Time),
+		changed:          make(chan struct{}, 1),
+	}
+	s.dnsServiceNamespace, s.dnsServiceName, _ = cache.SplitMetaNamespaceKey(dnsService)
+	return s
+}
+
+// Run watches the EgressFirewalls and ClusterEgressFirewalls, snoops the DNS responses and reports the learned IPs
+// until stopChan is closed
+func (s *egressDNSSnooper) Run(stopChan <-chan struct{}) {
+	s.watchFactory.AddEgressFirewallHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s.setEgressFirewall(obj.(*egressfirewallapi.EgressFirewall))
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			s.setEgressFirewall(newer.(*egressfirewallapi.EgressFirewall))
+		},
+		DeleteFunc: func(obj interface{}) {
+			egressFirewall, ok := obj.(*egressfirewallapi.EgressFirewall)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					return
+				}
+				if egressFirewall, ok = tombstone.Obj.(*egressfirewallapi.EgressFirewall); !ok {
+					return
+				}
+			}
+			s.deleteEgressFirewall(egressFirewall)
+		},
+	}, nil)
+	s.watchFactory.AddClusterEgressFirewallHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s.setClusterEgressFirewall(obj.(*egressfirewallapi.ClusterEgressFirewall))
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			s.setClusterEgressFirewall(newer.(*egressfirewallapi.ClusterEgressFirewall))
+		},
+		DeleteFunc: func(obj interface{}) {
+			clusterEgressFirewall, ok := obj.(*egressfirewallapi.ClusterEgressFirewall)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					return
+				}
+				if clusterEgressFirewall, ok = tombstone.Obj.(*egressfirewallapi.ClusterEgressFirewall); !ok {
+					return
+				}
+			}
+			s.deleteWildcardDNSNames(clusterEgressFirewall.Name)
+		},
+	}, nil)
+
+	go func() {
+		if err := listenDNSResponses(stopChan, s.handlePacket); err != nil {
+			klog.Errorf("Failed to snoop DNS responses, the wildcard dnsNames of EgressFirewall rules "+
+				"will not be learned on node %s: %v", s.nodeName, err)
+		}
+	}()
+
+	expiryTicker := time.NewTicker(egressDNSSnoopExpiryInterval)
+	defer expiryTicker.Stop()
+	for {
+		s.report()
+		// coalesce the changes learned from the DNS responses in between two reports
+		select {
+		case <-stopChan:
+			return
+		case <-time.After(egressDNSSnoopReportInterval):
+		}
+		select {
+		case <-stopChan:
+			return
+		case <-s.changed:
+		case <-expiryTicker.C:
+		}
+	}
+}
+
+// signalChanged wakes up Run to report the IPs of the wildcard dnsNames
+func (s *egressDNSSnooper) signalChanged() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *egressDNSSnooper) setEgressFirewall(egressFirewall *egressfirewallapi.EgressFirewall) {
+	s.setWildcardDNSNames(egressFirewall.Namespace+"/"+egressFirewall.Name, egressFirewall.Spec.Egress)
+}
+
+func (s *egressDNSSnooper) setClusterEgressFirewall(clusterEgressFirewall *egressfirewallapi.ClusterEgressFirewall) {
+	s.setWildcardDNSNames(clusterEgressFirewall.Name, clusterEgressFirewall.Spec.Egress)
+}
+
+// setWildcardDNSNames records the wildcard dnsNames of the rules of the EgressFirewall or
+// ClusterEgressFirewall with the given key
+func (s *egressDNSSnooper) setWildcardDNSNames(key string, rules []egressfirewallapi.EgressFirewallRule) {
+	var wildcardDNSNames []string
+	for _, rule := range rules {
+		if util.IsWildcardDNSName(rule.To.DNSName) {
+			wildcardDNSNames = append(wildcardDNSNames, rule.To.DNSName)
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if len(wildcardDNSNames) == 0 {
+		delete(s.wildcardDNSNames, key)
+	} else {
+		s.wildcardDNSNames[key] = wildcardDNSNames
+	}
+	s.signalChanged()
+}
+
+func (s *egressDNSSnooper) deleteEgressFirewall(egressFirewall *egressfirewallapi.EgressFirewall) {
+	s.deleteWildcardDNSNames(egressFirewall.Namespace + "/" + egressFirewall.Name)
+}
+
+func (s *egressDNSSnooper) deleteWildcardDNSNames(key string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.wildcardDNSNames, key)
+	s.signalChanged()
+}
+
+// isDNSServer returns true if ip is a cluster IP of the cluster DNS service
+func (s *egressDNSSnooper) isDNSServer(ip net.IP) bool {
+	service, err := s.watchFactory.GetService(s.dnsServiceNamespace, s.dnsServiceName)
+	if err != nil {
+		return false
+	}
+	for _, clusterIP := range util.GetClusterIPs(service) {
+		if ip.Equal(net.ParseIP(clusterIP)) {
+			return true
+		}
+	}
+	return false
+}
+
+// handlePacket learns the IPs of the wildcard dnsNames from a DNS response sent by the
+// cluster DNS service, packet starts at its IP header
+func (s *egressDNSSnooper) handlePacket(packet []byte) {
+	srcIP, payload := parseUDPPacket(packet)
+	if srcIP == nil || !s.isDNSServer(srcIP) {
+		return
+	}
+	msg := new(dns.Msg)
+	if err := msg.Unpack(payload); err != nil || !msg.Response || msg.Rcode != dns.RcodeSuccess {
+		return
+	}
+	s.handleDNSResponse(msg, time.Now())
+}
+
+func (s *egressDNSSnooper) handleDNSResponse(msg *dns.Msg, now time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	for _, wildcardDNSNames := range s.wildcardDNSNames {
+		for _, wildcardDNSName := range wildcardDNSNames {
+			// the answers of a query for a matching name, possibly through CNAMEs
+			// outside of the domain, are all IPs of the wildcard dnsName
+			questionMatches := false
+			for _, question := range msg.Question {
+				if util.MatchWildcardDNSName(wildcardDNSName, question.Name) {
+					questionMatches = true
+				}
+			}
+			for _, answer := range msg.Answer {
+				var ip net.IP
+				switch record := answer.(type) {
+				case *dns.A:
+					ip = record.A
+				case *dns.AAAA:
+					ip = record.AAAA
+				default:
+					continue
+				}
+				if !questionMatches && !util.MatchWildcardDNSName(wildcardDNSName, answer.Header().Name) {
+					continue
+				}
+				ttl := time.Duration(answer.Header().Ttl) * time.Second
+				if ttl < egressDNSSnoopMinTTL {
+					ttl = egressDNSSnoopMinTTL
+				}
+				if s.dnsIPs[wildcardDNSName] == nil {
+					s.dnsIPs[wildcardDNSName] = make(map[string]time.Time)
+				}
+				previous, known := s.dnsIPs[wildcardDNSName][ip.String()]
+				if expires := now.Add(ttl); expires.After(previous) {
+					s.dnsIPs[wildcardDNSName][ip.String()] = expires
+				}
+				// refreshing the expiry of a known IP does not change the report
+				if !known {
+					s.signalChanged()
+				}
+			}
+		}
+	}
+}
+
+// getDNSIPs returns the current IPs of the wildcard dnsNames of the EgressFirewall rules,
+// and forgets the expired ones
+func (s *egressDNSSnooper) getDNSIPs(now time.Time) map[string][]string {
+	s.Lock()
+	defer s.Unlock()
+	wildcardDNSNames := make(map[string]bool)
+	for _, names := range s.wildcardDNSNames {
+		for _, name := range names {
+			wildcardDNSNames[name] = true
+		}
+	}
+	dnsIPs := make(map[string][]string)
+	for wildcardDNSName, ips := range s.dnsIPs {
+		if !wildcardDNSNames[wildcardDNSName] {
+			delete(s.dnsIPs, wildcardDNSName)
+			continue
+		}
+		for ip, expires := range ips {
+			if now.After(expires) {
+				delete(ips, ip)
+				continue
+			}
+			dnsIPs[wildcardDNSName] = append(dnsIPs[wildcardDNSName], ip)
+		}
+		if len(ips) == 0 {
+			delete(s.dnsIPs, wildcardDNSName)
+		}
+		sort.Strings(dnsIPs[wildcardDNSName])
+	}
+	return dnsIPs
+}
+
+// report sets the node annotation with the current IPs of the wildcard dnsNames if they
+// changed since the last report
+func (s *egressDNSSnooper) report() {
+	dnsIPs := s.getDNSIPs(time.Now())
+	if s.reported != nil && reflect.DeepEqual(dnsIPs, s.reported) {
+		return
+	}
+	node := &kapi.Node{ObjectMeta: metav1.ObjectMeta{Name: s.nodeName}}
+	nodeAnnotator := kube.NewNodeAnnotator(s.kube, node)
+	if err := util.SetNodeEgressFirewallDNSIPs(nodeAnnotator, dnsIPs); err != nil {
+		klog.Errorf("Failed to set the egress firewall DNS IPs of node %s: %v", s.nodeName, err)
+		return
+	}
+	if err := nodeAnnotator.Run(); err != nil {
+		klog.Errorf("Failed to set the egress firewall DNS IPs of node %s: %v", s.nodeName, err)
+		return
+	}
+	s.reported = dnsIPs
+}
+
+// parseUDPPacket returns the source IP and the payload of an IPv4 or IPv6 UDP packet, or
+// nil if the packet cannot be parsed
+func parseUDPPacket(packet []byte) (net.IP, []byte) {
+	if len(packet) == 0 {
+		return nil, nil
+	}
+	var srcIP net.IP
+	var udp []byte
+	switch packet[0] >> 4 {
+	case 4:
+		headerLen := int(packet[0]&0x0f) * 4
+		if len(packet) < 20 || len(packet) < headerLen || packet[9] != 17 {
+			return nil, nil
+		}
+		srcIP = net.IP(packet[12:16])
+		udp = packet[headerLen:]
+	case 6:
+		// extension headers are not supported
+		if len(packet) < 40 || packet[6] != 17 {
+			return nil, nil
+		}
+		srcIP = net.IP(packet[8:24])
+		udp = packet[40:]
+	default:
+		return nil, nil
+	}
+	if len(udp) < 8 || binary.BigEndian.Uint16(udp[0:2]) != 53 {
+		return nil, nil
+	}
+	return srcIP, udp[8:]
+}
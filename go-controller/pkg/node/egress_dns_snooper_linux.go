@@ -0,0 +1,91 @@
+// +build linux
+
+package node
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// dnsResponseFilter is a classic BPF program accepting the UDP packets sent from port
+// 53, it runs on packets starting at their IP header
+var dnsResponseFilter = []unix.SockFilter{
+	// load the IP version
+	{Code: unix.BPF_LD | unix.BPF_B | unix.BPF_ABS, K: 0},
+	{Code: unix.BPF_ALU | unix.BPF_AND | unix.BPF_K, K: 0xf0},
+	{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: 0x60, Jt: 8},
+	{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: 0x40, Jf: 12},
+	// IPv4: UDP, first fragment, source port at the end of the variable length header
+	{Code: unix.BPF_LD | unix.BPF_B | unix.BPF_ABS, K: 9},
+	{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: unix.IPPROTO_UDP, Jf: 10},
+	{Code: unix.BPF_LD | unix.BPF_H | unix.BPF_ABS, K: 6},
+	{Code: unix.BPF_JMP | unix.BPF_JSET | unix.BPF_K, K: 0x1fff, Jt: 8},
+	{Code: unix.BPF_LDX | unix.BPF_B | unix.BPF_MSH, K: 0},
+	{Code: unix.BPF_LD | unix.BPF_H | unix.BPF_IND, K: 0},
+	{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: 53, Jt: 4, Jf: 5},
+	// IPv6: UDP without extension headers
+	{Code: unix.BPF_LD | unix.BPF_B | unix.BPF_ABS, K: 6},
+	{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: unix.IPPROTO_UDP, Jf: 3},
+	{Code: unix.BPF_LD | unix.BPF_H | unix.BPF_ABS, K: 40},
+	{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, K: 53, Jf: 1},
+	// accept the whole packet
+	{Code: unix.BPF_RET | unix.BPF_K, K: 0xffff},
+	// drop
+	{Code: unix.BPF_RET | unix.BPF_K, K: 0},
+}
+
+// listenDNSResponses calls handlePacket with the DNS responses sent by the node on any of
+// its interfaces, including the host side of the pod interfaces, until stopChan is closed
+func listenDNSResponses(stopChan <-chan struct{}, handlePacket func(packet []byte)) error {
+	// cooked packets start at their IP header
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_DGRAM, int(htons(unix.ETH_P_ALL)))
+	if err != nil {
+		return fmt.Errorf("failed to open packet socket: %v", err)
+	}
+	defer unix.Close(fd)
+	prog := unix.SockFprog{
+		Len:    uint16(len(dnsResponseFilter)),
+		Filter: &dnsResponseFilter[0],
+	}
+	if err := unix.SetsockoptSockFprog(fd, unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, &prog); err != nil {
+		return fmt.Errorf("failed to attach DNS response filter: %v", err)
+	}
+	// wake up regularly to check stopChan
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		return fmt.Errorf("failed to set packet socket receive timeout: %v", err)
+	}
+
+	buf := make([]byte, 65536)
+	for {
+		select {
+		case <-stopChan:
+			return nil
+		default:
+		}
+		n, from, err := unix.Recvfrom(fd, buf, 0)
+		if err == unix.EAGAIN || err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to receive DNS responses: %v", err)
+		}
+		if !isOutgoingPacket(from) {
+			continue
+		}
+		handlePacket(buf[:n])
+	}
+}
+
+// isOutgoingPacket returns true if the packet was sent by the node. The DNS responses
+// forwarded by OVN to the pods are sent on the host side of the pod interfaces, while the
+// packets received from the pods, which may carry a spoofed source IP, are not trusted
+func isOutgoingPacket(from unix.Sockaddr) bool {
+	sll, ok := from.(*unix.SockaddrLinklayer)
+	return ok && sll.Pkttype == unix.PACKET_OUTGOING
+}
+
+// htons converts a short from host to network byte order
+func htons(i uint16) uint16 {
+	return (i<<8)&0xff00 | i>>8
+}
@@ -0,0 +1,22 @@
+// +build linux
+
+package node
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/sys/unix"
+)
+
+var _ = Describe("Egress DNS snooper packet socket", func() {
+	It("only accepts the packets sent by the node", func() {
+		// a DNS response forwarded by OVN to a pod
+		Expect(isOutgoingPacket(&unix.SockaddrLinklayer{Ifindex: 10, Pkttype: unix.PACKET_OUTGOING})).To(BeTrue())
+		// a DNS response spoofed by a pod on its interface
+		Expect(isOutgoingPacket(&unix.SockaddrLinklayer{Ifindex: 10, Pkttype: unix.PACKET_HOST})).To(BeFalse())
+		Expect(isOutgoingPacket(&unix.SockaddrLinklayer{Ifindex: 10, Pkttype: unix.PACKET_OTHERHOST})).To(BeFalse())
+		Expect(isOutgoingPacket(&unix.SockaddrLinklayer{Ifindex: 10, Pkttype: unix.PACKET_BROADCAST})).To(BeFalse())
+		Expect(isOutgoingPacket(nil)).To(BeFalse())
+	})
+})
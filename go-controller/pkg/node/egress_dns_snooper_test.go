@@ -0,0 +1,131 @@
+package node
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newDNSResponse returns a DNS response to a query for name holding the given records
+func newDNSResponse(name string, records ...string) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), dns.TypeA)
+	msg.Response = true
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		Expect(err).NotTo(HaveOccurred())
+		msg.Answer = append(msg.Answer, rr)
+	}
+	return msg
+}
+
+// newUDPv4Packet returns an IPv4 UDP packet carrying payload
+func newUDPv4Packet(srcIP string, srcPort uint16, payload []byte) []byte {
+	packet := make([]byte, 28, 28+len(payload))
+	packet[0] = 0x45
+	binary.BigEndian.PutUint16(packet[2:4], uint16(28+len(payload)))
+	packet[8] = 64
+	packet[9] = 17
+	copy(packet[12:16], net.ParseIP(srcIP).To4())
+	copy(packet[16:20], net.ParseIP("10.244.0.5").To4())
+	binary.BigEndian.PutUint16(packet[20:22], srcPort)
+	binary.BigEndian.PutUint16(packet[22:24], 40000)
+	binary.BigEndian.PutUint16(packet[24:26], uint16(8+len(payload)))
+	return append(packet, payload...)
+}
+
+var _ = Describe("Egress DNS snooper", func() {
+	var snooper *egressDNSSnooper
+
+	BeforeEach(func() {
+		snooper = newEgressDNSSnooper("node1", nil, nil, "kube-system/kube-dns")
+		snooper.setEgressFirewall(&egressfirewallapi.EgressFirewall{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "namespace1"},
+			Spec: egressfirewallapi.EgressFirewallSpec{
+				Egress: []egressfirewallapi.EgressFirewallRule{
+					{
+						Type: egressfirewallapi.EgressFirewallRuleAllow,
+						To:   egressfirewallapi.EgressFirewallDestination{DNSName: "*.example.com"},
+					},
+					{
+						Type: egressfirewallapi.EgressFirewallRuleAllow,
+						To:   egressfirewallapi.EgressFirewallDestination{DNSName: "www.example.org"},
+					},
+				},
+			},
+		})
+	})
+
+	It("parses the DNS responses", func() {
+		payload, err := newDNSResponse("www.example.com", "www.example.com. 60 IN A 1.2.3.4").Pack()
+		Expect(err).NotTo(HaveOccurred())
+
+		srcIP, udpPayload := parseUDPPacket(newUDPv4Packet("10.96.0.10", 53, payload))
+		Expect(srcIP.String()).To(Equal("10.96.0.10"))
+		Expect(udpPayload).To(Equal(payload))
+
+		srcIP, _ = parseUDPPacket(newUDPv4Packet("10.96.0.10", 5353, payload))
+		Expect(srcIP).To(BeNil())
+		srcIP, _ = parseUDPPacket(payload)
+		Expect(srcIP).To(BeNil())
+	})
+
+	It("learns the IPs of the wildcard dnsNames until they expire", func() {
+		now := time.Now()
+		snooper.handleDNSResponse(newDNSResponse("www.example.com",
+			"www.example.com. 60 IN CNAME cdn.example.net.",
+			"cdn.example.net. 60 IN A 1.2.3.4",
+			"cdn.example.net. 60 IN AAAA 2001:db8::1"), now)
+		snooper.handleDNSResponse(newDNSResponse("a.b.example.com",
+			"a.b.example.com. 3600 IN A 1.2.3.5"), now)
+		// neither the domain itself nor the non-wildcard dnsNames are learned
+		snooper.handleDNSResponse(newDNSResponse("example.com", "example.com. 60 IN A 1.2.3.6"), now)
+		snooper.handleDNSResponse(newDNSResponse("www.example.org", "www.example.org. 60 IN A 1.2.3.7"), now)
+
+		Expect(snooper.getDNSIPs(now)).To(Equal(map[string][]string{
+			"*.example.com": {"1.2.3.4", "1.2.3.5", "2001:db8::1"},
+		}))
+		// short TTLs are extended to egressDNSSnoopMinTTL
+		Expect(snooper.getDNSIPs(now.Add(egressDNSSnoopMinTTL - time.Second))).To(Equal(map[string][]string{
+			"*.example.com": {"1.2.3.4", "1.2.3.5", "2001:db8::1"},
+		}))
+		Expect(snooper.getDNSIPs(now.Add(egressDNSSnoopMinTTL + time.Second))).To(Equal(map[string][]string{
+			"*.example.com": {"1.2.3.5"},
+		}))
+		Expect(snooper.getDNSIPs(now.Add(time.Hour + time.Second))).To(BeEmpty())
+	})
+
+	It("signals only the newly learned IPs", func() {
+		now := time.Now()
+		// drain the signal of the EgressFirewall added by BeforeEach
+		Eventually(snooper.changed).Should(Receive())
+
+		snooper.handleDNSResponse(newDNSResponse("www.example.com", "www.example.com. 60 IN A 1.2.3.4"), now)
+		Expect(snooper.changed).To(Receive())
+
+		snooper.handleDNSResponse(newDNSResponse("www.example.com", "www.example.com. 60 IN A 1.2.3.4"), now.Add(time.Minute))
+		Expect(snooper.changed).NotTo(Receive())
+		snooper.handleDNSResponse(newDNSResponse("www.example.org", "www.example.org. 60 IN A 1.2.3.7"), now)
+		Expect(snooper.changed).NotTo(Receive())
+	})
+
+	It("forgets the IPs of the dnsNames no longer used", func() {
+		now := time.Now()
+		snooper.handleDNSResponse(newDNSResponse("www.example.com", "www.example.com. 60 IN A 1.2.3.4"), now)
+		Expect(snooper.getDNSIPs(now)).To(HaveLen(1))
+
+		snooper.deleteEgressFirewall(&egressfirewallapi.EgressFirewall{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "namespace1"},
+		})
+		Expect(snooper.getDNSIPs(now)).To(BeEmpty())
+		Expect(snooper.dnsIPs).To(BeEmpty())
+	})
+})
@@ -0,0 +1,84 @@
+package node
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	"k8s.io/klog/v2"
+)
+
+// egressIPHealthCheckWriteTimeout bounds the time spent answering a single probe
+const egressIPHealthCheckWriteTimeout = 5 * time.Second
+
+// egressIPHealthServer answers the egress IP reachability probes of ovnkube-master.
+// A probe is a TCP connection to which the server writes types.EgressIPHealthCheckResponseOK
+// if the node is healthy, or an error description otherwise, and then closes.
+type egressIPHealthServer struct {
+	listener net.Listener
+	// checkHealth returns an error if the node cannot serve egress IPs
+	checkHealth func() error
+}
+
+// newEgressIPHealthServer starts listening on port on all the node's addresses
+func newEgressIPHealthServer(port int, checkHealth func() error) (*egressIPHealthServer, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for egress IP health checks on port %d: %v", port, err)
+	}
+	return &egressIPHealthServer{
+		listener:    listener,
+		checkHealth: checkHealth,
+	}, nil
+}
+
+// Run answers probes until stopChan is closed
+func (s *egressIPHealthServer) Run(stopChan <-chan struct{}) {
+	go func() {
+		<-stopChan
+		s.listener.Close()
+	}()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-stopChan:
+				return
+			default:
+			}
+			klog.Errorf("Failed to accept egress IP health check connection: %v", err)
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *egressIPHealthServer) handle(conn net.Conn) {
+	defer conn.Close()
+	response := types.EgressIPHealthCheckResponseOK
+	if err := s.checkHealth(); err != nil {
+		klog.Warningf("Egress IP health check from %s failed: %v", conn.RemoteAddr(), err)
+		response = err.Error()
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(egressIPHealthCheckWriteTimeout)); err != nil {
+		klog.Errorf("Failed to set write deadline on egress IP health check connection: %v", err)
+		return
+	}
+	if _, err := conn.Write([]byte(response + "\n")); err != nil {
+		klog.V(5).Infof("Failed to answer egress IP health check from %s: %v", conn.RemoteAddr(), err)
+	}
+}
+
+// checkOVSDatapathHealth verifies that ovs-vswitchd is responsive and that the
+// datapath of the integration bridge is operational
+func checkOVSDatapathHealth() error {
+	_, stderr, err := util.RunOVSAppctl("dpif/show-dp-features", "br-int")
+	if err != nil {
+		return fmt.Errorf("OVS datapath of br-int is not operational, stderr: %q, err: %v", stderr, err)
+	}
+	return nil
+}
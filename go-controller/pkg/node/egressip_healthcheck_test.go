@@ -0,0 +1,53 @@
+package node
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+)
+
+var _ = Describe("Egress IP health check server", func() {
+	var (
+		healthErr error
+		server    *egressIPHealthServer
+		stopChan  chan struct{}
+	)
+
+	probe := func() string {
+		port := server.listener.Addr().(*net.TCPAddr).Port
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+		response, err := bufio.NewReader(conn).ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		return response
+	}
+
+	BeforeEach(func() {
+		var err error
+		healthErr = nil
+		stopChan = make(chan struct{})
+		server, err = newEgressIPHealthServer(0, func() error { return healthErr })
+		Expect(err).NotTo(HaveOccurred())
+		go server.Run(stopChan)
+	})
+
+	AfterEach(func() {
+		close(stopChan)
+	})
+
+	It("answers OK when the node is healthy", func() {
+		Expect(probe()).To(Equal(types.EgressIPHealthCheckResponseOK + "\n"))
+	})
+
+	It("answers the health check error when the node is unhealthy", func() {
+		healthErr = fmt.Errorf("OVS datapath of br-int is not operational")
+		Expect(probe()).To(Equal("OVS datapath of br-int is not operational\n"))
+	})
+})
@@ -43,6 +43,9 @@ type gateway struct {
 	nodeIPManager    *addressManager
 	initFunc         func() error
 	readyFunc        func() (bool, error)
+
+	// hostPortWatcher is used to handle the hostPorts of the pods on the node
+	hostPortWatcher informer.PodEventHandler
 }
 
 func (g *gateway) AddService(svc *kapi.Service) {
@@ -144,6 +147,30 @@ func (g *gateway) DeleteEndpoints(ep *kapi.Endpoints) {
 	}
 }
 
+func (g *gateway) AddPod(pod *kapi.Pod) {
+	if g.hostPortWatcher != nil {
+		g.hostPortWatcher.AddPod(pod)
+	}
+}
+
+func (g *gateway) UpdatePod(old, new *kapi.Pod) {
+	if g.hostPortWatcher != nil {
+		g.hostPortWatcher.UpdatePod(old, new)
+	}
+}
+
+func (g *gateway) DeletePod(pod *kapi.Pod) {
+	if g.hostPortWatcher != nil {
+		g.hostPortWatcher.DeletePod(pod)
+	}
+}
+
+func (g *gateway) SyncPods(objs []interface{}) {
+	if g.hostPortWatcher != nil {
+		g.hostPortWatcher.SyncPods(objs)
+	}
+}
+
 func (g *gateway) Init(wf factory.NodeWatchFactory) error {
 	err := g.initFunc()
 	if err != nil {
@@ -180,6 +207,24 @@ func (g *gateway) Init(wf factory.NodeWatchFactory) error {
 			g.DeleteEndpoints(ep)
 		},
 	}, nil)
+
+	if g.hostPortWatcher != nil {
+		wf.AddPodHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				g.AddPod(pod)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				oldPod := old.(*kapi.Pod)
+				newPod := new.(*kapi.Pod)
+				g.UpdatePod(oldPod, newPod)
+			},
+			DeleteFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				g.DeletePod(pod)
+			},
+		}, g.SyncPods)
+	}
 	return nil
 }
 
@@ -0,0 +1,262 @@
+// +build linux
+
+package node
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+
+	"github.com/coreos/go-iptables/iptables"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+const iptableHostPortChain = "OVN-KUBE-HOSTPORT"
+
+// podHostPort is a hostPort of a pod container
+type podHostPort struct {
+	desc string
+	// hostIP is "" when the hostPort is exposed on all the node IPs
+	hostIP        string
+	hostPort      int32
+	containerPort int32
+	protocol      kapi.Protocol
+}
+
+// podHostPorts is the configuration of the hostPorts of a pod
+type podHostPorts struct {
+	ports []podHostPort
+	rules []iptRule
+}
+
+// hostPortWatcher DNATs the traffic to the hostPorts of the pods scheduled
+// on the node to the pods, and claims the hostPorts on the node so that no
+// other process can use them. The traffic to the node IPs reaches the host
+// networking stack in both gateway modes, and leaves to the pods through the
+// management port.
+type hostPortWatcher struct {
+	port portManager
+	// pods with hostPorts, by namespace/name
+	pods     map[ktypes.NamespacedName]podHostPorts
+	podsLock sync.Mutex
+}
+
+func newHostPortWatcher(recorder record.EventRecorder) (*hostPortWatcher, error) {
+	localAddrSet, err := getLocalAddrs()
+	if err != nil {
+		return nil, err
+	}
+	if err := initHostPortIPTables(); err != nil {
+		return nil, err
+	}
+	return &hostPortWatcher{
+		port: newLocalPortManager(recorder, localAddrSet),
+		pods: map[ktypes.NamespacedName]podHostPorts{},
+	}, nil
+}
+
+func initHostPortIPTables() error {
+	rules := []iptRule{}
+	for _, proto := range clusterIPTablesProtocols() {
+		ipt, err := util.GetIPTablesHelper(proto)
+		if err != nil {
+			return err
+		}
+		if err := ipt.NewChain("nat", iptableHostPortChain); err != nil {
+			klog.V(5).Infof("Chain: \"%s\" in table: \"%s\" already exists, skipping creation", "nat", iptableHostPortChain)
+		}
+		rules = append(rules, getSharedGatewayInitRules(iptableHostPortChain, proto)...)
+	}
+	if err := addIptRules(rules); err != nil {
+		return fmt.Errorf("failed to handle iptables rules %v: %v", rules, err)
+	}
+	return nil
+}
+
+// getHostPortIPTRules returns the rules DNATing the traffic to the hostPort to the pod IP,
+// none if the hostPort is exposed on a host IP of the other family.
+func getHostPortIPTRules(hostPort podHostPort, podIP string) []iptRule {
+	isIPv6 := utilnet.IsIPv6String(podIP)
+	if hostPort.hostIP != "" && utilnet.IsIPv6String(hostPort.hostIP) != isIPv6 {
+		return nil
+	}
+	protocol := iptables.ProtocolIPv4
+	if isIPv6 {
+		protocol = iptables.ProtocolIPv6
+	}
+
+	args := []string{"-p", string(hostPort.protocol)}
+	if hostPort.hostIP != "" {
+		args = append(args, "-d", hostPort.hostIP)
+	} else {
+		args = append(args, "-m", "addrtype", "--dst-type", "LOCAL")
+	}
+	args = append(args,
+		"--dport", fmt.Sprintf("%d", hostPort.hostPort),
+		"-j", "DNAT",
+		"--to-destination", util.JoinHostPortInt32(podIP, hostPort.containerPort),
+	)
+	return []iptRule{
+		{
+			table:    "nat",
+			chain:    iptableHostPortChain,
+			args:     args,
+			protocol: protocol,
+		},
+	}
+}
+
+// getPodHostPorts returns the hostPorts of the pod and the rules DNATing them to the pod.
+// Host networked pods listen directly on their hostPorts and completed pods have released
+// theirs, so neither needs any configuration.
+func getPodHostPorts(pod *kapi.Pod) podHostPorts {
+	var config podHostPorts
+	if !util.PodWantsNetwork(pod) || pod.Status.Phase == kapi.PodSucceeded || pod.Status.Phase == kapi.PodFailed {
+		return config
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerPort.HostPort <= 0 {
+				continue
+			}
+			protocol := containerPort.Protocol
+			if protocol == "" {
+				protocol = kapi.ProtocolTCP
+			}
+			if err := util.ValidatePort(protocol, containerPort.HostPort); err != nil {
+				klog.Errorf("Skipping hostPort of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+			hostIP := containerPort.HostIP
+			if ip := net.ParseIP(hostIP); ip == nil || ip.IsUnspecified() {
+				hostIP = ""
+			}
+			config.ports = append(config.ports, podHostPort{
+				desc:          getHostPortDescription(containerPort.Name, pod),
+				hostIP:        hostIP,
+				hostPort:      containerPort.HostPort,
+				containerPort: containerPort.ContainerPort,
+				protocol:      protocol,
+			})
+		}
+	}
+	if len(config.ports) == 0 {
+		return config
+	}
+
+	podIPs, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		// the pod isn't wired yet, an update will come with its IPs
+		klog.V(5).Infof("Skipping hostPorts of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return podHostPorts{}
+	}
+	for _, hostPort := range config.ports {
+		for _, podIP := range podIPs {
+			config.rules = append(config.rules, getHostPortIPTRules(hostPort, podIP.String())...)
+		}
+	}
+	return config
+}
+
+// getHostPortDescription follows the format of the LocalPorts descriptions of the services:
+// "hostPort for namespace/name[:portName]"
+func getHostPortDescription(portName string, pod *kapi.Pod) string {
+	podName := ktypes.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	if len(portName) == 0 {
+		return fmt.Sprintf("%s %s", hostPortDescr, podName.String())
+	}
+	return fmt.Sprintf("%s %s:%s", hostPortDescr, podName.String(), portName)
+}
+
+func podReference(pod *kapi.Pod) *kapi.ObjectReference {
+	return &kapi.ObjectReference{
+		Kind:      "Pod",
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+	}
+}
+
+// addPodHostPorts claims the hostPorts of a pod and DNATs them to the pod.
+// Must be called with the podsLock held.
+func (h *hostPortWatcher) addPodHostPorts(pod *kapi.Pod, config podHostPorts) {
+	if len(config.rules) == 0 {
+		return
+	}
+	owner := podReference(pod)
+	for _, hostPort := range config.ports {
+		if err := h.port.open(hostPort.desc, hostPort.hostIP, hostPort.hostPort, hostPort.protocol, owner); err != nil {
+			klog.Errorf("Error claiming hostPort %d for pod: %s/%s: %v", hostPort.hostPort, pod.Namespace, pod.Name, err)
+		}
+	}
+	if err := addIptRules(config.rules); err != nil {
+		klog.Errorf("Error adding hostPort rules for pod: %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	h.pods[ktypes.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = config
+}
+
+// deletePodHostPorts removes the DNAT of the hostPorts of a pod and releases them.
+// Must be called with the podsLock held.
+func (h *hostPortWatcher) deletePodHostPorts(pod *kapi.Pod) {
+	key := ktypes.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+	config, ok := h.pods[key]
+	if !ok {
+		return
+	}
+	if err := delIptRules(config.rules); err != nil {
+		klog.Errorf("Error removing hostPort rules for pod: %s/%s: %v", pod.Namespace, pod.Name, err)
+	}
+	owner := podReference(pod)
+	for _, hostPort := range config.ports {
+		if err := h.port.close(hostPort.desc, hostPort.hostIP, hostPort.hostPort, hostPort.protocol, owner); err != nil {
+			klog.Errorf("Error removing hostPort %d claim for pod: %s/%s: %v", hostPort.hostPort, pod.Namespace, pod.Name, err)
+		}
+	}
+	delete(h.pods, key)
+}
+
+func (h *hostPortWatcher) AddPod(pod *kapi.Pod) {
+	config := getPodHostPorts(pod)
+	h.podsLock.Lock()
+	defer h.podsLock.Unlock()
+	h.deletePodHostPorts(pod)
+	h.addPodHostPorts(pod, config)
+}
+
+func (h *hostPortWatcher) UpdatePod(old, new *kapi.Pod) {
+	config := getPodHostPorts(new)
+	h.podsLock.Lock()
+	defer h.podsLock.Unlock()
+	if existing, ok := h.pods[ktypes.NamespacedName{Namespace: new.Namespace, Name: new.Name}]; ok && reflect.DeepEqual(existing, config) {
+		return
+	}
+	h.deletePodHostPorts(old)
+	h.addPodHostPorts(new, config)
+}
+
+func (h *hostPortWatcher) DeletePod(pod *kapi.Pod) {
+	h.podsLock.Lock()
+	defer h.podsLock.Unlock()
+	h.deletePodHostPorts(pod)
+}
+
+// SyncPods removes the stale hostPort rules of the pods deleted while ovnkube-node was down
+func (h *hostPortWatcher) SyncPods(pods []interface{}) {
+	keepIPTRules := []iptRule{}
+	for _, obj := range pods {
+		pod, ok := obj.(*kapi.Pod)
+		if !ok {
+			klog.Errorf("Spurious object in syncPods: %v", obj)
+			continue
+		}
+		keepIPTRules = append(keepIPTRules, getPodHostPorts(pod).rules...)
+	}
+	recreateIPTRules("nat", iptableHostPortChain, keepIPTRules)
+}
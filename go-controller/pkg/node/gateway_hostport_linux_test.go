@@ -0,0 +1,144 @@
+package node
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/utils/net"
+)
+
+func newHostPortPod(name string, hostNetwork bool, ports ...v1.ContainerPort) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "namespace1"},
+		Spec: v1.PodSpec{
+			NodeName:    "node1",
+			HostNetwork: hostNetwork,
+			Containers: []v1.Container{{
+				Name:  "containerName",
+				Image: "containerImage",
+				Ports: ports,
+			}},
+		},
+		Status: v1.PodStatus{
+			Phase:  v1.PodRunning,
+			PodIPs: []v1.PodIP{{IP: "10.244.0.5"}, {IP: "fd00:10:244::5"}},
+		},
+	}
+}
+
+var _ = Describe("Node HostPort Operations", func() {
+	var (
+		iptV4, iptV6 util.IPTablesHelper
+		fakePort     *fakePortManager
+		h            *hostPortWatcher
+	)
+
+	BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.IPv4Mode = true
+		config.IPv6Mode = true
+
+		iptV4, iptV6 = util.SetFakeIPTablesHelpers()
+		Expect(initHostPortIPTables()).To(Succeed())
+		fakePort = &fakePortManager{tPortsMap: map[utilnet.LocalPort]bool{}}
+		h = &hostPortWatcher{
+			port: fakePort,
+			pods: map[ktypes.NamespacedName]podHostPorts{},
+		}
+	})
+
+	expectHostPortRules := func(v4Rules, v6Rules []string) {
+		for _, ipt := range []struct {
+			helper util.IPTablesHelper
+			rules  []string
+		}{{iptV4, v4Rules}, {iptV6, v6Rules}} {
+			expectedTables := map[string]util.FakeTable{
+				"nat": {
+					"PREROUTING":        []string{"-j OVN-KUBE-HOSTPORT"},
+					"OUTPUT":            []string{"-j OVN-KUBE-HOSTPORT"},
+					"OVN-KUBE-HOSTPORT": ipt.rules,
+				},
+			}
+			Expect(ipt.helper.(*util.FakeIPTables).MatchState(expectedTables)).To(Succeed())
+		}
+	}
+
+	It("DNATs the hostPorts of a pod to the pod and claims them", func() {
+		pod := newHostPortPod("pod1", false,
+			v1.ContainerPort{Name: "http", ContainerPort: 8080, HostPort: 80, Protocol: v1.ProtocolTCP},
+			v1.ContainerPort{ContainerPort: 53, HostPort: 5353, HostIP: "127.0.0.1", Protocol: v1.ProtocolUDP},
+			v1.ContainerPort{ContainerPort: 9090, Protocol: v1.ProtocolTCP},
+		)
+		fakePort.tPortOpen = []int32{80, 5353}
+		fakePort.tProtocolOpen = []v1.Protocol{v1.ProtocolTCP, v1.ProtocolUDP}
+		fakePort.tIPOpen = []string{"", "127.0.0.1"}
+
+		h.AddPod(pod)
+		expectHostPortRules(
+			[]string{
+				"-p UDP -d 127.0.0.1 --dport 5353 -j DNAT --to-destination 10.244.0.5:53",
+				"-p TCP -m addrtype --dst-type LOCAL --dport 80 -j DNAT --to-destination 10.244.0.5:8080",
+			},
+			[]string{
+				"-p TCP -m addrtype --dst-type LOCAL --dport 80 -j DNAT --to-destination [fd00:10:244::5]:8080",
+			},
+		)
+		Expect(fakePort.tPortOpenCount).To(Equal(2))
+		Expect(fakePort.tPortsMap).To(HaveLen(2))
+
+		// unrelated updates don't touch the claims
+		h.UpdatePod(pod, pod.DeepCopy())
+		Expect(fakePort.tPortOpenCount).To(Equal(2))
+
+		fakePort.tPortClose = []int32{80, 5353}
+		h.DeletePod(pod)
+		expectHostPortRules([]string{}, []string{})
+		Expect(fakePort.tPortCloseCount).To(Equal(2))
+		Expect(fakePort.tPortsMap).To(BeEmpty())
+	})
+
+	It("waits for the pod IPs and ignores host networked and completed pods", func() {
+		port := v1.ContainerPort{ContainerPort: 8080, HostPort: 80, Protocol: v1.ProtocolTCP}
+		pod := newHostPortPod("pod1", false, port)
+		pod.Status.PodIPs = nil
+
+		h.AddPod(pod)
+		h.AddPod(newHostPortPod("pod2", true, port))
+		expectHostPortRules([]string{}, []string{})
+		Expect(fakePort.tPortsMap).To(BeEmpty())
+
+		wiredPod := newHostPortPod("pod1", false, port)
+		wiredPod.Status.PodIPs = []v1.PodIP{{IP: "10.244.0.5"}}
+		h.UpdatePod(pod, wiredPod)
+		expectHostPortRules(
+			[]string{"-p TCP -m addrtype --dst-type LOCAL --dport 80 -j DNAT --to-destination 10.244.0.5:8080"},
+			[]string{},
+		)
+		Expect(fakePort.tPortsMap).To(HaveLen(1))
+
+		completedPod := wiredPod.DeepCopy()
+		completedPod.Status.Phase = v1.PodSucceeded
+		h.UpdatePod(wiredPod, completedPod)
+		expectHostPortRules([]string{}, []string{})
+		Expect(fakePort.tPortsMap).To(BeEmpty())
+	})
+
+	It("removes the stale hostPort rules on startup", func() {
+		pod := newHostPortPod("pod1", false,
+			v1.ContainerPort{ContainerPort: 8080, HostPort: 80, Protocol: v1.ProtocolTCP})
+		stalePod := newHostPortPod("pod2", false,
+			v1.ContainerPort{ContainerPort: 8080, HostPort: 81, Protocol: v1.ProtocolTCP})
+		Expect(addIptRules(getPodHostPorts(stalePod).rules)).To(Succeed())
+
+		h.SyncPods([]interface{}{pod})
+		expectHostPortRules(
+			[]string{"-p TCP -m addrtype --dst-type LOCAL --dport 80 -j DNAT --to-destination 10.244.0.5:8080"},
+			[]string{"-p TCP -m addrtype --dst-type LOCAL --dport 80 -j DNAT --to-destination [fd00:10:244::5]:8080"},
+		)
+	})
+})
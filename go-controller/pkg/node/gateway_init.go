@@ -342,6 +342,13 @@ func (n *OvnNode) initGateway(subnets []*net.IPNet, nodeAnnotator kube.Annotator
 	if portClaimWatcher != nil {
 		gw.portClaimWatcher = portClaimWatcher
 	}
+	if config.Gateway.Mode != config.GatewayModeDisabled && config.OvnKubeNode.Mode == types.NodeModeFull {
+		hostPortWatcher, err := newHostPortWatcher(n.recorder)
+		if err != nil {
+			return err
+		}
+		gw.hostPortWatcher = hostPortWatcher
+	}
 	initGw := func() error {
 		return gw.Init(n.watchFactory)
 	}
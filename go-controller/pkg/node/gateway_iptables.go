@@ -352,6 +352,33 @@ func recreateIPTRules(table, chain string, keepIPTRules []iptRule) {
 	}
 }
 
+// restrictToSourceRanges restricts the rules to the traffic coming from the loadBalancerSourceRanges
+// of a LoadBalancer service, by repeating them for each of the ranges of the IP family. There are no
+// rules left if the family has no ranges. OVN enforces the same ranges for the traffic it receives.
+func restrictToSourceRanges(service *kapi.Service, isIPv6 bool, rules []iptRule) []iptRule {
+	if service.Spec.Type != kapi.ServiceTypeLoadBalancer || len(service.Spec.LoadBalancerSourceRanges) == 0 {
+		return rules
+	}
+	ranges := []string{}
+	for _, sourceRange := range service.Spec.LoadBalancerSourceRanges {
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(sourceRange))
+		if err != nil || utilnet.IsIPv6CIDR(cidr) != isIPv6 {
+			continue
+		}
+		ranges = append(ranges, cidr.String())
+	}
+
+	restricted := make([]iptRule, 0, len(rules)*len(ranges))
+	for _, rule := range rules {
+		for _, sourceRange := range ranges {
+			restrictedRule := rule
+			restrictedRule.args = append([]string{"-s", sourceRange}, rule.args...)
+			restricted = append(restricted, restrictedRule)
+		}
+	}
+	return restricted
+}
+
 // getGatewayIPTRules returns NodePort and ExternalIP iptables rules for service. If nodeIP is non-nil, then
 // only incoming traffic on that IP will be accepted for NodePort rules; otherwise incoming traffic on the NodePort
 // on all IPs will be accepted. If gatewayIP is "", then NodePort traffic will be DNAT'ed to the service port on
@@ -400,7 +427,8 @@ func getGatewayIPTRules(service *kapi.Service, gatewayIPs []string, hasLocalHost
 					// Port redirect host -> ExternalIP -> host
 					rules = append(rules, getExternalLocalIPTRules(svcPort, externalIP, int32(svcPort.TargetPort.IntValue()))...)
 				} else {
-					rules = append(rules, getExternalIPTRules(svcPort, externalIP, clusterIP)...)
+					rules = append(rules, restrictToSourceRanges(service, utilnet.IsIPv6String(externalIP),
+						getExternalIPTRules(svcPort, externalIP, clusterIP))...)
 				}
 			}
 		}
@@ -177,7 +177,8 @@ func (l *localPortWatcher) addService(svc *kapi.Service) error {
 		for _, port := range svc.Spec.Ports {
 			// Fix Azure/GCP LoadBalancers. They will forward traffic directly to the node with the
 			// dest address as the load-balancer ingress IP and port
-			iptRules = append(iptRules, getLoadBalancerIPTRules(svc, port, ip, port.Port)...)
+			iptRules = append(iptRules, restrictToSourceRanges(svc, isIPv6Service,
+				getLoadBalancerIPTRules(svc, port, ip, port.Port))...)
 
 			if port.NodePort > 0 {
 				if gatewayIP != "" {
@@ -194,7 +195,8 @@ func (l *localPortWatcher) addService(svc *kapi.Service) error {
 					continue
 				}
 
-				iptRules = append(iptRules, getExternalIPTRules(port, externalIP, ip)...)
+				iptRules = append(iptRules, restrictToSourceRanges(svc, isIPv6Service,
+					getExternalIPTRules(port, externalIP, ip))...)
 				klog.V(5).Infof("Adding iptables rules for service: %s with external IP: %s", svc.Name, externalIP)
 
 			}
@@ -224,7 +226,8 @@ func (l *localPortWatcher) deleteService(svc *kapi.Service) error {
 		for _, port := range svc.Spec.Ports {
 			// Fix Azure/GCP LoadBalancers. They will forward traffic directly to the node with the
 			// dest address as the load-balancer ingress IP and port
-			iptRules = append(iptRules, getLoadBalancerIPTRules(svc, port, ip, port.Port)...)
+			iptRules = append(iptRules, restrictToSourceRanges(svc, isIPv6Service,
+				getLoadBalancerIPTRules(svc, port, ip, port.Port))...)
 			if port.NodePort > 0 {
 				if gatewayIP != "" {
 					iptRules = append(iptRules, getNodePortIPTRules(port, ip, port.Port)...)
@@ -237,7 +240,8 @@ func (l *localPortWatcher) deleteService(svc *kapi.Service) error {
 					continue
 				}
 
-				iptRules = append(iptRules, getExternalIPTRules(port, externalIP, ip)...)
+				iptRules = append(iptRules, restrictToSourceRanges(svc, isIPv6Service,
+					getExternalIPTRules(port, externalIP, ip))...)
 				klog.V(5).Infof("Will delete iptables rule for ExternalIP: %s", externalIP)
 
 			}
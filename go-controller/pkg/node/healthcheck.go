@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/kube/healthcheck"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
@@ -193,6 +194,19 @@ func checkForStaleOVSRepresentorInterfaces(nodeName string, wf factory.ObjectCac
 			// and add this check to filter out pods assigned to other nodes. (e.g when ovnkube master and node
 			// share the same process)
 			expectedIfaceIds[util.GetIfaceId(pod.Namespace, pod.Name)] = true
+			if !config.OVNKubernetesFeature.EnableMultiNetwork {
+				continue
+			}
+			// pods attached to secondary networks have an OVS interface per network
+			podNetworks, err := util.UnmarshalPodAnnotationAllNetworks(pod.Annotations)
+			if err != nil {
+				continue
+			}
+			for nadName := range podNetworks {
+				if nadName != util.OvnPodDefaultNetwork {
+					expectedIfaceIds[util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName)] = true
+				}
+			}
 		}
 	}
 
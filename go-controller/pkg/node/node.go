@@ -380,10 +380,48 @@ func (n *OvnNode) Start(wg *sync.WaitGroup) error {
 		}
 	}
 
+	var egressIPHealthServer *egressIPHealthServer
+	if config.OVNKubernetesFeature.EnableEgressIP && config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort != 0 &&
+		config.OvnKubeNode.Mode != types.NodeModeSmartNICHost {
+		egressIPHealthServer, err = newEgressIPHealthServer(config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort,
+			checkOVSDatapathHealth)
+		if err != nil {
+			return err
+		}
+		if err := util.SetNodeEgressIPHealthCheckPort(nodeAnnotator, config.OVNKubernetesFeature.EgressIPNodeHealthCheckPort); err != nil {
+			return err
+		}
+	} else if _, err := util.ParseNodeEgressIPHealthCheckPort(node); !util.IsAnnotationNotSetError(err) {
+		util.DeleteNodeEgressIPHealthCheckPort(nodeAnnotator)
+	}
+
 	if err := nodeAnnotator.Run(); err != nil {
+		if egressIPHealthServer != nil {
+			egressIPHealthServer.listener.Close()
+		}
 		return fmt.Errorf("failed to set node %s annotations: %v", n.name, err)
 	}
 
+	if egressIPHealthServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			egressIPHealthServer.Run(n.stopChan)
+		}()
+	}
+
+	// learn the IPs of the wildcard dnsNames of the EgressFirewall rules from the DNS
+	// responses to the local pods
+	if config.OVNKubernetesFeature.EnableEgressFirewall && config.OvnKubeNode.Mode != types.NodeModeSmartNICHost {
+		egressDNSSnooper := newEgressDNSSnooper(n.name, n.Kube, n.watchFactory,
+			config.OVNKubernetesFeature.EgressFirewallDNSService)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			egressDNSSnooper.Run(n.stopChan)
+		}()
+	}
+
 	// Wait for management port and gateway resources to be created by the master
 	klog.Infof("Waiting for gateway and management port readiness...")
 	start := time.Now()
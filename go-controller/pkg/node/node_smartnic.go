@@ -46,7 +46,7 @@ func (n *OvnNode) watchSmartNicPods(isOvnUpEnabled bool) {
 					retryPods.Store(pod.UID, true)
 					return
 				}
-				podInterfaceInfo, err := cni.PodAnnotation2PodInfo(pod.Annotations, isOvnUpEnabled, true)
+				podInterfaceInfo, err := cni.PodAnnotation2PodInfo(pod.Annotations, util.OvnPodDefaultNetwork, 0, isOvnUpEnabled, true)
 				if err != nil {
 					retryPods.Store(pod.UID, true)
 					return
@@ -82,7 +82,7 @@ func (n *OvnNode) watchSmartNicPods(isOvnUpEnabled bool) {
 					klog.Infof("Failed to get rep name, %s. retrying", err)
 					return
 				}
-				podInterfaceInfo, err := cni.PodAnnotation2PodInfo(pod.Annotations, isOvnUpEnabled, true)
+				podInterfaceInfo, err := cni.PodAnnotation2PodInfo(pod.Annotations, util.OvnPodDefaultNetwork, 0, isOvnUpEnabled, true)
 				if err != nil {
 					return
 				}
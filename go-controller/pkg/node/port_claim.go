@@ -19,13 +19,15 @@ import (
 const (
 	nodePortDescr     = "nodePort for"
 	externalPortDescr = "externalIP for"
+	hostPortDescr     = "hostPort for"
 )
 
-type handler func(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error
+// handler claims or releases a port on behalf of its owner, a service or a pod
+type handler func(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error
 
 type portManager interface {
-	open(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error
-	close(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error
+	open(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error
+	close(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error
 }
 
 type localPortManager struct {
@@ -36,8 +38,8 @@ type localPortManager struct {
 	portOpener        utilnet.PortOpener
 }
 
-func (p *localPortManager) open(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error {
-	klog.V(5).Infof("Opening socket for service: %s/%s, port: %v and protocol %s", svc.Namespace, svc.Name, port, protocol)
+func (p *localPortManager) open(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error {
+	klog.V(5).Infof("Opening socket for %s: %s/%s, port: %v and protocol %s", owner.Kind, owner.Namespace, owner.Name, port, protocol)
 
 	if ip != "" {
 		if _, exists := p.localAddrSet[ip]; !exists {
@@ -57,7 +59,7 @@ func (p *localPortManager) open(desc string, ip string, port int32, protocol kap
 		portError = fmt.Errorf("unknown protocol %q", protocol)
 	}
 	if portError != nil {
-		p.emitPortClaimEvent(svc, port, portError)
+		p.emitPortClaimEvent(owner, port, portError)
 		return portError
 	}
 	klog.V(5).Infof("Opening socket for LocalPort %v", localPort)
@@ -65,11 +67,11 @@ func (p *localPortManager) open(desc string, ip string, port int32, protocol kap
 	defer p.activeSocketsLock.Unlock()
 
 	if _, exists := p.portsMap[*localPort]; exists {
-		return fmt.Errorf("error try to open socket for svc: %s/%s on port: %v again", svc.Namespace, svc.Name, port)
+		return fmt.Errorf("error try to open socket for %s: %s/%s on port: %v again", owner.Kind, owner.Namespace, owner.Name, port)
 	} else {
 		closeable, err := p.portOpener.OpenLocalPort(localPort)
 		if err != nil {
-			p.emitPortClaimEvent(svc, port, err)
+			p.emitPortClaimEvent(owner, port, err)
 			return err
 		}
 		p.portsMap[*localPort] = closeable
@@ -77,8 +79,8 @@ func (p *localPortManager) open(desc string, ip string, port int32, protocol kap
 	return nil
 }
 
-func (p *localPortManager) close(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error {
-	klog.V(5).Infof("Closing socket claimed for service: %s/%s and port: %v", svc.Namespace, svc.Name, port)
+func (p *localPortManager) close(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error {
+	klog.V(5).Infof("Closing socket claimed for %s: %s/%s and port: %v", owner.Kind, owner.Namespace, owner.Name, port)
 
 	if protocol != kapi.ProtocolTCP && protocol != kapi.ProtocolUDP {
 		return nil
@@ -91,7 +93,7 @@ func (p *localPortManager) close(desc string, ip string, port int32, protocol ka
 	}
 	localPort, err := utilnet.NewLocalPort(desc, ip, "", int(port), utilnet.Protocol(protocol))
 	if err != nil {
-		return fmt.Errorf("error localPort creation for svc: %s/%s on port: %v, err: %v", svc.Namespace, svc.Name, port, err)
+		return fmt.Errorf("error localPort creation for %s: %s/%s on port: %v, err: %v", owner.Kind, owner.Namespace, owner.Name, port, err)
 	}
 	klog.V(5).Infof("Closing socket for LocalPort %v", localPort)
 
@@ -100,23 +102,18 @@ func (p *localPortManager) close(desc string, ip string, port int32, protocol ka
 
 	if _, exists := p.portsMap[*localPort]; exists {
 		if err = p.portsMap[*localPort].Close(); err != nil {
-			return fmt.Errorf("error closing socket for svc: %s/%s on port: %v, err: %v", svc.Namespace, svc.Name, port, err)
+			return fmt.Errorf("error closing socket for %s: %s/%s on port: %v, err: %v", owner.Kind, owner.Namespace, owner.Name, port, err)
 		}
 		delete(p.portsMap, *localPort)
 		return nil
 	}
-	return fmt.Errorf("error closing socket for svc: %s/%s on port: %v, port was never opened...?", svc.Namespace, svc.Name, port)
+	return fmt.Errorf("error closing socket for %s: %s/%s on port: %v, port was never opened...?", owner.Kind, owner.Namespace, owner.Name, port)
 }
 
-func (p *localPortManager) emitPortClaimEvent(svc *kapi.Service, port int32, err error) {
-	serviceRef := kapi.ObjectReference{
-		Kind:      "Service",
-		Namespace: svc.Namespace,
-		Name:      svc.Name,
-	}
-	p.recorder.Eventf(&serviceRef, kapi.EventTypeWarning,
-		"PortClaim", "Service: %s/%s requires port: %v to be opened on node, but port cannot be opened, err: %v", svc.Namespace, svc.Name, port, err)
-	klog.Warningf("PortClaim for svc: %s/%s on port: %v, err: %v", svc.Namespace, svc.Name, port, err)
+func (p *localPortManager) emitPortClaimEvent(owner *kapi.ObjectReference, port int32, err error) {
+	p.recorder.Eventf(owner, kapi.EventTypeWarning,
+		"PortClaim", "%s: %s/%s requires port: %v to be opened on node, but port cannot be opened, err: %v", owner.Kind, owner.Namespace, owner.Name, port, err)
+	klog.Warningf("PortClaim for %s: %s/%s on port: %v, err: %v", owner.Kind, owner.Namespace, owner.Name, port, err)
 }
 
 type portClaimWatcher struct {
@@ -129,16 +126,20 @@ func newPortClaimWatcher(recorder record.EventRecorder) (*portClaimWatcher, erro
 		return nil, err
 	}
 	return &portClaimWatcher{
-		port: &localPortManager{
-			recorder:          recorder,
-			activeSocketsLock: sync.Mutex{},
-			portsMap:          make(map[utilnet.LocalPort]utilnet.Closeable),
-			localAddrSet:      localAddrSet,
-			portOpener:        &utilnet.ListenPortOpener,
-		},
+		port: newLocalPortManager(recorder, localAddrSet),
 	}, nil
 }
 
+func newLocalPortManager(recorder record.EventRecorder, localAddrSet map[string]net.IPNet) *localPortManager {
+	return &localPortManager{
+		recorder:          recorder,
+		activeSocketsLock: sync.Mutex{},
+		portsMap:          make(map[utilnet.LocalPort]utilnet.Closeable),
+		localAddrSet:      localAddrSet,
+		portOpener:        &utilnet.ListenPortOpener,
+	}
+}
+
 func (p *portClaimWatcher) AddService(svc *kapi.Service) {
 	if errors := handleService(svc, p.port.open); len(errors) > 0 {
 		for _, err := range errors {
@@ -216,8 +217,16 @@ func handlePort(desc string, svc *kapi.Service, ip string, port int32, protocol
 	if err := util.ValidatePort(protocol, port); err != nil {
 		return fmt.Errorf("invalid service port %s, err: %v", svc.Name, err)
 	}
-	if err := handler(desc, ip, port, protocol, svc); err != nil {
+	if err := handler(desc, ip, port, protocol, serviceReference(svc)); err != nil {
 		return err
 	}
 	return nil
 }
+
+func serviceReference(svc *kapi.Service) *kapi.ObjectReference {
+	return &kapi.ObjectReference{
+		Kind:      "Service",
+		Namespace: svc.Namespace,
+		Name:      svc.Name,
+	}
+}
@@ -36,7 +36,7 @@ func (f *fakePortOpener) Close() error {
 	return nil
 }
 
-func (p *fakePortManager) open(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error {
+func (p *fakePortManager) open(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error {
 	localPort, portError := newLocalPort(desc, ip, port, protocol)
 	if portError != nil {
 		return portError
@@ -56,7 +56,7 @@ func (p *fakePortManager) open(desc string, ip string, port int32, protocol kapi
 	return nil
 }
 
-func (p *fakePortManager) close(desc string, ip string, port int32, protocol kapi.Protocol, svc *kapi.Service) error {
+func (p *fakePortManager) close(desc string, ip string, port int32, protocol kapi.Protocol, owner *kapi.ObjectReference) error {
 	localPort, portError := newLocalPort(desc, ip, port, protocol)
 	if portError != nil {
 		return portError
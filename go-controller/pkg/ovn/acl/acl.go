@@ -3,6 +3,7 @@ package acl
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
@@ -11,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 )
@@ -151,3 +153,133 @@ func PurgeRejectRules(clusterPortGroupUUID string) error {
 	}
 	return nil
 }
+
+// DropACL is an ACL dropping the traffic it matches
+type DropACL struct {
+	UUID        string
+	Match       string
+	ExternalIDs map[string]string
+}
+
+// FindDropACLs returns the drop ACLs having all of the given external ids
+func FindDropACLs(externalIDs map[string]string) ([]DropACL, error) {
+	type ovnACLData struct {
+		Data [][]interface{}
+	}
+	args := []string{"--columns=_uuid,match,external_ids", "--format=json", "find", "acl", "action=drop"}
+	args = append(args, externalIDsArgs(externalIDs)...)
+	data, stderr, err := util.RunOVNNbctl(args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while querying drop ACLs with external ids %v: %s", externalIDs, stderr)
+	}
+	x := ovnACLData{}
+	if err := json.Unmarshal([]byte(data), &x); err != nil {
+		return nil, errors.Wrapf(err, "Unable to parse drop ACLs with external ids %v", externalIDs)
+	}
+
+	acls := []DropACL{}
+	for _, entry := range x.Data {
+		// ACL entry format is a slice: [["uuid", <uuid>], <match>, ["map", [[<key>, <value>], ...]]]
+		if len(entry) != 3 {
+			continue
+		}
+		uuidData, ok := entry[0].([]interface{})
+		if !ok || len(uuidData) != 2 {
+			continue
+		}
+		uuid, ok := uuidData[1].(string)
+		if !ok {
+			continue
+		}
+		match, ok := entry[1].(string)
+		if !ok {
+			continue
+		}
+		acl := DropACL{UUID: uuid, Match: match, ExternalIDs: map[string]string{}}
+		if mapData, ok := entry[2].([]interface{}); ok && len(mapData) == 2 {
+			pairs, _ := mapData[1].([]interface{})
+			for _, pair := range pairs {
+				kv, ok := pair.([]interface{})
+				if !ok || len(kv) != 2 {
+					continue
+				}
+				k, _ := kv[0].(string)
+				v, _ := kv[1].(string)
+				acl.ExternalIDs[k] = v
+			}
+		}
+		acls = append(acls, acl)
+	}
+	return acls, nil
+}
+
+// SyncDropACLs ensures that the drop ACLs with the given external ids are exactly the
+// ones with the given matches, applied to the port group and to the logical switches.
+// The ACLs which are no longer needed are removed from everywhere they are applied,
+// the database then garbage collects them.
+func SyncDropACLs(portGroupUUID string, switches []string, priority string, externalIDs map[string]string, matches []string) error {
+	existing, err := FindDropACLs(externalIDs)
+	if err != nil {
+		return err
+	}
+
+	wantMatches := sets.NewString(matches...)
+	kept := sets.NewString()
+	txn := util.NewNBTxn()
+	for _, acl := range existing {
+		if wantMatches.Has(acl.Match) && !kept.Has(acl.Match) {
+			kept.Insert(acl.Match)
+			// the ACL may not be applied to switches added in the meantime
+			args := []string{"add", "port_group", portGroupUUID, "acls", acl.UUID}
+			for _, ls := range switches {
+				args = append(args, "--", "add", "logical_switch", ls, "acls", acl.UUID)
+			}
+			if _, stderr, err := txn.AddOrCommit(args); err != nil {
+				return errors.Wrapf(err, "Failed to apply drop ACL %s, stderr: %q", acl.UUID, stderr)
+			}
+			continue
+		}
+
+		data, stderr, err := util.RunOVNNbctl("--format=csv", "--data=bare", "--no-headings", "--columns=_uuid",
+			"find", "logical_switch", fmt.Sprintf("acls{>=}%s", acl.UUID))
+		if err != nil {
+			return errors.Wrapf(err, "Error while querying logical switches with drop ACL %s: %s", acl.UUID, stderr)
+		}
+		args := []string{"--if-exists", "remove", "port_group", portGroupUUID, "acls", acl.UUID}
+		for _, ls := range strings.Fields(data) {
+			args = append(args, "--", "--if-exists", "remove", "logical_switch", ls, "acls", acl.UUID)
+		}
+		if _, stderr, err := txn.AddOrCommit(args); err != nil {
+			return errors.Wrapf(err, "Failed to remove drop ACL %s, stderr: %q", acl.UUID, stderr)
+		}
+	}
+
+	for i, match := range wantMatches.Difference(kept).List() {
+		id := fmt.Sprintf("@drop_acl_%d", i)
+		args := []string{"--id=" + id, "create", "acl", "direction=" + types.DirectionFromLPort, "priority=" + priority,
+			fmt.Sprintf("match=\"%s\"", match), "action=drop"}
+		args = append(args, externalIDsArgs(externalIDs)...)
+		args = append(args, "--", "add", "port_group", portGroupUUID, "acls", id)
+		for _, ls := range switches {
+			args = append(args, "--", "add", "logical_switch", ls, "acls", id)
+		}
+		if _, stderr, err := txn.AddOrCommit(args); err != nil {
+			return errors.Wrapf(err, "Failed to add drop ACL %q, stderr: %q", match, stderr)
+		}
+	}
+
+	if _, stderr, err := txn.Commit(); err != nil {
+		return errors.Wrapf(err, "Failed to sync drop ACLs with external ids %v, stderr: %q", externalIDs, stderr)
+	}
+	return nil
+}
+
+// externalIDsArgs returns the nbctl column arguments of the external ids, in a stable order
+func externalIDsArgs(externalIDs map[string]string) []string {
+	args := make([]string, 0, len(externalIDs))
+	for k, v := range externalIDs {
+		args = append(args, fmt.Sprintf("external_ids:%s=%s", k, v))
+	}
+	sort.Strings(args)
+	return args
+}
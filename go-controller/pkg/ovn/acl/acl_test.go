@@ -267,3 +267,74 @@ func TestAddRejectACLToPortGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncDropACLs(t *testing.T) {
+	externalIDs := map[string]string{"k8s.ovn.org/kind": "Service", "k8s.ovn.org/owner": "ns/svc"}
+	keptMatch := "ip4.dst == {5.5.5.5} && tcp.dst == 80 && ip4.src != {10.0.0.0/8}"
+	newMatch := "ip4.dst == {5.5.5.5} && tcp.dst == 443 && ip4.src != {10.0.0.0/8}"
+	staleMatch := "ip4.dst == {5.5.5.5} && tcp.dst == 8080 && ip4.src != {10.0.0.0/8}"
+
+	tests := []struct {
+		name     string
+		switches []string
+		matches  []string
+		ovnCmds  []ovntest.ExpectedCmd
+		wantErr  bool
+	}{
+		{
+			name:     "keep, remove and create ACLs",
+			switches: []string{"ext_node1"},
+			matches:  []string{keptMatch, newMatch},
+			ovnCmds: []ovntest.ExpectedCmd{
+				{
+					Cmd: "ovn-nbctl --timeout=15 --columns=_uuid,match,external_ids --format=json find acl action=drop external_ids:k8s.ovn.org/kind=Service external_ids:k8s.ovn.org/owner=ns/svc",
+					Output: `{"data":[` +
+						`[["uuid","kept-uuid"],"` + keptMatch + `",["map",[["k8s.ovn.org/kind","Service"],["k8s.ovn.org/owner","ns/svc"]]]],` +
+						`[["uuid","stale-uuid"],"` + staleMatch + `",["map",[["k8s.ovn.org/kind","Service"],["k8s.ovn.org/owner","ns/svc"]]]]` +
+						`],"headings":["_uuid","match","external_ids"]}`,
+				},
+				{
+					Cmd:    "ovn-nbctl --timeout=15 --format=csv --data=bare --no-headings --columns=_uuid find logical_switch acls{>=}stale-uuid",
+					Output: "ext_node1\next_node2",
+				},
+				{
+					Cmd: "ovn-nbctl --timeout=15 add port_group pg-uuid acls kept-uuid -- add logical_switch ext_node1 acls kept-uuid" +
+						" -- --if-exists remove port_group pg-uuid acls stale-uuid -- --if-exists remove logical_switch ext_node1 acls stale-uuid -- --if-exists remove logical_switch ext_node2 acls stale-uuid" +
+						" -- --id=@drop_acl_0 create acl direction=" + types.DirectionFromLPort + " priority=" + types.LoadBalancerSourceRangesPriority +
+						" match=\"" + newMatch + "\" action=drop external_ids:k8s.ovn.org/kind=Service external_ids:k8s.ovn.org/owner=ns/svc" +
+						" -- add port_group pg-uuid acls @drop_acl_0 -- add logical_switch ext_node1 acls @drop_acl_0",
+				},
+			},
+		},
+		{
+			name: "no ACLs",
+			ovnCmds: []ovntest.ExpectedCmd{
+				{
+					Cmd:    "ovn-nbctl --timeout=15 --columns=_uuid,match,external_ids --format=json find acl action=drop external_ids:k8s.ovn.org/kind=Service external_ids:k8s.ovn.org/owner=ns/svc",
+					Output: `{"data":[],"headings":["_uuid","match","external_ids"]}`,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fexec := ovntest.NewLooseCompareFakeExec()
+			for i := range tt.ovnCmds {
+				fexec.AddFakeCmd(&tt.ovnCmds[i])
+			}
+			err := util.SetExec(fexec)
+			if err != nil {
+				t.Errorf("fexec error: %v", err)
+			}
+
+			err = SyncDropACLs("pg-uuid", tt.switches, types.LoadBalancerSourceRangesPriority, externalIDs, tt.matches)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SyncDropACLs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !fexec.CalledMatchesExpected() {
+				t.Error(fexec.ErrorDesc())
+			}
+		})
+	}
+}
@@ -161,6 +161,25 @@ func (f *FakeAddressSetFactory) EventuallyExpectNoAddressSet(name string) {
 	}).Should(gomega.BeFalse())
 }
 
+// EventuallyExpectAddressSetWithIPs ensures the named address set eventually holds exactly the given set of IPs
+func (f *FakeAddressSetFactory) EventuallyExpectAddressSetWithIPs(name string, ips []string) {
+	name4, name6 := MakeAddressSetName(name)
+	gomega.Eventually(func() []string {
+		var current []string
+		for _, asName := range []string{name4, name6} {
+			as := f.getAddressSet(asName)
+			if as == nil {
+				continue
+			}
+			for ip := range as.ips {
+				current = append(current, ip)
+			}
+			as.Unlock()
+		}
+		return current
+	}).Should(gomega.ConsistOf(ips))
+}
+
 type removeFunc func(string)
 
 type fakeAddressSet struct {
@@ -0,0 +1,764 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// adminNetworkPolicyExternalID is the external id of the ACLs of an AdminNetworkPolicy,
+	// its value is the name of the policy
+	adminNetworkPolicyExternalID = "admin-network-policy"
+	// adminNetworkPolicyPrefix prefixes the readable names of the port groups and the
+	// address sets of the AdminNetworkPolicies
+	adminNetworkPolicyPrefix = "anp_"
+	// adminNetworkPolicyGenerationSeparator separates the name of a policy from the
+	// generation of its port group and address sets, it is not valid in a policy name
+	adminNetworkPolicyGenerationSeparator = "@"
+	// adminNetworkPolicyMaxRules is the maximum number of rules in each direction of an
+	// AdminNetworkPolicy, the ACLs of a policy use a band of priorities of that size
+	adminNetworkPolicyMaxRules = 100
+)
+
+type adminNetworkPolicy struct {
+	// RWMutex synchronizes operations on the policy.
+	// Operations that change the subject pods take a RLock,
+	// whereas operations that affect the policy take a Lock.
+	sync.RWMutex
+	name     string
+	priority int32
+	// generation is bumped on each update of the policy, the port group and address
+	// sets of a new generation are set up before the ones of the old generation are
+	// removed
+	generation int
+
+	ingressRules []*adminNetworkPolicyRule
+	egressRules  []*adminNetworkPolicyRule
+
+	// subjectPods are the pods the policy applies to
+	// map of string -> *lpInfo
+	subjectPods     sync.Map
+	subjectSelector *anpPodSelector
+
+	portGroupName string
+	// acls are the ACLs last set on the port group, nil if they were never set
+	acls []*anpACL
+
+	deleted bool
+}
+
+type adminNetworkPolicyRule struct {
+	policyType   knet.PolicyType
+	idx          int
+	action       anpapi.AdminNetworkPolicyRuleAction
+	portPolicies []*portPolicy
+
+	// peerAddressSet holds the IPs of the pods selected by the peers of the rule
+	peerAddressSet addressset.AddressSet
+	peerSelectors  []*anpPodSelector
+}
+
+// anpACL is an ACL of an AdminNetworkPolicy
+type anpACL struct {
+	name     string
+	priority int
+	match    string
+	action   string
+	rule     string
+}
+
+// anpPodSelector watches the pods matching a pod selector in the namespaces matching
+// a namespace selector, with a pod handler for each of the namespaces
+type anpPodSelector struct {
+	sync.Mutex
+	nsHandler   *factory.Handler
+	podHandlers map[string]*factory.Handler
+	stopped     bool
+}
+
+// getAdminNetworkPolicyReadableName returns the name the port group and address sets of a
+// generation of a policy are named after
+func getAdminNetworkPolicyReadableName(name string, generation int) string {
+	if generation == 0 {
+		return adminNetworkPolicyPrefix + name
+	}
+	return fmt.Sprintf("%s%s%s%d", adminNetworkPolicyPrefix, name, adminNetworkPolicyGenerationSeparator, generation)
+}
+
+// parseAdminNetworkPolicyReadableName returns the policy name and the generation of a
+// readable name, false if it is not the one of an admin network policy
+func parseAdminNetworkPolicyReadableName(readableName string) (string, int, bool) {
+	if !strings.HasPrefix(readableName, adminNetworkPolicyPrefix) {
+		return "", 0, false
+	}
+	name := strings.TrimPrefix(readableName, adminNetworkPolicyPrefix)
+	i := strings.LastIndex(name, adminNetworkPolicyGenerationSeparator)
+	if i < 0 {
+		return name, 0, true
+	}
+	generation, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:i], generation, true
+}
+
+func getAdminNetworkPolicyPortGroupName(name string, generation int) (string, string) {
+	readableName := getAdminNetworkPolicyReadableName(name, generation)
+	return readableName, hashedPortGroup(readableName)
+}
+
+func getAdminNetworkPolicyAddressSetName(name string, generation int, policyType knet.PolicyType, idx int) string {
+	return fmt.Sprintf("%s.%s.%d", getAdminNetworkPolicyReadableName(name, generation),
+		strings.ToLower(string(policyType)), idx)
+}
+
+func newAdminNetworkPolicyRule(policyType knet.PolicyType, idx int, action anpapi.AdminNetworkPolicyRuleAction,
+	ports []anpapi.AdminNetworkPolicyPort) (*adminNetworkPolicyRule, error) {
+	switch action {
+	case anpapi.AdminNetworkPolicyRuleActionAllow, anpapi.AdminNetworkPolicyRuleActionDeny,
+		anpapi.AdminNetworkPolicyRuleActionPass:
+	default:
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+	rule := &adminNetworkPolicyRule{
+		policyType: policyType,
+		idx:        idx,
+		action:     action,
+	}
+	for _, port := range ports {
+		pp := &portPolicy{protocol: port.Protocol, port: port.Port}
+		if _, err := pp.getL4Match(); err != nil {
+			return nil, err
+		}
+		rule.portPolicies = append(rule.portPolicies, pp)
+	}
+	return rule, nil
+}
+
+// aclPriority returns the priority of the ACL of the rule: the rules of the policies
+// with a lower priority value come first, then the rules in the order of the policy
+func (anp *adminNetworkPolicy) aclPriority(rule *adminNetworkPolicyRule) int {
+	startPriority, _ := strconv.Atoi(types.AdminNetworkPolicyStartPriority)
+	return startPriority - int(anp.priority)*adminNetworkPolicyMaxRules - rule.idx
+}
+
+// getMatch returns the match of the traffic the rule applies to, without the
+// exclusion of the traffic passed by the rules of higher priority
+func (anp *adminNetworkPolicy) getMatch(rule *adminNetworkPolicyRule) string {
+	var lportMatch, direction string
+	if rule.policyType == knet.PolicyTypeIngress {
+		lportMatch = fmt.Sprintf("outport == @%s", anp.portGroupName)
+		direction = "src"
+	} else {
+		lportMatch = fmt.Sprintf("inport == @%s", anp.portGroupName)
+		direction = "dst"
+	}
+	ipv4AS, ipv6AS := rule.peerAddressSet.GetASHashNames()
+	l3Match := getACLMatchAF(fmt.Sprintf("ip4.%s == $%s", direction, ipv4AS),
+		fmt.Sprintf("ip6.%s == $%s", direction, ipv6AS))
+	match := fmt.Sprintf("%s && %s", lportMatch, l3Match)
+
+	l4Matches := make([]string, 0, len(rule.portPolicies))
+	for _, pp := range rule.portPolicies {
+		// the ports were validated when the rule was created
+		l4Match, _ := pp.getL4Match()
+		l4Matches = append(l4Matches, l4Match)
+	}
+	switch len(l4Matches) {
+	case 0:
+	case 1:
+		match = fmt.Sprintf("%s && %s", match, l4Matches[0])
+	default:
+		match = fmt.Sprintf("%s && (%s)", match, strings.Join(l4Matches, " || "))
+	}
+	return match
+}
+
+// getAdminNetworkPolicyACLs returns the ACLs of each of the policies, keyed by policy name.
+// OVN has no ACL action skipping the ACLs of lower priority, so the traffic matching a
+// Pass rule is excluded from the match of the Allow and Deny rules of lower priority,
+// leaving it to the NetworkPolicy ACLs.
+func getAdminNetworkPolicyACLs(anps []*adminNetworkPolicy) map[string][]*anpACL {
+	type orderedRule struct {
+		anp  *adminNetworkPolicy
+		rule *adminNetworkPolicyRule
+	}
+	acls := make(map[string][]*anpACL, len(anps))
+	for _, policyType := range []knet.PolicyType{knet.PolicyTypeIngress, knet.PolicyTypeEgress} {
+		rules := []orderedRule{}
+		for _, anp := range anps {
+			policyRules := anp.ingressRules
+			if policyType == knet.PolicyTypeEgress {
+				policyRules = anp.egressRules
+			}
+			for _, rule := range policyRules {
+				rules = append(rules, orderedRule{anp, rule})
+			}
+		}
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].anp.aclPriority(rules[i].rule) > rules[j].anp.aclPriority(rules[j].rule)
+		})
+
+		passMatches := []string{}
+		for _, r := range rules {
+			match := r.anp.getMatch(r.rule)
+			if r.rule.action == anpapi.AdminNetworkPolicyRuleActionPass {
+				passMatches = append(passMatches, match)
+				continue
+			}
+			for _, passMatch := range passMatches {
+				match = fmt.Sprintf("%s && !(%s)", match, passMatch)
+			}
+			action := "allow-related"
+			if r.rule.action == anpapi.AdminNetworkPolicyRuleActionDeny {
+				action = "drop"
+			}
+			ruleName := fmt.Sprintf("%s_%d", strings.ToLower(string(policyType)), r.rule.idx)
+			acls[r.anp.name] = append(acls[r.anp.name], &anpACL{
+				name:     fmt.Sprintf("%s_%s", r.anp.name, ruleName),
+				priority: r.anp.aclPriority(r.rule),
+				match:    match,
+				action:   action,
+				rule:     ruleName,
+			})
+		}
+	}
+	return acls
+}
+
+// syncAdminNetworkPolicies removes the port groups and address sets of the
+// AdminNetworkPolicies deleted while ovnkube-master was down, and of the generations
+// of the existing policies replaced by a later one
+func (oc *Controller) syncAdminNetworkPolicies(adminNetworkPolicies []interface{}) {
+	expectedPolicies := sets.NewString()
+	for _, obj := range adminNetworkPolicies {
+		anp, ok := obj.(*anpapi.AdminNetworkPolicy)
+		if !ok {
+			klog.Errorf("Spurious object in syncAdminNetworkPolicies: %v", obj)
+			continue
+		}
+		expectedPolicies.Insert(anp.Name)
+	}
+
+	stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=external_ids",
+		"--format=table", "find", "port_group")
+	if err != nil {
+		klog.Errorf("Cannot sync admin network policies, failed to list the port groups, stderr: %q, err: %v", stderr, err)
+		return
+	}
+	// the generation of the port group of each policy, only the latest one is kept
+	generations := map[string]int{}
+	stalePortGroups := []string{}
+	for _, externalID := range strings.Fields(stdout) {
+		readableName := strings.TrimPrefix(externalID, "name=")
+		if readableName == externalID {
+			continue
+		}
+		name, generation, ok := parseAdminNetworkPolicyReadableName(readableName)
+		if !ok {
+			continue
+		}
+		if !expectedPolicies.Has(name) {
+			stalePortGroups = append(stalePortGroups, readableName)
+			continue
+		}
+		if current, ok := generations[name]; ok {
+			if generation < current {
+				stalePortGroups = append(stalePortGroups, readableName)
+				continue
+			}
+			stalePortGroups = append(stalePortGroups, getAdminNetworkPolicyReadableName(name, current))
+		}
+		generations[name] = generation
+	}
+	for _, readableName := range stalePortGroups {
+		// the ACLs of the policy are garbage collected with the port group
+		if err := deletePortGroup(oc.ovnNBClient, hashedPortGroup(readableName)); err != nil {
+			klog.Errorf("%v", err)
+		}
+	}
+	for name, generation := range generations {
+		oc.adminNetworkPolicyGenerations.Store(name, generation)
+	}
+
+	err = oc.addressSetFactory.ProcessEachAddressSet(func(addrSetName, namespaceName, policyName string) {
+		if !strings.HasPrefix(addrSetName, adminNetworkPolicyPrefix) {
+			return
+		}
+		// the address set names end with the direction and the index of the rule
+		parts := strings.Split(addrSetName, ".")
+		if len(parts) < 3 {
+			return
+		}
+		name, generation, ok := parseAdminNetworkPolicyReadableName(strings.Join(parts[:len(parts)-2], "."))
+		if !ok || (expectedPolicies.Has(name) && generations[name] == generation) {
+			return
+		}
+		if err := oc.addressSetFactory.DestroyAddressSetInBackingStore(addrSetName); err != nil {
+			klog.Errorf(err.Error())
+		}
+	})
+	if err != nil {
+		klog.Errorf("Error in syncing admin network policies: %v", err)
+	}
+}
+
+// checkAdminNetworkPolicyPriority returns an error if another policy has the priority of the
+// policy: the ACL priorities of the rules of the two policies would collide
+func (oc *Controller) checkAdminNetworkPolicyPriority(policy *anpapi.AdminNetworkPolicy) error {
+	var err error
+	oc.adminNetworkPolicies.Range(func(_, value interface{}) bool {
+		anp := value.(*adminNetworkPolicy)
+		if anp.name != policy.Name && anp.priority == policy.Spec.Priority {
+			err = fmt.Errorf("admin network policy %s has the same priority %d as admin network policy %s",
+				policy.Name, policy.Spec.Priority, anp.name)
+			return false
+		}
+		return true
+	})
+	return err
+}
+
+// addAdminNetworkPolicy creates the port group of the subject pods of the policy and the
+// address sets of the peer pods of its rules. The ACLs are set by syncAdminNetworkPolicyACLs.
+func (oc *Controller) addAdminNetworkPolicy(policy *anpapi.AdminNetworkPolicy) error {
+	klog.Infof("Adding admin network policy %s", policy.Name)
+
+	if _, loaded := oc.adminNetworkPolicies.Load(policy.Name); loaded {
+		return fmt.Errorf("admin network policy %s already exists", policy.Name)
+	}
+	if err := oc.checkAdminNetworkPolicyPriority(policy); err != nil {
+		return err
+	}
+	// reuse the port group and address sets of the policy found on startup
+	generation := 0
+	if obj, loaded := oc.adminNetworkPolicyGenerations.LoadAndDelete(policy.Name); loaded {
+		generation = obj.(int)
+	}
+	anp, err := oc.newAdminNetworkPolicy(policy, generation)
+	if err != nil {
+		return err
+	}
+	oc.adminNetworkPolicies.Store(policy.Name, anp)
+	return nil
+}
+
+// updateAdminNetworkPolicy replaces the policy with a new generation of it. The ACLs of the
+// old generation apply until the ones of the new generation are committed, then its port
+// group and address sets are removed.
+func (oc *Controller) updateAdminNetworkPolicy(oldPolicy, newPolicy *anpapi.AdminNetworkPolicy) error {
+	klog.Infof("Updating admin network policy %s", newPolicy.Name)
+
+	obj, loaded := oc.adminNetworkPolicies.Load(oldPolicy.Name)
+	if !loaded {
+		if err := oc.addAdminNetworkPolicy(newPolicy); err != nil {
+			return err
+		}
+		return oc.syncAdminNetworkPolicyACLs()
+	}
+	oldANP := obj.(*adminNetworkPolicy)
+	if err := oc.checkAdminNetworkPolicyPriority(newPolicy); err != nil {
+		return err
+	}
+	anp, err := oc.newAdminNetworkPolicy(newPolicy, oldANP.generation+1)
+	if err != nil {
+		return err
+	}
+	oc.adminNetworkPolicies.Store(newPolicy.Name, anp)
+	err = oc.syncAdminNetworkPolicyACLs()
+	oc.destroyAdminNetworkPolicy(oldANP)
+	return err
+}
+
+// newAdminNetworkPolicy creates the port group and the address sets of a generation of
+// the policy and starts the handlers filling them
+func (oc *Controller) newAdminNetworkPolicy(policy *anpapi.AdminNetworkPolicy, generation int) (*adminNetworkPolicy, error) {
+	if len(policy.Spec.Ingress) > adminNetworkPolicyMaxRules || len(policy.Spec.Egress) > adminNetworkPolicyMaxRules {
+		return nil, fmt.Errorf("admin network policy %s has more than %d rules in a direction",
+			policy.Name, adminNetworkPolicyMaxRules)
+	}
+	anp := &adminNetworkPolicy{
+		name:       policy.Name,
+		priority:   policy.Spec.Priority,
+		generation: generation,
+	}
+	for i, ingress := range policy.Spec.Ingress {
+		rule, err := newAdminNetworkPolicyRule(knet.PolicyTypeIngress, i, ingress.Action, ingress.Ports)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create ingress rule %d of admin network policy %s: %v", i, policy.Name, err)
+		}
+		anp.ingressRules = append(anp.ingressRules, rule)
+	}
+	for i, egress := range policy.Spec.Egress {
+		rule, err := newAdminNetworkPolicyRule(knet.PolicyTypeEgress, i, egress.Action, egress.Ports)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create egress rule %d of admin network policy %s: %v", i, policy.Name, err)
+		}
+		anp.egressRules = append(anp.egressRules, rule)
+	}
+
+	readableGroupName, portGroupName := getAdminNetworkPolicyPortGroupName(policy.Name, generation)
+	if _, err := createPortGroup(oc.ovnNBClient, readableGroupName, portGroupName); err != nil {
+		return nil, fmt.Errorf("failed to create port_group for admin network policy %s: %v", policy.Name, err)
+	}
+	anp.portGroupName = portGroupName
+
+	// The rules are set up before the pod handlers that use them are started
+	peers := make([][]anpapi.AdminNetworkPolicyPeer, 0, len(anp.ingressRules)+len(anp.egressRules))
+	for _, ingress := range policy.Spec.Ingress {
+		peers = append(peers, ingress.From)
+	}
+	for _, egress := range policy.Spec.Egress {
+		peers = append(peers, egress.To)
+	}
+	rules := append(append([]*adminNetworkPolicyRule{}, anp.ingressRules...), anp.egressRules...)
+	for _, rule := range rules {
+		as, err := oc.addressSetFactory.NewAddressSet(
+			getAdminNetworkPolicyAddressSetName(policy.Name, generation, rule.policyType, rule.idx), nil)
+		if err != nil {
+			oc.destroyAdminNetworkPolicy(anp)
+			return nil, fmt.Errorf("cannot create peer addressSet of admin network policy %s: %v", policy.Name, err)
+		}
+		rule.peerAddressSet = as
+	}
+
+	for i, rule := range rules {
+		for _, peer := range peers[i] {
+			r := rule
+			ps, err := oc.newANPPodSelector(&peer.NamespaceSelector, &peer.PodSelector,
+				func(pods ...*kapi.Pod) {
+					oc.handleANPPeerPodsAdd(r, pods...)
+				}, func(pod *kapi.Pod) {
+					oc.handleANPPeerPodDelete(r, pod)
+				})
+			if err != nil {
+				oc.destroyAdminNetworkPolicy(anp)
+				return nil, fmt.Errorf("cannot select the peers of admin network policy %s: %v", policy.Name, err)
+			}
+			rule.peerSelectors = append(rule.peerSelectors, ps)
+		}
+	}
+
+	ps, err := oc.newANPPodSelector(&policy.Spec.Subject.NamespaceSelector, &policy.Spec.Subject.PodSelector,
+		func(pods ...*kapi.Pod) {
+			oc.handleANPSubjectPodsAdd(anp, pods...)
+		}, func(pod *kapi.Pod) {
+			oc.handleANPSubjectPodDelete(anp, pod)
+		})
+	if err != nil {
+		oc.destroyAdminNetworkPolicy(anp)
+		return nil, fmt.Errorf("cannot select the subject of admin network policy %s: %v", policy.Name, err)
+	}
+	anp.Lock()
+	anp.subjectSelector = ps
+	anp.Unlock()
+	return anp, nil
+}
+
+func (oc *Controller) deleteAdminNetworkPolicy(policy *anpapi.AdminNetworkPolicy) {
+	klog.Infof("Deleting admin network policy %s", policy.Name)
+	obj, loaded := oc.adminNetworkPolicies.LoadAndDelete(policy.Name)
+	if !loaded {
+		return
+	}
+	oc.destroyAdminNetworkPolicy(obj.(*adminNetworkPolicy))
+}
+
+// destroyAdminNetworkPolicy stops the handlers of the policy and removes its port group
+// and address sets. The policy must not be in the policy map.
+func (oc *Controller) destroyAdminNetworkPolicy(anp *adminNetworkPolicy) {
+	anp.Lock()
+	anp.deleted = true
+	subjectSelector := anp.subjectSelector
+	anp.Unlock()
+
+	if subjectSelector != nil {
+		oc.stopANPPodSelector(subjectSelector)
+	}
+	for _, rule := range append(append([]*adminNetworkPolicyRule{}, anp.ingressRules...), anp.egressRules...) {
+		for _, ps := range rule.peerSelectors {
+			oc.stopANPPodSelector(ps)
+		}
+		if rule.peerAddressSet != nil {
+			if err := rule.peerAddressSet.Destroy(); err != nil {
+				klog.Errorf(err.Error())
+			}
+		}
+	}
+	if anp.portGroupName != "" {
+		if err := deletePortGroup(oc.ovnNBClient, anp.portGroupName); err != nil {
+			klog.Errorf("%v", err)
+		}
+	}
+}
+
+// syncAdminNetworkPolicyACLs sets the ACLs of all the AdminNetworkPolicies on their port
+// groups. The match of the ACLs of a policy depends on the Pass rules of the policies of
+// higher priority, so all the policies are synced whenever one of them changes. The ACLs
+// which changed are all replaced in a single transaction.
+func (oc *Controller) syncAdminNetworkPolicyACLs() error {
+	anps := []*adminNetworkPolicy{}
+	oc.adminNetworkPolicies.Range(func(_, value interface{}) bool {
+		anps = append(anps, value.(*adminNetworkPolicy))
+		return true
+	})
+	sort.Slice(anps, func(i, j int) bool {
+		return anps[i].name < anps[j].name
+	})
+
+	acls := getAdminNetworkPolicyACLs(anps)
+	// the policies are locked in the order of their names until the transaction is committed
+	changed := []*adminNetworkPolicy{}
+	for _, anp := range anps {
+		anp.Lock()
+		defer anp.Unlock()
+		if anp.deleted || (anp.acls != nil && reflect.DeepEqual(anp.acls, acls[anp.name])) {
+			continue
+		}
+		changed = append(changed, anp)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	txn := util.NewNBTxn()
+	aclCount := 0
+	for _, anp := range changed {
+		if err := anp.addSetACLsToTxn(txn, acls[anp.name], &aclCount); err != nil {
+			return err
+		}
+	}
+	if _, stderr, err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit the ACLs of admin network policies, stderr: %q, err: %v", stderr, err)
+	}
+	for _, anp := range changed {
+		anp.acls = acls[anp.name]
+		if anp.acls == nil {
+			anp.acls = []*anpACL{}
+		}
+	}
+	return nil
+}
+
+// addSetACLsToTxn adds the replacement of the ACLs of the port group of the policy to txn,
+// the ids of the created ACLs are numbered from aclCount. The policy must be locked.
+func (anp *adminNetworkPolicy) addSetACLsToTxn(txn *util.NBTxn, acls []*anpACL, aclCount *int) error {
+	_, stderr, err := txn.AddOrCommit([]string{"clear", "port_group", anp.portGroupName, "acls"})
+	if err != nil {
+		return fmt.Errorf("failed to clear the ACLs of admin network policy %s, stderr: %q, err: %v", anp.name, stderr, err)
+	}
+	for _, acl := range acls {
+		id := fmt.Sprintf("@acl%d", *aclCount)
+		*aclCount++
+		_, stderr, err := txn.AddOrCommit([]string{"--id=" + id, "create", "acl",
+			fmt.Sprintf("priority=%d", acl.priority),
+			fmt.Sprintf("direction=%s", types.DirectionToLPort),
+			fmt.Sprintf("match=\"%s\"", acl.match),
+			fmt.Sprintf("action=%s", acl.action),
+			fmt.Sprintf("name=%.63s", acl.name),
+			fmt.Sprintf("external-ids:%s=%s", adminNetworkPolicyExternalID, anp.name),
+			fmt.Sprintf("external-ids:rule=%s", acl.rule),
+			"--", "add", "port_group", anp.portGroupName, "acls", id})
+		if err != nil {
+			return fmt.Errorf("failed to create the ACLs of admin network policy %s, stderr: %q, err: %v", anp.name, stderr, err)
+		}
+	}
+	return nil
+}
+
+// handleANPSubjectPodsAdd adds the logical ports of the pods to the port group of the policy.
+// Pods without a logical port yet are added on update.
+func (oc *Controller) handleANPSubjectPodsAdd(anp *adminNetworkPolicy, pods ...*kapi.Pod) {
+	anp.RLock()
+	defer anp.RUnlock()
+	if anp.deleted {
+		return
+	}
+
+	ports := make([]*lpInfo, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		portInfo, err := oc.logicalPortCache.get(util.GetLogicalPortName(pod.Namespace, pod.Name))
+		if err != nil {
+			continue
+		}
+		if _, loaded := anp.subjectPods.LoadOrStore(portInfo.name, portInfo); loaded {
+			continue
+		}
+		ports = append(ports, portInfo)
+	}
+	if len(ports) == 0 {
+		return
+	}
+	if err := addToPortGroup(oc.ovnNBClient, anp.portGroupName, ports...); err != nil {
+		klog.Errorf("Failed to add pods to the port group of admin network policy %s: %v", anp.name, err)
+		for _, portInfo := range ports {
+			anp.subjectPods.Delete(portInfo.name)
+		}
+	}
+}
+
+func (oc *Controller) handleANPSubjectPodDelete(anp *adminNetworkPolicy, pod *kapi.Pod) {
+	anp.RLock()
+	defer anp.RUnlock()
+	if anp.deleted {
+		return
+	}
+
+	obj, loaded := anp.subjectPods.LoadAndDelete(util.GetLogicalPortName(pod.Namespace, pod.Name))
+	if !loaded {
+		return
+	}
+	portInfo := obj.(*lpInfo)
+	if err := deleteFromPortGroup(oc.ovnNBClient, anp.portGroupName, portInfo); err != nil {
+		klog.Errorf("Failed to delete logicalPort %s from the port group of admin network policy %s: %v",
+			portInfo.name, anp.name, err)
+	}
+}
+
+// handleANPPeerPodsAdd adds the IPs of the pods to the peer address set of the rule
+func (oc *Controller) handleANPPeerPodsAdd(rule *adminNetworkPolicyRule, pods ...*kapi.Pod) {
+	ips := make([]net.IP, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		podIPs, err := util.GetAllPodIPs(pod)
+		if err != nil {
+			continue
+		}
+		ips = append(ips, podIPs...)
+	}
+	if len(ips) == 0 {
+		return
+	}
+	if err := rule.peerAddressSet.AddIPs(ips); err != nil {
+		klog.Errorf("Failed to add pod IPs %v to address set %s: %v", ips, rule.peerAddressSet.GetName(), err)
+	}
+}
+
+func (oc *Controller) handleANPPeerPodDelete(rule *adminNetworkPolicyRule, pod *kapi.Pod) {
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	ips, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		return
+	}
+	if err := rule.peerAddressSet.DeleteIPs(ips); err != nil {
+		klog.Errorf("Failed to delete pod IPs %v from address set %s: %v", ips, rule.peerAddressSet.GetName(), err)
+	}
+}
+
+// newANPPodSelector calls addPods with the pods matching podSelector in the namespaces
+// matching namespaceSelector, and deletePod with the pods that stop matching them
+func (oc *Controller) newANPPodSelector(namespaceSelector, podSelector *metav1.LabelSelector,
+	addPods func(pods ...*kapi.Pod), deletePod func(pod *kapi.Pod)) (*anpPodSelector, error) {
+	nsSel, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	podSel, err := metav1.LabelSelectorAsSelector(podSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &anpPodSelector{podHandlers: map[string]*factory.Handler{}}
+	nsHandler := oc.watchFactory.AddFilteredNamespaceHandler("", nsSel,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				namespace := obj.(*kapi.Namespace)
+				ps.Lock()
+				stopped := ps.stopped || ps.podHandlers[namespace.Name] != nil
+				ps.Unlock()
+				if stopped {
+					return
+				}
+				// The AddFilteredPodHandler call calls addPods on the existing
+				// pods so we can't be holding the lock at this point
+				podHandler := oc.watchFactory.AddFilteredPodHandler(namespace.Name, podSel,
+					cache.ResourceEventHandlerFuncs{
+						AddFunc: func(obj interface{}) {
+							addPods(obj.(*kapi.Pod))
+						},
+						UpdateFunc: func(oldObj, newObj interface{}) {
+							addPods(newObj.(*kapi.Pod))
+						},
+						DeleteFunc: func(obj interface{}) {
+							deletePod(obj.(*kapi.Pod))
+						},
+					}, nil)
+				ps.Lock()
+				defer ps.Unlock()
+				if ps.stopped {
+					oc.watchFactory.RemovePodHandler(podHandler)
+					return
+				}
+				ps.podHandlers[namespace.Name] = podHandler
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+			},
+			DeleteFunc: func(obj interface{}) {
+				// The namespace was deleted or its labels no longer match
+				namespace := obj.(*kapi.Namespace)
+				ps.Lock()
+				podHandler := ps.podHandlers[namespace.Name]
+				delete(ps.podHandlers, namespace.Name)
+				stopped := ps.stopped
+				ps.Unlock()
+				if podHandler == nil || stopped {
+					return
+				}
+				oc.watchFactory.RemovePodHandler(podHandler)
+				pods, err := oc.watchFactory.GetPods(namespace.Name)
+				if err != nil {
+					klog.Errorf("Failed to get the pods of namespace %s: %v", namespace.Name, err)
+					return
+				}
+				for _, pod := range pods {
+					if podSel.Matches(labels.Set(pod.Labels)) {
+						deletePod(pod)
+					}
+				}
+			},
+		}, nil)
+	ps.Lock()
+	ps.nsHandler = nsHandler
+	ps.Unlock()
+	return ps, nil
+}
+
+func (oc *Controller) stopANPPodSelector(ps *anpPodSelector) {
+	ps.Lock()
+	defer ps.Unlock()
+	ps.stopped = true
+	if ps.nsHandler != nil {
+		oc.watchFactory.RemoveNamespaceHandler(ps.nsHandler)
+	}
+	for _, podHandler := range ps.podHandlers {
+		oc.watchFactory.RemovePodHandler(podHandler)
+	}
+	ps.podHandlers = map[string]*factory.Handler{}
+}
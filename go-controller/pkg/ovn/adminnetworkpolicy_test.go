@@ -0,0 +1,349 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	goovn "github.com/ebay/go-ovn"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	anpapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+	"github.com/urfave/cli/v2"
+
+	v1 "k8s.io/api/core/v1"
+	knet "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newAdminNetworkPolicyObject(name string, priority int32, subjectNamespace string,
+	ingress []anpapi.AdminNetworkPolicyIngressRule) *anpapi.AdminNetworkPolicy {
+	subject := anpapi.AdminNetworkPolicySubject{}
+	if subjectNamespace != "" {
+		subject.NamespaceSelector.MatchLabels = map[string]string{"name": subjectNamespace}
+	}
+	return &anpapi.AdminNetworkPolicy{
+		ObjectMeta: newObjectMeta(name, ""),
+		Spec: anpapi.AdminNetworkPolicySpec{
+			Priority: priority,
+			Subject:  subject,
+			Ingress:  ingress,
+		},
+	}
+}
+
+func anpACLCmd(portGroupName, policyName string, acls ...*anpACL) string {
+	cmd := "ovn-nbctl --timeout=15 clear port_group " + portGroupName + " acls"
+	for i, acl := range acls {
+		cmd += fmt.Sprintf(" -- --id=@acl%d create acl priority=%d direction=to-lport match=\"%s\" action=%s "+
+			"name=%s external-ids:admin-network-policy=%s external-ids:rule=%s -- add port_group %s acls @acl%d",
+			i, acl.priority, acl.match, acl.action, acl.name, policyName, acl.rule, portGroupName, i)
+	}
+	return cmd
+}
+
+var _ = ginkgo.Describe("OVN AdminNetworkPolicy Operations", func() {
+	const (
+		node1Name string = "node1"
+	)
+	var (
+		app     *cli.App
+		fakeOVN *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.OVNKubernetesFeature.EnableAdminNetworkPolicy = true
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOVN = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	portGroupExists := func(name string) func() bool {
+		return func() bool {
+			_, err := fakeOVN.ovnNBClient.PortGroupGet(name)
+			return err != goovn.ErrorNotFound
+		}
+	}
+
+	ginkgo.It("removes the port groups and address sets of deleted policies on startup", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find port_group",
+				Output: "name=anp_stale\nname=anp_existing\nname=namespace1_policy\n",
+			})
+			_, existingPG := getAdminNetworkPolicyPortGroupName("existing", 0)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				anpACLCmd(existingPG, "existing"),
+			})
+
+			existing := newAdminNetworkPolicyObject("existing", 10, "namespace1", nil)
+			fakeOVN.start(ctx, &anpapi.AdminNetworkPolicyList{Items: []anpapi.AdminNetworkPolicy{*existing}})
+
+			readableStalePG, stalePG := getAdminNetworkPolicyPortGroupName("stale", 0)
+			_, err := createPortGroup(fakeOVN.ovnNBClient, readableStalePG, stalePG)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			staleASName := getAdminNetworkPolicyAddressSetName("stale", 0, knet.PolicyTypeIngress, 0)
+			_, err = fakeOVN.asf.NewAddressSet(staleASName, nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			fakeOVN.controller.WatchAdminNetworkPolicy()
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(portGroupExists(stalePG)).Should(gomega.BeFalse())
+			staleASName4, _ := addressset.MakeAddressSetName(staleASName)
+			fakeOVN.asf.EventuallyExpectNoAddressSet(staleASName4)
+			gomega.Eventually(portGroupExists(existingPG)).Should(gomega.BeTrue())
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("evaluates the rules of the policies in the order of their priority", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find port_group",
+			})
+
+			namespace1 := *newNamespace("namespace1")
+			monitoring := *newNamespace("monitoring")
+			server := newPod(namespace1.Name, "server", node1Name, "10.128.1.3")
+			prometheus := newPod(monitoring.Name, "prometheus", node1Name, "10.128.1.4")
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespace1, monitoring}},
+				&v1.PodList{Items: []v1.Pod{*server, *prometheus}})
+			fakeOVN.controller.logicalPortCache.add(node1Name, util.GetLogicalPortName(server.Namespace, server.Name),
+				fakeUUID, ovntest.MustParseMAC("0a:58:0a:80:01:03"),
+				[]*net.IPNet{ovntest.MustParseIPNet("10.128.1.3/24")})
+			fakeOVN.controller.WatchAdminNetworkPolicy()
+
+			ginkgo.By("Creating a policy passing the traffic of the monitoring namespace and denying the rest on port 80")
+			denyPolicy := newAdminNetworkPolicyObject("deny", 10, namespace1.Name, []anpapi.AdminNetworkPolicyIngressRule{
+				{
+					Action: anpapi.AdminNetworkPolicyRuleActionPass,
+					From: []anpapi.AdminNetworkPolicyPeer{{
+						NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"name": monitoring.Name}},
+					}},
+				},
+				{
+					Action: anpapi.AdminNetworkPolicyRuleActionDeny,
+					From:   []anpapi.AdminNetworkPolicyPeer{{}},
+					Ports:  []anpapi.AdminNetworkPolicyPort{{Protocol: "TCP", Port: 80}},
+				},
+			})
+			_, denyPG := getAdminNetworkPolicyPortGroupName(denyPolicy.Name, 0)
+			passASName := getAdminNetworkPolicyAddressSetName(denyPolicy.Name, 0, knet.PolicyTypeIngress, 0)
+			passASv4, _ := addressset.MakeAddressSetHashNames(passASName)
+			denyASName := getAdminNetworkPolicyAddressSetName(denyPolicy.Name, 0, knet.PolicyTypeIngress, 1)
+			denyASv4, _ := addressset.MakeAddressSetHashNames(denyASName)
+			passMatch := fmt.Sprintf("outport == @%s && ip4.src == $%s", denyPG, passASv4)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				anpACLCmd(denyPG, denyPolicy.Name, &anpACL{
+					name:     "deny_ingress_1",
+					priority: 28999,
+					match:    fmt.Sprintf("outport == @%s && ip4.src == $%s && tcp && tcp.dst==80 && !(%s)", denyPG, denyASv4, passMatch),
+					action:   "drop",
+					rule:     "ingress_1",
+				}),
+			})
+			_, err := fakeOVN.fakeClient.AdminNetworkPolicyClient.K8sV1().AdminNetworkPolicies().Create(context.TODO(), denyPolicy, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.EventuallyExpectAddressSetWithIPs(passASName, []string{"10.128.1.4"})
+			fakeOVN.asf.EventuallyExpectAddressSetWithIPs(denyASName, []string{"10.128.1.3", "10.128.1.4"})
+			gomega.Eventually(func() ([]string, error) {
+				pg, err := fakeOVN.ovnNBClient.PortGroupGet(denyPG)
+				if err != nil {
+					return nil, err
+				}
+				return pg.Ports, nil
+			}).Should(gomega.ConsistOf(fakeUUID))
+
+			ginkgo.By("Creating a lower priority policy whose rules do not apply to the passed traffic")
+			allowPolicy := newAdminNetworkPolicyObject("allow", 20, "", []anpapi.AdminNetworkPolicyIngressRule{
+				{
+					Action: anpapi.AdminNetworkPolicyRuleActionAllow,
+					From:   []anpapi.AdminNetworkPolicyPeer{{}},
+				},
+			})
+			_, allowPG := getAdminNetworkPolicyPortGroupName(allowPolicy.Name, 0)
+			allowASName := getAdminNetworkPolicyAddressSetName(allowPolicy.Name, 0, knet.PolicyTypeIngress, 0)
+			allowASv4, _ := addressset.MakeAddressSetHashNames(allowASName)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				anpACLCmd(allowPG, allowPolicy.Name, &anpACL{
+					name:     "allow_ingress_0",
+					priority: 28000,
+					match:    fmt.Sprintf("outport == @%s && ip4.src == $%s && !(%s)", allowPG, allowASv4, passMatch),
+					action:   "allow-related",
+					rule:     "ingress_0",
+				}),
+			})
+			_, err = fakeOVN.fakeClient.AdminNetworkPolicyClient.K8sV1().AdminNetworkPolicies().Create(context.TODO(), allowPolicy, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			ginkgo.By("Deleting the higher priority policy, the passed traffic is matched again")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				anpACLCmd(allowPG, allowPolicy.Name, &anpACL{
+					name:     "allow_ingress_0",
+					priority: 28000,
+					match:    fmt.Sprintf("outport == @%s && ip4.src == $%s", allowPG, allowASv4),
+					action:   "allow-related",
+					rule:     "ingress_0",
+				}),
+			})
+			err = fakeOVN.fakeClient.AdminNetworkPolicyClient.K8sV1().AdminNetworkPolicies().Delete(context.TODO(), denyPolicy.Name, metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(portGroupExists(denyPG)).Should(gomega.BeFalse())
+			passASName4, _ := addressset.MakeAddressSetName(passASName)
+			fakeOVN.asf.EventuallyExpectNoAddressSet(passASName4)
+			denyASName4, _ := addressset.MakeAddressSetName(denyASName)
+			fakeOVN.asf.EventuallyExpectNoAddressSet(denyASName4)
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("replaces the ACLs of an updated policy before removing its old port group and rejects duplicate priorities", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find port_group",
+			})
+
+			namespace1 := *newNamespace("namespace1")
+			fakeOVN.start(ctx, &v1.NamespaceList{Items: []v1.Namespace{namespace1}})
+			fakeOVN.controller.WatchAdminNetworkPolicy()
+
+			ginkgo.By("Creating a policy denying the ingress traffic")
+			policy := newAdminNetworkPolicyObject("policy", 10, namespace1.Name, []anpapi.AdminNetworkPolicyIngressRule{
+				{
+					Action: anpapi.AdminNetworkPolicyRuleActionDeny,
+					From:   []anpapi.AdminNetworkPolicyPeer{{}},
+				},
+			})
+			_, oldPG := getAdminNetworkPolicyPortGroupName(policy.Name, 0)
+			oldASName := getAdminNetworkPolicyAddressSetName(policy.Name, 0, knet.PolicyTypeIngress, 0)
+			oldASv4, _ := addressset.MakeAddressSetHashNames(oldASName)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				anpACLCmd(oldPG, policy.Name, &anpACL{
+					name:     "policy_ingress_0",
+					priority: 29000,
+					match:    fmt.Sprintf("outport == @%s && ip4.src == $%s", oldPG, oldASv4),
+					action:   "drop",
+					rule:     "ingress_0",
+				}),
+			})
+			_, err := fakeOVN.fakeClient.AdminNetworkPolicyClient.K8sV1().AdminNetworkPolicies().Create(context.TODO(), policy, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			ginkgo.By("Updating the policy to allow the ingress traffic")
+			_, newPG := getAdminNetworkPolicyPortGroupName(policy.Name, 1)
+			newASName := getAdminNetworkPolicyAddressSetName(policy.Name, 1, knet.PolicyTypeIngress, 0)
+			newASv4, _ := addressset.MakeAddressSetHashNames(newASName)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				anpACLCmd(newPG, policy.Name, &anpACL{
+					name:     "policy_ingress_0",
+					priority: 29000,
+					match:    fmt.Sprintf("outport == @%s && ip4.src == $%s", newPG, newASv4),
+					action:   "allow-related",
+					rule:     "ingress_0",
+				}),
+			})
+			policy.Spec.Ingress[0].Action = anpapi.AdminNetworkPolicyRuleActionAllow
+			_, err = fakeOVN.fakeClient.AdminNetworkPolicyClient.K8sV1().AdminNetworkPolicies().Update(context.TODO(), policy, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(portGroupExists(oldPG)).Should(gomega.BeFalse())
+			oldASName4, _ := addressset.MakeAddressSetName(oldASName)
+			fakeOVN.asf.EventuallyExpectNoAddressSet(oldASName4)
+			gomega.Expect(portGroupExists(newPG)()).To(gomega.BeTrue())
+			fakeOVN.asf.ExpectEmptyAddressSet(newASName)
+
+			ginkgo.By("Creating a policy with the same priority")
+			duplicate := newAdminNetworkPolicyObject("duplicate", 10, namespace1.Name, nil)
+			_, duplicatePG := getAdminNetworkPolicyPortGroupName(duplicate.Name, 0)
+			_, err = fakeOVN.fakeClient.AdminNetworkPolicyClient.K8sV1().AdminNetworkPolicies().Create(context.TODO(), duplicate, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Consistently(portGroupExists(duplicatePG)).Should(gomega.BeFalse())
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
+
+var _ = ginkgo.Describe("OVN AdminNetworkPolicy ACLs", func() {
+	ginkgo.BeforeEach(func() {
+		config.PrepareTestConfig()
+		config.IPv4Mode = true
+		config.IPv6Mode = false
+	})
+
+	newRule := func(policyType knet.PolicyType, idx int, action anpapi.AdminNetworkPolicyRuleAction) *adminNetworkPolicyRule {
+		rule, err := newAdminNetworkPolicyRule(policyType, idx, action, nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		rule.peerAddressSet, err = addressset.NewFakeAddressSetFactory().NewAddressSet(fmt.Sprintf("%s%d", policyType, idx), nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		return rule
+	}
+
+	ginkgo.It("excludes the traffic passed by higher priority rules in the same direction only", func() {
+		pass := &adminNetworkPolicy{
+			name:          "pass",
+			priority:      1,
+			portGroupName: "pg_pass",
+			ingressRules:  []*adminNetworkPolicyRule{newRule(knet.PolicyTypeIngress, 0, anpapi.AdminNetworkPolicyRuleActionPass)},
+		}
+		deny := &adminNetworkPolicy{
+			name:          "deny",
+			priority:      0,
+			portGroupName: "pg_deny",
+			egressRules:   []*adminNetworkPolicyRule{newRule(knet.PolicyTypeEgress, 0, anpapi.AdminNetworkPolicyRuleActionDeny)},
+			ingressRules:  []*adminNetworkPolicyRule{newRule(knet.PolicyTypeIngress, 0, anpapi.AdminNetworkPolicyRuleActionDeny)},
+		}
+		allow := &adminNetworkPolicy{
+			name:          "allow",
+			priority:      2,
+			portGroupName: "pg_allow",
+			ingressRules:  []*adminNetworkPolicyRule{newRule(knet.PolicyTypeIngress, 0, anpapi.AdminNetworkPolicyRuleActionAllow)},
+		}
+
+		acls := getAdminNetworkPolicyACLs([]*adminNetworkPolicy{allow, deny, pass})
+		gomega.Expect(acls).NotTo(gomega.HaveKey("pass"))
+		gomega.Expect(acls["deny"]).To(gomega.HaveLen(2))
+		gomega.Expect(acls["deny"][0].priority).To(gomega.Equal(30000))
+		gomega.Expect(acls["deny"][0].match).NotTo(gomega.ContainSubstring("!("))
+		gomega.Expect(acls["deny"][1].rule).To(gomega.Equal("egress_0"))
+		gomega.Expect(acls["deny"][1].match).To(gomega.HavePrefix("inport == @pg_deny && ip4.dst == $"))
+		gomega.Expect(acls["allow"]).To(gomega.HaveLen(1))
+		gomega.Expect(acls["allow"][0].priority).To(gomega.Equal(29800))
+		gomega.Expect(acls["allow"][0].action).To(gomega.Equal("allow-related"))
+		gomega.Expect(acls["allow"][0].match).To(gomega.HaveSuffix(
+			fmt.Sprintf(" && !(%s)", pass.getMatch(pass.ingressRules[0]))))
+	})
+})
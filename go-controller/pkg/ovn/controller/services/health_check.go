@@ -0,0 +1,194 @@
+package services
+
+import (
+	"net"
+	"sort"
+
+	ovnlb "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/loadbalancer"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+// Defaults of the kubelet for the fields of a readiness probe left empty
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+)
+
+// backendProbe is how OVN should probe a service backend
+type backendProbe struct {
+	// the logical switch port of the backend pod
+	logicalPort string
+	// the IP OVN sends the probes from, on the backend's node subnet
+	sourceIP string
+
+	interval     int32
+	timeout      int32
+	successCount int32
+	failureCount int32
+}
+
+// getBackendProbes returns the probes of the service backends, keyed by "ip:port".
+// Only the IPv4 TCP backends whose pod has a readiness probe checking the backend
+// port can be probed: OVN then follows the timing and thresholds of the readiness probe.
+func (c *Controller) getBackendProbes(service *v1.Service, slices []*discovery.EndpointSlice, nodeInfos []nodeInfo) map[string]backendProbe {
+	probes := map[string]backendProbe{}
+	if c.podLister == nil {
+		return probes
+	}
+
+	nodeSubnets := map[string]*net.IPNet{}
+	for _, node := range nodeInfos {
+		if c.hasLBHealthCheckIP != nil && !c.hasLBHealthCheckIP(node.name) {
+			continue
+		}
+		for i, subnet := range node.podSubnets {
+			if !utilnet.IsIPv6CIDR(&subnet) {
+				nodeSubnets[node.name] = &node.podSubnets[i]
+				break
+			}
+		}
+	}
+
+	for _, slice := range slices {
+		if slice.AddressType != discovery.AddressTypeIPv4 {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.TargetRef == nil || ep.TargetRef.Kind != "Pod" || len(ep.Addresses) == 0 {
+				continue
+			}
+			pod, err := c.podLister.Pods(ep.TargetRef.Namespace).Get(ep.TargetRef.Name)
+			if err != nil {
+				klog.V(5).Infof("Unable to get backend pod %s/%s of service %s/%s: %v",
+					ep.TargetRef.Namespace, ep.TargetRef.Name, service.Namespace, service.Name, err)
+				continue
+			}
+			subnet, ok := nodeSubnets[pod.Spec.NodeName]
+			if !ok {
+				continue
+			}
+			ip := ep.Addresses[0]
+			// host networked backends are not on a logical switch port
+			if !subnet.Contains(net.ParseIP(ip)) {
+				continue
+			}
+
+			for _, port := range slice.Ports {
+				if port.Port == nil || (port.Protocol != nil && *port.Protocol != v1.ProtocolTCP) {
+					continue
+				}
+				probe := podReadinessProbe(pod, *port.Port)
+				if probe == nil {
+					continue
+				}
+				probes[util.JoinHostPortInt32(ip, *port.Port)] = backendProbe{
+					logicalPort:  util.GetLogicalPortName(pod.Namespace, pod.Name),
+					sourceIP:     util.GetNodeLBHealthCheckIfAddr(subnet).IP.String(),
+					interval:     probeValue(probe.PeriodSeconds, defaultProbePeriodSeconds),
+					timeout:      probeValue(probe.TimeoutSeconds, defaultProbeTimeoutSeconds),
+					successCount: probeValue(probe.SuccessThreshold, defaultProbeSuccessThreshold),
+					failureCount: probeValue(probe.FailureThreshold, defaultProbeFailureThreshold),
+				}
+			}
+		}
+	}
+	return probes
+}
+
+// podReadinessProbe returns the readiness probe of the pod checking the given port, if any
+func podReadinessProbe(pod *v1.Pod, port int32) *v1.Probe {
+	for _, container := range pod.Spec.Containers {
+		probe := container.ReadinessProbe
+		if probe == nil {
+			continue
+		}
+		var probePort *intstr.IntOrString
+		switch {
+		case probe.TCPSocket != nil:
+			probePort = &probe.TCPSocket.Port
+		case probe.HTTPGet != nil:
+			probePort = &probe.HTTPGet.Port
+		default:
+			continue
+		}
+		if resolveContainerPort(container, *probePort) == port {
+			return probe
+		}
+	}
+	return nil
+}
+
+// resolveContainerPort returns the number of a probe port, which may be named
+// after one of the container ports; 0 if it can't be resolved.
+func resolveContainerPort(container v1.Container, port intstr.IntOrString) int32 {
+	if port.Type == intstr.Int {
+		return port.IntVal
+	}
+	for _, containerPort := range container.Ports {
+		if containerPort.Name == port.StrVal {
+			return containerPort.ContainerPort
+		}
+	}
+	return 0
+}
+
+func probeValue(value, defaultValue int32) int32 {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// addHealthChecks adds a health check to every IPv4 vip of the load balancers
+// with at least one probed backend. The timing and thresholds of a vip are taken
+// from the first of its probed backends, as they all come from the same pod template
+// in the common case.
+func addHealthChecks(lbs []ovnlb.LB, probes map[string]backendProbe) {
+	if len(probes) == 0 {
+		return
+	}
+	for i := range lbs {
+		lb := &lbs[i]
+		for _, rule := range lb.Rules {
+			if utilnet.IsIPv6String(rule.Source.IP) {
+				continue
+			}
+			var hcProbe *backendProbe
+			for _, target := range rule.Targets {
+				probe, ok := probes[target.String()]
+				if !ok {
+					continue
+				}
+				if hcProbe == nil {
+					hcProbe = &probe
+				}
+				if lb.IPPortMappings == nil {
+					lb.IPPortMappings = map[string]string{}
+				}
+				lb.IPPortMappings[target.IP] = probe.logicalPort + ":" + probe.sourceIP
+			}
+			if hcProbe == nil {
+				continue
+			}
+			lb.HealthChecks = append(lb.HealthChecks, ovnlb.LBHealthCheck{
+				VIP:          rule.Source,
+				Interval:     hcProbe.interval,
+				Timeout:      hcProbe.timeout,
+				SuccessCount: hcProbe.successCount,
+				FailureCount: hcProbe.failureCount,
+			})
+		}
+		// for unit testing - stable order
+		sort.Slice(lb.HealthChecks, func(a, b int) bool {
+			return lb.HealthChecks[a].VIP.String() < lb.HealthChecks[b].VIP.String()
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"net"
+	"testing"
+
+	globalconfig "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ovnlb "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/loadbalancer"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_getBackendProbes(t *testing.T) {
+	_, nodeSubnet, _ := net.ParseCIDR("10.128.1.0/24")
+	_, nodeSubnet6, _ := net.ParseCIDR("fe00:0:0:1::/64")
+	nodeInfos := []nodeInfo{{
+		name:       "node-a",
+		podSubnets: []net.IPNet{*nodeSubnet6, *nodeSubnet},
+		switchName: "node-a",
+	}}
+
+	tcp := v1.ProtocolTCP
+	udp := v1.ProtocolUDP
+	port := int32(8080)
+	otherPort := int32(9090)
+
+	makePod := func(name, ip string, probe *v1.Probe) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testns"},
+			Spec: v1.PodSpec{
+				NodeName: "node-a",
+				Containers: []v1.Container{{
+					Name:           "server",
+					Ports:          []v1.ContainerPort{{Name: "http", ContainerPort: port}},
+					ReadinessProbe: probe,
+				}},
+			},
+			Status: v1.PodStatus{PodIP: ip},
+		}
+	}
+	makeSlice := func(addressType discovery.AddressType, proto *v1.Protocol, ports []int32, pods ...*v1.Pod) *discovery.EndpointSlice {
+		slice := &discovery.EndpointSlice{
+			ObjectMeta:  metav1.ObjectMeta{Name: "foo-ab1", Namespace: "testns"},
+			AddressType: addressType,
+		}
+		for i := range ports {
+			slice.Ports = append(slice.Ports, discovery.EndpointPort{Protocol: proto, Port: &ports[i]})
+		}
+		for _, pod := range pods {
+			slice.Endpoints = append(slice.Endpoints, discovery.Endpoint{
+				Addresses: []string{pod.Status.PodIP},
+				TargetRef: &v1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name},
+			})
+		}
+		return slice
+	}
+
+	tcpProbe := &v1.Probe{
+		Handler:          v1.Handler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(int(port))}},
+		PeriodSeconds:    5,
+		TimeoutSeconds:   2,
+		SuccessThreshold: 1,
+		FailureThreshold: 4,
+	}
+	namedHTTPProbe := &v1.Probe{
+		Handler: v1.Handler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromString("http")}},
+	}
+	execProbe := &v1.Probe{
+		Handler: v1.Handler{Exec: &v1.ExecAction{Command: []string{"true"}}},
+	}
+
+	podTCP := makePod("pod-tcp", "10.128.1.5", tcpProbe)
+	podHTTP := makePod("pod-http", "10.128.1.6", namedHTTPProbe)
+	podExec := makePod("pod-exec", "10.128.1.7", execProbe)
+	podNoProbe := makePod("pod-none", "10.128.1.8", nil)
+	podHostNetwork := makePod("pod-host", "192.168.0.10", tcpProbe)
+
+	tests := []struct {
+		name   string
+		slices []*discovery.EndpointSlice
+		want   map[string]backendProbe
+	}{
+		{
+			name:   "tcp and named http readiness probes",
+			slices: []*discovery.EndpointSlice{makeSlice(discovery.AddressTypeIPv4, &tcp, []int32{port}, podTCP, podHTTP)},
+			want: map[string]backendProbe{
+				"10.128.1.5:8080": {
+					logicalPort:  "testns_pod-tcp",
+					sourceIP:     "10.128.1.254",
+					interval:     5,
+					timeout:      2,
+					successCount: 1,
+					failureCount: 4,
+				},
+				"10.128.1.6:8080": {
+					logicalPort:  "testns_pod-http",
+					sourceIP:     "10.128.1.254",
+					interval:     defaultProbePeriodSeconds,
+					timeout:      defaultProbeTimeoutSeconds,
+					successCount: defaultProbeSuccessThreshold,
+					failureCount: defaultProbeFailureThreshold,
+				},
+			},
+		},
+		{
+			name:   "pods without readiness probe on a port are not probed",
+			slices: []*discovery.EndpointSlice{makeSlice(discovery.AddressTypeIPv4, &tcp, []int32{otherPort}, podTCP, podExec, podNoProbe)},
+			want:   map[string]backendProbe{},
+		},
+		{
+			name:   "udp backends are not probed",
+			slices: []*discovery.EndpointSlice{makeSlice(discovery.AddressTypeIPv4, &udp, []int32{port}, podTCP)},
+			want:   map[string]backendProbe{},
+		},
+		{
+			name:   "host networked backends are not probed",
+			slices: []*discovery.EndpointSlice{makeSlice(discovery.AddressTypeIPv4, &tcp, []int32{port}, podHostNetwork)},
+			want:   map[string]backendProbe{},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	for _, pod := range []*v1.Pod{podTCP, podHTTP, podExec, podNoProbe, podHostNetwork} {
+		if err := podInformer.Informer().GetStore().Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &Controller{podLister: podInformer.Lister()}
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "testns"}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.getBackendProbes(service, tt.slices, nodeInfos))
+		})
+	}
+
+	t.Run("backends on a node without health check IP are not probed", func(t *testing.T) {
+		c := &Controller{
+			podLister:          podInformer.Lister(),
+			hasLBHealthCheckIP: func(nodeName string) bool { return nodeName != "node-a" },
+		}
+		slices := []*discovery.EndpointSlice{makeSlice(discovery.AddressTypeIPv4, &tcp, []int32{port}, podTCP)}
+		assert.Equal(t, map[string]backendProbe{}, c.getBackendProbes(service, slices, nodeInfos))
+	})
+}
+
+func Test_addHealthChecks(t *testing.T) {
+	probe := backendProbe{
+		logicalPort:  "testns_pod-a",
+		sourceIP:     "10.128.1.254",
+		interval:     5,
+		timeout:      2,
+		successCount: 1,
+		failureCount: 4,
+	}
+	lbs := []ovnlb.LB{
+		{
+			Name: "Service_testns/foo_TCP_cluster",
+			Rules: []ovnlb.LBRule{
+				{
+					Source: ovnlb.Addr{IP: "192.168.1.1", Port: 80},
+					Targets: []ovnlb.Addr{
+						{IP: "10.128.1.5", Port: 8080},
+						{IP: "10.128.2.5", Port: 8080},
+					},
+				},
+				{
+					Source:  ovnlb.Addr{IP: "fe10::1", Port: 80},
+					Targets: []ovnlb.Addr{{IP: "fe00:0:0:1::5", Port: 8080}},
+				},
+			},
+		},
+		{
+			Name: "Service_testns/foo_TCP_node_router_node-a",
+			Rules: []ovnlb.LBRule{
+				{
+					Source:  ovnlb.Addr{IP: "10.0.0.1", Port: 30080},
+					Targets: []ovnlb.Addr{{IP: "10.128.2.5", Port: 8080}},
+				},
+			},
+		},
+	}
+	probes := map[string]backendProbe{"10.128.1.5:8080": probe}
+
+	addHealthChecks(lbs, probes)
+
+	assert.Equal(t, []ovnlb.LBHealthCheck{{
+		VIP:          ovnlb.Addr{IP: "192.168.1.1", Port: 80},
+		Interval:     5,
+		Timeout:      2,
+		SuccessCount: 1,
+		FailureCount: 4,
+	}}, lbs[0].HealthChecks)
+	assert.Equal(t, map[string]string{"10.128.1.5": "testns_pod-a:10.128.1.254"}, lbs[0].IPPortMappings)
+	assert.Empty(t, lbs[1].HealthChecks)
+	assert.Empty(t, lbs[1].IPPortMappings)
+}
+
+func Test_svcNeedsHealthCheck(t *testing.T) {
+	oldEnabled := globalconfig.OVNKubernetesFeature.EnableLBHealthCheck
+	defer func() {
+		globalconfig.OVNKubernetesFeature.EnableLBHealthCheck = oldEnabled
+	}()
+
+	annotated := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{OvnServiceHealthCheckAnnotation: "true"},
+	}}
+
+	globalconfig.OVNKubernetesFeature.EnableLBHealthCheck = false
+	assert.False(t, svcNeedsHealthCheck(annotated))
+
+	globalconfig.OVNKubernetesFeature.EnableLBHealthCheck = true
+	assert.True(t, svcNeedsHealthCheck(annotated))
+	assert.False(t, svcNeedsHealthCheck(&v1.Service{}))
+}
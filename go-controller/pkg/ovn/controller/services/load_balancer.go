@@ -32,6 +32,10 @@ type lbConfig struct {
 	// that means, skipSNAT, and remove any non-local endpoints.
 	// (see below)
 	externalTrafficLocal bool
+
+	// if true, then the vips are ClusterIPs with InternalTrafficPolicy=Local:
+	// all non-local endpoints are removed, on the switch and on the router.
+	internalTrafficLocal bool
 }
 
 // just used for consistent ordering
@@ -55,14 +59,22 @@ var protos = []v1.Protocol{
 // - services with NodePort set
 // - services with host-network endpoints (for shared gateway mode)
 // - services with ExternalTrafficPolicy=Local
+// - services with InternalTrafficPolicy=Local
+// - services with topology aware hints, when all endpoints have zone hints
 func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.EndpointSlice) (perNodeConfigs []lbConfig, clusterConfigs []lbConfig) {
 	// For each svcPort, determine if it will be applied per-node or cluster-wide
 	for _, svcPort := range service.Spec.Ports {
 		eps := util.GetLbEndpoints(endpointSlices, svcPort)
+		// the zone hints are only followed if the service asks for it
+		if !svcHasTopologyHints(service) {
+			eps.ZoneHints = nil
+		}
 
 		// if ExternalTrafficPolicy is local, then we need to do things a bit differently
 		externalTrafficLocal := globalconfig.Gateway.Mode == globalconfig.GatewayModeShared &&
 			service.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypeLocal
+		internalTrafficLocal := service.Spec.InternalTrafficPolicy != nil &&
+			*service.Spec.InternalTrafficPolicy == v1.ServiceInternalTrafficPolicyLocal
 
 		// NodePort services get a per-node load balancer, but with the node's physical IP as the vip
 		// Thus, the vip "node" will be expanded later.
@@ -83,6 +95,22 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 		if len(vips) == 0 {
 			vips = []string{service.Spec.ClusterIP}
 		}
+
+		// if ITP=Local, then the ClusterIPs need per-node configs, as each node
+		// only reaches its local endpoints. This doesn't apply to ExternalIPs and
+		// LoadBalancer IPs, which are governed by the ExternalTrafficPolicy.
+		if internalTrafficLocal {
+			internalTrafficLocalConfig := lbConfig{
+				protocol:             svcPort.Protocol,
+				inport:               svcPort.Port,
+				vips:                 vips,
+				eps:                  eps,
+				internalTrafficLocal: true,
+			}
+			perNodeConfigs = append(perNodeConfigs, internalTrafficLocalConfig)
+			vips = []string{}
+		}
+
 		externalVips := []string{}
 		// ExternalIP
 		externalVips = append(externalVips, service.Spec.ExternalIPs...)
@@ -108,6 +136,11 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 			vips = append(vips, externalVips...)
 		}
 
+		// Nothing left for the clusterIP config
+		if len(vips) == 0 {
+			continue
+		}
+
 		// Build the clusterIP config
 		// This is NEVER influenced by ExternalTrafficPolicy
 		clusterIPConfig := lbConfig{
@@ -124,8 +157,9 @@ func buildServiceLBConfigs(service *v1.Service, endpointSlices []*discovery.Endp
 		// - Any of the endpoints are host-network
 		//
 		// In that case, we need to create per-node LBs.
-		if globalconfig.Gateway.Mode == globalconfig.GatewayModeShared &&
-			(hasHostEndpoints(eps.V4IPs) || hasHostEndpoints(eps.V6IPs)) {
+		// The same goes when following zone hints, as each zone has its own endpoints.
+		if eps.ZoneHints != nil || (globalconfig.Gateway.Mode == globalconfig.GatewayModeShared &&
+			(hasHostEndpoints(eps.V4IPs) || hasHostEndpoints(eps.V6IPs))) {
 			perNodeConfigs = append(perNodeConfigs, clusterIPConfig)
 		} else {
 			clusterConfigs = append(clusterConfigs, clusterIPConfig)
@@ -250,6 +284,13 @@ func buildClusterLBs(service *v1.Service, configs []lbConfig, nodeInfos []nodeIn
 // - targets filtered to only local targets
 // - SkipSNAT enabled
 // This results in the creation of an additional load balancer on the GatewayRouters.
+//
+// For InternalTrafficPolicy=Local, the ClusterIPs have their targets filtered to only
+// local targets, on both the switch and the router. Without local targets, the rules
+// have no backends and OVN does not forward the traffic.
+//
+// For services with topology aware hints, the targets of all the other vips are filtered
+// to the ones hinted for the node's zone, as long as there are any.
 func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo) []ovnlb.LB {
 	cbp := configsByProto(configs)
 	eids := util.ExternalIDsForObject(service)
@@ -276,15 +317,29 @@ func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo)
 			for _, config := range configs {
 				vips := config.vips
 
-				routerV4targetips := config.eps.V4IPs
-				routerV6targetips := config.eps.V6IPs
+				switchV4targetips := config.eps.V4IPs
+				switchV6targetips := config.eps.V6IPs
+
+				// for InternalTrafficPolicy=Local, remove non-local endpoints from all the targets
+				// otherwise, for topology aware hints, remove the endpoints hinted for other zones
+				if config.internalTrafficLocal {
+					switchV4targetips = util.FilterIPsSlice(switchV4targetips, node.nodeSubnets(), true)
+					switchV6targetips = util.FilterIPsSlice(switchV6targetips, node.nodeSubnets(), true)
+				} else if config.eps.ZoneHints != nil && node.zone != "" {
+					switchV4targetips = filterZoneHinted(switchV4targetips, config.eps.ZoneHints, node.zone)
+					switchV6targetips = filterZoneHinted(switchV6targetips, config.eps.ZoneHints, node.zone)
+				}
+
+				routerV4targetips := switchV4targetips
+				routerV6targetips := switchV6targetips
 
 				// shared gateway needs to "massage" some of the targets
 				if globalconfig.Gateway.Mode == "shared" {
 					// for ExternalTrafficPolicy=Local, then remove non-local endpoints from the router targets
+					// (zone hints don't apply there)
 					if config.externalTrafficLocal {
-						routerV4targetips = util.FilterIPsSlice(routerV4targetips, node.nodeSubnets(), true)
-						routerV6targetips = util.FilterIPsSlice(routerV6targetips, node.nodeSubnets(), true)
+						routerV4targetips = util.FilterIPsSlice(config.eps.V4IPs, node.nodeSubnets(), true)
+						routerV6targetips = util.FilterIPsSlice(config.eps.V6IPs, node.nodeSubnets(), true)
 					}
 
 					// at this point, the targets may be empty
@@ -298,8 +353,8 @@ func buildPerNodeLBs(service *v1.Service, configs []lbConfig, nodes []nodeInfo)
 				routerV4targets := ovnlb.JoinHostsPort(routerV4targetips, config.eps.Port)
 				routerV6targets := ovnlb.JoinHostsPort(routerV6targetips, config.eps.Port)
 
-				switchV4Targets := ovnlb.JoinHostsPort(config.eps.V4IPs, config.eps.Port)
-				switchV6Targets := ovnlb.JoinHostsPort(config.eps.V6IPs, config.eps.Port)
+				switchV4Targets := ovnlb.JoinHostsPort(switchV4targetips, config.eps.Port)
+				switchV6Targets := ovnlb.JoinHostsPort(switchV6targetips, config.eps.Port)
 
 				// Substitute the special vip "node" for the node's physical ips
 				// This is used for nodeport
@@ -41,6 +41,7 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 	emptyEPs := util.LbEndpoints{V4IPs: []string{}, V6IPs: []string{}, Port: 0}
 	tcp := v1.ProtocolTCP
 	udp := v1.ProtocolUDP
+	itpLocal := v1.ServiceInternalTrafficPolicyLocal
 
 	// make slices
 	// nil slice = don't use this family
@@ -120,6 +121,32 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 		return out
 	}
 
+	hintedSlices := []*discovery.EndpointSlice{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName + "ab1",
+			Namespace: ns,
+			Labels:    map[string]string{discovery.LabelServiceName: serviceName},
+		},
+		Ports: []discovery.EndpointPort{{
+			Protocol: &tcp,
+			Port:     &outport,
+			Name:     &portName,
+		}},
+		AddressType: discovery.AddressTypeIPv4,
+		Endpoints: []discovery.Endpoint{
+			{
+				Conditions: discovery.EndpointConditions{Ready: utilpointer.BoolPtr(true)},
+				Addresses:  []string{"10.128.0.2"},
+				Hints:      &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: "zone-a"}}},
+			},
+			{
+				Conditions: discovery.EndpointConditions{Ready: utilpointer.BoolPtr(true)},
+				Addresses:  []string{"10.128.1.2"},
+				Hints:      &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: "zone-b"}}},
+			},
+		},
+	}}
+
 	type args struct {
 		service *v1.Service
 		slices  []*discovery.EndpointSlice
@@ -711,6 +738,148 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "dual-stack clusterip, one port, endpoints, external ips, InternalTrafficPolicy=Local",
+			args: args{
+				slices: makeSlices([]string{"10.128.0.2"}, []string{"fe00::1:1"}, v1.ProtocolTCP),
+				service: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+					Spec: v1.ServiceSpec{
+						Type:       v1.ServiceTypeClusterIP,
+						ClusterIP:  "192.168.1.1",
+						ClusterIPs: []string{"192.168.1.1", "2002::1"},
+						Ports: []v1.ServicePort{{
+							Port:       inport,
+							Protocol:   v1.ProtocolTCP,
+							TargetPort: outportstr,
+						}},
+						ExternalIPs:           []string{"4.2.2.2"},
+						InternalTrafficPolicy: &itpLocal,
+					},
+				},
+			},
+			resultsSame: true,
+			// the ClusterIPs are per-node, the external IPs are not affected
+			resultSharedGatewayNode: []lbConfig{{
+				vips:     []string{"192.168.1.1", "2002::1"},
+				protocol: v1.ProtocolTCP,
+				inport:   inport,
+				eps: util.LbEndpoints{
+					V4IPs: []string{"10.128.0.2"},
+					V6IPs: []string{"fe00::1:1"},
+					Port:  outport,
+				},
+				internalTrafficLocal: true,
+			}},
+			resultSharedGatewayCluster: []lbConfig{{
+				vips:     []string{"4.2.2.2"},
+				protocol: v1.ProtocolTCP,
+				inport:   inport,
+				eps: util.LbEndpoints{
+					V4IPs: []string{"10.128.0.2"},
+					V6IPs: []string{"fe00::1:1"},
+					Port:  outport,
+				},
+			}},
+		},
+		{
+			name: "v4 clusterip, one port, endpoints, InternalTrafficPolicy=Local",
+			args: args{
+				slices: makeSlices([]string{"10.128.0.2"}, nil, v1.ProtocolTCP),
+				service: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+					Spec: v1.ServiceSpec{
+						Type:       v1.ServiceTypeClusterIP,
+						ClusterIP:  "192.168.1.1",
+						ClusterIPs: []string{"192.168.1.1"},
+						Ports: []v1.ServicePort{{
+							Port:       inport,
+							Protocol:   v1.ProtocolTCP,
+							TargetPort: outportstr,
+						}},
+						InternalTrafficPolicy: &itpLocal,
+					},
+				},
+			},
+			resultsSame: true,
+			resultSharedGatewayNode: []lbConfig{{
+				vips:     []string{"192.168.1.1"},
+				protocol: v1.ProtocolTCP,
+				inport:   inport,
+				eps: util.LbEndpoints{
+					V4IPs: []string{"10.128.0.2"},
+					V6IPs: []string{},
+					Port:  outport,
+				},
+				internalTrafficLocal: true,
+			}},
+		},
+		{
+			name: "v4 clusterip, one port, endpoints with zone hints, topology aware hints",
+			args: args{
+				slices: hintedSlices,
+				service: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        serviceName,
+						Namespace:   ns,
+						Annotations: map[string]string{v1.AnnotationTopologyAwareHints: "Auto"},
+					},
+					Spec: v1.ServiceSpec{
+						Type:       v1.ServiceTypeClusterIP,
+						ClusterIP:  "192.168.1.1",
+						ClusterIPs: []string{"192.168.1.1"},
+						Ports: []v1.ServicePort{{
+							Port:       inport,
+							Protocol:   v1.ProtocolTCP,
+							TargetPort: outportstr,
+						}},
+					},
+				},
+			},
+			resultsSame: true,
+			// each zone has its own endpoints, so the configs are per-node
+			resultSharedGatewayNode: []lbConfig{{
+				vips:     []string{"192.168.1.1"},
+				protocol: v1.ProtocolTCP,
+				inport:   inport,
+				eps: util.LbEndpoints{
+					V4IPs:     []string{"10.128.0.2", "10.128.1.2"},
+					V6IPs:     []string{},
+					Port:      outport,
+					ZoneHints: map[string][]string{"10.128.0.2": {"zone-a"}, "10.128.1.2": {"zone-b"}},
+				},
+			}},
+		},
+		{
+			name: "v4 clusterip, one port, endpoints with zone hints, no topology aware hints",
+			args: args{
+				slices: hintedSlices,
+				service: &v1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+					Spec: v1.ServiceSpec{
+						Type:       v1.ServiceTypeClusterIP,
+						ClusterIP:  "192.168.1.1",
+						ClusterIPs: []string{"192.168.1.1"},
+						Ports: []v1.ServicePort{{
+							Port:       inport,
+							Protocol:   v1.ProtocolTCP,
+							TargetPort: outportstr,
+						}},
+					},
+				},
+			},
+			resultsSame: true,
+			resultSharedGatewayCluster: []lbConfig{{
+				vips:     []string{"192.168.1.1"},
+				protocol: v1.ProtocolTCP,
+				inport:   inport,
+				eps: util.LbEndpoints{
+					V4IPs: []string{"10.128.0.2", "10.128.1.2"},
+					V6IPs: []string{},
+					Port:  outport,
+				},
+			}},
+		},
 	}
 
 	for i, tt := range tests {
@@ -983,6 +1152,30 @@ func Test_buildPerNodeLBs(t *testing.T) {
 		},
 	}
 
+	zonedNodes := []nodeInfo{
+		{
+			name:       "node-a",
+			nodeIPs:    []string{"10.0.0.1"},
+			switchName: "switch-node-a",
+			podSubnets: []net.IPNet{{IP: net.ParseIP("10.128.0.0"), Mask: net.CIDRMask(24, 32)}},
+			zone:       "zone-a",
+		},
+		{
+			name:       "node-b",
+			nodeIPs:    []string{"10.0.0.2"},
+			switchName: "switch-node-b",
+			podSubnets: []net.IPNet{{IP: net.ParseIP("10.128.1.0"), Mask: net.CIDRMask(24, 32)}},
+			zone:       "zone-b",
+		},
+		{
+			name:       "node-c",
+			nodeIPs:    []string{"10.0.0.3"},
+			switchName: "switch-node-c",
+			podSubnets: []net.IPNet{{IP: net.ParseIP("10.128.2.0"), Mask: net.CIDRMask(24, 32)}},
+			zone:       "zone-c",
+		},
+	}
+
 	defaultExternalIDs := map[string]string{
 		"k8s.ovn.org/kind":  "Service",
 		"k8s.ovn.org/owner": fmt.Sprintf("%s/%s", namespace, name),
@@ -995,6 +1188,7 @@ func Test_buildPerNodeLBs(t *testing.T) {
 		name           string
 		service        *v1.Service
 		configs        []lbConfig
+		nodes          []nodeInfo // defaultNodes if nil
 		expectedShared []ovnlb.LB
 		expectedLocal  []ovnlb.LB
 	}{
@@ -1328,20 +1522,211 @@ func Test_buildPerNodeLBs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "clusterip service, InternalTrafficPolicy=Local",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1"},
+					protocol: v1.ProtocolTCP,
+					inport:   80,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2", "10.128.0.3"},
+						Port:  8080,
+					},
+					internalTrafficLocal: true,
+				},
+			},
+			// node-b has no local endpoints
+			expectedShared: []ovnlb.LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-a"},
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"10.128.0.2", 8080}, {"10.128.0.3", 8080}},
+						},
+					},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-b",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-b"},
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{},
+						},
+					},
+				},
+			},
+			expectedLocal: []ovnlb.LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"10.128.0.2", 8080}, {"10.128.0.3", 8080}},
+						},
+					},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-b",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "clusterip service, host-network pod, InternalTrafficPolicy=Local",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1"},
+					protocol: v1.ProtocolTCP,
+					inport:   80,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.0.0.1"},
+						Port:  8080,
+					},
+					internalTrafficLocal: true,
+				},
+			},
+			expectedShared: []ovnlb.LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_router_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-a"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"169.254.169.2", 8080}},
+						},
+					},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"10.0.0.1", 8080}},
+						},
+					},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-b",
+					ExternalIDs: defaultExternalIDs,
+					Routers:     []string{"gr-node-b"},
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "clusterip service, zone hints",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1"},
+					protocol: v1.ProtocolTCP,
+					inport:   80,
+					eps: util.LbEndpoints{
+						V4IPs: []string{"10.128.0.2", "10.128.1.2", "10.128.2.2"},
+						Port:  8080,
+						ZoneHints: map[string][]string{
+							"10.128.0.2": {"zone-a"},
+							"10.128.1.2": {"zone-a", "zone-b"},
+							"10.128.2.2": {"zone-b"},
+						},
+					},
+				},
+			},
+			// node-c is in a zone without hinted endpoints, so it gets them all
+			nodes: zonedNodes,
+			expectedLocal: []ovnlb.LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-a",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"10.128.0.2", 8080}, {"10.128.1.2", 8080}},
+						},
+					},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-b",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"10.128.1.2", 8080}, {"10.128.2.2", 8080}},
+						},
+					},
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_switch_node-c",
+					ExternalIDs: defaultExternalIDs,
+					Switches:    []string{"switch-node-c"},
+					Protocol:    "TCP",
+					Rules: []ovnlb.LBRule{
+						{
+							Source:  ovnlb.Addr{"192.168.1.1", 80},
+							Targets: []ovnlb.Addr{{"10.128.0.2", 8080}, {"10.128.1.2", 8080}, {"10.128.2.2", 8080}},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, tt := range tc {
 		t.Run(fmt.Sprintf("%d_%s", i, tt.name), func(t *testing.T) {
 
+			nodes := tt.nodes
+			if nodes == nil {
+				nodes = defaultNodes
+			}
+
 			if tt.expectedShared != nil {
 				globalconfig.Gateway.Mode = globalconfig.GatewayModeShared
-				actual := buildPerNodeLBs(tt.service, tt.configs, defaultNodes)
+				actual := buildPerNodeLBs(tt.service, tt.configs, nodes)
 				assert.Equal(t, tt.expectedShared, actual, "shared gateway mode not as expected")
 			}
 
 			if tt.expectedLocal != nil {
 				globalconfig.Gateway.Mode = globalconfig.GatewayModeLocal
-				actual := buildPerNodeLBs(tt.service, tt.configs, defaultNodes)
+				actual := buildPerNodeLBs(tt.service, tt.configs, nodes)
 				assert.Equal(t, tt.expectedLocal, actual, "local gateway mode not as expected")
 			}
 
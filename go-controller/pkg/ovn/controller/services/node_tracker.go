@@ -40,6 +40,8 @@ type nodeInfo struct {
 	gatewayRouterName string
 	// The name of the node's switch - never empty
 	switchName string
+	// The node's topology zone, or "" if unknown
+	zone string
 }
 
 // returns a list of all ip blocks "assigned" to this node
@@ -117,13 +119,14 @@ func newNodeTracker(nodeInformer coreinformers.NodeInformer) *nodeTracker {
 
 // updateNodeInfo updates the node info cache, and syncs all services
 // if it changed.
-func (nt *nodeTracker) updateNodeInfo(nodeName, switchName, routerName string, nodeIPs []string, podSubnets []*net.IPNet) {
+func (nt *nodeTracker) updateNodeInfo(nodeName, switchName, routerName, zone string, nodeIPs []string, podSubnets []*net.IPNet) {
 	ni := nodeInfo{
 		name:              nodeName,
 		nodeIPs:           nodeIPs,
 		podSubnets:        make([]net.IPNet, 0, len(podSubnets)),
 		gatewayRouterName: routerName,
 		switchName:        switchName,
+		zone:              zone,
 	}
 	for i := range podSubnets {
 		ni.podSubnets = append(ni.podSubnets, *podSubnets[i]) // de-pointer
@@ -140,7 +143,7 @@ func (nt *nodeTracker) updateNodeInfo(nodeName, switchName, routerName string, n
 	nt.nodes[nodeName] = ni
 	nt.Unlock()
 
-	klog.Infof("Node %s switch + router changed, syncing services", nodeName)
+	klog.Infof("Node %s switch + router or zone changed, syncing services", nodeName)
 	// Resync all services
 	nt.resyncFn()
 }
@@ -155,7 +158,7 @@ func (nt *nodeTracker) removeNode(nodeName string) {
 	delete(nt.nodes, nodeName)
 }
 
-// UpdateNode is called when a node's gateway router / switch / IPs have changed
+// UpdateNode is called when a node's gateway router / switch / IPs / zone have changed
 // The switch exists when the HostSubnet annotation is set.
 // The gateway router will exist sometime after the L3Gateway annotation is set.
 func (nt *nodeTracker) updateNode(node *v1.Node) {
@@ -190,6 +193,7 @@ func (nt *nodeTracker) updateNode(node *v1.Node) {
 		node.Name,
 		switchName,
 		grName,
+		node.Labels[v1.LabelTopologyZone],
 		ips,
 		hsn,
 	)
@@ -109,6 +109,33 @@ func (r *repair) runBeforeSync(clusterPortGroupUUID string) {
 	}
 	klog.V(2).Infof("Deleted %d stale service LBs", len(staleLBs))
 
+	// Look for any load balancer source ranges ACLs whose Service no longer exists
+	// in the apiserver or no longer has source ranges
+	existingACLs, err := acl.FindDropACLs(map[string]string{"k8s.ovn.org/kind": "Service"})
+	if err != nil {
+		klog.Errorf("Failed to find service drop ACLs: %v", err)
+	}
+	staleACLOwners := sets.NewString()
+	for _, dropACL := range existingACLs {
+		owner := dropACL.ExternalIDs["k8s.ovn.org/owner"]
+		namespace, name, err := cache.SplitMetaNamespaceKey(owner)
+		if err != nil || namespace == "" {
+			klog.Warningf("Service ACL %#v has unreadable owner, ignoring", dropACL)
+			continue
+		}
+		service, err := r.serviceLister.Services(namespace).Get(name)
+		if apierrors.IsNotFound(err) || (err == nil && len(buildSourceRangesACLMatches(service)) == 0) {
+			klog.V(5).Infof("Found stale service ACL %#v", dropACL)
+			staleACLOwners.Insert(owner)
+		}
+	}
+	for _, owner := range staleACLOwners.List() {
+		externalIDs := map[string]string{"k8s.ovn.org/kind": "Service", "k8s.ovn.org/owner": owner}
+		if err := acl.SyncDropACLs(clusterPortGroupUUID, nil, "", externalIDs, nil); err != nil {
+			klog.Errorf("Failed to delete stale ACLs of service %s: %v", owner, err)
+		}
+	}
+
 	// Remove existing reject rules. They are not used anymore
 	// given the introduction of idling loadbalancers
 	err = acl.PurgeRejectRules(clusterPortGroupUUID)
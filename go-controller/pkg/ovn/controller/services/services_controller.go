@@ -7,6 +7,7 @@ import (
 	"time"
 
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	globalconfig "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	ovnlb "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/loadbalancer"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
@@ -51,6 +52,8 @@ func NewController(client clientset.Interface,
 	serviceInformer coreinformers.ServiceInformer,
 	endpointSliceInformer discoveryinformers.EndpointSliceInformer,
 	nodeInformer coreinformers.NodeInformer,
+	podInformer coreinformers.PodInformer,
+	hasLBHealthCheckIP func(nodeName string) bool,
 ) *Controller {
 	klog.V(4).Info("Creating event broadcaster")
 	broadcaster := record.NewBroadcaster()
@@ -64,6 +67,8 @@ func NewController(client clientset.Interface,
 		queue:            workqueue.NewNamedRateLimitingQueue(newRatelimiter(100), controllerName),
 		workerLoopPeriod: time.Second,
 		alreadyApplied:   map[string][]ovnlb.LB{},
+
+		alreadyAppliedSourceRanges: map[string]sourceRangesACLs{},
 	}
 
 	// services
@@ -87,6 +92,17 @@ func NewController(client clientset.Interface,
 	c.endpointSliceLister = endpointSliceInformer.Lister()
 	c.endpointSlicesSynced = endpointSliceInformer.Informer().HasSynced
 
+	// pods are only needed to derive the load balancer health checks from
+	// their readiness probes, don't watch them otherwise. Pod changes are
+	// not handled: probes can't be updated and a pod being (re)created
+	// updates the endpoint slices of its services.
+	c.podsSynced = func() bool { return true }
+	if globalconfig.OVNKubernetesFeature.EnableLBHealthCheck {
+		c.podLister = podInformer.Lister()
+		c.podsSynced = podInformer.Informer().HasSynced
+		c.hasLBHealthCheckIP = hasLBHealthCheckIP
+	}
+
 	c.eventBroadcaster = broadcaster
 	c.eventRecorder = recorder
 
@@ -127,6 +143,14 @@ type Controller struct {
 
 	nodesSynced cache.InformerSynced
 
+	// podLister is able to list/get pods, it is only set when load
+	// balancer health checks are enabled
+	podLister  corelisters.PodLister
+	podsSynced cache.InformerSynced
+	// hasLBHealthCheckIP returns false for the nodes whose load balancer health
+	// check IP could not be reserved, their backends are not probed
+	hasLBHealthCheckIP func(nodeName string) bool
+
 	// Services that need to be updated. A channel is inappropriate here,
 	// because it allows services with lots of pods to be serviced much
 	// more often than services with few pods; it also would cause a
@@ -147,6 +171,14 @@ type Controller struct {
 	// if a service's config hasn't changed
 	alreadyApplied     map[string][]ovnlb.LB
 	alreadyAppliedLock sync.Mutex
+
+	// alreadyAppliedSourceRanges is a map of service key -> already applied load balancer
+	// source ranges ACLs, for the services with source ranges. Protected by alreadyAppliedLock.
+	alreadyAppliedSourceRanges map[string]sourceRangesACLs
+
+	// the UUID of the port group of all the pods and management ports,
+	// the load balancer source ranges ACLs are applied to it
+	clusterPortGroupUUID string
 }
 
 // Run will not return until stopCh is closed. workers determines how many
@@ -160,9 +192,10 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}, runRepair bool, cl
 
 	// Wait for the caches to be synced
 	klog.Info("Waiting for informer caches to sync")
-	if !cache.WaitForNamedCacheSync(controllerName, stopCh, c.servicesSynced, c.endpointSlicesSynced, c.nodesSynced) {
+	if !cache.WaitForNamedCacheSync(controllerName, stopCh, c.servicesSynced, c.endpointSlicesSynced, c.nodesSynced, c.podsSynced) {
 		return fmt.Errorf("error syncing cache")
 	}
+	c.clusterPortGroupUUID = clusterPortGroupUUID
 
 	if runRepair {
 		// Run the repair controller only once
@@ -268,6 +301,9 @@ func (c *Controller) syncService(key string) error {
 			return fmt.Errorf("failed to delete load balancers for service %s/%s: %w",
 				namespace, name, err)
 		}
+		if err := c.syncSourceRanges(key, service, nil); err != nil {
+			return err
+		}
 
 		c.repair.serviceSynced(key)
 		return nil
@@ -301,6 +337,11 @@ func (c *Controller) syncService(key string) error {
 		key, len(clusterConfigs), len(perNodeConfigs), len(clusterLBs), len(perNodeLBs))
 	lbs := append(clusterLBs, perNodeLBs...)
 
+	if svcNeedsHealthCheck(service) {
+		probes := c.getBackendProbes(service, endpointSlices, nodeInfos)
+		addHealthChecks(lbs, probes)
+	}
+
 	// Short-circuit if nothing has changed
 	c.alreadyAppliedLock.Lock()
 	existingLBs, ok := c.alreadyApplied[key]
@@ -321,6 +362,10 @@ func (c *Controller) syncService(key string) error {
 		c.alreadyAppliedLock.Unlock()
 	}
 
+	if err := c.syncSourceRanges(key, service, nodeInfos); err != nil {
+		return err
+	}
+
 	if !c.repair.legacyLBsDeleted() {
 		if err := deleteServiceFromLegacyLBs(service); err != nil {
 			klog.Warningf("Failed to delete legacy vips for service %s: %v", key)
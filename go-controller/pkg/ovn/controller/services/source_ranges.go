@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	globalconfig "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/acl"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+// sourceRangesACLs is the configuration of the ACLs enforcing the
+// loadBalancerSourceRanges of a service
+type sourceRangesACLs struct {
+	matches  []string
+	switches []string
+}
+
+// buildSourceRangesACLMatches returns the matches of the ACLs dropping the traffic to the
+// LoadBalancer and External IPs of a LoadBalancer service whose source isn't in the
+// service's loadBalancerSourceRanges. A family without ranges drops all the traffic.
+func buildSourceRangesACLMatches(service *v1.Service) []string {
+	if service.Spec.Type != v1.ServiceTypeLoadBalancer || len(service.Spec.LoadBalancerSourceRanges) == 0 {
+		return nil
+	}
+
+	vips := append([]string{}, service.Spec.ExternalIPs...)
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			vips = append(vips, ingress.IP)
+		}
+	}
+
+	matches := []string{}
+	for _, isIPv6 := range []bool{false, true} {
+		l3Prefix := "ip4"
+		if isIPv6 {
+			l3Prefix = "ip6"
+		}
+
+		familyVIPs := []string{}
+		for _, vip := range vips {
+			if utilnet.IsIPv6String(vip) == isIPv6 {
+				familyVIPs = append(familyVIPs, vip)
+			}
+		}
+		if len(familyVIPs) == 0 {
+			continue
+		}
+
+		ranges := []string{}
+		allowAll := false
+		for _, sourceRange := range service.Spec.LoadBalancerSourceRanges {
+			_, cidr, err := net.ParseCIDR(strings.TrimSpace(sourceRange))
+			if err != nil {
+				klog.Warningf("Ignoring invalid load balancer source range %q of service %s/%s",
+					sourceRange, service.Namespace, service.Name)
+				continue
+			}
+			if utilnet.IsIPv6CIDR(cidr) != isIPv6 {
+				continue
+			}
+			if ones, _ := cidr.Mask.Size(); ones == 0 {
+				allowAll = true
+				break
+			}
+			ranges = append(ranges, cidr.String())
+		}
+		if allowAll {
+			continue
+		}
+
+		for _, svcPort := range service.Spec.Ports {
+			proto := strings.ToLower(string(svcPort.Protocol))
+			match := fmt.Sprintf("%s.dst == {%s} && %s.dst == %d", l3Prefix, strings.Join(familyVIPs, ", "),
+				proto, svcPort.Port)
+			if len(ranges) > 0 {
+				match += fmt.Sprintf(" && %s.src != {%s}", l3Prefix, strings.Join(ranges, ", "))
+			}
+			matches = append(matches, match)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// syncSourceRanges drops the traffic to the LoadBalancer and External IPs of the service
+// coming from outside of its loadBalancerSourceRanges. The ACLs are applied to the cluster
+// port group, for the traffic from the pods and the management ports, and in shared gateway
+// mode to the external switches, for the traffic entering through the gateway routers.
+func (c *Controller) syncSourceRanges(key string, service *v1.Service, nodeInfos []nodeInfo) error {
+	want := sourceRangesACLs{
+		matches: buildSourceRangesACLMatches(service),
+	}
+	if len(want.matches) > 0 && globalconfig.Gateway.Mode == globalconfig.GatewayModeShared {
+		for _, node := range nodeInfos {
+			if node.gatewayRouterName != "" {
+				want.switches = append(want.switches, types.ExternalSwitchPrefix+node.name)
+			}
+		}
+	}
+
+	c.alreadyAppliedLock.Lock()
+	existing, ok := c.alreadyAppliedSourceRanges[key]
+	c.alreadyAppliedLock.Unlock()
+	// stale ACLs of services not applied since startup are removed by the repair
+	if (!ok && len(want.matches) == 0) || (ok && reflect.DeepEqual(existing, want)) {
+		return nil
+	}
+
+	if err := acl.SyncDropACLs(c.clusterPortGroupUUID, want.switches, types.LoadBalancerSourceRangesPriority,
+		util.ExternalIDsForObject(service), want.matches); err != nil {
+		return fmt.Errorf("failed to sync load balancer source ranges of service %s: %w", key, err)
+	}
+
+	c.alreadyAppliedLock.Lock()
+	if len(want.matches) == 0 {
+		delete(c.alreadyAppliedSourceRanges, key)
+	} else {
+		c.alreadyAppliedSourceRanges[key] = want
+	}
+	c.alreadyAppliedLock.Unlock()
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_buildSourceRangesACLMatches(t *testing.T) {
+	makeService := func(svcType v1.ServiceType, sourceRanges []string, externalIPs []string, ingressIPs ...string) *v1.Service {
+		svc := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "testns"},
+			Spec: v1.ServiceSpec{
+				Type:                     svcType,
+				ClusterIP:                "192.168.1.1",
+				ExternalIPs:              externalIPs,
+				LoadBalancerSourceRanges: sourceRanges,
+				Ports: []v1.ServicePort{
+					{Port: 80, Protocol: v1.ProtocolTCP},
+					{Port: 53, Protocol: v1.ProtocolUDP},
+				},
+			},
+		}
+		for _, ip := range ingressIPs {
+			svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, v1.LoadBalancerIngress{IP: ip})
+		}
+		return svc
+	}
+
+	tests := []struct {
+		name    string
+		service *v1.Service
+		want    []string
+	}{
+		{
+			name:    "no source ranges",
+			service: makeService(v1.ServiceTypeLoadBalancer, nil, nil, "5.5.5.5"),
+			want:    nil,
+		},
+		{
+			name:    "not a LoadBalancer service",
+			service: makeService(v1.ServiceTypeClusterIP, []string{"10.0.0.0/8"}, []string{"4.4.4.4"}),
+			want:    nil,
+		},
+		{
+			name:    "ingress and external IPs",
+			service: makeService(v1.ServiceTypeLoadBalancer, []string{"10.0.0.0/8", " 172.16.0.0/12"}, []string{"4.4.4.4"}, "5.5.5.5"),
+			want: []string{
+				"ip4.dst == {4.4.4.4, 5.5.5.5} && tcp.dst == 80 && ip4.src != {10.0.0.0/8, 172.16.0.0/12}",
+				"ip4.dst == {4.4.4.4, 5.5.5.5} && udp.dst == 53 && ip4.src != {10.0.0.0/8, 172.16.0.0/12}",
+			},
+		},
+		{
+			name:    "dual-stack, no IPv6 ranges",
+			service: makeService(v1.ServiceTypeLoadBalancer, []string{"10.0.0.0/8"}, nil, "5.5.5.5", "2001::5"),
+			want: []string{
+				"ip4.dst == {5.5.5.5} && tcp.dst == 80 && ip4.src != {10.0.0.0/8}",
+				"ip4.dst == {5.5.5.5} && udp.dst == 53 && ip4.src != {10.0.0.0/8}",
+				"ip6.dst == {2001::5} && tcp.dst == 80",
+				"ip6.dst == {2001::5} && udp.dst == 53",
+			},
+		},
+		{
+			name:    "allow all IPv4 sources, invalid range ignored",
+			service: makeService(v1.ServiceTypeLoadBalancer, []string{"0.0.0.0/0", "invalid", "fd00::/8"}, nil, "5.5.5.5", "2001::5"),
+			want: []string{
+				"ip6.dst == {2001::5} && tcp.dst == 80 && ip6.src != {fd00::/8}",
+				"ip6.dst == {2001::5} && udp.dst == 53 && ip6.src != {fd00::/8}",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSourceRangesACLMatches(tt.service)
+			if tt.want == nil {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -140,3 +140,40 @@ func svcNeedsIdling(annotations map[string]string) bool {
 	}
 	return false
 }
+
+// OvnServiceHealthCheckAnnotation opts a service in to OVN load balancer health checks
+const OvnServiceHealthCheckAnnotation = "k8s.ovn.org/lb-health-check"
+
+// svcNeedsHealthCheck returns true if OVN should probe the backends of the service,
+// which requires the health checks to be enabled cluster-wide.
+func svcNeedsHealthCheck(service *v1.Service) bool {
+	if !globalconfig.OVNKubernetesFeature.EnableLBHealthCheck {
+		return false
+	}
+	return service.Annotations[OvnServiceHealthCheckAnnotation] == "true"
+}
+
+// svcHasTopologyHints returns true if the service's endpoints should be
+// selected according to the zone hints of its endpoint slices.
+func svcHasTopologyHints(service *v1.Service) bool {
+	hints := service.Annotations[v1.AnnotationTopologyAwareHints]
+	return hints == "Auto" || hints == "auto"
+}
+
+// filterZoneHinted returns the IPs hinted for the given zone. If there are none,
+// all the IPs are returned, as the hints are then of no use for the zone.
+func filterZoneHinted(ips []string, zoneHints map[string][]string, zone string) []string {
+	out := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		for _, hintedZone := range zoneHints[ip] {
+			if hintedZone == zone {
+				out = append(out, ip)
+				break
+			}
+		}
+	}
+	if len(out) == 0 {
+		return ips
+	}
+	return out
+}
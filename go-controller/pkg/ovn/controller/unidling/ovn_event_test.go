@@ -4,56 +4,88 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/sbdb"
+
 	kapi "k8s.io/api/core/v1"
 )
 
-func Test_extractEmptyLBBackendsEvents(t *testing.T) {
-	type args struct {
-		out []byte
-	}
+func Test_extractEmptyLBBackendsEvent(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    args
-		want    []emptyLBBackendEvent
-		wantErr bool
+		name      string
+		event     *sbdb.ControllerEvent
+		want      emptyLBBackendEvent
+		wantFound bool
+		wantErr   bool
 	}{
 		{
 			name: "loadbalancer empty event",
-			args: args{
-				out: ([]byte)(`{"data":[[["uuid","d8d63259-0ce4-41e8-bcbd-967d896d5b54"],["uuid","bea419d0-ff07-4e9d-878d-4e204eaeae20"],["map",[["load_balancer","420a18ee-0c34-4f87-81a3-39cb11c5e6be"],["protocol","tcp"],["vip","172.30.72.79:80"]]],"empty_lb_backends",8]],"headings":["_uuid","chassis","event_info","event_type","seq_num"]}`),
+			event: &sbdb.ControllerEvent{
+				UUID: "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
+				EventInfo: map[string]string{
+					"load_balancer": "420a18ee-0c34-4f87-81a3-39cb11c5e6be",
+					"protocol":      "tcp",
+					"vip":           "172.30.72.79:80",
+				},
+				EventType: sbdb.ControllerEventEventTypeEmptyLbBackends,
+				SeqNum:    8,
 			},
-			want: []emptyLBBackendEvent{
-				{
-					vip:      "172.30.72.79:80",
-					protocol: kapi.ProtocolTCP,
-					uuid:     "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
+			want: emptyLBBackendEvent{
+				vip:      "172.30.72.79:80",
+				protocol: kapi.ProtocolTCP,
+				uuid:     "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
+			},
+			wantFound: true,
+		},
+		{
+			name: "udp loadbalancer empty event",
+			event: &sbdb.ControllerEvent{
+				UUID: "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
+				EventInfo: map[string]string{
+					"protocol": "udp",
+					"vip":      "[fd00::10]:53",
 				},
+				EventType: sbdb.ControllerEventEventTypeEmptyLbBackends,
+			},
+			want: emptyLBBackendEvent{
+				vip:      "[fd00::10]:53",
+				protocol: kapi.ProtocolUDP,
+				uuid:     "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
 			},
-			wantErr: false,
+			wantFound: true,
 		},
 		{
-			name: "loadbalancer wrong format  event",
-			args: args{
-				out: ([]byte)(`{"data":[[["uuid","d8d63259-0ce4-41e8-bcbd-967d896d5b54"],["uuid","bea419d0-ff07-4e9d-878d-4e204eaeae20"],["map",[["load_balancer","420a18ee-0c34-4f87-81a3-39cb11c5e6be"],["protocol","tcp"]],"empty_lb_backends",8]],"headings":["_uuid","chassis","event_info","event_type","seq_num"]}`),
+			name: "loadbalancer wrong format event",
+			event: &sbdb.ControllerEvent{
+				UUID: "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
+				EventInfo: map[string]string{
+					"load_balancer": "420a18ee-0c34-4f87-81a3-39cb11c5e6be",
+					"protocol":      "tcp",
+				},
+				EventType: sbdb.ControllerEventEventTypeEmptyLbBackends,
 			},
-			want:    []emptyLBBackendEvent{},
-			wantErr: true,
+			wantFound: true,
+			wantErr:   true,
 		},
 		{
-			name:    "no event",
-			want:    []emptyLBBackendEvent{},
-			wantErr: true,
+			name: "other event type",
+			event: &sbdb.ControllerEvent{
+				UUID:      "d8d63259-0ce4-41e8-bcbd-967d896d5b54",
+				EventType: "other",
+			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := extractEmptyLBBackendsEvents(tt.args.out)
+			got, found, err := extractEmptyLBBackendsEvent(tt.event)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("extractEmptyLBBackendsEvents() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("extractEmptyLBBackendsEvent() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if found != tt.wantFound {
+				t.Errorf("extractEmptyLBBackendsEvent() found = %v, want %v", found, tt.wantFound)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("extractEmptyLBBackendsEvents() = %v, want %v", got, tt.want)
+				t.Errorf("extractEmptyLBBackendsEvent() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -1,22 +1,38 @@
 package unidling
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	libovsdbcache "github.com/ovn-org/libovsdb/cache"
 	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/libovsdbops"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/sbdb"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 
 	"k8s.io/klog/v2"
 )
 
-// unidlingController checks periodically the OVN events db
+const (
+	controllerEventTable = "Controller_Event"
+	// maxRetries is the number of times a controller event will be retried before it is dropped out of the queue
+	maxRetries = 15
+	// eventResyncPeriod is the period of the resync of the controller events left in the cache
+	eventResyncPeriod = time.Minute
+)
+
+// unidlingController watches the OVN events db
 // and generates a Kubernetes NeedPods events with the Service
 // associated to the VIP
 type unidlingController struct {
@@ -25,6 +41,12 @@ type unidlingController struct {
 	serviceVIPToName     map[ServiceVIPKey]types.NamespacedName
 	serviceVIPToNameLock sync.Mutex
 	sbClient             libovsdbclient.Client
+
+	// queue of the UUIDs of the controller events to process
+	queue workqueue.RateLimitingInterface
+	// when the controller events in the queue were received, for the latency metrics
+	received     map[string]time.Time
+	receivedLock sync.Mutex
 }
 
 // NewController creates a new unidling controller
@@ -33,6 +55,8 @@ func NewController(recorder record.EventRecorder, serviceInformer cache.SharedIn
 		eventRecorder:    recorder,
 		serviceVIPToName: map[ServiceVIPKey]types.NamespacedName{},
 		sbClient:         sbClient,
+		queue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "unidling"),
+		received:         map[string]time.Time{},
 	}
 
 	// we only process events on unidling, there is no reconcilation
@@ -45,6 +69,11 @@ func NewController(recorder record.EventRecorder, serviceInformer cache.SharedIn
 		},
 		DeleteFunc: uc.onServiceDelete,
 	})
+
+	klog.Info("Setting up event handlers for controller events")
+	sbClient.Cache().AddEventHandler(&libovsdbcache.EventHandlerFuncs{
+		AddFunc: uc.onControllerEventAdd,
+	})
 	return uc
 }
 
@@ -116,41 +145,148 @@ func (uc *unidlingController) DeleteServiceVIPToName(vip string, protocol v1.Pro
 	delete(uc.serviceVIPToName, ServiceVIPKey{vip, protocol})
 }
 
+// Run monitors the Controller_Event table of the OVN southbound database and
+// processes the empty load balancer backends events until stopCh is closed
 func (uc *unidlingController) Run(stopCh <-chan struct{}) {
-	ticker := time.NewTicker(5 * time.Second)
-
-	for {
-		select {
-		case <-ticker.C:
-			out, _, err := util.RunOVNSbctl("--format=json", "list", "controller_event")
-			if err != nil {
-				continue
-			}
+	defer utilruntime.HandleCrash()
+	defer uc.queue.ShutDown()
 
-			events, err := extractEmptyLBBackendsEvents([]byte(out))
-			if err != nil || len(events) == 0 {
-				continue
-			}
+	go wait.Until(uc.worker, time.Second, stopCh)
+	// the libovsdb cache drops events when its buffer is full, and the rows
+	// cached before the handler was added are never notified: catch up
+	// periodically, and right away, with the rows left in the cache
+	go wait.Until(uc.resync, eventResyncPeriod, stopCh)
 
-			for _, event := range events {
-				_, _, err := util.RunOVNSbctl("destroy", "controller_event", event.uuid)
-				if err != nil {
-					// Don't unidle until we are able to remove the controller event
-					klog.Errorf("Unable to remove controller event %s", event.uuid)
-					continue
-				}
-				if serviceName, ok := uc.GetServiceVIPToName(event.vip, event.protocol); ok {
-					serviceRef := v1.ObjectReference{
-						Kind:      "Service",
-						Namespace: serviceName.Namespace,
-						Name:      serviceName.Name,
-					}
-					klog.V(5).Infof("Sending a NeedPods event for service %s in namespace %s.", serviceName.Name, serviceName.Namespace)
-					uc.eventRecorder.Eventf(&serviceRef, v1.EventTypeNormal, "NeedPods", "The service %s needs pods", serviceName.Name)
-				}
-			}
-		case <-stopCh:
-			return
+	<-stopCh
+}
+
+// onControllerEventAdd queues the empty load balancer backends events.
+// It is called by the libovsdb cache and must not block.
+func (uc *unidlingController) onControllerEventAdd(table string, m model.Model) {
+	if table != controllerEventTable {
+		return
+	}
+	event, ok := m.(*sbdb.ControllerEvent)
+	if !ok || event.EventType != sbdb.ControllerEventEventTypeEmptyLbBackends {
+		return
+	}
+	uc.enqueue(event.UUID)
+}
+
+// resync queues the empty load balancer backends events still in the cache
+func (uc *unidlingController) resync() {
+	events := []sbdb.ControllerEvent{}
+	err := uc.sbClient.WhereCache(func(event *sbdb.ControllerEvent) bool {
+		return event.EventType == sbdb.ControllerEventEventTypeEmptyLbBackends
+	}).List(&events)
+	if err != nil {
+		klog.Errorf("Unable to list the OVN controller events: %v", err)
+		return
+	}
+	for _, event := range events {
+		uc.enqueue(event.UUID)
+	}
+}
+
+// enqueue queues an event, recording when it was first received
+func (uc *unidlingController) enqueue(uuid string) {
+	uc.receivedLock.Lock()
+	if _, ok := uc.received[uuid]; !ok {
+		uc.received[uuid] = time.Now()
+	}
+	uc.receivedLock.Unlock()
+	uc.queue.Add(uuid)
+}
+
+func (uc *unidlingController) worker() {
+	for uc.processNextWorkItem() {
+	}
+}
+
+func (uc *unidlingController) processNextWorkItem() bool {
+	key, quit := uc.queue.Get()
+	if quit {
+		return false
+	}
+	defer uc.queue.Done(key)
+
+	uuid := key.(string)
+	err := uc.handleEvent(uuid)
+	if err == nil {
+		uc.forget(uuid)
+		return true
+	}
+
+	if uc.queue.NumRequeues(key) < maxRetries {
+		klog.V(2).Infof("Error handling controller event %s, retrying: %v", uuid, err)
+		uc.queue.AddRateLimited(key)
+		return true
+	}
+
+	klog.Warningf("Dropping controller event %s out of the queue: %v", uuid, err)
+	metrics.MetricUnidleEventCount.WithLabelValues("error").Inc()
+	uc.forget(uuid)
+	utilruntime.HandleError(err)
+	return true
+}
+
+func (uc *unidlingController) forget(uuid string) {
+	uc.queue.Forget(uuid)
+	uc.receivedLock.Lock()
+	delete(uc.received, uuid)
+	uc.receivedLock.Unlock()
+}
+
+// handleEvent removes the controller event from the southbound database
+// and emits a NeedPods event for the service of its VIP
+func (uc *unidlingController) handleEvent(uuid string) error {
+	row := &sbdb.ControllerEvent{UUID: uuid}
+	if err := uc.sbClient.Get(row); err != nil {
+		if errors.Is(err, libovsdbclient.ErrNotFound) {
+			// already handled
+			return nil
 		}
+		return err
+	}
+	event, ok, err := extractEmptyLBBackendsEvent(row)
+	if !ok {
+		return nil
+	}
+
+	ops, opErr := uc.sbClient.Where(row).Delete()
+	if opErr != nil {
+		return fmt.Errorf("unable to remove controller event %s: %v", uuid, opErr)
+	}
+	// Don't unidle until we are able to remove the controller event
+	if _, opErr = libovsdbops.TransactAndCheck(uc.sbClient, ops); opErr != nil {
+		return fmt.Errorf("unable to remove controller event %s: %v", uuid, opErr)
+	}
+	if err != nil {
+		klog.Warningf("Ignoring malformed controller event: %v", err)
+		metrics.MetricUnidleEventCount.WithLabelValues("malformed").Inc()
+		return nil
+	}
+
+	serviceName, ok := uc.GetServiceVIPToName(event.vip, event.protocol)
+	if !ok {
+		klog.V(5).Infof("No service found for the %s vip %s of controller event %s", event.protocol, event.vip, uuid)
+		metrics.MetricUnidleEventCount.WithLabelValues("unknown_service").Inc()
+		return nil
+	}
+	serviceRef := v1.ObjectReference{
+		Kind:      "Service",
+		Namespace: serviceName.Namespace,
+		Name:      serviceName.Name,
+	}
+	klog.V(5).Infof("Sending a NeedPods event for service %s in namespace %s.", serviceName.Name, serviceName.Namespace)
+	uc.eventRecorder.Eventf(&serviceRef, v1.EventTypeNormal, "NeedPods", "The service %s needs pods", serviceName.Name)
+
+	metrics.MetricUnidleEventCount.WithLabelValues("need_pods").Inc()
+	uc.receivedLock.Lock()
+	received, ok := uc.received[uuid]
+	uc.receivedLock.Unlock()
+	if ok {
+		metrics.MetricUnidleNeedPodsLatency.Observe(time.Since(received).Seconds())
 	}
+	return nil
 }
@@ -0,0 +1,82 @@
+package unidling
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	libovsdbclient "github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/sbdb"
+	libovsdbtest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestUnidlingControllerNeedPods(t *testing.T) {
+	const eventUUID = "d8d63259-0ce4-41e8-bcbd-967d896d5b54"
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+
+	sbClient, err := libovsdbtest.NewSBTestHarness(libovsdbtest.TestSetup{
+		SBData: []libovsdbtest.TestData{
+			&sbdb.ControllerEvent{
+				UUID: eventUUID,
+				EventInfo: map[string]string{
+					"load_balancer": "420a18ee-0c34-4f87-81a3-39cb11c5e6be",
+					"protocol":      "tcp",
+					"vip":           "192.168.1.1:80",
+				},
+				EventType: sbdb.ControllerEventEventTypeEmptyLbBackends,
+				SeqNum:    1,
+			},
+		},
+	}, stopChan)
+	if err != nil {
+		t.Fatalf("Error creating the southbound test harness: %v", err)
+	}
+
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "testns"},
+		Spec: v1.ServiceSpec{
+			Type:       v1.ServiceTypeClusterIP,
+			ClusterIP:  "192.168.1.1",
+			ClusterIPs: []string{"192.168.1.1"},
+			Ports:      []v1.ServicePort{{Port: 80, Protocol: v1.ProtocolTCP}},
+		},
+	}
+	client := fake.NewSimpleClientset(service)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	serviceInformer := informerFactory.Core().V1().Services().Informer()
+	recorder := record.NewFakeRecorder(10)
+
+	uc := NewController(recorder, serviceInformer, sbClient)
+	informerFactory.Start(stopChan)
+	informerFactory.WaitForCacheSync(stopChan)
+	go uc.Run(stopChan)
+
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "NeedPods") || !strings.Contains(event, "The service foo needs pods") {
+			t.Errorf("Unexpected event %q", event)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the NeedPods event")
+	}
+
+	// the controller event is removed from the southbound database
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		err := sbClient.Get(&sbdb.ControllerEvent{UUID: eventUUID})
+		if err == libovsdbclient.ErrNotFound {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Controller event not removed: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
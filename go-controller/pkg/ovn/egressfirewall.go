@@ -3,20 +3,25 @@ package ovn
 import (
 	"fmt"
 	"net"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 
-	"github.com/pkg/errors"
-
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
-	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressfirewallapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
@@ -26,6 +31,15 @@ const (
 	egressFirewallAppliedCorrectly = "EgressFirewall Rules applied"
 	egressFirewallAddError         = "EgressFirewall Rules not correctly added"
 	egressFirewallUpdateError      = "EgressFirewall Rules not correctly updated"
+
+	egressFirewallRuleApplied    = "Applied"
+	egressFirewallRuleFailed     = "Failed"
+	egressFirewallRuleNotApplied = "NotApplied"
+
+	// clusterEgressFirewallPrefix prefixes the name of a clusterEgressFirewall to build the key
+	// used in place of a namespace for its ACLs and address sets, it cannot clash with a
+	// namespace name
+	clusterEgressFirewallPrefix = "cef_"
 )
 
 type egressFirewall struct {
@@ -40,11 +54,19 @@ type egressFirewallRule struct {
 	access egressfirewallapi.EgressFirewallRuleType
 	ports  []egressfirewallapi.EgressFirewallPort
 	to     destination
+	// podSelector is nil when the rule applies to all the pods of the namespace
+	podSelector labels.Selector
+	// podAddressSet holds the IPs of the pods matching podSelector
+	podAddressSet addressset.AddressSet
+	podHandler    *factory.Handler
 }
 
 type destination struct {
 	cidrSelector string
 	dnsName      string
+	nodeSelector labels.Selector
+	// nodeAddressSet holds the IPs of the nodes matching nodeSelector
+	nodeAddressSet addressset.AddressSet
 }
 
 // cloneEgressFirewall shallow copies the egressfirewallapi.EgressFirewall object provided.
@@ -59,14 +81,52 @@ func cloneEgressFirewall(originalEgressfirewall *egressfirewallapi.EgressFirewal
 	return ef
 }
 
-func newEgressFirewallRule(rawEgressFirewallRule egressfirewallapi.EgressFirewallRule, id int) (*egressFirewallRule, error) {
+// getClusterEgressFirewallKey returns the key of a clusterEgressFirewall, which is used in place
+// of the namespace of an egressFirewall
+func getClusterEgressFirewallKey(name string) string {
+	return clusterEgressFirewallPrefix + name
+}
+
+// isClusterEgressFirewallKey tells whether key is the key of a clusterEgressFirewall rather
+// than the namespace of an egressFirewall
+func isClusterEgressFirewallKey(key string) bool {
+	return strings.HasPrefix(key, clusterEgressFirewallPrefix)
+}
+
+// newEgressFirewallRule parses a rule of an egressFirewall, podSelector is the podSelector
+// of the egressFirewall spec
+func newEgressFirewallRule(rawEgressFirewallRule egressfirewallapi.EgressFirewallRule, id int,
+	podSelector *metav1.LabelSelector) (*egressFirewallRule, error) {
 	efr := &egressFirewallRule{
 		id:     id,
 		access: rawEgressFirewallRule.Type,
 	}
 
+	// the rule applies to the pods matching both the podSelector of the spec and its own
+	var podRequirements labels.Requirements
+	for _, selector := range []*metav1.LabelSelector{podSelector, rawEgressFirewallRule.PodSelector} {
+		if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+			continue
+		}
+		parsedSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+		requirements, _ := parsedSelector.Requirements()
+		podRequirements = append(podRequirements, requirements...)
+	}
+	if len(podRequirements) > 0 {
+		efr.podSelector = labels.NewSelector().Add(podRequirements...)
+	}
+
 	if rawEgressFirewallRule.To.DNSName != "" {
 		efr.to.dnsName = rawEgressFirewallRule.To.DNSName
+	} else if rawEgressFirewallRule.To.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rawEgressFirewallRule.To.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+		efr.to.nodeSelector = selector
 	} else {
 
 		_, _, err := net.ParseCIDR(rawEgressFirewallRule.To.CIDRSelector)
@@ -75,11 +135,49 @@ func newEgressFirewallRule(rawEgressFirewallRule egressfirewallapi.EgressFirewal
 		}
 		efr.to.cidrSelector = rawEgressFirewallRule.To.CIDRSelector
 	}
+	for _, port := range rawEgressFirewallRule.Ports {
+		if err := validateEgressFirewallPort(port); err != nil {
+			return nil, err
+		}
+		// ICMP only matches IPv4 and ICMPv6 only IPv6 destinations, reject a CIDR of the
+		// other family rather than rendering a rule that never matches
+		if efr.to.cidrSelector == "" {
+			continue
+		}
+		isIPv6 := utilnet.IsIPv6CIDRString(efr.to.cidrSelector)
+		if (port.Protocol == egressfirewallapi.ProtocolICMP && isIPv6) ||
+			(port.Protocol == egressfirewallapi.ProtocolICMPv6 && !isIPv6) {
+			return nil, fmt.Errorf("protocol %s does not match the family of cidrSelector %s",
+				port.Protocol, efr.to.cidrSelector)
+		}
+	}
 	efr.ports = rawEgressFirewallRule.Ports
 
 	return efr, nil
 }
 
+// validateEgressFirewallPort checks the combination of the fields of a port, which cannot be
+// expressed in the CRD validation
+func validateEgressFirewallPort(port egressfirewallapi.EgressFirewallPort) error {
+	switch port.Protocol {
+	case egressfirewallapi.ProtocolICMP, egressfirewallapi.ProtocolICMPv6:
+		if port.Port != 0 || port.EndPort != 0 {
+			return fmt.Errorf("port and endPort cannot be set for protocol %s", port.Protocol)
+		}
+		if port.ICMPCode != nil && port.ICMPType == nil {
+			return fmt.Errorf("icmpCode requires icmpType")
+		}
+	default:
+		if port.ICMPType != nil || port.ICMPCode != nil {
+			return fmt.Errorf("icmpType and icmpCode cannot be set for protocol %s", port.Protocol)
+		}
+		if port.EndPort != 0 && (port.Port == 0 || port.EndPort < port.Port) {
+			return fmt.Errorf("endPort %d requires a port lower than or equal to it", port.EndPort)
+		}
+	}
+	return nil
+}
+
 // This function is used to sync egress firewall setup. It does three "cleanups"
 
 // - 	Cleanup the old implementation (using LRP) in local GW mode -> new implementation (using ACLs) local GW mode
@@ -92,25 +190,39 @@ func newEgressFirewallRule(rawEgressFirewallRule egressfirewallapi.EgressFirewal
 // -	Cleanup the old implementation (using LRP) in local GW mode -> shared GW mode implementation (using ACLs on the join switch)
 //  	For this it just deletes all LRP setup done for egress firewall
 
-// NOTE: Utilize the fact that we know that all egress firewall related setup must have a priority: types.MinimumReservedEgressFirewallPriority <= priority <= types.EgressFirewallStartPriority
+// NOTE: Utilize the fact that we know that all egress firewall related setup must have a priority: types.MinimumReservedClusterEgressFirewallPriority <= priority <= types.EgressFirewallStartPriority
 func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 	if config.Gateway.Mode == config.GatewayModeShared {
 		// Mode is shared gateway mode, make sure to delete all ACLs on the node switches
-		egressFirewallACLIDs, stderr, err := util.RunOVNNbctl(
+		stdout, stderr, err := util.RunOVNNbctl(
 			"--data=bare",
 			"--no-heading",
-			"--columns=_uuid",
+			"--columns=_uuid,external_ids",
 			"--format=table",
 			"find",
 			"acl",
 			fmt.Sprintf("priority<=%s", types.EgressFirewallStartPriority),
-			fmt.Sprintf("priority>=%s", types.MinimumReservedEgressFirewallPriority),
+			fmt.Sprintf("priority>=%s", types.MinimumReservedClusterEgressFirewallPriority),
 		)
 		if err != nil {
 			klog.Errorf("Unable to list egress firewall logical router policies, cannot cleanup old stale data, stderr: %s, err: %v", stderr, err)
 			return
 		}
-		if egressFirewallACLIDs != "" {
+		// only the ACLs owned by an egressFirewall or a clusterEgressFirewall are removed
+		var egressFirewallACLIDs []string
+		for _, line := range strings.Split(stdout, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			for _, externalID := range fields[1:] {
+				if strings.HasPrefix(externalID, "egressFirewall=") {
+					egressFirewallACLIDs = append(egressFirewallACLIDs, fields[0])
+					break
+				}
+			}
+		}
+		if len(egressFirewallACLIDs) > 0 {
 			nodes, err := oc.watchFactory.GetNodes()
 			if err != nil {
 				klog.Errorf("Unable to cleanup egress firewall ACLs remaining from local gateway mode, cannot list nodes, err: %v", err)
@@ -133,7 +245,7 @@ func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 				if err != nil {
 					klog.Errorf("Unable to remove egress firewall acl, cannot list ACLs on switch: %s, stderr: %s, err: %v", logicalSwitch, stderr, err)
 				}
-				for _, egressFirewallACLID := range strings.Fields(egressFirewallACLIDs) {
+				for _, egressFirewallACLID := range egressFirewallACLIDs {
 					if strings.Contains(switchACLs, egressFirewallACLID) {
 						_, stderr, err := util.RunOVNNbctl(
 							"remove",
@@ -158,7 +270,7 @@ func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 		"find",
 		"acl",
 		fmt.Sprintf("priority<=%s", types.EgressFirewallStartPriority),
-		fmt.Sprintf("priority>=%s", types.MinimumReservedEgressFirewallPriority),
+		fmt.Sprintf("priority>=%s", types.MinimumReservedClusterEgressFirewallPriority),
 		fmt.Sprintf("direction=%s", types.DirectionFromLPort),
 	)
 	if err != nil {
@@ -188,7 +300,7 @@ func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 		"find",
 		"logical_router_policy",
 		fmt.Sprintf("priority<=%s", types.EgressFirewallStartPriority),
-		fmt.Sprintf("priority>=%s", types.MinimumReservedEgressFirewallPriority),
+		fmt.Sprintf("priority>=%s", types.MinimumReservedClusterEgressFirewallPriority),
 	)
 	if err != nil {
 		klog.Errorf("Unable to list egress firewall logical router policies, cannot cleanup old stale data, stderr: %s, err: %v", stderr, err)
@@ -218,7 +330,7 @@ func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 		"find",
 		"acl",
 		fmt.Sprintf("priority<=%s", types.EgressFirewallStartPriority),
-		fmt.Sprintf("priority>=%s", types.MinimumReservedEgressFirewallPriority),
+		fmt.Sprintf("priority>=%s", types.MinimumReservedClusterEgressFirewallPriority),
 	)
 	if err != nil {
 		klog.Errorf("Cannot reconcile the state of egressfirewalls in ovn database and k8s. stderr: %s, err: %v", stderr, err)
@@ -233,7 +345,12 @@ func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 			// Most egressFirewalls will have more then one ACL but we only need to know if there is one for the namespace
 			// so a map is fine and we will add an entry every iteration but because it is a map will overwrite the previous
 			// entry if it already existed
-			ovnEgressFirewalls[strings.Split(externalID, "egressFirewall=")[1]] = struct{}{}
+			namespace := strings.Split(externalID, "egressFirewall=")[1]
+			if isClusterEgressFirewallKey(namespace) {
+				// the ACLs of the clusterEgressFirewalls are synced by syncClusterEgressFirewall
+				continue
+			}
+			ovnEgressFirewalls[namespace] = struct{}{}
 		}
 	}
 
@@ -265,6 +382,7 @@ func (oc *Controller) syncEgressFirewall(egressFirwalls []interface{}) {
 func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.EgressFirewall, txn *util.NBTxn) error {
 	klog.Infof("Adding egressFirewall %s in namespace %s", egressFirewall.Name, egressFirewall.Namespace)
 
+	aclLogging := oc.getEgressFirewallACLLogging(egressFirewall.Namespace)
 	ef := cloneEgressFirewall(egressFirewall)
 	ef.Lock()
 	defer ef.Unlock()
@@ -274,7 +392,6 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 			egressFirewall.Name, egressFirewall.Namespace)
 	}
 
-	var addErrors error
 	egressFirewallStartPriorityInt, err := strconv.Atoi(types.EgressFirewallStartPriority)
 	if err != nil {
 		return fmt.Errorf("failed to convert egressFirewallStartPriority to Integer: cannot add egressFirewall for namespace %s", egressFirewall.Namespace)
@@ -283,6 +400,7 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 	if err != nil {
 		return fmt.Errorf("failed to convert minumumReservedEgressFirewallPriority to Integer: cannot add egressFirewall for namespace %s", egressFirewall.Namespace)
 	}
+	var addErrors []error
 	for i, egressFirewallRule := range egressFirewall.Spec.Egress {
 		// process Rules into egressFirewallRules for egressFirewall struct
 		if i > egressFirewallStartPriorityInt-minimumReservedEgressFirewallPriorityInt {
@@ -290,17 +408,16 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 				egressFirewall.Namespace)
 			break
 		}
-		efr, err := newEgressFirewallRule(egressFirewallRule, i)
+		efr, err := newEgressFirewallRule(egressFirewallRule, i, egressFirewall.Spec.PodSelector)
 		if err != nil {
-			addErrors = errors.Wrapf(addErrors, "error: cannot create EgressFirewall Rule to destination %s for namespace %s - %v",
-				egressFirewallRule.To.CIDRSelector, egressFirewall.Namespace, err)
+			addErrors = append(addErrors, fmt.Errorf("cannot create EgressFirewall Rule %d for namespace %s: %v",
+				i, egressFirewall.Namespace, err))
 			continue
-
 		}
 		ef.egressRules = append(ef.egressRules, efr)
 	}
-	if addErrors != nil {
-		return addErrors
+	if len(addErrors) > 0 {
+		return kerrors.NewAggregate(addErrors)
 	}
 
 	// EgressFirewall needs to make sure that the address_set for the namespace exists independently of the namespace object
@@ -310,8 +427,14 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 	if err != nil {
 		return fmt.Errorf("cannot Ensure that addressSet for namespace %s exists %v", egressFirewall.Namespace, err)
 	}
+	if err := oc.ensureEgressFirewallNodeAddressSets(ef); err != nil {
+		return err
+	}
+	if err := oc.ensureEgressFirewallPodAddressSets(ef); err != nil {
+		return err
+	}
 	ipv4HashedAS, ipv6HashedAS := addressset.MakeAddressSetHashNames(egressFirewall.Namespace)
-	err = oc.addEgressFirewallRules(ef, ipv4HashedAS, ipv6HashedAS, egressFirewallStartPriorityInt, txn)
+	err = oc.addEgressFirewallRules(ef, ipv4HashedAS, ipv6HashedAS, egressFirewallStartPriorityInt, aclLogging, txn)
 	if err != nil {
 		return err
 	}
@@ -319,38 +442,111 @@ func (oc *Controller) addEgressFirewall(egressFirewall *egressfirewallapi.Egress
 	return nil
 }
 
-func (oc *Controller) updateEgressFirewall(oldEgressFirewall, newEgressFirewall *egressfirewallapi.EgressFirewall, txn *util.NBTxn) error {
-	updateErrors := oc.deleteEgressFirewall(oldEgressFirewall, txn)
-	if updateErrors != nil {
-		return updateErrors
+// updateEgressFirewall replaces the ACLs of oldEgressFirewall with the ones of newEgressFirewall
+// in a single transaction, then destroys the address sets the new rules do not use anymore
+func (oc *Controller) updateEgressFirewall(oldEgressFirewall, newEgressFirewall *egressfirewallapi.EgressFirewall) error {
+	klog.Infof("Updating egress Firewall %s in namespace %s", newEgressFirewall.Name, newEgressFirewall.Namespace)
+	txn := util.NewNBTxn()
+	oldEF, err := oc.removeEgressFirewall(oldEgressFirewall.Namespace, txn)
+	if err != nil {
+		return err
+	}
+	defer oldEF.Unlock()
+	if err := oc.addEgressFirewall(newEgressFirewall, txn); err != nil {
+		return err
 	}
-	updateErrors = oc.addEgressFirewall(newEgressFirewall, txn)
-	return updateErrors
+	return oc.commitEgressFirewallRemoval(oldEF, newEgressFirewall.Namespace, txn)
 }
 
-func (oc *Controller) deleteEgressFirewall(egressFirewallObj *egressfirewallapi.EgressFirewall, txn *util.NBTxn) error {
+func (oc *Controller) deleteEgressFirewall(egressFirewallObj *egressfirewallapi.EgressFirewall) error {
 	klog.Infof("Deleting egress Firewall %s in namespace %s", egressFirewallObj.Name, egressFirewallObj.Namespace)
-	deleteDNS := false
-	obj, loaded := oc.egressFirewalls.LoadAndDelete(egressFirewallObj.Namespace)
+	return oc.deleteEgressFirewallByKey(egressFirewallObj.Namespace)
+}
+
+// deleteEgressFirewallByKey deletes the ACLs and address sets of the egressFirewall of a
+// namespace or of a clusterEgressFirewall, stored under key in the egressFirewalls map
+func (oc *Controller) deleteEgressFirewallByKey(key string) error {
+	txn := util.NewNBTxn()
+	ef, err := oc.removeEgressFirewall(key, txn)
+	if err != nil {
+		return err
+	}
+	defer ef.Unlock()
+	return oc.commitEgressFirewallRemoval(ef, "", txn)
+}
+
+// removeEgressFirewall removes the egressFirewall stored under key from the egressFirewalls
+// map, stops its handlers and adds the removal of its ACLs to txn. The egressFirewall is
+// returned locked.
+func (oc *Controller) removeEgressFirewall(key string, txn *util.NBTxn) (*egressFirewall, error) {
+	obj, loaded := oc.egressFirewalls.LoadAndDelete(key)
 	if !loaded {
-		return fmt.Errorf("there is no egressFirewall found in namespace %s", egressFirewallObj.Namespace)
+		return nil, fmt.Errorf("there is no egressFirewall found for %s", key)
 	}
 
-	ef, _ := obj.(egressFirewall)
+	ef, ok := obj.(*egressFirewall)
+	if !ok {
+		return nil, fmt.Errorf("spurious object found in egressFirewall map for %s: %v", key, obj)
+	}
 
 	ef.Lock()
-	defer ef.Unlock()
+	deleteDNS := false
 	for _, rule := range ef.egressRules {
 		if len(rule.to.dnsName) > 0 {
 			deleteDNS = true
-			break
+		}
+		if rule.podHandler != nil {
+			oc.watchFactory.RemovePodHandler(rule.podHandler)
 		}
 	}
 	if deleteDNS {
-		oc.egressFirewallDNS.Delete(egressFirewallObj.Namespace)
+		oc.egressFirewallDNS.Delete(key)
 	}
 
-	return oc.deleteEgressFirewallRules(egressFirewallObj.Namespace, txn)
+	if err := oc.deleteEgressFirewallRules(key, txn); err != nil {
+		ef.Unlock()
+		return nil, err
+	}
+	return ef, nil
+}
+
+// commitEgressFirewallRemoval commits txn, removing the ACLs of ef, and only then destroys
+// the address sets the ACLs refer to, except the ones in use by the egressFirewall stored
+// under newKey, if any. The address sets are all attempted, failures are aggregated.
+func (oc *Controller) commitEgressFirewallRemoval(ef *egressFirewall, newKey string, txn *util.NBTxn) error {
+	if _, stderr, err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit db changes for egressFirewall %s stderr: %q, err: %+v", ef.namespace, stderr, err)
+	}
+
+	inUse := sets.NewString()
+	if obj, ok := oc.egressFirewalls.Load(newKey); ok && newKey != "" {
+		newEF := obj.(*egressFirewall)
+		newEF.Lock()
+		for _, rule := range newEF.egressRules {
+			if rule.to.nodeAddressSet != nil {
+				inUse.Insert(rule.to.nodeAddressSet.GetName())
+			}
+			if rule.podAddressSet != nil {
+				inUse.Insert(rule.podAddressSet.GetName())
+			}
+		}
+		newEF.Unlock()
+	}
+
+	var deleteErrors []error
+	for _, rule := range ef.egressRules {
+		if rule.to.nodeAddressSet != nil && !inUse.Has(rule.to.nodeAddressSet.GetName()) {
+			if err := rule.to.nodeAddressSet.Destroy(); err != nil {
+				deleteErrors = append(deleteErrors, fmt.Errorf("cannot delete node addressSet of egressFirewall for %s: %v", ef.namespace, err))
+			}
+		}
+		if rule.podAddressSet != nil && !inUse.Has(rule.podAddressSet.GetName()) {
+			if err := rule.podAddressSet.Destroy(); err != nil {
+				deleteErrors = append(deleteErrors, fmt.Errorf("cannot delete pod addressSet of egressFirewall for %s: %v", ef.namespace, err))
+			}
+		}
+	}
+	return kerrors.NewAggregate(deleteErrors)
 }
 
 func (oc *Controller) updateEgressFirewallWithRetry(egressfirewall *egressfirewallapi.EgressFirewall) error {
@@ -364,14 +560,147 @@ func (oc *Controller) updateEgressFirewallWithRetry(egressfirewall *egressfirewa
 	return nil
 }
 
-func (oc *Controller) addEgressFirewallRules(ef *egressFirewall, hashedAddressSetNameIPv4, hashedAddressSetNameIPv6 string, efStartPriority int, txn *util.NBTxn) error {
+// syncClusterEgressFirewall removes the ACLs of the clusterEgressFirewalls deleted while
+// ovnkube-master was down
+func (oc *Controller) syncClusterEgressFirewall(clusterEgressFirewalls []interface{}) {
+	expectedKeys := sets.NewString()
+	for _, obj := range clusterEgressFirewalls {
+		clusterEgressFirewall, ok := obj.(*egressfirewallapi.ClusterEgressFirewall)
+		if !ok {
+			klog.Errorf("Spurious object in syncClusterEgressFirewall: %v", obj)
+			continue
+		}
+		expectedKeys.Insert(getClusterEgressFirewallKey(clusterEgressFirewall.Name))
+	}
+
+	ovnEgressFirewallExternalIDs, stderr, err := util.RunOVNNbctl(
+		"--data=bare",
+		"--no-heading",
+		"--columns=external_id",
+		"--format=table",
+		"find",
+		"acl",
+		fmt.Sprintf("priority<=%s", types.ClusterEgressFirewallStartPriority),
+		fmt.Sprintf("priority>=%s", types.MinimumReservedClusterEgressFirewallPriority),
+	)
+	if err != nil {
+		klog.Errorf("Cannot reconcile the state of clusterEgressFirewalls in ovn database and k8s. stderr: %s, err: %v", stderr, err)
+		return
+	}
+	staleKeys := sets.NewString()
+	for _, externalID := range strings.Fields(ovnEgressFirewallExternalIDs) {
+		if !strings.Contains(externalID, "egressFirewall=") {
+			continue
+		}
+		key := strings.Split(externalID, "egressFirewall=")[1]
+		if isClusterEgressFirewallKey(key) && !expectedKeys.Has(key) {
+			staleKeys.Insert(key)
+		}
+	}
+
+	txn := util.NewNBTxn()
+	for _, key := range staleKeys.List() {
+		if err := oc.deleteEgressFirewallRules(key, txn); err != nil {
+			klog.Errorf("Cannot fully reconcile the state of clusterEgressFirewall ACLs for %s still exist in ovn db: %v", key, err)
+			return
+		}
+	}
+	if _, stderr, err := txn.Commit(); err != nil {
+		klog.Errorf("Cannot fully reconcile the state of clusterEgressFirewall ACLs that still exist in ovn db: stderr: %q, err: %+v", stderr, err)
+	}
+}
+
+// addClusterEgressFirewall renders the rules of the clusterEgressFirewall in the priority band
+// below the one of the namespace egressFirewalls, so that they apply to the traffic of the pods
+// that no rule of the egressFirewall of their namespace matched
+func (oc *Controller) addClusterEgressFirewall(clusterEgressFirewall *egressfirewallapi.ClusterEgressFirewall, txn *util.NBTxn) error {
+	klog.Infof("Adding clusterEgressFirewall %s", clusterEgressFirewall.Name)
+
+	key := getClusterEgressFirewallKey(clusterEgressFirewall.Name)
+	ef := &egressFirewall{
+		name:        clusterEgressFirewall.Name,
+		namespace:   key,
+		egressRules: make([]*egressFirewallRule, 0),
+	}
+	ef.Lock()
+	defer ef.Unlock()
+	if _, loaded := oc.egressFirewalls.LoadOrStore(key, ef); loaded {
+		return fmt.Errorf("error attempting to add clusterEgressFirewall %s when it already exists", clusterEgressFirewall.Name)
+	}
+
+	clusterEgressFirewallStartPriorityInt, err := strconv.Atoi(types.ClusterEgressFirewallStartPriority)
+	if err != nil {
+		return fmt.Errorf("failed to convert clusterEgressFirewallStartPriority to Integer: cannot add clusterEgressFirewall %s", clusterEgressFirewall.Name)
+	}
+	minimumReservedClusterEgressFirewallPriorityInt, err := strconv.Atoi(types.MinimumReservedClusterEgressFirewallPriority)
+	if err != nil {
+		return fmt.Errorf("failed to convert minimumReservedClusterEgressFirewallPriority to Integer: cannot add clusterEgressFirewall %s", clusterEgressFirewall.Name)
+	}
+	for i, egressFirewallRule := range clusterEgressFirewall.Spec.Egress {
+		if i > clusterEgressFirewallStartPriorityInt-minimumReservedClusterEgressFirewallPriorityInt {
+			klog.Warningf("clusterEgressFirewall %s has too many rules, the rest will be ignored", clusterEgressFirewall.Name)
+			break
+		}
+		efr, err := newEgressFirewallRule(egressFirewallRule, i, nil)
+		if err != nil {
+			return fmt.Errorf("cannot create ClusterEgressFirewall Rule %d for %s: %v", i, clusterEgressFirewall.Name, err)
+		}
+		ef.egressRules = append(ef.egressRules, efr)
+	}
+
+	if err := oc.ensureEgressFirewallNodeAddressSets(ef); err != nil {
+		return err
+	}
+	if err := oc.ensureEgressFirewallPodAddressSets(ef); err != nil {
+		return err
+	}
+	// the clusterEgressFirewall has no namespace address set, its rules without podSelector
+	// match the traffic from the cluster subnets. The namespace ACL logging does not apply.
+	return oc.addEgressFirewallRules(ef, "", "", clusterEgressFirewallStartPriorityInt, ACLLoggingLevels{}, txn)
+}
+
+// updateClusterEgressFirewall replaces the ACLs of oldClusterEgressFirewall with the ones of
+// newClusterEgressFirewall in a single transaction, like updateEgressFirewall
+func (oc *Controller) updateClusterEgressFirewall(oldClusterEgressFirewall, newClusterEgressFirewall *egressfirewallapi.ClusterEgressFirewall) error {
+	klog.Infof("Updating clusterEgressFirewall %s", newClusterEgressFirewall.Name)
+	txn := util.NewNBTxn()
+	oldEF, err := oc.removeEgressFirewall(getClusterEgressFirewallKey(oldClusterEgressFirewall.Name), txn)
+	if err != nil {
+		return err
+	}
+	defer oldEF.Unlock()
+	if err := oc.addClusterEgressFirewall(newClusterEgressFirewall, txn); err != nil {
+		return err
+	}
+	return oc.commitEgressFirewallRemoval(oldEF, getClusterEgressFirewallKey(newClusterEgressFirewall.Name), txn)
+}
+
+func (oc *Controller) deleteClusterEgressFirewall(clusterEgressFirewall *egressfirewallapi.ClusterEgressFirewall) error {
+	klog.Infof("Deleting clusterEgressFirewall %s", clusterEgressFirewall.Name)
+	return oc.deleteEgressFirewallByKey(getClusterEgressFirewallKey(clusterEgressFirewall.Name))
+}
+
+func (oc *Controller) updateClusterEgressFirewallWithRetry(clusterEgressFirewall *egressfirewallapi.ClusterEgressFirewall) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return oc.kube.UpdateClusterEgressFirewall(clusterEgressFirewall)
+	})
+	if retryErr != nil {
+		return fmt.Errorf("error in updating status on ClusterEgressFirewall %s: %v", clusterEgressFirewall.Name, retryErr)
+	}
+	return nil
+}
+
+func (oc *Controller) addEgressFirewallRules(ef *egressFirewall, hashedAddressSetNameIPv4, hashedAddressSetNameIPv6 string, efStartPriority int,
+	aclLogging ACLLoggingLevels, txn *util.NBTxn) error {
 	for _, rule := range ef.egressRules {
-		var action string
+		var action, ruleLogging string
 		var matchTargets []matchTarget
 		if rule.access == egressfirewallapi.EgressFirewallRuleAllow {
 			action = "allow"
+			ruleLogging = aclLogging.Allow
 		} else {
 			action = "drop"
+			ruleLogging = aclLogging.Deny
 		}
 		if rule.to.cidrSelector != "" {
 			if utilnet.IsIPv6CIDRString(rule.to.cidrSelector) {
@@ -379,6 +708,15 @@ func (oc *Controller) addEgressFirewallRules(ef *egressFirewall, hashedAddressSe
 			} else {
 				matchTargets = []matchTarget{{matchKindV4CIDR, rule.to.cidrSelector}}
 			}
+		} else if rule.to.nodeAddressSet != nil {
+			// rule based on node selector
+			nodeIPv4ASHashName, nodeIPv6ASHashName := rule.to.nodeAddressSet.GetASHashNames()
+			if nodeIPv4ASHashName != "" {
+				matchTargets = append(matchTargets, matchTarget{matchKindV4AddressSet, nodeIPv4ASHashName})
+			}
+			if nodeIPv6ASHashName != "" {
+				matchTargets = append(matchTargets, matchTarget{matchKindV6AddressSet, nodeIPv6ASHashName})
+			}
 		} else {
 			// rule based on DNS NAME
 			dnsNameAddressSets, err := oc.egressFirewallDNS.Add(ef.namespace, rule.to.dnsName)
@@ -393,8 +731,18 @@ func (oc *Controller) addEgressFirewallRules(ef *egressFirewall, hashedAddressSe
 				matchTargets = append(matchTargets, matchTarget{matchKindV6AddressSet, dnsNameIPv6ASHashName})
 			}
 		}
-		match := generateMatch(hashedAddressSetNameIPv4, hashedAddressSetNameIPv6, matchTargets, rule.ports)
-		err := oc.createEgressFirewallRules(efStartPriority-rule.id, match, action, ef.namespace, txn)
+		var match string
+		if rule.podAddressSet != nil {
+			// the rule only applies to the pods matching its podSelector
+			srcIPv4ASHashName, srcIPv6ASHashName := rule.podAddressSet.GetASHashNames()
+			match = generateMatch(srcIPv4ASHashName, srcIPv6ASHashName, matchTargets, rule.ports)
+		} else if isClusterEgressFirewallKey(ef.namespace) {
+			// the rule of the clusterEgressFirewall applies to all the pods of the cluster
+			match = generateMatchFromSource(getClusterSubnetsSource(), matchTargets, rule.ports)
+		} else {
+			match = generateMatch(hashedAddressSetNameIPv4, hashedAddressSetNameIPv6, matchTargets, rule.ports)
+		}
+		err := oc.createEgressFirewallRules(efStartPriority-rule.id, match, action, ruleLogging, ef.namespace, txn)
 		if err != nil {
 			return err
 		}
@@ -402,9 +750,212 @@ func (oc *Controller) addEgressFirewallRules(ef *egressFirewall, hashedAddressSe
 	return nil
 }
 
+// getEgressFirewallNodeAddressSetName returns the name of the address set holding the IPs
+// of the nodes selected by an egressFirewall rule
+func getEgressFirewallNodeAddressSetName(namespace string, ruleID int) string {
+	return fmt.Sprintf("%s.egressfirewall-nodes.%d", namespace, ruleID)
+}
+
+// getEgressFirewallPodAddressSetName returns the name of the address set holding the IPs
+// of the pods selected by an egressFirewall rule
+func getEgressFirewallPodAddressSetName(namespace string, ruleID int) string {
+	return fmt.Sprintf("%s.egressfirewall-pods.%d", namespace, ruleID)
+}
+
+// getEgressFirewallNodeIPs returns the IPs of a node that egressFirewall nodeSelector
+// rules apply to: the node's internal and external addresses and its host addresses
+func getEgressFirewallNodeIPs(node *kapi.Node) []net.IP {
+	addresses := sets.NewString()
+	for _, address := range node.Status.Addresses {
+		if address.Type == kapi.NodeInternalIP || address.Type == kapi.NodeExternalIP {
+			addresses.Insert(address.Address)
+		}
+	}
+	if hostAddresses, err := util.ParseNodeHostAddresses(node); err == nil {
+		addresses = addresses.Union(hostAddresses)
+	}
+	ips := make([]net.IP, 0, addresses.Len())
+	for _, address := range addresses.List() {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			klog.Warningf("Failed to parse address %q of node %s", address, node.Name)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+// getEgressFirewallDNSIPs returns the IPs of the wildcard dnsNames seen in the DNS responses to
+// the pods of the node
+func getEgressFirewallDNSIPs(node *kapi.Node) map[string][]net.IP {
+	dnsIPs, err := util.ParseNodeEgressFirewallDNSIPs(node)
+	if err != nil && !util.IsAnnotationNotSetError(err) {
+		klog.Warningf("Failed to get the egress firewall DNS IPs of node %s: %v", node.Name, err)
+	}
+	return dnsIPs
+}
+
+// ensureEgressFirewallNodeAddressSets creates an address set for every nodeSelector rule of
+// the egressFirewall, holding the IPs of the nodes that currently match the selector
+func (oc *Controller) ensureEgressFirewallNodeAddressSets(ef *egressFirewall) error {
+	var nodes []*kapi.Node
+	for _, rule := range ef.egressRules {
+		if rule.to.nodeSelector == nil {
+			continue
+		}
+		if nodes == nil {
+			var err error
+			nodes, err = oc.watchFactory.GetNodes()
+			if err != nil {
+				return fmt.Errorf("unable to list nodes for egressFirewall in namespace %s: %v", ef.namespace, err)
+			}
+		}
+		var ips []net.IP
+		for _, node := range nodes {
+			if rule.to.nodeSelector.Matches(labels.Set(node.Labels)) {
+				ips = append(ips, getEgressFirewallNodeIPs(node)...)
+			}
+		}
+		as, err := oc.addressSetFactory.NewAddressSet(getEgressFirewallNodeAddressSetName(ef.namespace, rule.id), ips)
+		if err != nil {
+			return fmt.Errorf("cannot create node addressSet for egressFirewall in namespace %s: %v", ef.namespace, err)
+		}
+		rule.to.nodeAddressSet = as
+	}
+	return nil
+}
+
+// ensureEgressFirewallPodAddressSets creates an address set for every podSelector rule of the
+// egressFirewall and keeps it up to date with the IPs of the pods matching the selector
+func (oc *Controller) ensureEgressFirewallPodAddressSets(ef *egressFirewall) error {
+	for _, rule := range ef.egressRules {
+		if rule.podSelector == nil {
+			continue
+		}
+		as, err := oc.addressSetFactory.NewAddressSet(getEgressFirewallPodAddressSetName(ef.namespace, rule.id), nil)
+		if err != nil {
+			return fmt.Errorf("cannot create pod addressSet of egressFirewall in namespace %s: %v", ef.namespace, err)
+		}
+		rule.podAddressSet = as
+
+		// the podSelector of a clusterEgressFirewall rule selects the pods of all the namespaces
+		podNamespace := ef.namespace
+		if isClusterEgressFirewallKey(ef.namespace) {
+			podNamespace = ""
+		}
+		r := rule
+		rule.podHandler = oc.watchFactory.AddFilteredPodHandler(podNamespace, rule.podSelector,
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					oc.handleEgressFirewallPodAddUpdate(r, obj)
+				},
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					oc.handleEgressFirewallPodAddUpdate(r, newObj)
+				},
+				DeleteFunc: func(obj interface{}) {
+					oc.handleEgressFirewallPodDelete(r, obj)
+				},
+			}, func(objs []interface{}) {
+				oc.handleEgressFirewallPodAddUpdate(r, objs...)
+			})
+	}
+	return nil
+}
+
+// handleEgressFirewallPodAddUpdate adds the IPs of the pods matching the podSelector of an
+// egressFirewall rule to the rule's address set. Pods not wired yet are added on update.
+func (oc *Controller) handleEgressFirewallPodAddUpdate(rule *egressFirewallRule, objs ...interface{}) {
+	ips := make([]net.IP, 0, len(objs))
+	for _, obj := range objs {
+		pod := obj.(*kapi.Pod)
+		if pod.Spec.NodeName == "" || !util.PodWantsNetwork(pod) {
+			continue
+		}
+		podIPs, err := util.GetAllPodIPs(pod)
+		if err != nil {
+			klog.V(5).Infof("Skipping pod %s/%s for egressFirewall: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		ips = append(ips, podIPs...)
+	}
+	if len(ips) == 0 {
+		return
+	}
+	if err := rule.podAddressSet.AddIPs(ips); err != nil {
+		klog.Errorf("Failed to add pod IPs %v to egressFirewall address set %s: %v", ips, rule.podAddressSet.GetName(), err)
+	}
+}
+
+func (oc *Controller) handleEgressFirewallPodDelete(rule *egressFirewallRule, obj interface{}) {
+	pod, ok := obj.(*kapi.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if pod, ok = tombstone.Obj.(*kapi.Pod); !ok {
+			return
+		}
+	}
+	if pod.Spec.NodeName == "" || !util.PodWantsNetwork(pod) {
+		return
+	}
+	ips, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		return
+	}
+	if err := rule.podAddressSet.DeleteIPs(ips); err != nil {
+		klog.Errorf("Failed to delete pod IPs %v from egressFirewall address set %s: %v", ips, rule.podAddressSet.GetName(), err)
+	}
+}
+
+// updateEgressFirewallForNode keeps the node address sets of all egressFirewall nodeSelector
+// rules up to date when a node is added (oldNode is nil), updated or deleted (newNode is nil)
+func (oc *Controller) updateEgressFirewallForNode(oldNode, newNode *kapi.Node) {
+	var oldIPs, newIPs []net.IP
+	var oldLabels, newLabels labels.Set
+	if oldNode != nil {
+		oldIPs = getEgressFirewallNodeIPs(oldNode)
+		oldLabels = labels.Set(oldNode.Labels)
+	}
+	if newNode != nil {
+		newIPs = getEgressFirewallNodeIPs(newNode)
+		newLabels = labels.Set(newNode.Labels)
+	}
+	ipsChanged := !reflect.DeepEqual(oldIPs, newIPs)
+
+	oc.egressFirewalls.Range(func(_, value interface{}) bool {
+		ef := value.(*egressFirewall)
+		ef.Lock()
+		defer ef.Unlock()
+		for _, rule := range ef.egressRules {
+			if rule.to.nodeAddressSet == nil {
+				continue
+			}
+			oldMatches := oldNode != nil && rule.to.nodeSelector.Matches(oldLabels)
+			newMatches := newNode != nil && rule.to.nodeSelector.Matches(newLabels)
+			if oldMatches && (!newMatches || ipsChanged) {
+				if err := rule.to.nodeAddressSet.DeleteIPs(oldIPs); err != nil {
+					klog.Errorf("Failed to remove IPs of node %s from egressFirewall in namespace %s: %v",
+						oldNode.Name, ef.namespace, err)
+				}
+			}
+			if newMatches && (!oldMatches || ipsChanged) {
+				if err := rule.to.nodeAddressSet.AddIPs(newIPs); err != nil {
+					klog.Errorf("Failed to add IPs of node %s to egressFirewall in namespace %s: %v",
+						newNode.Name, ef.namespace, err)
+				}
+			}
+		}
+		return true
+	})
+}
+
 // createEgressFirewallRules uses the previously generated elements and creates the
-// logical_router_policy/join_switch_acl for a specific egressFirewallRouter
-func (oc *Controller) createEgressFirewallRules(priority int, match, action, externalID string, txn *util.NBTxn) error {
+// logical_router_policy/join_switch_acl for a specific egressFirewallRouter. aclLogging
+// is the logging severity of the ACL, logging is disabled if it is empty
+func (oc *Controller) createEgressFirewallRules(priority int, match, action, aclLogging, externalID string, txn *util.NBTxn) error {
 	logicalSwitches := []string{}
 	if config.Gateway.Mode == config.GatewayModeLocal {
 		nodes, err := oc.watchFactory.GetNodes()
@@ -423,6 +974,17 @@ func (oc *Controller) createEgressFirewallRules(priority int, match, action, ext
 	if err != nil {
 		return fmt.Errorf("error executing find ACL command, stderr: %q, %+v", stderr, err)
 	}
+	aclName := fmt.Sprintf("%s_%d", externalID, priority)
+	for _, uuid := range strings.Fields(uuids) {
+		_, stderr, err := txn.AddOrCommit([]string{"set", "acl", uuid,
+			fmt.Sprintf("log=%t", aclLogging != ""),
+			fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
+			fmt.Sprintf("meter=%s", types.OvnACLLoggingMeter),
+			fmt.Sprintf("name=%.63s", aclName)})
+		if err != nil {
+			return fmt.Errorf("failed to commit db changes for egressFirewall stderr: %q, err: %+v", stderr, err)
+		}
+	}
 	sort.Strings(logicalSwitches)
 	for _, logicalSwitch := range logicalSwitches {
 		if uuids == "" {
@@ -430,6 +992,10 @@ func (oc *Controller) createEgressFirewallRules(priority int, match, action, ext
 			_, stderr, err := txn.AddOrCommit([]string{"--id=@" + id, "create", "acl",
 				fmt.Sprintf("priority=%d", priority),
 				fmt.Sprintf("direction=%s", types.DirectionToLPort), match, "action=" + action,
+				fmt.Sprintf("log=%t", aclLogging != ""),
+				fmt.Sprintf("severity=%s", getACLLoggingSeverity(aclLogging)),
+				fmt.Sprintf("meter=%s", types.OvnACLLoggingMeter),
+				fmt.Sprintf("name=%.63s", aclName),
 				fmt.Sprintf("external-ids:egressFirewall=%s", externalID),
 				"--", "add", "logical_switch", logicalSwitch,
 				"acls", "@" + id})
@@ -450,6 +1016,142 @@ func (oc *Controller) createEgressFirewallRules(priority int, match, action, ext
 	return nil
 }
 
+// getEgressFirewallACLLogging returns the ACL logging levels set on the namespace
+// through the k8s.ovn.org/acl-logging annotation
+func (oc *Controller) getEgressFirewallACLLogging(namespace string) ACLLoggingLevels {
+	nsInfo, nsUnlock := oc.getNamespaceLocked(namespace, true)
+	if nsInfo == nil {
+		return ACLLoggingLevels{}
+	}
+	defer nsUnlock()
+	return nsInfo.aclLogging
+}
+
+// setEgressFirewallACLLogging updates the logging of the allow and drop ACLs of the
+// egressFirewall in namespace to the given ACL logging levels
+func (oc *Controller) setEgressFirewallACLLogging(namespace string, aclLogging ACLLoggingLevels) error {
+	txn := util.NewNBTxn()
+	for _, acl := range []struct {
+		action string
+		level  string
+	}{
+		{"allow", aclLogging.Allow},
+		{"drop", aclLogging.Deny},
+	} {
+		output, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid,priority", "--format=table",
+			"find", "ACL", "action="+acl.action, fmt.Sprintf("external-ids:egressFirewall=%s", namespace))
+		if err != nil {
+			return fmt.Errorf("error executing find ACL command, stderr: %q, %+v", stderr, err)
+		}
+		for _, line := range strings.Split(output, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			_, stderr, err := txn.AddOrCommit([]string{"set", "acl", fields[0],
+				fmt.Sprintf("log=%t", acl.level != ""),
+				fmt.Sprintf("severity=%s", getACLLoggingSeverity(acl.level)),
+				fmt.Sprintf("meter=%s", types.OvnACLLoggingMeter),
+				fmt.Sprintf("name=%.63s", fmt.Sprintf("%s_%s", namespace, fields[1]))})
+			if err != nil {
+				return fmt.Errorf("failed to commit db changes for egressFirewall stderr: %q, err: %+v", stderr, err)
+			}
+		}
+	}
+	if _, stderr, err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to commit db changes for egressFirewall stderr: %q, err: %+v", stderr, err)
+	}
+	return nil
+}
+
+// getEgressFirewallRuleStatuses returns the programming result of every rule of the egressFirewall.
+// applied tells whether the ACLs of the egressFirewall were committed to the OVN database
+func (oc *Controller) getEgressFirewallRuleStatuses(egressFirewall *egressfirewallapi.EgressFirewall, applied bool) []egressfirewallapi.EgressFirewallRuleStatus {
+	egressFirewallStartPriorityInt, _ := strconv.Atoi(types.EgressFirewallStartPriority)
+	minimumReservedEgressFirewallPriorityInt, _ := strconv.Atoi(types.MinimumReservedEgressFirewallPriority)
+	return oc.getRuleStatuses(egressFirewall.Spec.Egress, egressFirewall.Spec.PodSelector,
+		egressFirewallStartPriorityInt-minimumReservedEgressFirewallPriorityInt+1, applied)
+}
+
+// getClusterEgressFirewallRuleStatuses returns the programming result of every rule of the
+// clusterEgressFirewall
+func (oc *Controller) getClusterEgressFirewallRuleStatuses(clusterEgressFirewall *egressfirewallapi.ClusterEgressFirewall, applied bool) []egressfirewallapi.EgressFirewallRuleStatus {
+	clusterEgressFirewallStartPriorityInt, _ := strconv.Atoi(types.ClusterEgressFirewallStartPriority)
+	minimumReservedClusterEgressFirewallPriorityInt, _ := strconv.Atoi(types.MinimumReservedClusterEgressFirewallPriority)
+	return oc.getRuleStatuses(clusterEgressFirewall.Spec.Egress, nil,
+		clusterEgressFirewallStartPriorityInt-minimumReservedClusterEgressFirewallPriorityInt+1, applied)
+}
+
+// getRuleStatuses returns the programming result of the rules, only the first maxRules of them
+// have a priority
+func (oc *Controller) getRuleStatuses(rules []egressfirewallapi.EgressFirewallRule, podSelector *metav1.LabelSelector,
+	maxRules int, applied bool) []egressfirewallapi.EgressFirewallRuleStatus {
+	ruleStatuses := make([]egressfirewallapi.EgressFirewallRuleStatus, 0, len(rules))
+	for i, egressFirewallRule := range rules {
+		ruleStatus := egressfirewallapi.EgressFirewallRuleStatus{
+			Index:  i,
+			Status: egressFirewallRuleApplied,
+		}
+		if i >= maxRules {
+			ruleStatus.Status = egressFirewallRuleNotApplied
+			ruleStatus.Message = "egressFirewall has too many rules, rule is ignored"
+		} else if _, err := newEgressFirewallRule(egressFirewallRule, i, podSelector); err != nil {
+			ruleStatus.Status = egressFirewallRuleFailed
+			ruleStatus.Message = err.Error()
+		} else if !applied {
+			ruleStatus.Status = egressFirewallRuleNotApplied
+		} else if egressFirewallRule.To.DNSName != "" && oc.egressFirewallDNS != nil {
+			if err := oc.egressFirewallDNS.GetResolveError(egressFirewallRule.To.DNSName); err != nil {
+				ruleStatus.Message = fmt.Sprintf("dnsName %s failed to resolve: %v", egressFirewallRule.To.DNSName, err)
+			}
+		}
+		ruleStatuses = append(ruleStatuses, ruleStatus)
+	}
+	return ruleStatuses
+}
+
+// updateEgressFirewallStatusForNamespaces refreshes the rule statuses of the egressFirewalls
+// of the given namespaces, it is called when a dnsName used by them starts or stops resolving
+func (oc *Controller) updateEgressFirewallStatusForNamespaces(namespaces []string) {
+	for _, namespace := range namespaces {
+		obj, loaded := oc.egressFirewalls.Load(namespace)
+		if !loaded {
+			continue
+		}
+		ef := obj.(*egressFirewall)
+		if isClusterEgressFirewallKey(namespace) {
+			oc.updateClusterEgressFirewallStatus(ef.name)
+			continue
+		}
+		egressFirewall, err := oc.watchFactory.GetEgressFirewall(namespace, ef.name)
+		if err != nil {
+			klog.Errorf("Unable to get egressFirewall %s in namespace %s to update its status: %v", ef.name, namespace, err)
+			continue
+		}
+		egressFirewall = egressFirewall.DeepCopy()
+		egressFirewall.Status.Rules = oc.getEgressFirewallRuleStatuses(egressFirewall,
+			egressFirewall.Status.Status == egressFirewallAppliedCorrectly)
+		if err := oc.updateEgressFirewallWithRetry(egressFirewall); err != nil {
+			klog.Error(err)
+		}
+	}
+}
+
+// updateClusterEgressFirewallStatus refreshes the rule statuses of a clusterEgressFirewall
+func (oc *Controller) updateClusterEgressFirewallStatus(name string) {
+	clusterEgressFirewall, err := oc.watchFactory.GetClusterEgressFirewall(name)
+	if err != nil {
+		klog.Errorf("Unable to get clusterEgressFirewall %s to update its status: %v", name, err)
+		return
+	}
+	clusterEgressFirewall = clusterEgressFirewall.DeepCopy()
+	clusterEgressFirewall.Status.Rules = oc.getClusterEgressFirewallRuleStatuses(clusterEgressFirewall,
+		clusterEgressFirewall.Status.Status == egressFirewallAppliedCorrectly)
+	if err := oc.updateClusterEgressFirewallWithRetry(clusterEgressFirewall); err != nil {
+		klog.Error(err)
+	}
+}
+
 // deleteEgressFirewallRules delete the specific logical router policy/join switch Acls
 func (oc *Controller) deleteEgressFirewallRules(externalID string, txn *util.NBTxn) error {
 	logicalSwitches := []string{}
@@ -523,8 +1225,6 @@ func (m *matchTarget) toExpr() (string, error) {
 // match=\"(ip4.dst == 1.2.3.4/32) && ip4.src == $testv4 && ip4.dst != 10.128.0.0/14\
 func generateMatch(ipv4Source, ipv6Source string, destinations []matchTarget, dstPorts []egressfirewallapi.EgressFirewallPort) string {
 	var src string
-	var dst string
-	var extraMatch string
 	switch {
 	case config.IPv4Mode && config.IPv6Mode:
 		src = fmt.Sprintf("(ip4.src == $%s || ip6.src == $%s)", ipv4Source, ipv6Source)
@@ -533,7 +1233,14 @@ func generateMatch(ipv4Source, ipv6Source string, destinations []matchTarget, ds
 	case config.IPv6Mode:
 		src = fmt.Sprintf("ip6.src == $%s", ipv6Source)
 	}
+	return generateMatchFromSource(src, destinations, dstPorts)
+}
 
+// generateMatchFromSource generates the "match" section of ACL generation for egressFirewallRules
+// from the given source expression
+func generateMatchFromSource(src string, destinations []matchTarget, dstPorts []egressfirewallapi.EgressFirewallPort) string {
+	var dst string
+	var extraMatch string
 	for _, entry := range destinations {
 		if entry.value == "" {
 			continue
@@ -571,25 +1278,20 @@ func egressGetL4Match(ports []egressfirewallapi.EgressFirewallPort) string {
 	var udpString string
 	var tcpString string
 	var sctpString string
+	var icmp4String string
+	var icmp6String string
 	for _, port := range ports {
-		if kapi.Protocol(port.Protocol) == kapi.ProtocolUDP && udpString != "udp" {
-			if port.Port == 0 {
-				udpString = "udp"
-			} else {
-				udpString = fmt.Sprintf("%s udp.dst == %d ||", udpString, port.Port)
-			}
-		} else if kapi.Protocol(port.Protocol) == kapi.ProtocolTCP && tcpString != "tcp" {
-			if port.Port == 0 {
-				tcpString = "tcp"
-			} else {
-				tcpString = fmt.Sprintf("%s tcp.dst == %d ||", tcpString, port.Port)
-			}
-		} else if kapi.Protocol(port.Protocol) == kapi.ProtocolSCTP && sctpString != "sctp" {
-			if port.Port == 0 {
-				sctpString = "sctp"
-			} else {
-				sctpString = fmt.Sprintf("%s sctp.dst == %d ||", sctpString, port.Port)
-			}
+		switch port.Protocol {
+		case string(kapi.ProtocolUDP):
+			udpString = egressGetPortMatch("udp", udpString, port)
+		case string(kapi.ProtocolTCP):
+			tcpString = egressGetPortMatch("tcp", tcpString, port)
+		case string(kapi.ProtocolSCTP):
+			sctpString = egressGetPortMatch("sctp", sctpString, port)
+		case egressfirewallapi.ProtocolICMP:
+			icmp4String = egressGetICMPMatch("icmp4", icmp4String, port)
+		case egressfirewallapi.ProtocolICMPv6:
+			icmp6String = egressGetICMPMatch("icmp6", icmp6String, port)
 		}
 	}
 	// build the l4 match
@@ -611,6 +1313,14 @@ func egressGetL4Match(ports []egressfirewallapi.EgressFirewallPort) string {
 			protocolName:     "sctp",
 			protocolFormated: sctpString,
 		},
+		{
+			protocolName:     "icmp4",
+			protocolFormated: icmp4String,
+		},
+		{
+			protocolName:     "icmp6",
+			protocolFormated: icmp6String,
+		},
 	}
 	for _, entry := range list {
 		if entry.protocolName == entry.protocolFormated {
@@ -630,6 +1340,51 @@ func egressGetL4Match(ports []egressfirewallapi.EgressFirewallPort) string {
 	return fmt.Sprintf("(%s)", l4Match)
 }
 
+// egressGetPortMatch adds the match of a TCP, UDP or SCTP port or port range to the match of
+// the other ports of the protocol, the match is the protocol name once a port matches all the
+// ports of the protocol
+func egressGetPortMatch(protocolName, protocolMatch string, port egressfirewallapi.EgressFirewallPort) string {
+	switch {
+	case protocolMatch == protocolName || port.Port == 0:
+		return protocolName
+	case port.EndPort > port.Port:
+		return fmt.Sprintf("%s %d <= %s.dst <= %d ||", protocolMatch, port.Port, protocolName, port.EndPort)
+	default:
+		return fmt.Sprintf("%s %s.dst == %d ||", protocolMatch, protocolName, port.Port)
+	}
+}
+
+// egressGetICMPMatch adds the match of an ICMP type and code to the match of the other ICMP
+// messages of the protocol, the match is the protocol name once all the types are matched
+func egressGetICMPMatch(protocolName, protocolMatch string, port egressfirewallapi.EgressFirewallPort) string {
+	switch {
+	case protocolMatch == protocolName || port.ICMPType == nil:
+		return protocolName
+	case port.ICMPCode == nil:
+		return fmt.Sprintf("%s %s.type == %d ||", protocolMatch, protocolName, *port.ICMPType)
+	default:
+		return fmt.Sprintf("%s (%s.type == %d && %s.code == %d) ||", protocolMatch, protocolName, *port.ICMPType,
+			protocolName, *port.ICMPCode)
+	}
+}
+
+// getClusterSubnetsSource returns the source expression matching the traffic of all the pods
+// of the cluster
+func getClusterSubnetsSource() string {
+	var sources []string
+	for _, clusterSubnet := range config.Default.ClusterSubnets {
+		if utilnet.IsIPv6CIDR(clusterSubnet.CIDR) {
+			sources = append(sources, fmt.Sprintf("%s.src == %s", "ip6", clusterSubnet.CIDR))
+		} else {
+			sources = append(sources, fmt.Sprintf("%s.src == %s", "ip4", clusterSubnet.CIDR))
+		}
+	}
+	if len(sources) == 1 {
+		return sources[0]
+	}
+	return fmt.Sprintf("(%s)", strings.Join(sources, " || "))
+}
+
 func getClusterSubnetsExclusion() string {
 	var exclusion string
 	for _, clusterSubnet := range config.Default.ClusterSubnets {
@@ -6,10 +6,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/metrics"
 	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 )
 
@@ -22,10 +26,15 @@ type EgressDNS struct {
 	dnsEntries map[string]*dnsEntry
 	// allows for the creation of addresssets
 	addressSetFactory addressset.AddressSetFactory
+	// called with the namespaces referencing a dnsName whenever the dnsName
+	// starts or stops failing to resolve
+	resolveStatusChanged func(namespaces []string)
+	// holds the IPs of the wildcard dnsNames seen in the DNS responses to the pods
+	// of each node, wildcard dnsNames cannot be resolved by querying them
+	snoopedIPs map[string]map[string][]net.IP
 
-	// Report change when Add operation is done
-	added          chan struct{}
-	stopChan       chan struct{}
+	// the dnsNames to resolve, each dnsName is queued again after its TTL
+	queue          workqueue.DelayingInterface
 	controllerStop <-chan struct{}
 }
 
@@ -37,9 +46,12 @@ type dnsEntry struct {
 	dnsResolves []net.IP
 	// the addressSet that contains the current IPs
 	dnsAddressSet addressset.AddressSet
+	// the error of the last lookup of the dnsName, nil if it resolved
+	resolveErr error
 }
 
-func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, controllerStop <-chan struct{}) (*EgressDNS, error) {
+func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, resolveStatusChanged func(namespaces []string),
+	controllerStop <-chan struct{}) (*EgressDNS, error) {
 	dnsInfo, err := util.NewDNS("/etc/resolv.conf")
 	if err != nil {
 		return nil, err
@@ -48,10 +60,12 @@ func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, controllerStop
 	egressDNS := &EgressDNS{
 		dns:               dnsInfo,
 		dnsEntries:        make(map[string]*dnsEntry),
+		snoopedIPs:        make(map[string]map[string][]net.IP),
 		addressSetFactory: addressSetFactory,
 
-		added:          make(chan struct{}),
-		stopChan:       make(chan struct{}),
+		resolveStatusChanged: resolveStatusChanged,
+
+		queue:          workqueue.NewNamedDelayingQueue("egress-firewall-dns"),
 		controllerStop: controllerStop,
 	}
 
@@ -75,7 +89,13 @@ func (e *EgressDNS) Add(namespace, dnsName string) (addressset.AddressSet, error
 			return nil, fmt.Errorf("cannot create addressSet for %s: %v", dnsName, err)
 		}
 		e.dnsEntries[dnsName] = &dnsEntry
-		go e.addToDNS(dnsName)
+		if util.IsWildcardDNSName(dnsName) {
+			if err := e.updateWildcardEntryLocked(dnsName); err != nil {
+				utilruntime.HandleError(err)
+			}
+		} else {
+			e.queue.Add(dnsName)
+		}
 	}
 	e.dnsEntries[dnsName].namespaces[namespace] = struct{}{}
 	return e.dnsEntries[dnsName].dnsAddressSet, nil
@@ -84,7 +104,7 @@ func (e *EgressDNS) Add(namespace, dnsName string) (addressset.AddressSet, error
 
 func (e *EgressDNS) Delete(namespace string) bool {
 	e.lock.Lock()
-	var dnsNamesToDelete []string
+	defer e.lock.Unlock()
 
 	// go through all dnsNames for namespaces
 	for dnsName, dnsEntry := range e.dnsEntries {
@@ -98,98 +118,186 @@ func (e *EgressDNS) Delete(namespace string) bool {
 			}
 			// the dnsEntry is no longer needed because nothing references it delete it
 			delete(e.dnsEntries, dnsName)
-			dnsNamesToDelete = append(dnsNamesToDelete, dnsName)
+			e.dns.Delete(dnsName)
 		}
 	}
+	return len(e.dnsEntries) == 0
+}
+
+// GetResolveError returns the error of the last lookup of dnsName, or nil if
+// it resolved or has not been looked up yet
+func (e *EgressDNS) GetResolveError(dnsName string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if entry, exists := e.dnsEntries[dnsName]; exists {
+		return entry.resolveErr
+	}
+	return nil
+}
+
+// setResolveError records the result of the last lookup of dnsName and notifies
+// the namespaces referencing it if the dnsName started or stopped resolving
+func (e *EgressDNS) setResolveError(dnsName string, resolveErr error) {
+	e.lock.Lock()
+	entry, exists := e.dnsEntries[dnsName]
+	if !exists {
+		e.lock.Unlock()
+		return
+	}
+	changed := (entry.resolveErr == nil) != (resolveErr == nil)
+	entry.resolveErr = resolveErr
+	namespaces := make([]string, 0, len(entry.namespaces))
+	for namespace := range entry.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
 	e.lock.Unlock()
-	for _, name := range dnsNamesToDelete {
-		e.dns.Delete(name)
+
+	if changed && e.resolveStatusChanged != nil {
+		e.resolveStatusChanged(namespaces)
 	}
-	return len(e.dnsEntries) == 0
 }
 
-func (e *EgressDNS) Update(dns string) (bool, error) {
-	return e.dns.Update(dns)
+// SetSnoopedIPs records the IPs of the wildcard dnsNames seen in the DNS responses to the
+// pods of a node, and updates the address sets of the wildcard dnsNames with the IPs seen
+// on all the nodes. dnsIPs is nil when the node is deleted.
+func (e *EgressDNS) SetSnoopedIPs(nodeName string, dnsIPs map[string][]net.IP) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	changed := sets.NewString()
+	for dnsName := range e.snoopedIPs[nodeName] {
+		changed.Insert(dnsName)
+	}
+	for dnsName := range dnsIPs {
+		changed.Insert(dnsName)
+	}
+	if len(dnsIPs) == 0 {
+		delete(e.snoopedIPs, nodeName)
+	} else {
+		e.snoopedIPs[nodeName] = dnsIPs
+	}
+	for _, dnsName := range changed.List() {
+		if _, exists := e.dnsEntries[dnsName]; !exists {
+			continue
+		}
+		if err := e.updateWildcardEntryLocked(dnsName); err != nil {
+			utilruntime.HandleError(err)
+		}
+	}
+}
+
+// updateWildcardEntryLocked sets the address set of a wildcard dnsName to the IPs seen
+// for it on all the nodes, e.lock must be held
+func (e *EgressDNS) updateWildcardEntryLocked(dnsName string) error {
+	seen := sets.NewString()
+	ips := []net.IP{}
+	for _, dnsIPs := range e.snoopedIPs {
+		for _, ip := range dnsIPs[dnsName] {
+			if !seen.Has(ip.String()) {
+				seen.Insert(ip.String())
+				ips = append(ips, ip)
+			}
+		}
+	}
+	e.dnsEntries[dnsName].dnsResolves = ips
+	if err := e.dnsEntries[dnsName].dnsAddressSet.SetIPs(ips); err != nil {
+		return fmt.Errorf("cannot set IPs of EgressFirewall AddressSet %s: %v", dnsName, err)
+	}
+	return nil
 }
 
+// updateEntryForName sets the address set of dnsName to the IPs it resolves to
 func (e *EgressDNS) updateEntryForName(dnsName string) error {
 	e.lock.Lock()
 	defer e.lock.Unlock()
+	entry, exists := e.dnsEntries[dnsName]
+	if !exists {
+		// deleted while being resolved
+		e.dns.Delete(dnsName)
+		return nil
+	}
 	ips := e.dns.GetIPs(dnsName)
-	e.dnsEntries[dnsName].dnsResolves = ips
-
-	if err := e.dnsEntries[dnsName].dnsAddressSet.SetIPs(ips); err != nil {
+	added, deleted := diffIPs(entry.dnsResolves, ips)
+	if err := entry.dnsAddressSet.SetIPs(ips); err != nil {
 		return fmt.Errorf("cannot add IPs from EgressFirewall AddressSet %s: %v", dnsName, err)
 	}
+	entry.dnsResolves = ips
+	metrics.MetricEgressFirewallDNSAddressSetChanges.WithLabelValues("add").Add(float64(added))
+	metrics.MetricEgressFirewallDNSAddressSetChanges.WithLabelValues("delete").Add(float64(deleted))
+
+	// resolve the dnsName again when its TTL expires
+	if nextQueryTime, ok := e.dns.GetNextQueryTime(dnsName); ok {
+		e.queue.AddAfter(dnsName, time.Until(nextQueryTime))
+	}
 	return nil
 }
 
-// addToDNS takes the dnsName adds it to the underlying dns resolver and
-// performs the first update. After completing that signals the
-// thread performing periodic updates that a new DNS name has been added and
-// so that it can updates GetNextQueryTime() if needed
-func (e *EgressDNS) addToDNS(dnsName string) {
-	if err := e.dns.Add(dnsName); err != nil {
+// diffIPs returns the number of IPs added to and deleted from oldIPs in newIPs
+func diffIPs(oldIPs, newIPs []net.IP) (int, int) {
+	oldSet := sets.NewString()
+	for _, ip := range oldIPs {
+		oldSet.Insert(ip.String())
+	}
+	newSet := sets.NewString()
+	for _, ip := range newIPs {
+		newSet.Insert(ip.String())
+	}
+	return newSet.Difference(oldSet).Len(), oldSet.Difference(newSet).Len()
+}
+
+// resolve resolves dnsName and updates its address set, dnsNames are resolved again
+// after their TTL, within the configured bounds, or after their negative TTL when they
+// fail to resolve
+func (e *EgressDNS) resolve(dnsName string) {
+	e.lock.Lock()
+	_, exists := e.dnsEntries[dnsName]
+	e.lock.Unlock()
+	if !exists {
+		return
+	}
+
+	start := time.Now()
+	err := e.dns.Add(dnsName)
+	metrics.MetricEgressFirewallDNSResolutionLatency.Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.MetricEgressFirewallDNSResolutionFailures.Inc()
 		utilruntime.HandleError(err)
 	}
+	e.setResolveError(dnsName, err)
 	if err := e.updateEntryForName(dnsName); err != nil {
 		utilruntime.HandleError(err)
 	}
-	// No need to block waiting to signal the add.
-	select {
-	case e.added <- struct{}{}:
-		klog.V(5).Infof("Recalculation of next query time requested")
-	default:
-		klog.V(5).Infof("Recalculation of next query time already requested")
-	}
-}
-
-// Run spawns a goroutine that handles updates to the dns entries for dnsNames used in
-// EgressFirewalls. The loop runs after receiving one of two signals
-// 1. time.After(durationTillNextQuery) times out and the dnsName with the lowest ttl is checked
-//    and the durationTillNextQuery is updated
-// 2. e.added is recived and durationTillNextQuery is recomputed
-func (e *EgressDNS) Run(defaultInterval time.Duration) {
-	var dnsName string
-	var ttl time.Time
-	var timeSet bool
-	// initially the next DNS Query happens at the default interval
-	durationTillNextQuery := defaultInterval
-	go func() {
-		for {
-			// perform periodic updates on dnsNames as each ttl runs out, checking for updates at
-			// least every defaultInterval. Update durationTillNextQuery everytime a new DNS name gets
-			// added
-			select {
-			case <-e.added:
-				//on update need to check if the GetNextQueryTime has changed
-			case <-time.After(durationTillNextQuery):
-				if len(dnsName) > 0 {
-					if _, err := e.Update(dnsName); err != nil {
-						utilruntime.HandleError(err)
-					}
-					if err := e.updateEntryForName(dnsName); err != nil {
-						utilruntime.HandleError(err)
-					}
-				}
-			case <-e.stopChan:
-				return
-			case <-e.controllerStop:
-				return
-			}
+}
 
-			// before waiting on the signals get the next time this thread needs to wake up
-			ttl, dnsName, timeSet = e.dns.GetNextQueryTime()
-			if time.Until(ttl) > defaultInterval || !timeSet {
-				durationTillNextQuery = defaultInterval
-			} else {
-				durationTillNextQuery = time.Until(ttl)
-			}
-		}
+// Run starts workers goroutines resolving the dnsNames used in EgressFirewalls. Each
+// dnsName is queued again when its TTL expires, so that a slow nameserver only delays
+// the dnsNames being resolved by the blocked workers.
+func (e *EgressDNS) Run(workers int) {
+	for i := 0; i < workers; i++ {
+		go wait.Until(e.worker, time.Second, e.controllerStop)
+	}
+	go func() {
+		<-e.controllerStop
+		e.queue.ShutDown()
 	}()
+}
 
+// worker resolves the queued dnsNames until the queue is shut down, the queue
+// guarantees that a dnsName is not resolved by several workers at the same time
+func (e *EgressDNS) worker() {
+	for e.processNextDNSName() {
+	}
+}
+
+func (e *EgressDNS) processNextDNSName() bool {
+	key, quit := e.queue.Get()
+	if quit {
+		return false
+	}
+	defer e.queue.Done(key)
+	e.resolve(key.(string))
+	return true
 }
 
 func (e *EgressDNS) Shutdown() {
-	close(e.stopChan)
+	e.queue.ShutDown()
 }
@@ -61,7 +61,7 @@ func TestNewEgressDNS(t *testing.T) {
 				}
 				call.Once()
 			}
-			_, err := NewEgressDNS(testOvnAddFtry, testCh)
+			_, err := NewEgressDNS(testOvnAddFtry, nil, testCh)
 			//t.Log(res, err)
 			if tc.errExp {
 				assert.Error(t, err)
@@ -99,16 +99,14 @@ func TestAdd(t *testing.T) {
 		configIPv4                 bool
 		configIPv6                 bool
 		testingUpdateOnQueryTime   bool
-		syncTime                   time.Duration
 		waitForSyncLoop            bool
 		dnsOpsMockHelper           []ovntest.TestifyMockHelper
 		addressSetFactoryOpsHelper []ovntest.TestifyMockHelper
 		addressSetOpsHelper        []ovntest.TestifyMockHelper
 	}{
 		{
-			desc:     "NewAddressSet returns error",
-			errExp:   true,
-			syncTime: 5 * time.Minute,
+			desc:   "NewAddressSet returns error",
+			errExp: true,
 			dnsOpsMockHelper: []ovntest.TestifyMockHelper{
 				{"ClientConfigFromFile", []string{"string"}, []interface{}{&dns.ClientConfig{
 					Servers: []string{"1.1.1.1"},
@@ -121,7 +119,6 @@ func TestAdd(t *testing.T) {
 		{
 			desc:       "EgressFirewall Add(dnsName) succeeds IPv4 only",
 			errExp:     false,
-			syncTime:   5 * time.Minute,
 			dnsName:    test1DNSName,
 			configIPv4: true,
 			configIPv6: false,
@@ -146,7 +143,6 @@ func TestAdd(t *testing.T) {
 		{
 			desc:                     "EgressFirewall Add(dnsName) succeeds dual stack",
 			errExp:                   false,
-			syncTime:                 5 * time.Minute,
 			dnsName:                  test1DNSName,
 			testingUpdateOnQueryTime: false,
 			configIPv4:               true,
@@ -175,7 +171,6 @@ func TestAdd(t *testing.T) {
 			errExp:                   false,
 			dnsName:                  test1DNSName,
 			testingUpdateOnQueryTime: true,
-			syncTime:                 5 * time.Minute,
 			configIPv4:               true,
 			configIPv6:               false,
 
@@ -238,14 +233,14 @@ func TestAdd(t *testing.T) {
 				}
 				call.Once()
 			}
-			res, err := NewEgressDNS(mockAddressSetFactoryOps, testCh)
+			res, err := NewEgressDNS(mockAddressSetFactoryOps, nil, testCh)
 
 			t.Log(res, err)
 			addResult, err := res.Add("addNamespace", test1DNSName)
 			if tc.errExp {
 				assert.Error(t, err)
 			} else {
-				res.Run(tc.syncTime)
+				res.Run(1)
 				assert.Nil(t, err)
 				for stay, timeout := true, time.After(10*time.Second); stay; {
 					_, dnsResolves, _ := res.getDNSEntry(tc.dnsName)
@@ -303,7 +298,6 @@ func TestDelete(t *testing.T) {
 		configIPv4                 bool
 		configIPv6                 bool
 		testingUpdateOnQueryTime   bool
-		syncTime                   time.Duration
 		waitForSyncLoop            bool
 		dnsOpsMockHelper           []ovntest.TestifyMockHelper
 		addressSetFactoryOpsHelper []ovntest.TestifyMockHelper
@@ -312,7 +306,6 @@ func TestDelete(t *testing.T) {
 		{
 			desc:                     "EgressFirewall Delete functions",
 			errExp:                   false,
-			syncTime:                 5 * time.Minute,
 			dnsName:                  test1DNSName,
 			testingUpdateOnQueryTime: false,
 			configIPv4:               true,
@@ -374,14 +367,14 @@ func TestDelete(t *testing.T) {
 				}
 				call.Once()
 			}
-			res, err := NewEgressDNS(mockAddressSetFactoryOps, testCh)
+			res, err := NewEgressDNS(mockAddressSetFactoryOps, nil, testCh)
 
 			t.Log(res, err)
 			addResult, err := res.Add("addNamespace", test1DNSName)
 			if tc.errExp {
 				assert.Error(t, err)
 			} else {
-				res.Run(tc.syncTime)
+				res.Run(1)
 				assert.Nil(t, err)
 				for stay, timeout := true, time.After(10*time.Second); stay; {
 					_, dnsResolves, _ := res.getDNSEntry(tc.dnsName)
@@ -432,3 +425,116 @@ func (e *EgressDNS) getDNSEntry(dnsName string) (map[string]struct{}, []net.IP,
 
 	return nil, nil, nil
 }
+
+func TestSetSnoopedIPs(t *testing.T) {
+	mockAddressSetFactoryOps := new(mocks.AddressSetFactory)
+	mockAddressSetOps := new(mocks.AddressSet)
+	mockDnsOps := new(util_mocks.DNSOps)
+	util.SetDNSLibOpsMockInst(mockDnsOps)
+	wildcardDNSName := "*.test.com"
+	node1IPs := []net.IP{net.ParseIP("2.2.2.2"), net.ParseIP("3.3.3.3")}
+	node2IPs := []net.IP{net.ParseIP("3.3.3.3"), net.ParseIP("4.4.4.4")}
+
+	testCh := make(chan struct{})
+	defer close(testCh)
+	mockDnsOps.On("ClientConfigFromFile", mock.AnythingOfType("string")).Return(&dns.ClientConfig{}, nil).Once()
+	mockAddressSetFactoryOps.On("NewAddressSet", wildcardDNSName, mock.Anything).Return(mockAddressSetOps, nil).Once()
+	// the address set is filled with the IPs seen on all the nodes, wildcard dnsNames
+	// are never queried
+	mockAddressSetOps.On("SetIPs", []net.IP{}).Return(nil).Once()
+	mockAddressSetOps.On("SetIPs", node1IPs).Return(nil).Once()
+	mockAddressSetOps.On("SetIPs", mock.MatchedBy(func(ips []net.IP) bool {
+		return len(ips) == 3
+	})).Return(nil).Twice()
+	mockAddressSetOps.On("SetIPs", node2IPs).Return(nil).Once()
+
+	res, err := NewEgressDNS(mockAddressSetFactoryOps, nil, testCh)
+	assert.Nil(t, err)
+	_, err = res.Add("addNamespace", wildcardDNSName)
+	assert.Nil(t, err)
+
+	res.SetSnoopedIPs("node1", map[string][]net.IP{wildcardDNSName: node1IPs})
+	res.SetSnoopedIPs("node2", map[string][]net.IP{wildcardDNSName: node2IPs})
+	_, dnsResolves, _ := res.getDNSEntry(wildcardDNSName)
+	assert.ElementsMatch(t, []string{"2.2.2.2", "3.3.3.3", "4.4.4.4"}, ipsToStrings(dnsResolves))
+	// IPs of dnsNames not used by any EgressFirewall are ignored
+	res.SetSnoopedIPs("node2", map[string][]net.IP{"*.other.com": node2IPs, wildcardDNSName: node2IPs})
+
+	res.SetSnoopedIPs("node1", nil)
+	_, dnsResolves, _ = res.getDNSEntry(wildcardDNSName)
+	assert.ElementsMatch(t, []string{"3.3.3.3", "4.4.4.4"}, ipsToStrings(dnsResolves))
+
+	mockDnsOps.AssertExpectations(t)
+	mockAddressSetFactoryOps.AssertExpectations(t)
+	mockAddressSetOps.AssertExpectations(t)
+}
+
+func ipsToStrings(ips []net.IP) []string {
+	ipStrings := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		ipStrings = append(ipStrings, ip.String())
+	}
+	return ipStrings
+}
+
+func TestResolveConcurrently(t *testing.T) {
+	mockAddressSetFactoryOps := new(mocks.AddressSetFactory)
+	mockAddressSetOps := new(mocks.AddressSet)
+	mockDnsOps := new(util_mocks.DNSOps)
+	util.SetDNSLibOpsMockInst(mockDnsOps)
+	slowDNSName := "slow.test.com"
+	fastDNSName := "fast.test.com"
+	config.IPv4Mode = true
+	config.IPv6Mode = false
+
+	testCh := make(chan struct{})
+	defer close(testCh)
+	slowNameserver := make(chan time.Time)
+	mockDnsOps.On("ClientConfigFromFile", mock.AnythingOfType("string")).Return(&dns.ClientConfig{
+		Servers: []string{"1.1.1.1"},
+		Port:    "1234"}, nil).Once()
+	for _, dnsName := range []string{slowDNSName, fastDNSName} {
+		mockDnsOps.On("Fqdn", dnsName).Return(dnsName + ".").Once()
+		mockAddressSetFactoryOps.On("NewAddressSet", dnsName, mock.Anything).Return(mockAddressSetOps, nil).Once()
+	}
+	mockDnsOps.On("SetQuestion", mock.Anything, mock.Anything, mock.Anything).Return(&dns.Msg{}).Run(func(args mock.Arguments) {
+		args.Get(0).(*dns.Msg).SetQuestion(args.String(1), args.Get(2).(uint16))
+	}).Twice()
+	isQuestion := func(dnsName string) interface{} {
+		return mock.MatchedBy(func(msg *dns.Msg) bool {
+			return len(msg.Question) == 1 && msg.Question[0].Name == dnsName+"."
+		})
+	}
+	mockDnsOps.On("Exchange", mock.Anything, isQuestion(slowDNSName), mock.Anything).
+		Return(&dns.Msg{Answer: []dns.RR{generateRR(slowDNSName, "2.2.2.2", "300")}}, 5*time.Second, nil).
+		WaitUntil(slowNameserver).Once()
+	mockDnsOps.On("Exchange", mock.Anything, isQuestion(fastDNSName), mock.Anything).
+		Return(&dns.Msg{Answer: []dns.RR{generateRR(fastDNSName, "3.3.3.3", "300")}}, time.Millisecond, nil).Once()
+	mockAddressSetOps.On("SetIPs", mock.AnythingOfType("[]net.IP")).Return(nil).Twice()
+
+	res, err := NewEgressDNS(mockAddressSetFactoryOps, nil, testCh)
+	assert.Nil(t, err)
+	res.Run(2)
+	_, err = res.Add("addNamespace", slowDNSName)
+	assert.Nil(t, err)
+	_, err = res.Add("addNamespace", fastDNSName)
+	assert.Nil(t, err)
+
+	// the slow nameserver does not delay the resolution of the other dnsNames
+	assert.Eventually(t, func() bool {
+		_, dnsResolves, _ := res.getDNSEntry(fastDNSName)
+		return len(dnsResolves) == 1 && dnsResolves[0].String() == "3.3.3.3"
+	}, 5*time.Second, 10*time.Millisecond)
+	_, dnsResolves, _ := res.getDNSEntry(slowDNSName)
+	assert.Nil(t, dnsResolves)
+
+	close(slowNameserver)
+	assert.Eventually(t, func() bool {
+		_, dnsResolves, _ := res.getDNSEntry(slowDNSName)
+		return len(dnsResolves) == 1 && dnsResolves[0].String() == "2.2.2.2"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mockDnsOps.AssertExpectations(t)
+	mockAddressSetFactoryOps.AssertExpectations(t)
+	mockAddressSetOps.AssertExpectations(t)
+}
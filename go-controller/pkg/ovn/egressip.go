@@ -1,10 +1,13 @@
 package ovn
 
 import (
+	"bufio"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -19,6 +22,7 @@ import (
 	kapi "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilwait "k8s.io/apimachinery/pkg/util/wait"
@@ -29,7 +33,7 @@ import (
 )
 
 type egressIPDialer interface {
-	dial(ip net.IP) bool
+	dial(ip net.IP, healthCheckPort int, timeout time.Duration) bool
 }
 
 var dialer egressIPDialer = &egressIPDial{}
@@ -43,6 +47,8 @@ func (oc *Controller) addEgressIP(eIP *egressipv1.EgressIP) error {
 		}
 	}
 
+	oc.eIPC.handlerStatusItems.Store(getEgressIPKey(eIP), eIP.Status.Items)
+
 	oc.eIPC.namespaceHandlerMutex.Lock()
 	defer oc.eIPC.namespaceHandlerMutex.Unlock()
 
@@ -63,7 +69,7 @@ func (oc *Controller) addEgressIP(eIP *egressipv1.EgressIP) error {
 			DeleteFunc: func(obj interface{}) {
 				namespace := obj.(*kapi.Namespace)
 				klog.V(5).Infof("EgressIP: %s stopped matching on namespace: %s", eIP.Name, namespace.Name)
-				if err := oc.deleteNamespaceEgressIP(eIP, namespace); err != nil {
+				if err := oc.deleteNamespaceEgressIP(oc.eIPC.handlerEgressIP(eIP), namespace); err != nil {
 					klog.Errorf("error: unable to delete namespace handler for EgressIP: %s, err: %v", eIP.Name, err)
 				}
 			},
@@ -73,8 +79,27 @@ func (oc *Controller) addEgressIP(eIP *egressipv1.EgressIP) error {
 }
 
 func (oc *Controller) deleteEgressIP(eIP *egressipv1.EgressIP) error {
+	// the status of eIP may not have caught up yet with the last re-assignment
+	eIP = oc.eIPC.handlerEgressIP(eIP)
 	oc.releaseEgressIPs(eIP)
+	oc.removeEgressIPHandlers(eIP)
 
+	namespaces, err := oc.kube.GetNamespaces(eIP.Spec.NamespaceSelector)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces.Items {
+		if err := oc.deleteNamespacePodsEgressIP(eIP, &namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeEgressIPHandlers stops the namespace and pod handlers of eIP without
+// touching what they programmed in OVN
+func (oc *Controller) removeEgressIPHandlers(eIP *egressipv1.EgressIP) {
+	defer oc.eIPC.handlerStatusItems.Delete(getEgressIPKey(eIP))
 	oc.eIPC.namespaceHandlerMutex.Lock()
 	defer oc.eIPC.namespaceHandlerMutex.Unlock()
 	if nH, exists := oc.eIPC.namespaceHandlerCache[getEgressIPKey(eIP)]; exists {
@@ -88,17 +113,6 @@ func (oc *Controller) deleteEgressIP(eIP *egressipv1.EgressIP) error {
 		oc.watchFactory.RemovePodHandler(&pH)
 		delete(oc.eIPC.podHandlerCache, getEgressIPKey(eIP))
 	}
-
-	namespaces, err := oc.kube.GetNamespaces(eIP.Spec.NamespaceSelector)
-	if err != nil {
-		return err
-	}
-	for _, namespace := range namespaces.Items {
-		if err := oc.deleteNamespacePodsEgressIP(eIP, &namespace); err != nil {
-			return err
-		}
-	}
-	return nil
 }
 
 func (oc *Controller) isEgressNodeReady(egressNode *kapi.Node) bool {
@@ -152,6 +166,10 @@ func (oc *Controller) syncEgressIPs(eIPs []interface{}) {
 				klog.Errorf("Allocator error: EgressIP: %s assigned to node: %s which is not ready, will attempt rebalancing", eIP.Name, eIPStatus.Node)
 				break
 			}
+			if !eNode.hasCapacity() {
+				klog.Errorf("Allocator error: EgressIP: %s assigned to node: %s which has reached its egress IP capacity, will attempt rebalancing", eIP.Name, eIPStatus.Node)
+				break
+			}
 			ip := net.ParseIP(eIPStatus.EgressIP)
 			if ip == nil {
 				klog.Errorf("Allocator error: EgressIP allocation contains unparsable IP address: %s", eIPStatus.EgressIP)
@@ -178,6 +196,16 @@ func (oc *Controller) syncEgressIPs(eIPs []interface{}) {
 			validAssignment = true
 			eNode.tainted = true
 		}
+		if validAssignment {
+			for _, eIPStatus := range eIP.Status.Items {
+				oc.eIPC.allocator[eIPStatus.Node].allocations[net.ParseIP(eIPStatus.EgressIP).String()] = true
+			}
+			if len(eIP.Status.Items) < len(eIP.Spec.EgressIPs) {
+				oc.eIPC.assignmentRetryMutex.Lock()
+				oc.eIPC.assignmentRetry[eIP.Name] = true
+				oc.eIPC.assignmentRetryMutex.Unlock()
+			}
+		}
 		// In the unlikely event that any status has been misallocated previously:
 		// unassign that by updating the entire status and re-allocate properly in addEgressIP
 		if !validAssignment {
@@ -192,7 +220,8 @@ func (oc *Controller) syncEgressIPs(eIPs []interface{}) {
 				}
 			}
 			eIP.Status = egressipv1.EgressIPStatus{
-				Items: []egressipv1.EgressIPStatusItem{},
+				Items:      []egressipv1.EgressIPStatusItem{},
+				Conditions: eIP.Status.Conditions,
 			}
 			if err := oc.updateEgressIPWithRetry(eIP); err != nil {
 				klog.Error(err)
@@ -411,7 +440,7 @@ func (oc *Controller) addNamespaceEgressIP(eIP *egressipv1.EgressIP, namespace *
 			AddFunc: func(obj interface{}) {
 				pod := obj.(*kapi.Pod)
 				klog.V(5).Infof("EgressIP: %s has matched on pod: %s in namespace: %s", eIP.Name, pod.Name, namespace.Name)
-				if err := oc.eIPC.addPodEgressIP(eIP, pod); err != nil {
+				if err := oc.eIPC.addPodEgressIP(oc.eIPC.handlerEgressIP(eIP), pod); err != nil {
 					klog.Errorf("Unable to add pod: %s/%s to EgressIP: %s, err: %v", pod.Namespace, pod.Name, eIP.Name, err)
 				}
 			},
@@ -422,7 +451,7 @@ func (oc *Controller) addNamespaceEgressIP(eIP *egressipv1.EgressIP, namespace *
 				// this watcher receives a delete.
 				if oc.eIPC.needsRetry(newPod) {
 					klog.V(5).Infof("EgressIP: %s update for pod: %s in namespace: %s", eIP.Name, newPod.Name, namespace.Name)
-					if err := oc.eIPC.addPodEgressIP(eIP, newPod); err != nil {
+					if err := oc.eIPC.addPodEgressIP(oc.eIPC.handlerEgressIP(eIP), newPod); err != nil {
 						klog.Errorf("Unable to add pod: %s/%s to EgressIP: %s, err: %v", newPod.Namespace, newPod.Name, eIP.Name, err)
 					}
 				}
@@ -434,7 +463,7 @@ func (oc *Controller) addNamespaceEgressIP(eIP *egressipv1.EgressIP, namespace *
 				// we should not process that delete (as nothing exists in OVN for it)
 				klog.V(5).Infof("EgressIP: %s has stopped matching on pod: %s in namespace: %s, needs delete: %v", eIP.Name, pod.Name, namespace.Name, !oc.eIPC.needsRetry(pod))
 				if !oc.eIPC.needsRetry(pod) {
-					if err := oc.eIPC.deletePodEgressIP(eIP, pod); err != nil {
+					if err := oc.eIPC.deletePodEgressIP(oc.eIPC.handlerEgressIP(eIP), pod); err != nil {
 						klog.Errorf("Unable to delete pod: %s/%s to EgressIP: %s, err: %v", pod.Namespace, pod.Name, eIP.Name, err)
 					}
 				}
@@ -475,18 +504,23 @@ func (oc *Controller) deleteNamespacePodsEgressIP(eIP *egressipv1.EgressIP, name
 func (oc *Controller) assignEgressIPs(eIP *egressipv1.EgressIP) error {
 	oc.eIPC.allocatorMutex.Lock()
 	assignments := []egressipv1.EgressIPStatusItem{}
+	unassigned := []egressipv1.EgressIPUnassignedItem{}
 	defer func() {
 		eIP.Status.Items = assignments
+		eIP.Status.UnassignedItems = unassigned
+		oc.setEgressIPConditions(eIP)
 		oc.eIPC.allocatorMutex.Unlock()
 	}()
 	assignableNodes, existingAllocations := oc.getSortedEgressData()
 	if len(assignableNodes) == 0 {
-		oc.eIPC.assignmentRetry[eIP.Name] = true
-		eIPRef := kapi.ObjectReference{
-			Kind: "EgressIP",
-			Name: eIP.Name,
+		for _, egressIP := range eIP.Spec.EgressIPs {
+			unassigned = append(unassigned, egressipv1.EgressIPUnassignedItem{
+				EgressIP: egressIP,
+				Reason:   oc.getUnassignedReason(net.ParseIP(egressIP), false),
+			})
 		}
-		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "NoMatchingNodeFound", "no assignable nodes for EgressIP: %s, please tag at least one node with label: %s", eIP.Name, util.GetNodeEgressLabel())
+		oc.eIPC.assignmentRetry[eIP.Name] = true
+		oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "NoMatchingNodeFound", "no assignable nodes for EgressIP: %s, please tag at least one node with label: %s", eIP.Name, util.GetNodeEgressLabel())
 		return fmt.Errorf("no assignable nodes")
 	}
 	klog.V(5).Infof("Current assignments are: %+v", existingAllocations)
@@ -494,68 +528,175 @@ func (oc *Controller) assignEgressIPs(eIP *egressipv1.EgressIP) error {
 		klog.V(5).Infof("Will attempt assignment for egress IP: %s", egressIP)
 		eIPC := net.ParseIP(egressIP)
 		if eIPC == nil {
-			eIPRef := kapi.ObjectReference{
-				Kind: "EgressIP",
-				Name: eIP.Name,
-			}
-			oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "InvalidEgressIP", "egress IP: %s for object EgressIP: %s is not a valid IP address", egressIP, eIP.Name)
+			oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "InvalidEgressIP", "egress IP: %s for object EgressIP: %s is not a valid IP address", egressIP, eIP.Name)
 			return fmt.Errorf("unable to parse provided EgressIP: %s, invalid", egressIP)
 		}
 		if node := oc.isAnyClusterNodeIP(eIPC); node != nil {
-			eIPRef := kapi.ObjectReference{
-				Kind: "EgressIP",
-				Name: eIP.Name,
-			}
 			oc.recorder.Eventf(
-				&eIPRef,
+				egressIPRef(eIP),
 				kapi.EventTypeWarning,
 				"UnsupportedRequest",
 				"Egress IP: %v for object EgressIP: %s is the IP address of node: %s, this is unsupported", eIPC, eIP.Name, node.name,
 			)
 			return fmt.Errorf("egress IP: %v is the IP address of node: %s", eIPC, node.name)
 		}
+		if _, exists := existingAllocations[eIPC.String()]; exists {
+			klog.V(5).Infof("EgressIP: %v is already allocated, skipping", eIPC)
+			continue
+		}
+		isAssigned, isCapacityExceeded := false, false
 		for i := 0; i < len(assignableNodes); i++ {
 			klog.V(5).Infof("Attempting assignment on egress node: %+v", assignableNodes[i])
-			if _, exists := existingAllocations[eIPC.String()]; exists {
-				klog.V(5).Infof("EgressIP: %v is already allocated, skipping", eIPC)
-				break
-			}
 			if assignableNodes[i].tainted {
 				klog.V(5).Infof("Node: %s is already in use by another egress IP for this EgressIP: %s, trying another node", assignableNodes[i].name, eIP.Name)
 				continue
 			}
-			if (assignableNodes[i].v6Subnet != nil && assignableNodes[i].v6Subnet.Contains(eIPC)) ||
-				(assignableNodes[i].v4Subnet != nil && assignableNodes[i].v4Subnet.Contains(eIPC)) {
+			if assignableNodes[i].canHost(eIPC) {
+				if !assignableNodes[i].hasCapacity() {
+					klog.V(5).Infof("Node: %s has reached its egress IP capacity: %d, trying another node", assignableNodes[i].name, assignableNodes[i].capacity)
+					isCapacityExceeded = true
+					continue
+				}
 				assignableNodes[i].tainted, oc.eIPC.allocator[assignableNodes[i].name].allocations[eIPC.String()] = true, true
 				assignments = append(assignments, egressipv1.EgressIPStatusItem{
 					EgressIP: eIPC.String(),
 					Node:     assignableNodes[i].name,
 				})
 				klog.V(5).Infof("Successful assignment of egress IP: %s on node: %+v", egressIP, assignableNodes[i])
+				isAssigned = true
 				break
 			}
 		}
+		if !isAssigned {
+			unassigned = append(unassigned, egressipv1.EgressIPUnassignedItem{
+				EgressIP: eIPC.String(),
+				Reason:   oc.getUnassignedReason(eIPC, isCapacityExceeded),
+			})
+		}
+	}
+	for _, item := range unassigned {
+		switch item.Reason {
+		case egressipv1.EgressIPUnassignedCapacityExceeded:
+			oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "CapacityExceeded", "Egress IP: %s for object EgressIP: %s cannot be assigned, all nodes which can host it have reached their egress IP capacity", item.EgressIP, eIP.Name)
+		case egressipv1.EgressIPUnassignedNoReachableNode:
+			oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "NoReachableNode", "Egress IP: %s for object EgressIP: %s cannot be assigned, all nodes which can host it are unreachable", item.EgressIP, eIP.Name)
+		}
 	}
 	if len(assignments) == 0 {
 		oc.eIPC.assignmentRetry[eIP.Name] = true
-		eIPRef := kapi.ObjectReference{
-			Kind: "EgressIP",
-			Name: eIP.Name,
-		}
-		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "NoMatchingNodeFound", "No matching nodes found, which can host any of the egress IPs: %v for object EgressIP: %s", eIP.Spec.EgressIPs, eIP.Name)
+		oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "NoMatchingNodeFound", "No matching nodes found, which can host any of the egress IPs: %v for object EgressIP: %s", eIP.Spec.EgressIPs, eIP.Name)
 		return fmt.Errorf("no matching host found")
 	}
 	if len(assignments) < len(eIP.Spec.EgressIPs) {
 		oc.eIPC.assignmentRetry[eIP.Name] = true
-		eIPRef := kapi.ObjectReference{
-			Kind: "EgressIP",
-			Name: eIP.Name,
-		}
-		oc.recorder.Eventf(&eIPRef, kapi.EventTypeWarning, "UnassignedRequest", "Not all egress IPs for EgressIP: %s could be assigned, please tag more nodes", eIP.Name)
+		oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "UnassignedRequest", "Not all egress IPs for EgressIP: %s could be assigned, please tag more nodes", eIP.Name)
 	}
 	return nil
 }
 
+// getUnassignedReason returns why no node could be found for the egress IP,
+// must be called with the allocator mutex held
+func (oc *Controller) getUnassignedReason(ip net.IP, isCapacityExceeded bool) string {
+	if isCapacityExceeded {
+		return egressipv1.EgressIPUnassignedCapacityExceeded
+	}
+	if ip != nil {
+		for _, eNode := range oc.eIPC.allocator {
+			if eNode.isEgressAssignable && eNode.isReady && !eNode.isReachable && eNode.canHost(ip) {
+				return egressipv1.EgressIPUnassignedNoReachableNode
+			}
+		}
+	}
+	return egressipv1.EgressIPUnassignedNoMatchingNode
+}
+
+// setEgressIPConditions sets the Assigned, PartiallyAssigned and Reachable
+// conditions according to the assignment of the egress IPs, must be called
+// with the allocator mutex held
+func (oc *Controller) setEgressIPConditions(eIP *egressipv1.EgressIP) {
+	requested, assigned := len(eIP.Spec.EgressIPs), len(eIP.Status.Items)
+	unassigned := make([]string, 0, len(eIP.Status.UnassignedItems))
+	unreachable := []string{}
+	for _, item := range eIP.Status.UnassignedItems {
+		unassigned = append(unassigned, fmt.Sprintf("%s (%s)", item.EgressIP, item.Reason))
+		if item.Reason == egressipv1.EgressIPUnassignedNoReachableNode {
+			unreachable = append(unreachable, item.EgressIP)
+		}
+	}
+	unreachableNodes := []string{}
+	for _, status := range eIP.Status.Items {
+		if eNode, exists := oc.eIPC.allocator[status.Node]; !exists || !eNode.isReachable {
+			unreachableNodes = append(unreachableNodes, status.Node)
+		}
+	}
+
+	assignedCondition := metav1.Condition{
+		Type:               egressipv1.EgressIPConditionAssigned,
+		Status:             metav1.ConditionTrue,
+		Reason:             "EgressIPsAssigned",
+		Message:            fmt.Sprintf("All %d egress IPs are assigned", requested),
+		ObservedGeneration: eIP.Generation,
+	}
+	if assigned < requested || requested == 0 {
+		assignedCondition.Status = metav1.ConditionFalse
+		assignedCondition.Reason = "EgressIPsUnassigned"
+		assignedCondition.Message = fmt.Sprintf("%d of %d egress IPs are assigned", assigned, requested)
+		if len(unassigned) > 0 {
+			assignedCondition.Message += fmt.Sprintf(", unassigned: %s", strings.Join(unassigned, ", "))
+		}
+	}
+	meta.SetStatusCondition(&eIP.Status.Conditions, assignedCondition)
+
+	partiallyAssignedCondition := metav1.Condition{
+		Type:               egressipv1.EgressIPConditionPartiallyAssigned,
+		Status:             metav1.ConditionFalse,
+		Reason:             "EgressIPsAssigned",
+		Message:            assignedCondition.Message,
+		ObservedGeneration: eIP.Generation,
+	}
+	if assigned == 0 {
+		partiallyAssignedCondition.Reason = "NoEgressIPsAssigned"
+	} else if assigned < requested {
+		partiallyAssignedCondition.Status = metav1.ConditionTrue
+		partiallyAssignedCondition.Reason = "EgressIPsUnassigned"
+	}
+	meta.SetStatusCondition(&eIP.Status.Conditions, partiallyAssignedCondition)
+
+	reachableCondition := metav1.Condition{
+		Type:               egressipv1.EgressIPConditionReachable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NodesReachable",
+		Message:            "All nodes hosting egress IPs are reachable",
+		ObservedGeneration: eIP.Generation,
+	}
+	if len(unreachable) > 0 || len(unreachableNodes) > 0 {
+		reachableCondition.Status = metav1.ConditionFalse
+		reachableCondition.Reason = "NodesUnreachable"
+		reachableCondition.Message = "Unreachable egress nodes"
+		if len(unreachableNodes) > 0 {
+			reachableCondition.Message += fmt.Sprintf(" hosting egress IPs: %s", strings.Join(unreachableNodes, ", "))
+		}
+		if len(unreachable) > 0 {
+			reachableCondition.Message += fmt.Sprintf(", egress IPs left unassigned: %s", strings.Join(unreachable, ", "))
+		}
+	} else if assigned == 0 {
+		reachableCondition.Status = metav1.ConditionUnknown
+		reachableCondition.Reason = "NoEgressIPsAssigned"
+		reachableCondition.Message = "No egress IPs are assigned"
+	}
+	meta.SetStatusCondition(&eIP.Status.Conditions, reachableCondition)
+}
+
+// egressIPRef returns the reference used for the events of eIP, it carries
+// the UID so that the events are listed by `kubectl describe egressip`
+func egressIPRef(eIP *egressipv1.EgressIP) *kapi.ObjectReference {
+	return &kapi.ObjectReference{
+		Kind: "EgressIP",
+		Name: eIP.Name,
+		UID:  eIP.UID,
+	}
+}
+
 func (oc *Controller) releaseEgressIPs(eIP *egressipv1.EgressIP) {
 	oc.eIPC.allocatorMutex.Lock()
 	defer oc.eIPC.allocatorMutex.Unlock()
@@ -579,8 +720,13 @@ func (oc *Controller) getSortedEgressData() ([]egressNode, map[string]bool) {
 			allAllocations[ip] = true
 		}
 	}
+	// prefer the nodes with the fewest assignments, and amongst those the
+	// nodes with the most room left
 	sort.Slice(assignableNodes, func(i, j int) bool {
-		return len(assignableNodes[i].allocations) < len(assignableNodes[j].allocations)
+		if len(assignableNodes[i].allocations) != len(assignableNodes[j].allocations) {
+			return len(assignableNodes[i].allocations) < len(assignableNodes[j].allocations)
+		}
+		return assignableNodes[i].freeCapacity() > assignableNodes[j].freeCapacity()
 	})
 	return assignableNodes, allAllocations
 }
@@ -590,9 +736,25 @@ func (oc *Controller) setNodeEgressAssignable(nodeName string, isAssignable bool
 	defer oc.eIPC.allocatorMutex.Unlock()
 	if eNode, exists := oc.eIPC.allocator[nodeName]; exists {
 		eNode.isEgressAssignable = isAssignable
+		if !isAssignable {
+			eNode.hasJoined = false
+		}
 	}
 }
 
+// setNodeEgressJoined marks the node as taking part in egress IP assignment and
+// returns true if it was not already, i.e. if the node newly became an egress node
+func (oc *Controller) setNodeEgressJoined(nodeName string) bool {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	eNode, exists := oc.eIPC.allocator[nodeName]
+	if !exists || eNode.hasJoined {
+		return false
+	}
+	eNode.hasJoined = true
+	return true
+}
+
 func (oc *Controller) setNodeEgressReady(nodeName string, isReady bool) {
 	oc.eIPC.allocatorMutex.Lock()
 	defer oc.eIPC.allocatorMutex.Unlock()
@@ -643,9 +805,193 @@ func (oc *Controller) addEgressNode(egressNode *kapi.Node) error {
 			delete(oc.eIPC.assignmentRetry, eIP.Name)
 		}
 	}
+	// only rebalance onto nodes which newly became egress nodes: a node which is
+	// ready or reachable again after a flap does not move the healthy assignments
+	if oc.setNodeEgressJoined(egressNode.Name) {
+		oc.rebalanceEgressIPs(egressNode.Name)
+	}
+	return nil
+}
+
+// rebalanceEgressIPs moves to the egress node which just joined an egress IP of
+// the EgressIPs which have one on a node hosting at least two more egress IPs
+// than it, so that assignments do not stay pinned to the first egress nodes.
+func (oc *Controller) rebalanceEgressIPs(nodeName string) {
+	egressIPs, err := oc.kube.GetEgressIPs()
+	if err != nil {
+		klog.Errorf("Unable to list egressIPs for rebalancing, err: %v", err)
+		return
+	}
+	for i := range egressIPs.Items {
+		eIP := &egressIPs.Items[i]
+		statuses := oc.getRebalancedStatuses(eIP, nodeName)
+		if statuses == nil {
+			continue
+		}
+		klog.Infof("Rebalancing EgressIP: %s onto egress node: %s", eIP.Name, nodeName)
+		if err := oc.updateEgressIPAssignments(eIP, statuses, eIP.Status.UnassignedItems); err != nil {
+			klog.Errorf("EgressIP: %s rebalancing error: %v", eIP.Name, err)
+		}
+	}
+}
+
+// getRebalancedStatuses returns the status items of eIP with the egress IP which should
+// move to the node named nodeName re-assigned to it, nil if none should. The allocation
+// of the moved egress IP is transferred to the node.
+func (oc *Controller) getRebalancedStatuses(eIP *egressipv1.EgressIP, nodeName string) []egressipv1.EgressIPStatusItem {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	eNode, exists := oc.eIPC.allocator[nodeName]
+	if !exists || !eNode.hasCapacity() {
+		return nil
+	}
+	for _, status := range eIP.Status.Items {
+		if status.Node == nodeName {
+			return nil
+		}
+	}
+	for i, status := range eIP.Status.Items {
+		assignedNode, exists := oc.eIPC.allocator[status.Node]
+		if !exists {
+			continue
+		}
+		ip := net.ParseIP(status.EgressIP)
+		if ip == nil || !eNode.canHost(ip) {
+			continue
+		}
+		if len(assignedNode.allocations)-len(eNode.allocations) > 1 {
+			statuses := append([]egressipv1.EgressIPStatusItem{}, eIP.Status.Items...)
+			statuses[i].Node = nodeName
+			delete(assignedNode.allocations, ip.String())
+			eNode.allocations[ip.String()] = true
+			return statuses
+		}
+	}
 	return nil
 }
 
+// enforceEgressIPCapacity moves the egress IPs exceeding the capacity of the node
+// named nodeName, which was lowered, to other egress nodes. The egress IPs which
+// no other node can host are left unassigned and retried when a node joins.
+func (oc *Controller) enforceEgressIPCapacity(nodeName string) {
+	if !oc.isEgressNodeOverCapacity(nodeName) {
+		return
+	}
+	egressIPs, err := oc.kube.GetEgressIPs()
+	if err != nil {
+		klog.Errorf("Unable to list egressIPs to enforce the egress IP capacity of node %s, err: %v", nodeName, err)
+		return
+	}
+	for i := range egressIPs.Items {
+		eIP := &egressIPs.Items[i]
+		statuses, unassigned := oc.getCapacityEnforcedStatuses(eIP, nodeName)
+		if statuses == nil {
+			continue
+		}
+		klog.Infof("EgressIP: %s exceeds the egress IP capacity of node: %s, moving it off", eIP.Name, nodeName)
+		if len(unassigned) > len(eIP.Status.UnassignedItems) {
+			oc.eIPC.assignmentRetryMutex.Lock()
+			oc.eIPC.assignmentRetry[eIP.Name] = true
+			oc.eIPC.assignmentRetryMutex.Unlock()
+		}
+		if err := oc.updateEgressIPAssignments(eIP, statuses, unassigned); err != nil {
+			klog.Errorf("EgressIP: %s capacity enforcement error: %v", eIP.Name, err)
+		}
+	}
+}
+
+func (oc *Controller) isEgressNodeOverCapacity(nodeName string) bool {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	eNode, exists := oc.eIPC.allocator[nodeName]
+	return exists && eNode.capacity != egressIPCapacityUnlimited && len(eNode.allocations) > eNode.capacity
+}
+
+// getCapacityEnforcedStatuses returns the status and unassigned items of eIP with its
+// egress IP on the node named nodeName moved to another egress node, or unassigned if
+// none can host it, as long as the node exceeds its capacity. It returns nil statuses
+// if eIP has nothing to move. The allocator is updated with the move.
+func (oc *Controller) getCapacityEnforcedStatuses(eIP *egressipv1.EgressIP, nodeName string) ([]egressipv1.EgressIPStatusItem, []egressipv1.EgressIPUnassignedItem) {
+	oc.eIPC.allocatorMutex.Lock()
+	defer oc.eIPC.allocatorMutex.Unlock()
+	eNode, exists := oc.eIPC.allocator[nodeName]
+	if !exists || eNode.capacity == egressIPCapacityUnlimited || len(eNode.allocations) <= eNode.capacity {
+		return nil, nil
+	}
+	hostsEgressIP := func(name string) bool {
+		for _, status := range eIP.Status.Items {
+			if status.Node == name {
+				return true
+			}
+		}
+		return false
+	}
+	for i, status := range eIP.Status.Items {
+		if status.Node != nodeName {
+			continue
+		}
+		ip := net.ParseIP(status.EgressIP)
+		if ip == nil {
+			continue
+		}
+		delete(eNode.allocations, ip.String())
+		assignableNodes, _ := oc.getSortedEgressData()
+		for _, candidate := range assignableNodes {
+			if hostsEgressIP(candidate.name) || !candidate.canHost(ip) || !candidate.hasCapacity() {
+				continue
+			}
+			statuses := append([]egressipv1.EgressIPStatusItem{}, eIP.Status.Items...)
+			statuses[i].Node = candidate.name
+			oc.eIPC.allocator[candidate.name].allocations[ip.String()] = true
+			return statuses, eIP.Status.UnassignedItems
+		}
+		statuses := append([]egressipv1.EgressIPStatusItem{}, eIP.Status.Items[:i]...)
+		statuses = append(statuses, eIP.Status.Items[i+1:]...)
+		unassigned := append([]egressipv1.EgressIPUnassignedItem{}, eIP.Status.UnassignedItems...)
+		unassigned = append(unassigned, egressipv1.EgressIPUnassignedItem{
+			EgressIP: ip.String(),
+			Reason:   egressipv1.EgressIPUnassignedCapacityExceeded,
+		})
+		oc.recorder.Eventf(egressIPRef(eIP), kapi.EventTypeWarning, "CapacityExceeded", "Egress IP: %s for object EgressIP: %s cannot be assigned, all nodes which can host it have reached their egress IP capacity", ip, eIP.Name)
+		return statuses, unassigned
+	}
+	return nil, nil
+}
+
+// updateEgressIPAssignments re-assigns the egress IPs of eIP to statuses without
+// re-programming the egress IPs which did not move: only the NAT rules of the
+// moved egress IPs are replaced and the next hops of the reroute policies of the
+// pods are updated in place.
+func (oc *Controller) updateEgressIPAssignments(eIP *egressipv1.EgressIP, statuses []egressipv1.EgressIPStatusItem, unassigned []egressipv1.EgressIPUnassignedItem) error {
+	// the handlers program the pods they get from now on with the new status items
+	oc.eIPC.handlerStatusItems.Store(getEgressIPKey(eIP), statuses)
+	namespaces, err := oc.kube.GetNamespaces(eIP.Spec.NamespaceSelector)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces.Items {
+		pods, err := oc.kube.GetPods(namespace.Name, eIP.Spec.PodSelector)
+		if err != nil {
+			return err
+		}
+		for i := range pods.Items {
+			if oc.eIPC.needsRetry(&pods.Items[i]) {
+				continue
+			}
+			if err := oc.eIPC.updatePodEgressIP(eIP.Name, &pods.Items[i], eIP.Status.Items, statuses); err != nil {
+				return err
+			}
+		}
+	}
+	eIP = eIP.DeepCopy()
+	eIP.Status.Items = statuses
+	eIP.Status.UnassignedItems = unassigned
+	oc.eIPC.allocatorMutex.Lock()
+	oc.setEgressIPConditions(eIP)
+	oc.eIPC.allocatorMutex.Unlock()
+	return oc.updateEgressIPWithRetry(eIP)
+}
+
 func (oc *Controller) deleteEgressNode(egressNode *kapi.Node) error {
 	klog.V(5).Infof("Egress node: %s about to be removed", egressNode.Name)
 	if stdout, stderr, err := util.RunOVNNbctl(
@@ -685,7 +1031,8 @@ func (oc *Controller) reassignEgressIP(eIP *egressipv1.EgressIP) (*egressipv1.Eg
 	}
 	eIP = eIP.DeepCopy()
 	eIP.Status = egressipv1.EgressIPStatus{
-		Items: []egressipv1.EgressIPStatusItem{},
+		Items:      []egressipv1.EgressIPStatusItem{},
+		Conditions: eIP.Status.Conditions,
 	}
 	var reassignError error
 	if err := oc.addEgressIP(eIP); err != nil {
@@ -729,6 +1076,20 @@ func (oc *Controller) initEgressIPAllocator(node *kapi.Node) (err error) {
 			allocations: make(map[string]bool),
 		}
 	}
+	// the node may start or stop advertising its health check server at any time
+	healthCheckPort, err := util.ParseNodeEgressIPHealthCheckPort(node)
+	if err != nil && !util.IsAnnotationNotSetError(err) {
+		klog.Warningf("Falling back to legacy reachability check for egress node %s: %v", node.Name, err)
+	}
+	oc.eIPC.allocator[node.Name].healthCheckPort = healthCheckPort
+	capacity, err := util.ParseNodeEgressIPCapacity(node)
+	if err != nil {
+		if !util.IsAnnotationNotSetError(err) {
+			klog.Warningf("Ignoring egress IP capacity of node %s: %v", node.Name, err)
+		}
+		capacity = egressIPCapacityUnlimited
+	}
+	oc.eIPC.allocator[node.Name].capacity = capacity
 	return nil
 }
 
@@ -774,6 +1135,36 @@ type egressNode struct {
 	isEgressAssignable bool
 	tainted            bool
 	name               string
+	// hasJoined is set once the node has been added for egress assignment since
+	// it was labelled, it is not reset when the node is not ready or reachable
+	hasJoined bool
+	// healthCheckPort is the port of the node's egress IP health check server,
+	// 0 if the node does not run one
+	healthCheckPort int
+	// capacity is the maximum number of egress IPs the node can host,
+	// egressIPCapacityUnlimited if the node does not advertise one
+	capacity int
+}
+
+// egressIPCapacityUnlimited is the capacity of egress nodes without an egress IP capacity annotation
+const egressIPCapacityUnlimited = -1
+
+// hasCapacity returns true if one more egress IP can be assigned to the node
+func (e *egressNode) hasCapacity() bool {
+	return e.capacity == egressIPCapacityUnlimited || len(e.allocations) < e.capacity
+}
+
+// freeCapacity returns how many more egress IPs can be assigned to the node
+func (e *egressNode) freeCapacity() int {
+	if e.capacity == egressIPCapacityUnlimited {
+		return math.MaxInt32
+	}
+	return e.capacity - len(e.allocations)
+}
+
+// canHost returns true if the egress IP is on the subnet of the node's primary interface
+func (e *egressNode) canHost(ip net.IP) bool {
+	return (e.v6Subnet != nil && e.v6Subnet.Contains(ip)) || (e.v4Subnet != nil && e.v4Subnet.Contains(ip))
 }
 
 type egressIPController struct {
@@ -801,6 +1192,10 @@ type egressIPController struct {
 	// Cache used for keeping track of EgressIP pod handlers
 	podHandlerCache map[string]factory.Handler
 
+	// Cache of the status items the handlers of each EgressIP program the pods with,
+	// updated in place when the egress IPs are re-assigned
+	handlerStatusItems sync.Map
+
 	// A cache used for egress IP assignments containing data for all cluster nodes
 	// used for egress IP assignments
 	allocator map[string]*egressNode
@@ -812,6 +1207,17 @@ type egressIPController struct {
 	nbClient libovsdbclient.Client
 }
 
+// handlerEgressIP returns eIP with the status items its handlers currently program the pods with
+func (e *egressIPController) handlerEgressIP(eIP *egressipv1.EgressIP) *egressipv1.EgressIP {
+	statuses, ok := e.handlerStatusItems.Load(getEgressIPKey(eIP))
+	if !ok {
+		return eIP
+	}
+	handlerEIP := *eIP
+	handlerEIP.Status.Items = statuses.([]egressipv1.EgressIPStatusItem)
+	return &handlerEIP
+}
+
 func (e *egressIPController) addPodEgressIP(eIP *egressipv1.EgressIP, pod *kapi.Pod) error {
 	if pod.Spec.HostNetwork {
 		return nil
@@ -854,6 +1260,55 @@ func (e *egressIPController) deletePodEgressIP(eIP *egressipv1.EgressIP, pod *ka
 	return nil
 }
 
+// updatePodEgressIP re-programs the egress IP of the pod from the oldStatuses to the
+// statuses of its EgressIP, leaving the NAT rules of the unchanged status items alone
+func (e *egressIPController) updatePodEgressIP(egressIPName string, pod *kapi.Pod, oldStatuses, statuses []egressipv1.EgressIPStatusItem) error {
+	if pod.Spec.HostNetwork {
+		return nil
+	}
+	podIPs := e.getPodIPs(pod)
+	if podIPs == nil {
+		return nil
+	}
+	for _, status := range oldStatuses {
+		if !hasEgressIPStatusItem(statuses, status) {
+			if err := deleteNATRule(podIPs, status, egressIPName); err != nil {
+				return fmt.Errorf("unable to delete NAT rule for status: %v, err: %v", status, err)
+			}
+		}
+	}
+	for _, status := range statuses {
+		if !hasEgressIPStatusItem(oldStatuses, status) {
+			if err := createNATRule(podIPs, status, egressIPName); err != nil {
+				return fmt.Errorf("unable to create NAT rule for status: %v, err: %v", status, err)
+			}
+		}
+	}
+	oldGatewayRouterIPv4s, oldGatewayRouterIPv6s := e.getGatewayRouterIPs(oldStatuses)
+	gatewayRouterIPv4s, gatewayRouterIPv6s := e.getGatewayRouterIPs(statuses)
+	for _, podIP := range podIPs {
+		var err error
+		if utilnet.IsIPv6(podIP) {
+			err = e.updateEgressReroutePolicy(fmt.Sprintf("ip6.src == %s", podIP.String()), egressIPName, oldGatewayRouterIPv6s, gatewayRouterIPv6s)
+		} else {
+			err = e.updateEgressReroutePolicy(fmt.Sprintf("ip4.src == %s", podIP.String()), egressIPName, oldGatewayRouterIPv4s, gatewayRouterIPv4s)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to update logical router policy, err: %v", err)
+		}
+	}
+	return nil
+}
+
+func hasEgressIPStatusItem(statuses []egressipv1.EgressIPStatusItem, item egressipv1.EgressIPStatusItem) bool {
+	for _, status := range statuses {
+		if status == item {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *egressIPController) getGatewayRouterJoinIP(node string, wantsIPv6 bool) (net.IP, error) {
 	var gatewayIPs []*net.IPNet
 	if item, exists := e.gatewayIPCache.Load(node); exists {
@@ -903,20 +1358,7 @@ func (e *egressIPController) needsRetry(pod *kapi.Pod) bool {
 // to retrive the internal gateway router IP attached to the egress node. This method handles both the shared and
 // local gateway mode case
 func (e *egressIPController) handleEgressReroutePolicy(podIps []net.IP, statuses []egressipv1.EgressIPStatusItem, egressIPName string, cb func(filterOption, egressIPName string, gatewayRouterIPs []net.IP) error) error {
-	gatewayRouterIPv4s, gatewayRouterIPv6s := []net.IP{}, []net.IP{}
-	for _, status := range statuses {
-		isEgressIPv6 := utilnet.IsIPv6String(status.EgressIP)
-		gatewayRouterIP, err := e.getGatewayRouterJoinIP(status.Node, isEgressIPv6)
-		if err != nil {
-			klog.Errorf("Unable to retrieve gateway IP for node: %s, protocol is IPv6: %v, err: %v", status.Node, isEgressIPv6, err)
-			continue
-		}
-		if isEgressIPv6 {
-			gatewayRouterIPv6s = append(gatewayRouterIPv6s, gatewayRouterIP)
-		} else {
-			gatewayRouterIPv4s = append(gatewayRouterIPv4s, gatewayRouterIP)
-		}
-	}
+	gatewayRouterIPv4s, gatewayRouterIPv6s := e.getGatewayRouterIPs(statuses)
 	for _, podIP := range podIps {
 		if utilnet.IsIPv6(podIP) {
 			if len(gatewayRouterIPv6s) > 0 {
@@ -941,6 +1383,25 @@ func (e *egressIPController) handleEgressReroutePolicy(podIps []net.IP, statuses
 	return nil
 }
 
+// getGatewayRouterIPs returns the IPv4 and IPv6 gateway router join IPs of the egress nodes of statuses
+func (e *egressIPController) getGatewayRouterIPs(statuses []egressipv1.EgressIPStatusItem) ([]net.IP, []net.IP) {
+	gatewayRouterIPv4s, gatewayRouterIPv6s := []net.IP{}, []net.IP{}
+	for _, status := range statuses {
+		isEgressIPv6 := utilnet.IsIPv6String(status.EgressIP)
+		gatewayRouterIP, err := e.getGatewayRouterJoinIP(status.Node, isEgressIPv6)
+		if err != nil {
+			klog.Errorf("Unable to retrieve gateway IP for node: %s, protocol is IPv6: %v, err: %v", status.Node, isEgressIPv6, err)
+			continue
+		}
+		if isEgressIPv6 {
+			gatewayRouterIPv6s = append(gatewayRouterIPv6s, gatewayRouterIP)
+		} else {
+			gatewayRouterIPv4s = append(gatewayRouterIPv4s, gatewayRouterIP)
+		}
+	}
+	return gatewayRouterIPv4s, gatewayRouterIPv6s
+}
+
 func (e *egressIPController) createEgressReroutePolicy(filterOption, egressIPName string, gatewayRouterIPs []net.IP) error {
 	policyIDs, err := findReroutePolicyIDs(filterOption, egressIPName, gatewayRouterIPs)
 	if err != nil {
@@ -990,6 +1451,36 @@ func (e *egressIPController) deleteEgressReroutePolicy(filterOption, egressIPNam
 	return nil
 }
 
+// updateEgressReroutePolicy changes the next hops of the reroute policy from oldGatewayRouterIPs to
+// gatewayRouterIPs in place, so that the traffic to the egress nodes which did not change keeps flowing
+func (e *egressIPController) updateEgressReroutePolicy(filterOption, egressIPName string, oldGatewayRouterIPs, gatewayRouterIPs []net.IP) error {
+	if fmt.Sprintf("%q", oldGatewayRouterIPs) == fmt.Sprintf("%q", gatewayRouterIPs) {
+		return nil
+	}
+	if len(gatewayRouterIPs) == 0 {
+		return e.deleteEgressReroutePolicy(filterOption, egressIPName, oldGatewayRouterIPs)
+	}
+	policyIDs, err := findReroutePolicyIDs(filterOption, egressIPName, oldGatewayRouterIPs)
+	if err != nil {
+		return err
+	}
+	if policyIDs == nil {
+		return e.createEgressReroutePolicy(filterOption, egressIPName, gatewayRouterIPs)
+	}
+	for _, policyID := range policyIDs {
+		_, stderr, err := util.RunOVNNbctl(
+			"set",
+			"logical_router_policy",
+			policyID,
+			fmt.Sprintf("nexthops=%q", gatewayRouterIPs),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to update logical router policy, stderr: %s, err: %v", stderr, err)
+		}
+	}
+	return nil
+}
+
 func findReroutePolicyIDs(filterOption, egressIPName string, gatewayRouterIPs []net.IP) ([]string, error) {
 	policyIDs, stderr, err := util.RunOVNNbctl(
 		"--format=csv",
@@ -1066,26 +1557,61 @@ func (oc *Controller) checkEgressNodesReachability() {
 				}
 			}
 		}
-		time.Sleep(5 * time.Second)
+		time.Sleep(time.Duration(config.OVNKubernetesFeature.EgressIPReachabilityCheckInterval) * time.Second)
 	}
 }
 
 func (oc *Controller) isReachable(node *egressNode) bool {
 	reachable := false
+	timeout := time.Duration(config.OVNKubernetesFeature.EgressIPReachabilityCheckTimeout) * time.Second
 	// check IPv6 only if IPv4 fails
 	if node.v4IP != nil {
-		if reachable = dialer.dial(node.v4IP); reachable {
+		if reachable = dialer.dial(node.v4IP, node.healthCheckPort, timeout); reachable {
 			return reachable
 		}
 	}
 	if node.v6IP != nil {
-		reachable = dialer.dial(node.v6IP)
+		reachable = dialer.dial(node.v6IP, node.healthCheckPort, timeout)
 	}
 	return reachable
 }
 
 type egressIPDial struct{}
 
+// dial checks whether the node owning ip is reachable. Nodes running an egress IP
+// health check server on healthCheckPort are probed through it, the others are
+// checked with a connection attempt to their discard port.
+func (e *egressIPDial) dial(ip net.IP, healthCheckPort int, timeout time.Duration) bool {
+	if healthCheckPort == 0 {
+		return e.dialDiscard(ip, timeout)
+	}
+	return e.dialHealthCheck(ip, healthCheckPort, timeout)
+}
+
+// dialHealthCheck connects to the egress IP health check server of a node and
+// returns whether the node reported itself healthy within timeout
+func (e *egressIPDial) dialHealthCheck(ip net.IP, healthCheckPort int, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(healthCheckPort)), timeout)
+	if err != nil {
+		klog.V(5).Infof("Egress IP health check of %s failed: %v", ip, err)
+		return false
+	}
+	defer conn.Close()
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return false
+	}
+	response, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		klog.V(5).Infof("Egress IP health check of %s failed: %v", ip, err)
+		return false
+	}
+	if response = strings.TrimSpace(response); response != types.EgressIPHealthCheckResponseOK {
+		klog.Warningf("Egress IP health check of %s reported the node unhealthy: %s", ip, response)
+		return false
+	}
+	return true
+}
+
 // Blantant copy from: https://github.com/openshift/sdn/blob/master/pkg/network/common/egressip.go#L499-L505
 // Ping a node and return whether or not we think it is online. We do this by trying to
 // open a TCP connection to the "discard" service (port 9); if the node is offline, the
@@ -1093,8 +1619,7 @@ type egressIPDial struct{}
 // we will return false). If the node is online then we presumably will get a "connection
 // refused" error; but the code below assumes that anything other than timeout or "no
 // route" indicates that the node is online.
-func (e *egressIPDial) dial(ip net.IP) bool {
-	timeout := time.Second
+func (e *egressIPDial) dialDiscard(ip net.IP, timeout time.Duration) bool {
 	conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip.String(), "9"), timeout)
 	if conn != nil {
 		conn.Close()
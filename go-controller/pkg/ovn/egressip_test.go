@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
+	"time"
 
 	"github.com/onsi/ginkgo"
 	"github.com/onsi/gomega"
@@ -13,6 +15,7 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/urfave/cli/v2"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	utilnet "k8s.io/utils/net"
@@ -20,7 +23,7 @@ import (
 
 type fakeEgressIPDialer struct{}
 
-func (f fakeEgressIPDialer) dial(ip net.IP) bool {
+func (f fakeEgressIPDialer) dial(ip net.IP, healthCheckPort int, timeout time.Duration) bool {
 	return true
 }
 
@@ -89,6 +92,7 @@ func setupNode(nodeName string, ipNets []string, mockAllocationIPs []string) egr
 		isReady:            true,
 		isReachable:        true,
 		isEgressAssignable: true,
+		capacity:           egressIPCapacityUnlimited,
 	}
 	return node
 }
@@ -2354,6 +2358,478 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("should re-balance EgressIPs onto a newly tagged node", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP1 := "192.168.126.25"
+				egressIP2 := "192.168.126.30"
+				node1IPv4 := "192.168.126.51/24"
+				node2IPv4 := "192.168.126.101/24"
+
+				node1 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node1Name,
+						Labels: map[string]string{
+							"k8s.ovn.org/egress-assignable": "",
+						},
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", node1IPv4),
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+				node2 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node2Name,
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", node2IPv4),
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+
+				eIP1 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta("egressip-1"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								Node:     node1.Name,
+								EgressIP: egressIP1,
+							},
+						},
+					},
+				}
+				eIP2 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta("egressip-2"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP2},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								Node:     node1.Name,
+								EgressIP: egressIP2,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP1, eIP2},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{node1, node2},
+					})
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=%s nexthop!=[]", types.EgressIPReroutePriority),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --may-exist lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
+					},
+				)
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids,match find logical_router_policy priority=100"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids,logical_ip find nat"),
+					},
+				)
+				fakeOvn.controller.WatchEgressNodes()
+				fakeOvn.controller.WatchEgressIP()
+
+				gomega.Eventually(getEgressIPAllocatorSizeSafely).Should(gomega.Equal(2))
+				gomega.Expect(getEgressIPStatus(eIP1.Name)[0].Node).To(gomega.Equal(node1.Name))
+				gomega.Expect(getEgressIPStatus(eIP2.Name)[0].Node).To(gomega.Equal(node1.Name))
+
+				node2.Labels = map[string]string{
+					"k8s.ovn.org/egress-assignable": "",
+				}
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
+					},
+				)
+				_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node2, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				assignedNodes := func() []string {
+					nodes := []string{}
+					for _, name := range []string{eIP1.Name, eIP2.Name} {
+						for _, status := range getEgressIPStatus(name) {
+							nodes = append(nodes, status.Node)
+						}
+					}
+					return nodes
+				}
+				gomega.Eventually(assignedNodes).Should(gomega.ConsistOf(node1.Name, node2.Name))
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("should not re-balance EgressIPs onto an egress node which is ready again", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP1 := "192.168.126.25"
+				egressIP2 := "192.168.126.30"
+				node1IPv4 := "192.168.126.51/24"
+				node2IPv4 := "192.168.126.101/24"
+
+				node1 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node1Name,
+						Labels: map[string]string{
+							"k8s.ovn.org/egress-assignable": "",
+						},
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", node1IPv4),
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+				node2 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node2Name,
+						Labels: map[string]string{
+							"k8s.ovn.org/egress-assignable": "",
+						},
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", node2IPv4),
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+
+				eIP1 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta("egressip-1"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								Node:     node1.Name,
+								EgressIP: egressIP1,
+							},
+						},
+					},
+				}
+				eIP2 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta("egressip-2"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP2},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								Node:     node1.Name,
+								EgressIP: egressIP2,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP1, eIP2},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{node1, node2},
+					})
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=%s nexthop!=[]", types.EgressIPReroutePriority),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --may-exist lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
+					},
+				)
+				fakeOvn.controller.WatchEgressNodes()
+				gomega.Eventually(getEgressIPAllocatorSizeSafely).Should(gomega.Equal(2))
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids,match find logical_router_policy priority=100"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids,logical_ip find nat"),
+					},
+				)
+				fakeOvn.controller.WatchEgressIP()
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				isEgressNodeReady := func() bool {
+					fakeOvn.controller.eIPC.allocatorMutex.Lock()
+					defer fakeOvn.controller.eIPC.allocatorMutex.Unlock()
+					return fakeOvn.controller.eIPC.allocator[node2.Name].isReady
+				}
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 remove logical_switch_port etor-GR_node2 options nat-addresses=router"),
+					},
+				)
+				node2.Status.Conditions[0].Status = v1.ConditionFalse
+				_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node2, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(isEgressNodeReady).Should(gomega.BeFalse())
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
+					},
+				)
+				node2.Status.Conditions[0].Status = v1.ConditionTrue
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node2, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(isEgressNodeReady).Should(gomega.BeTrue())
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				assignedNodes := func() []string {
+					nodes := []string{}
+					for _, name := range []string{eIP1.Name, eIP2.Name} {
+						for _, status := range getEgressIPStatus(name) {
+							nodes = append(nodes, status.Node)
+						}
+					}
+					return nodes
+				}
+				gomega.Consistently(assignedNodes).Should(gomega.ConsistOf(node1.Name, node1.Name))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should move the EgressIPs exceeding the lowered egress IP capacity of a node", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				egressIP1 := "192.168.126.25"
+				egressIP2 := "192.168.126.30"
+				node1IPv4 := "192.168.126.51/24"
+				node2IPv4 := "192.168.126.101/24"
+
+				node1 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node1Name,
+						Labels: map[string]string{
+							"k8s.ovn.org/egress-assignable": "",
+						},
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", node1IPv4),
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+				node2 := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: node2Name,
+						Labels: map[string]string{
+							"k8s.ovn.org/egress-assignable": "",
+						},
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr": fmt.Sprintf("{\"ipv4\": \"%s\"}", node2IPv4),
+						},
+					},
+					Status: v1.NodeStatus{
+						Conditions: []v1.NodeCondition{
+							{
+								Type:   v1.NodeReady,
+								Status: v1.ConditionTrue,
+							},
+						},
+					},
+				}
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								Node:     node1.Name,
+								EgressIP: egressIP1,
+							},
+						},
+					},
+				}
+				eIP2 := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta("egressip-2"),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP2},
+					},
+					Status: egressipv1.EgressIPStatus{
+						Items: []egressipv1.EgressIPStatusItem{
+							{
+								Node:     node1.Name,
+								EgressIP: egressIP2,
+							},
+						},
+					},
+				}
+
+				fakeOvn.start(ctx,
+					&egressipv1.EgressIPList{
+						Items: []egressipv1.EgressIP{eIP, eIP2},
+					},
+					&v1.NodeList{
+						Items: []v1.Node{node1, node2},
+					})
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy priority=%s nexthop!=[]", types.EgressIPReroutePriority),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --may-exist lr-policy-add ovn_cluster_router 101 ip4.src == 10.128.0.0/14 && ip4.dst == 10.128.0.0/14 allow"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node1 options:nat-addresses=router"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_switch_port etor-GR_node2 options:nat-addresses=router"),
+					},
+				)
+				fakeOvn.controller.WatchEgressNodes()
+				gomega.Eventually(getEgressIPAllocatorSizeSafely).Should(gomega.Equal(2))
+
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids,match find logical_router_policy priority=100"),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid,external_ids,logical_ip find nat"),
+					},
+				)
+				fakeOvn.controller.WatchEgressIP()
+				gomega.Eventually(fakeOvn.fakeExec.CalledMatchesExpected).Should(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				node1.Annotations["k8s.ovn.org/egress-ip-capacity"] = "1"
+				_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Nodes().Update(context.TODO(), &node1, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				assignedNodes := func() []string {
+					nodes := []string{}
+					for _, name := range []string{eIP.Name, eIP2.Name} {
+						for _, status := range getEgressIPStatus(name) {
+							nodes = append(nodes, status.Node)
+						}
+					}
+					return nodes
+				}
+				gomega.Eventually(assignedNodes).Should(gomega.ConsistOf(node1.Name, node2.Name))
+				gomega.Consistently(assignedNodes).Should(gomega.ConsistOf(node1.Name, node2.Name))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should only re-program the moved egress IP of a pod", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP1 := "192.168.126.25"
+				egressIP2 := "192.168.126.30"
+				node3Name := "node3"
+				for node, gatewayIP := range map[string]string{node1Name: "100.64.0.2", node2Name: "100.64.0.3", node3Name: "100.64.0.4"} {
+					fakeOvn.controller.eIPC.gatewayIPCache.Store(node, []*net.IPNet{ovntest.MustParseIPNet(gatewayIP + "/29")})
+				}
+				egressPod := newPodWithLabels(namespace, podName, node1Name, podV4IP, egressPodLabel)
+				oldStatuses := []egressipv1.EgressIPStatusItem{
+					{
+						Node:     node1Name,
+						EgressIP: egressIP1,
+					},
+					{
+						Node:     node2Name,
+						EgressIP: egressIP2,
+					},
+				}
+				statuses := []egressipv1.EgressIPStatusItem{
+					{
+						Node:     node1Name,
+						EgressIP: egressIP1,
+					},
+					{
+						Node:     node3Name,
+						EgressIP: egressIP2,
+					},
+				}
+
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=\"%s\" external_ip=\"%s\"", egressIPName, podV4IP, egressIP2),
+						Output: natID,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 remove logical_router GR_%s nat %s", node2Name, natID),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find nat external_ids:name=%s logical_ip=\"%s\" external_ip=\"%s\"", egressIPName, podV4IP, egressIP2),
+						fmt.Sprintf("ovn-nbctl --timeout=15 --id=@nat create nat type=snat %s %s %s %s -- add logical_router GR_%s nat @nat", fmt.Sprintf("logical_port=k8s-%s", node3Name), fmt.Sprintf("external_ip=\"%s\"", egressIP2), fmt.Sprintf("logical_ip=\"%s\"", podV4IP), fmt.Sprintf("external_ids:name=%s", egressIPName), node3Name),
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmd(
+					&ovntest.ExpectedCmd{
+						Cmd:    fmt.Sprintf("ovn-nbctl --timeout=15 --format=csv --data=bare --no-heading --columns=_uuid find logical_router_policy match=\"%s\" priority=%s external_ids:name=%s nexthops=%q", fmt.Sprintf("ip4.src == %s", podV4IP), types.EgressIPReroutePriority, egressIPName, []string{"100.64.0.2", "100.64.0.3"}),
+						Output: reroutePolicyID,
+					},
+				)
+				fakeOvn.fakeExec.AddFakeCmdsNoOutputNoError(
+					[]string{
+						fmt.Sprintf("ovn-nbctl --timeout=15 set logical_router_policy %s nexthops=%q", reroutePolicyID, []string{"100.64.0.2", "100.64.0.4"}),
+					},
+				)
+
+				err := fakeOvn.controller.eIPC.updatePodEgressIP(egressIPName, egressPod, oldStatuses, statuses)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(fakeOvn.fakeExec.CalledMatchesExpected()).To(gomega.BeTrue(), fakeOvn.fakeExec.ErrorDesc)
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 
 	ginkgo.Context("Dual-stack assignment", func() {
@@ -2424,6 +2900,147 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("should not allocate on a node which has reached its egress IP capacity", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP := "192.168.126.101"
+				node1 := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{"192.168.126.102"})
+				node2 := setupNode(node2Name, []string{"192.168.126.51/24"}, []string{"192.168.126.68", "192.168.126.111"})
+				node1.capacity = 1
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.HaveLen(1))
+				gomega.Expect(eIP.Status.Items[0].Node).To(gomega.Equal(node2.name))
+				gomega.Expect(eIP.Status.UnassignedItems).To(gomega.BeEmpty())
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should report egress IPs which cannot be assigned because of node capacity", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP1 := "192.168.126.101"
+				egressIP2 := "192.168.126.102"
+				egressIP3 := "192.168.200.10"
+				node1 := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{"192.168.126.110"})
+				node2 := setupNode(node2Name, []string{"192.168.126.51/24"}, []string{})
+				node1.capacity = 1
+				node2.capacity = 1
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1, egressIP2, egressIP3},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.Equal([]egressipv1.EgressIPStatusItem{
+					{
+						EgressIP: egressIP1,
+						Node:     node2.name,
+					},
+				}))
+				gomega.Expect(eIP.Status.UnassignedItems).To(gomega.Equal([]egressipv1.EgressIPUnassignedItem{
+					{
+						EgressIP: egressIP2,
+						Reason:   egressipv1.EgressIPUnassignedCapacityExceeded,
+					},
+					{
+						EgressIP: egressIP3,
+						Reason:   egressipv1.EgressIPUnassignedNoMatchingNode,
+					},
+				}))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should set the EgressIP conditions when only some egress IPs can be assigned", func() {
+			app.Action = func(ctx *cli.Context) error {
+
+				fakeOvn.start(ctx)
+
+				egressIP1 := "192.168.126.101"
+				egressIP2 := "192.168.126.102"
+				node1 := setupNode(node1Name, []string{"192.168.126.12/24"}, []string{})
+				node2 := setupNode(node2Name, []string{"192.168.126.51/24"}, []string{})
+				node2.isReachable = false
+
+				fakeOvn.controller.eIPC.allocator[node1.name] = &node1
+				fakeOvn.controller.eIPC.allocator[node2.name] = &node2
+
+				eIP := egressipv1.EgressIP{
+					ObjectMeta: newEgressIPMeta(egressIPName),
+					Spec: egressipv1.EgressIPSpec{
+						EgressIPs: []string{egressIP1, egressIP2},
+					},
+				}
+				err := fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.Equal([]egressipv1.EgressIPStatusItem{
+					{
+						EgressIP: egressIP1,
+						Node:     node1.name,
+					},
+				}))
+				gomega.Expect(eIP.Status.UnassignedItems).To(gomega.Equal([]egressipv1.EgressIPUnassignedItem{
+					{
+						EgressIP: egressIP2,
+						Reason:   egressipv1.EgressIPUnassignedNoReachableNode,
+					},
+				}))
+
+				assigned := meta.FindStatusCondition(eIP.Status.Conditions, egressipv1.EgressIPConditionAssigned)
+				gomega.Expect(assigned).NotTo(gomega.BeNil())
+				gomega.Expect(assigned.Status).To(gomega.Equal(metav1.ConditionFalse))
+				gomega.Expect(assigned.Message).To(gomega.Equal(fmt.Sprintf("1 of 2 egress IPs are assigned, unassigned: %s (NoReachableNode)", egressIP2)))
+				gomega.Expect(meta.IsStatusConditionTrue(eIP.Status.Conditions, egressipv1.EgressIPConditionPartiallyAssigned)).To(gomega.BeTrue())
+				gomega.Expect(meta.IsStatusConditionFalse(eIP.Status.Conditions, egressipv1.EgressIPConditionReachable)).To(gomega.BeTrue())
+
+				recordedEvent := <-fakeOvn.fakeRecorder.Events
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("Egress IP: %s for object EgressIP: %s cannot be assigned, all nodes which can host it are unreachable", egressIP2, eIP.Name))
+
+				node2.isReachable = true
+				fakeOvn.controller.releaseEgressIPs(&eIP)
+				err = fakeOvn.controller.assignEgressIPs(&eIP)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(eIP.Status.Items).To(gomega.HaveLen(2))
+				gomega.Expect(eIP.Status.UnassignedItems).To(gomega.BeEmpty())
+				gomega.Expect(meta.IsStatusConditionTrue(eIP.Status.Conditions, egressipv1.EgressIPConditionAssigned)).To(gomega.BeTrue())
+				gomega.Expect(meta.IsStatusConditionFalse(eIP.Status.Conditions, egressipv1.EgressIPConditionPartiallyAssigned)).To(gomega.BeTrue())
+				gomega.Expect(meta.IsStatusConditionTrue(eIP.Status.Conditions, egressipv1.EgressIPConditionReachable)).To(gomega.BeTrue())
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 
 	ginkgo.Context("IPv6 assignment", func() {
@@ -3416,4 +4033,79 @@ var _ = ginkgo.Describe("OVN master EgressIP Operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 	})
+
+	ginkgo.Context("egress node reachability", func() {
+
+		ginkgo.It("should use the health check port advertised by the node", func() {
+			app.Action = func(ctx *cli.Context) error {
+				node := v1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node",
+						Annotations: map[string]string{
+							"k8s.ovn.org/node-primary-ifaddr":         "{\"ipv4\":\"192.168.126.12/24\"}",
+							"k8s.ovn.org/egress-ip-health-check-port": strconv.Itoa(9107),
+						},
+					},
+				}
+				fakeOvn.start(ctx)
+
+				err := fakeOvn.controller.initEgressIPAllocator(&node)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(fakeOvn.controller.eIPC.allocator["node"].healthCheckPort).To(gomega.Equal(9107))
+
+				// a node that stops advertising it falls back to the legacy check
+				delete(node.Annotations, "k8s.ovn.org/egress-ip-health-check-port")
+				err = fakeOvn.controller.initEgressIPAllocator(&node)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(fakeOvn.controller.eIPC.allocator["node"].healthCheckPort).To(gomega.Equal(0))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+	})
+})
+
+var _ = ginkgo.Describe("OVN master EgressIP reachability checks", func() {
+
+	// serveHealthCheck answers every connection accepted on a local listener with response
+	serveHealthCheck := func(response string) (int, func()) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				if response != "" {
+					conn.Write([]byte(response + "\n"))
+					conn.Close()
+				}
+			}
+		}()
+		return listener.Addr().(*net.TCPAddr).Port, func() { listener.Close() }
+	}
+
+	ginkgo.It("should report a node healthy through its health check server", func() {
+		port, stop := serveHealthCheck(types.EgressIPHealthCheckResponseOK)
+		defer stop()
+		d := &egressIPDial{}
+		gomega.Expect(d.dial(net.ParseIP("127.0.0.1"), port, time.Second)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should report a node unreachable when its health check fails", func() {
+		port, stop := serveHealthCheck("OVS datapath of br-int is not operational")
+		defer stop()
+		d := &egressIPDial{}
+		gomega.Expect(d.dial(net.ParseIP("127.0.0.1"), port, time.Second)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("should report a node unreachable when its health check server does not answer in time", func() {
+		port, stop := serveHealthCheck("")
+		defer stop()
+		d := &egressIPDial{}
+		gomega.Expect(d.dial(net.ParseIP("127.0.0.1"), port, 100*time.Millisecond)).To(gomega.BeFalse())
+	})
 })
@@ -0,0 +1,487 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	egressQoSAppliedCorrectly = "EgressQoS Rules applied"
+	egressQoSAddError         = "EgressQoS Rules not correctly added"
+	egressQoSUpdateError      = "EgressQoS Rules not correctly updated"
+
+	// egressQoSExternalID is the external id of the QoS rows of an EgressQoS,
+	// its value is the namespace of the EgressQoS
+	egressQoSExternalID = "EgressQoS"
+)
+
+type egressQoS struct {
+	sync.Mutex
+	name      string
+	namespace string
+	rules     []*egressQoSRule
+}
+
+type egressQoSRule struct {
+	priority int
+	dscp     int
+	dstCIDR  string
+	// podSelector is nil when the rule applies to all the pods of the namespace
+	podSelector labels.Selector
+	// podAddressSet holds the IPs of the pods matching podSelector
+	podAddressSet addressset.AddressSet
+	podHandler    *factory.Handler
+}
+
+func newEgressQoSRule(rawEgressQoSRule egressqosapi.EgressQoSRule, priority int) (*egressQoSRule, error) {
+	if rawEgressQoSRule.DSCP < 0 || rawEgressQoSRule.DSCP > 63 {
+		return nil, fmt.Errorf("invalid dscp %d, must be between 0 and 63", rawEgressQoSRule.DSCP)
+	}
+	eqr := &egressQoSRule{
+		priority: priority,
+		dscp:     rawEgressQoSRule.DSCP,
+	}
+
+	if rawEgressQoSRule.DstCIDR != nil {
+		_, _, err := net.ParseCIDR(*rawEgressQoSRule.DstCIDR)
+		if err != nil {
+			return nil, err
+		}
+		eqr.dstCIDR = *rawEgressQoSRule.DstCIDR
+	}
+
+	if len(rawEgressQoSRule.PodSelector.MatchLabels) > 0 || len(rawEgressQoSRule.PodSelector.MatchExpressions) > 0 {
+		selector, err := metav1.LabelSelectorAsSelector(&rawEgressQoSRule.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		eqr.podSelector = selector
+	}
+
+	return eqr, nil
+}
+
+// syncEgressQoS removes the QoS rules of the EgressQoSes deleted while ovnkube-master was down
+func (oc *Controller) syncEgressQoS(egressQoSes []interface{}) {
+	namespaces := sets.NewString()
+	for _, obj := range egressQoSes {
+		egressQoS, ok := obj.(*egressqosapi.EgressQoS)
+		if !ok {
+			klog.Errorf("Spurious object in syncEgressQoS: %v", obj)
+			continue
+		}
+		namespaces.Insert(egressQoS.Namespace)
+	}
+
+	stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=external_ids",
+		"--format=table", "find", "qos")
+	if err != nil {
+		klog.Errorf("Cannot sync egressQoS, failed to list the QoS rules, stderr: %q, err: %v", stderr, err)
+		return
+	}
+	staleNamespaces := sets.NewString()
+	for _, externalID := range strings.Fields(stdout) {
+		if namespace := strings.TrimPrefix(externalID, egressQoSExternalID+"="); namespace != externalID && !namespaces.Has(namespace) {
+			staleNamespaces.Insert(namespace)
+		}
+	}
+	if staleNamespaces.Len() == 0 {
+		return
+	}
+
+	txn := util.NewNBTxn()
+	for _, namespace := range staleNamespaces.List() {
+		if err := deleteEgressQoSRules(namespace, txn); err != nil {
+			klog.Errorf("Cannot sync egressQoS of namespace %s: %v", namespace, err)
+		}
+	}
+	if _, stderr, err := txn.Commit(); err != nil {
+		klog.Errorf("Failed to commit db changes while syncing egressQoS, stderr: %q, err: %v", stderr, err)
+	}
+}
+
+func (oc *Controller) addEgressQoS(egressQoSObj *egressqosapi.EgressQoS, txn *util.NBTxn) error {
+	klog.Infof("Adding egressQoS %s in namespace %s", egressQoSObj.Name, egressQoSObj.Namespace)
+
+	eq := &egressQoS{
+		name:      egressQoSObj.Name,
+		namespace: egressQoSObj.Namespace,
+		rules:     make([]*egressQoSRule, 0),
+	}
+	eq.Lock()
+	defer eq.Unlock()
+
+	egressQoSStartPriorityInt, err := strconv.Atoi(types.EgressQoSStartPriority)
+	if err != nil {
+		return fmt.Errorf("failed to convert egressQoSStartPriority to Integer: cannot add egressQoS for namespace %s", egressQoSObj.Namespace)
+	}
+	for i, egressQoSRule := range egressQoSObj.Spec.Egress {
+		if i >= egressQoSStartPriorityInt {
+			klog.Warningf("egressQoS for namespace %s has too many rules, the rest will be ignored",
+				egressQoSObj.Namespace)
+			break
+		}
+		eqr, err := newEgressQoSRule(egressQoSRule, egressQoSStartPriorityInt-i)
+		if err != nil {
+			return fmt.Errorf("cannot create EgressQoS Rule %d for namespace %s: %v",
+				i, egressQoSObj.Namespace, err)
+		}
+		eq.rules = append(eq.rules, eqr)
+	}
+	// there should not be an item already in egressQoS map for the given Namespace
+	if _, loaded := oc.egressQoSes.Load(egressQoSObj.Namespace); loaded {
+		return fmt.Errorf("error attempting to add egressQoS %s to namespace %s when it already has an egressQoS",
+			egressQoSObj.Name, egressQoSObj.Namespace)
+	}
+
+	if err := oc.setupEgressQoS(eq, txn); err != nil {
+		if cleanupErr := oc.removeEgressQoSPodAddressSets(eq); cleanupErr != nil {
+			klog.Errorf("Failed to clean up egressQoS in namespace %s: %v", eq.namespace, cleanupErr)
+		}
+		return err
+	}
+	// the egressQoS is only stored once it is set up, so that an egressQoS which failed
+	// to be added is not deleted again
+	oc.egressQoSes.Store(egressQoSObj.Namespace, eq)
+	return nil
+}
+
+// setupEgressQoS creates the address sets and the pod handlers of the rules of an
+// egressQoS, and adds its QoS rules to the transaction
+func (oc *Controller) setupEgressQoS(eq *egressQoS, txn *util.NBTxn) error {
+	// EgressQoS needs to make sure that the address_set for the namespace exists independently of the namespace object
+	// so that OVN doesn't get unresolved references to the address_set.
+	if err := oc.addressSetFactory.EnsureAddressSet(eq.namespace); err != nil {
+		return fmt.Errorf("cannot Ensure that addressSet for namespace %s exists %v", eq.namespace, err)
+	}
+	if err := oc.ensureEgressQoSPodAddressSets(eq); err != nil {
+		return err
+	}
+
+	logicalSwitches, err := oc.getEgressQoSLogicalSwitches()
+	if err != nil {
+		return err
+	}
+	// replace the QoS rules left over by a previous run for the namespace
+	if err := deleteEgressQoSRules(eq.namespace, txn); err != nil {
+		return err
+	}
+	return createEgressQoSRules(eq, logicalSwitches, txn)
+}
+
+func (oc *Controller) updateEgressQoS(oldEgressQoS, newEgressQoS *egressqosapi.EgressQoS, txn *util.NBTxn) error {
+	// an egressQoS which failed to be added was never stored, there is nothing to delete then
+	if _, loaded := oc.egressQoSes.Load(oldEgressQoS.Namespace); loaded {
+		if err := oc.deleteEgressQoS(oldEgressQoS, txn); err != nil {
+			return err
+		}
+	}
+	return oc.addEgressQoS(newEgressQoS, txn)
+}
+
+func (oc *Controller) deleteEgressQoS(egressQoSObj *egressqosapi.EgressQoS, txn *util.NBTxn) error {
+	klog.Infof("Deleting egressQoS %s in namespace %s", egressQoSObj.Name, egressQoSObj.Namespace)
+	obj, loaded := oc.egressQoSes.LoadAndDelete(egressQoSObj.Namespace)
+	if !loaded {
+		return fmt.Errorf("there is no egressQoS found in namespace %s", egressQoSObj.Namespace)
+	}
+
+	eq, ok := obj.(*egressQoS)
+	if !ok {
+		return fmt.Errorf("spurious object found in egressQoS map for namespace %s: %v", egressQoSObj.Namespace, obj)
+	}
+
+	eq.Lock()
+	defer eq.Unlock()
+	var deleteErrors []error
+	if err := oc.removeEgressQoSPodAddressSets(eq); err != nil {
+		deleteErrors = append(deleteErrors, err)
+	}
+	if err := deleteEgressQoSRules(egressQoSObj.Namespace, txn); err != nil {
+		deleteErrors = append(deleteErrors, err)
+	}
+	return kerrors.NewAggregate(deleteErrors)
+}
+
+// removeEgressQoSPodAddressSets removes the pod handlers and destroys the pod address sets
+// of the rules of an egressQoS, going on with the other rules when one fails
+func (oc *Controller) removeEgressQoSPodAddressSets(eq *egressQoS) error {
+	var deleteErrors []error
+	for _, rule := range eq.rules {
+		if rule.podHandler != nil {
+			oc.watchFactory.RemovePodHandler(rule.podHandler)
+			rule.podHandler = nil
+		}
+		if rule.podAddressSet != nil {
+			if err := rule.podAddressSet.Destroy(); err != nil {
+				deleteErrors = append(deleteErrors, fmt.Errorf("cannot delete pod addressSet of egressQoS in namespace %s: %v",
+					eq.namespace, err))
+				continue
+			}
+			rule.podAddressSet = nil
+		}
+	}
+	return kerrors.NewAggregate(deleteErrors)
+}
+
+func (oc *Controller) updateEgressQoSWithRetry(egressQoS *egressqosapi.EgressQoS) error {
+	retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return oc.kube.UpdateEgressQoS(egressQoS)
+	})
+	if retryErr != nil {
+		return fmt.Errorf("error in updating status on EgressQoS %s/%s: %v",
+			egressQoS.Namespace, egressQoS.Name, retryErr)
+	}
+	return nil
+}
+
+// getEgressQoSPodAddressSetName returns the name of the address set holding the IPs
+// of the pods selected by an egressQoS rule
+func getEgressQoSPodAddressSetName(namespace string, priority int) string {
+	return fmt.Sprintf("%s.egressqos-pods.%d", namespace, priority)
+}
+
+// ensureEgressQoSPodAddressSets creates an address set for every podSelector rule of the
+// egressQoS and keeps it up to date with the IPs of the pods matching the selector
+func (oc *Controller) ensureEgressQoSPodAddressSets(eq *egressQoS) error {
+	for _, rule := range eq.rules {
+		if rule.podSelector == nil {
+			continue
+		}
+		as, err := oc.addressSetFactory.NewAddressSet(getEgressQoSPodAddressSetName(eq.namespace, rule.priority), nil)
+		if err != nil {
+			return fmt.Errorf("cannot create pod addressSet of egressQoS in namespace %s: %v", eq.namespace, err)
+		}
+		rule.podAddressSet = as
+
+		r := rule
+		rule.podHandler = oc.watchFactory.AddFilteredPodHandler(eq.namespace, rule.podSelector,
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					oc.handleEgressQoSPodAddUpdate(r, obj)
+				},
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					oc.handleEgressQoSPodAddUpdate(r, newObj)
+				},
+				DeleteFunc: func(obj interface{}) {
+					oc.handleEgressQoSPodDelete(r, obj)
+				},
+			}, func(objs []interface{}) {
+				oc.handleEgressQoSPodAddUpdate(r, objs...)
+			})
+	}
+	return nil
+}
+
+// handleEgressQoSPodAddUpdate adds the IPs of the pods matching the podSelector of an
+// egressQoS rule to the rule's address set. Pods not wired yet are added on update.
+func (oc *Controller) handleEgressQoSPodAddUpdate(rule *egressQoSRule, objs ...interface{}) {
+	ips := make([]net.IP, 0, len(objs))
+	for _, obj := range objs {
+		pod := obj.(*kapi.Pod)
+		if pod.Spec.NodeName == "" || !util.PodWantsNetwork(pod) {
+			continue
+		}
+		podIPs, err := util.GetAllPodIPs(pod)
+		if err != nil {
+			klog.V(5).Infof("Skipping pod %s/%s for egressQoS: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		ips = append(ips, podIPs...)
+	}
+	if len(ips) == 0 {
+		return
+	}
+	if err := rule.podAddressSet.AddIPs(ips); err != nil {
+		klog.Errorf("Failed to add pod IPs %v to egressQoS address set %s: %v", ips, rule.podAddressSet.GetName(), err)
+	}
+}
+
+func (oc *Controller) handleEgressQoSPodDelete(rule *egressQoSRule, obj interface{}) {
+	pod := obj.(*kapi.Pod)
+	if pod.Spec.NodeName == "" || !util.PodWantsNetwork(pod) {
+		return
+	}
+	ips, err := util.GetAllPodIPs(pod)
+	if err != nil {
+		return
+	}
+	if err := rule.podAddressSet.DeleteIPs(ips); err != nil {
+		klog.Errorf("Failed to delete pod IPs %v from egressQoS address set %s: %v", ips, rule.podAddressSet.GetName(), err)
+	}
+}
+
+// getEgressQoSLogicalSwitches returns the node switches the QoS rules of the egressQoSes
+// are applied to, the traffic of the pods enters OVN through them
+func (oc *Controller) getEgressQoSLogicalSwitches() ([]string, error) {
+	nodes, err := oc.watchFactory.GetNodes()
+	if err != nil {
+		return nil, fmt.Errorf("unable to setup egress QoS on cluster nodes, err: %v", err)
+	}
+	logicalSwitches := []string{}
+	for _, node := range nodes {
+		// only the switches the master has created already
+		if oc.lsManager.GetSwitchSubnets(node.Name) == nil {
+			continue
+		}
+		logicalSwitches = append(logicalSwitches, node.Name)
+	}
+	sort.Strings(logicalSwitches)
+	return logicalSwitches, nil
+}
+
+// generateEgressQoSMatch returns the match of the traffic from the source address sets to dstCIDR,
+// or to any destination if dstCIDR is empty
+func generateEgressQoSMatch(ipv4Source, ipv6Source, dstCIDR string) string {
+	if dstCIDR != "" {
+		if utilnet.IsIPv6CIDRString(dstCIDR) {
+			return fmt.Sprintf("match=\"ip6.src == $%s && ip6.dst == %s\"", ipv6Source, dstCIDR)
+		}
+		return fmt.Sprintf("match=\"ip4.src == $%s && ip4.dst == %s\"", ipv4Source, dstCIDR)
+	}
+	var src string
+	switch {
+	case config.IPv4Mode && config.IPv6Mode:
+		src = fmt.Sprintf("(ip4.src == $%s || ip6.src == $%s)", ipv4Source, ipv6Source)
+	case config.IPv4Mode:
+		src = fmt.Sprintf("ip4.src == $%s", ipv4Source)
+	case config.IPv6Mode:
+		src = fmt.Sprintf("ip6.src == $%s", ipv6Source)
+	}
+	return fmt.Sprintf("match=\"%s\"", src)
+}
+
+// createEgressQoSRules creates the QoS rules of the egressQoS and applies them to the
+// logical switches. Must be called with the egressQoS locked.
+func createEgressQoSRules(eq *egressQoS, logicalSwitches []string, txn *util.NBTxn) error {
+	ipv4HashedAS, ipv6HashedAS := addressset.MakeAddressSetHashNames(eq.namespace)
+	for _, rule := range eq.rules {
+		srcIPv4HashedAS, srcIPv6HashedAS := ipv4HashedAS, ipv6HashedAS
+		if rule.podAddressSet != nil {
+			srcIPv4HashedAS, srcIPv6HashedAS = rule.podAddressSet.GetASHashNames()
+		}
+		match := generateEgressQoSMatch(srcIPv4HashedAS, srcIPv6HashedAS, rule.dstCIDR)
+		if err := createEgressQoSRule(rule.priority, match, rule.dscp, eq.namespace, logicalSwitches, txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createEgressQoSRule creates the QoS rule marking the matching traffic with dscp
+// and applies it to the logical switches
+func createEgressQoSRule(priority int, match string, dscp int, namespace string, logicalSwitches []string, txn *util.NBTxn) error {
+	// the id must be unique in the transaction which may hold the rules of several namespaces
+	id := fmt.Sprintf("@qos-%s-%d", namespace, priority)
+	args := []string{"--id=" + id, "create", "qos",
+		fmt.Sprintf("priority=%d", priority),
+		fmt.Sprintf("direction=%s", types.DirectionFromLPort), match,
+		fmt.Sprintf("action=dscp=%d", dscp),
+		fmt.Sprintf("external-ids:%s=%s", egressQoSExternalID, namespace)}
+	for _, logicalSwitch := range logicalSwitches {
+		args = append(args, "--", "add", "logical_switch", logicalSwitch, "qos_rules", id)
+	}
+	_, stderr, err := txn.AddOrCommit(args)
+	if err != nil {
+		return fmt.Errorf("failed to commit db changes for egressQoS stderr: %q, err: %+v", stderr, err)
+	}
+	return nil
+}
+
+// findEgressQoSRules returns the UUIDs of the QoS rules of the egressQoS of a namespace
+func findEgressQoSRules(namespace string) ([]string, error) {
+	stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=_uuid", "--format=table",
+		"find", "qos", fmt.Sprintf("external-ids:%s=%s", egressQoSExternalID, namespace))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the QoS rules of egressQoS in namespace %s, stderr: %q, err: %v",
+			namespace, stderr, err)
+	}
+	return strings.Fields(stdout), nil
+}
+
+// deleteEgressQoSRules removes the QoS rules of the egressQoS of a namespace from the
+// logical switches using them, the database then garbage collects them
+func deleteEgressQoSRules(namespace string, txn *util.NBTxn) error {
+	uuids, err := findEgressQoSRules(namespace)
+	if err != nil {
+		return err
+	}
+	for _, uuid := range uuids {
+		stdout, stderr, err := util.RunOVNNbctl("--data=bare", "--no-heading", "--columns=name", "--format=table",
+			"find", "logical_switch", fmt.Sprintf("qos_rules{>=}%s", uuid))
+		if err != nil {
+			return fmt.Errorf("cannot find the logical switches using QoS rule %s, stderr: %q, err: %v", uuid, stderr, err)
+		}
+		for _, logicalSwitch := range strings.Fields(stdout) {
+			_, stderr, err := txn.AddOrCommit([]string{"--if-exists", "remove", "logical_switch", logicalSwitch, "qos_rules", uuid})
+			if err != nil {
+				return fmt.Errorf("failed to commit db changes for egressQoS stderr: %q, err: %+v", stderr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addEgressQoSToNodeSwitch applies the QoS rules of all the egressQoSes to a new node switch
+func (oc *Controller) addEgressQoSToNodeSwitch(nodeName string) error {
+	egressQoSes := map[string]*egressQoS{}
+	namespaces := []string{}
+	oc.egressQoSes.Range(func(key, value interface{}) bool {
+		namespaces = append(namespaces, key.(string))
+		egressQoSes[key.(string)] = value.(*egressQoS)
+		return true
+	})
+	sort.Strings(namespaces)
+
+	txn := util.NewNBTxn()
+	for _, namespace := range namespaces {
+		if err := addEgressQoSToNodeSwitch(egressQoSes[namespace], nodeName, txn); err != nil {
+			return err
+		}
+	}
+	if _, stderr, err := txn.Commit(); err != nil {
+		return fmt.Errorf("failed to add egressQoS rules to logical switch %s, stderr: %q, err: %+v", nodeName, stderr, err)
+	}
+	return nil
+}
+
+func addEgressQoSToNodeSwitch(eq *egressQoS, nodeName string, txn *util.NBTxn) error {
+	eq.Lock()
+	defer eq.Unlock()
+	uuids, err := findEgressQoSRules(eq.namespace)
+	if err != nil {
+		return err
+	}
+	if len(uuids) == 0 {
+		// the QoS rules were garbage collected if no switch was using them
+		return createEgressQoSRules(eq, []string{nodeName}, txn)
+	}
+	for _, uuid := range uuids {
+		_, stderr, err := txn.AddOrCommit([]string{"add", "logical_switch", nodeName, "qos_rules", uuid})
+		if err != nil {
+			return fmt.Errorf("failed to add egressQoS rules to logical switch %s, stderr: %q, err: %+v", nodeName, stderr, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	egressqosapi "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	addressset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/urfave/cli/v2"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEgressQoSObject(name, namespace string, egressRules []egressqosapi.EgressQoSRule) *egressqosapi.EgressQoS {
+	return &egressqosapi.EgressQoS{
+		ObjectMeta: newObjectMeta(name, namespace),
+		Spec: egressqosapi.EgressQoSSpec{
+			Egress: egressRules,
+		},
+	}
+}
+
+var _ = ginkgo.Describe("OVN EgressQoS Operations", func() {
+	const (
+		node1Name string = "node1"
+		node2Name string = "node2"
+	)
+	var (
+		app     *cli.App
+		fakeOVN *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.OVNKubernetesFeature.EnableEgressQoS = true
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOVN = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOVN.shutdown()
+	})
+
+	nodeList := func(names ...string) *v1.NodeList {
+		nodes := &v1.NodeList{}
+		for _, name := range names {
+			nodes.Items = append(nodes.Items, v1.Node{
+				Status:     v1.NodeStatus{Phase: v1.NodeRunning},
+				ObjectMeta: newObjectMeta(name, ""),
+			})
+		}
+		return nodes
+	}
+
+	ginkgo.It("removes the QoS rules of deleted EgressQoSes on startup", func() {
+		app.Action = func(ctx *cli.Context) error {
+			nsASv4, _ := addressset.MakeAddressSetHashNames("namespace1")
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find qos",
+				Output: "EgressQoS=namespace1\nEgressQoS=stale\n\n",
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=stale",
+				Output: fakeUUID,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=name --format=table find logical_switch qos_rules{>=}" + fakeUUID,
+				Output: node1Name,
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --if-exists remove logical_switch " + node1Name + " qos_rules " + fakeUUID,
+				// the rules of the existing EgressQoS are recreated
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				"ovn-nbctl --timeout=15 --id=@qos-namespace1-1000 create qos priority=1000 direction=from-lport " +
+					fmt.Sprintf("match=\"ip4.src == $%s\" ", nsASv4) +
+					"action=dscp=46 external-ids:EgressQoS=namespace1 -- add logical_switch node1 qos_rules @qos-namespace1-1000",
+			})
+
+			egressQoS := newEgressQoSObject("default", "namespace1", []egressqosapi.EgressQoSRule{{DSCP: 46}})
+			fakeOVN.start(ctx,
+				&egressqosapi.EgressQoSList{Items: []egressqosapi.EgressQoS{*egressQoS}},
+				nodeList(node1Name))
+			fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+
+			fakeOVN.controller.WatchEgressQoS()
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("adds the QoS rules of several namespaces to a new node switch", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find qos",
+			})
+
+			namespace1 := *newNamespace("namespace1")
+			namespace2 := *newNamespace("namespace2")
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespace1, namespace2}},
+				nodeList(node1Name, node2Name))
+			fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+			fakeOVN.controller.WatchEgressQoS()
+
+			ns1ASv4, _ := addressset.MakeAddressSetHashNames(namespace1.Name)
+			ns2ASv4, _ := addressset.MakeAddressSetHashNames(namespace2.Name)
+			for _, ns := range []string{namespace1.Name, namespace2.Name} {
+				nsASv4, _ := addressset.MakeAddressSetHashNames(ns)
+				fExec.AddFakeCmdsNoOutputNoError([]string{
+					"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=" + ns,
+					fmt.Sprintf("ovn-nbctl --timeout=15 --id=@qos-%s-1000 create qos priority=1000 direction=from-lport ", ns) +
+						fmt.Sprintf("match=\"ip4.src == $%s\" ", nsASv4) +
+						fmt.Sprintf("action=dscp=46 external-ids:EgressQoS=%s -- add logical_switch node1 qos_rules @qos-%s-1000", ns, ns),
+				})
+				egressQoS := newEgressQoSObject("default", ns, []egressqosapi.EgressQoSRule{{DSCP: 46}})
+				_, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(ns).Create(context.TODO(), egressQoS, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			}
+
+			// the QoS rules of both namespaces are created with distinct ids in the same transaction
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace2",
+				"ovn-nbctl --timeout=15 --id=@qos-namespace1-1000 create qos priority=1000 direction=from-lport " +
+					fmt.Sprintf("match=\"ip4.src == $%s\" ", ns1ASv4) +
+					"action=dscp=46 external-ids:EgressQoS=namespace1 -- add logical_switch node2 qos_rules @qos-namespace1-1000" +
+					" -- --id=@qos-namespace2-1000 create qos priority=1000 direction=from-lport " +
+					fmt.Sprintf("match=\"ip4.src == $%s\" ", ns2ASv4) +
+					"action=dscp=46 external-ids:EgressQoS=namespace2 -- add logical_switch node2 qos_rules @qos-namespace2-1000",
+			})
+			err := fakeOVN.controller.addEgressQoSToNodeSwitch(node2Name)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("does not keep an invalid EgressQoS", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find qos",
+			})
+
+			namespace1 := *newNamespace("namespace1")
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespace1}},
+				nodeList(node1Name))
+			fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+			fakeOVN.controller.WatchEgressQoS()
+
+			getStatus := func() string {
+				egressQoS, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), "default", metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return egressQoS.Status.Status
+			}
+
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{{DSCP: 64}})
+			_, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Create(context.TODO(), egressQoS, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(getStatus).Should(gomega.Equal(egressQoSAddError))
+			_, loaded := fakeOVN.controller.egressQoSes.Load(namespace1.Name)
+			gomega.Expect(loaded).To(gomega.BeFalse())
+
+			// fixing the EgressQoS applies it
+			nsASv4, _ := addressset.MakeAddressSetHashNames(namespace1.Name)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				"ovn-nbctl --timeout=15 --id=@qos-namespace1-1000 create qos priority=1000 direction=from-lport " +
+					fmt.Sprintf("match=\"ip4.src == $%s\" ", nsASv4) +
+					"action=dscp=46 external-ids:EgressQoS=namespace1 -- add logical_switch node1 qos_rules @qos-namespace1-1000",
+			})
+			egressQoS, err = fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), "default", metav1.GetOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			egressQoS.Spec.Egress[0].DSCP = 46
+			_, err = fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Update(context.TODO(), egressQoS, metav1.UpdateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(getStatus).Should(gomega.Equal(egressQoSAppliedCorrectly))
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("does not keep an EgressQoS which failed to be set up", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find qos",
+			})
+
+			namespace1 := *newNamespace("namespace1")
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespace1}},
+				nodeList(node1Name))
+			fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+			fakeOVN.controller.WatchEgressQoS()
+
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd: "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				Err: fmt.Errorf("connection refused"),
+			})
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{{
+				DSCP:        46,
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "voice"}},
+			}})
+			_, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Create(context.TODO(), egressQoS, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(func() string {
+				egressQoS, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), "default", metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return egressQoS.Status.Status
+			}).Should(gomega.Equal(egressQoSAddError))
+			gomega.Expect(fExec.CalledMatchesExpected()).To(gomega.BeTrue(), fExec.ErrorDesc)
+			_, loaded := fakeOVN.controller.egressQoSes.Load(namespace1.Name)
+			gomega.Expect(loaded).To(gomega.BeFalse())
+			podASName4, _ := addressset.MakeAddressSetName(getEgressQoSPodAddressSetName(namespace1.Name, 1000))
+			fakeOVN.asf.EventuallyExpectNoAddressSet(podASName4)
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("marks the traffic of the selected pods to the destination CIDRs", func() {
+		app.Action = func(ctx *cli.Context) error {
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=external_ids --format=table find qos",
+			})
+
+			namespace1 := *newNamespace("namespace1")
+			pod := newPod(namespace1.Name, "voice", node1Name, "10.128.1.3")
+			pod.Labels = map[string]string{"app": "voice"}
+			otherPod := newPod(namespace1.Name, "web", node1Name, "10.128.1.4")
+			dstCIDR := "1.2.3.0/24"
+			egressQoS := newEgressQoSObject("default", namespace1.Name, []egressqosapi.EgressQoSRule{
+				{
+					DSCP:        46,
+					DstCIDR:     &dstCIDR,
+					PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "voice"}},
+				},
+				{
+					DSCP: 10,
+				},
+			})
+			fakeOVN.start(ctx,
+				&v1.NamespaceList{Items: []v1.Namespace{namespace1}},
+				&v1.PodList{Items: []v1.Pod{*pod, *otherPod}},
+				nodeList(node1Name, node2Name))
+			fakeOVN.controller.lsManager.AddNode(node1Name, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+
+			podASName := getEgressQoSPodAddressSetName(namespace1.Name, 1000)
+			podASv4, _ := addressset.MakeAddressSetHashNames(podASName)
+			nsASv4, _ := addressset.MakeAddressSetHashNames(namespace1.Name)
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				"ovn-nbctl --timeout=15 --id=@qos-namespace1-1000 create qos priority=1000 direction=from-lport " +
+					fmt.Sprintf("match=\"ip4.src == $%s && ip4.dst == 1.2.3.0/24\" ", podASv4) +
+					"action=dscp=46 external-ids:EgressQoS=namespace1 -- add logical_switch node1 qos_rules @qos-namespace1-1000" +
+					" -- --id=@qos-namespace1-999 create qos priority=999 direction=from-lport " +
+					fmt.Sprintf("match=\"ip4.src == $%s\" ", nsASv4) +
+					"action=dscp=10 external-ids:EgressQoS=namespace1 -- add logical_switch node1 qos_rules @qos-namespace1-999",
+			})
+			fakeOVN.controller.WatchEgressQoS()
+			_, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Create(context.TODO(), egressQoS, metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			fakeOVN.asf.EventuallyExpectAddressSetWithIPs(podASName, []string{"10.128.1.3"})
+			gomega.Eventually(func() string {
+				egressQoS, err := fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Get(context.TODO(), "default", metav1.GetOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				return egressQoS.Status.Status
+			}).Should(gomega.Equal(egressQoSAppliedCorrectly))
+
+			// a new node switch gets the QoS rules
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				Output: fakeUUID + "\n" + fakeUUIDv6 + "\n",
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 add logical_switch node2 qos_rules " + fakeUUID + " -- add logical_switch node2 qos_rules " + fakeUUIDv6,
+			})
+			err = fakeOVN.controller.addEgressQoSToNodeSwitch(node2Name)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			// deleting the EgressQoS removes the QoS rules and the pod address set
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=_uuid --format=table find qos external-ids:EgressQoS=namespace1",
+				Output: fakeUUID,
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd:    "ovn-nbctl --timeout=15 --data=bare --no-heading --columns=name --format=table find logical_switch qos_rules{>=}" + fakeUUID,
+				Output: node1Name + "\n" + node2Name + "\n",
+			})
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --if-exists remove logical_switch node1 qos_rules " + fakeUUID +
+					" -- --if-exists remove logical_switch node2 qos_rules " + fakeUUID,
+			})
+			err = fakeOVN.fakeClient.EgressQoSClient.K8sV1().EgressQoSes(namespace1.Name).Delete(context.TODO(), "default", metav1.DeleteOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			podASName4, _ := addressset.MakeAddressSetName(podASName)
+			fakeOVN.asf.EventuallyExpectNoAddressSet(podASName4)
+
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+})
+
+var _ = ginkgo.Describe("OVN EgressQoS match", func() {
+	ginkgo.BeforeEach(func() {
+		config.PrepareTestConfig()
+	})
+
+	ginkgo.It("generates the match of dual stack rules", func() {
+		config.IPv4Mode = true
+		config.IPv6Mode = true
+		gomega.Expect(generateEgressQoSMatch("as4", "as6", "")).To(gomega.Equal(
+			"match=\"(ip4.src == $as4 || ip6.src == $as6)\""))
+		gomega.Expect(generateEgressQoSMatch("as4", "as6", "2001:db8::/64")).To(gomega.Equal(
+			"match=\"ip6.src == $as6 && ip6.dst == 2001:db8::/64\""))
+	})
+})
@@ -3,6 +3,8 @@ package ovn
 import (
 	"fmt"
 	"net"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
@@ -13,6 +15,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
@@ -37,32 +40,78 @@ type gressPolicy struct {
 	// the rule in question.
 	portPolicies []*portPolicy
 
+	// namedPortPods holds, for every pod that the named ports of this rule
+	// are resolved against, the pod IPs and the port numbers the names map
+	// to. It is keyed by pod namespace/name. For ingress rules these are the
+	// pods selected by the policy, for egress rules the peer pods.
+	namedPortPods map[string]*namedPortEndpoints
+
 	ipBlock []*knet.IPBlock
 }
 
 type portPolicy struct {
 	protocol string
 	port     int32
+	// endPort, if non-zero, extends the policy to the range port..endPort
+	endPort int32
+	// name is set instead of port when the policy refers to a named
+	// container port
+	name string
+}
+
+// namedPortEndpoints are the IPs of a pod and the numbers that the named
+// ports of a gress policy resolve to in the pod's container spec, keyed by
+// portPolicy.namedPortKey()
+type namedPortEndpoints struct {
+	ips   []net.IP
+	ports map[string]int32
 }
 
+// getL4Match returns the L4 match for the port policy. For named ports the
+// returned string only identifies the policy; the actual match depends on
+// the pods the name resolves to and is built by getNamedPortL4Match.
 func (pp *portPolicy) getL4Match() (string, error) {
-	if pp.protocol == TCP {
-		if pp.port != 0 {
-			return fmt.Sprintf("tcp && tcp.dst==%d", pp.port), nil
-		}
-		return "tcp", nil
-	} else if pp.protocol == UDP {
-		if pp.port != 0 {
-			return fmt.Sprintf("udp && udp.dst==%d", pp.port), nil
-		}
-		return "udp", nil
-	} else if pp.protocol == SCTP {
-		if pp.port != 0 {
-			return fmt.Sprintf("sctp && sctp.dst==%d", pp.port), nil
+	var proto string
+	switch pp.protocol {
+	case TCP:
+		proto = "tcp"
+	case UDP:
+		proto = "udp"
+	case SCTP:
+		proto = "sctp"
+	default:
+		return "", fmt.Errorf("unknown port protocol %v", pp.protocol)
+	}
+	switch {
+	case pp.name != "":
+		return fmt.Sprintf("%s && %s.dst==%s", proto, proto, pp.name), nil
+	case pp.port != 0 && pp.endPort > pp.port:
+		return fmt.Sprintf("%s && %s.dst>=%d && %s.dst<=%d", proto, proto, pp.port, proto, pp.endPort), nil
+	case pp.port != 0:
+		return fmt.Sprintf("%s && %s.dst==%d", proto, proto, pp.port), nil
+	}
+	return proto, nil
+}
+
+// namedPortKey identifies a named port by protocol and name
+func (pp *portPolicy) namedPortKey() string {
+	return pp.protocol + "/" + pp.name
+}
+
+// resolveNamedPort looks up the named port in the container spec of the pod
+func (pp *portPolicy) resolveNamedPort(pod *v1.Pod) (int32, bool) {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = v1.ProtocolTCP
+			}
+			if port.Name == pp.name && string(protocol) == pp.protocol {
+				return port.ContainerPort, true
+			}
 		}
-		return "sctp", nil
 	}
-	return "", fmt.Errorf("unknown port protocol %v", pp.protocol)
+	return 0, false
 }
 
 func newGressPolicy(policyType knet.PolicyType, idx int, namespace, name string) *gressPolicy {
@@ -74,6 +123,7 @@ func newGressPolicy(policyType knet.PolicyType, idx int, namespace, name string)
 		peerV4AddressSets: sets.String{},
 		peerV6AddressSets: sets.String{},
 		portPolicies:      make([]*portPolicy, 0),
+		namedPortPods:     make(map[string]*namedPortEndpoints),
 	}
 }
 
@@ -158,15 +208,113 @@ func (gp *gressPolicy) deletePeerPod(pod *v1.Pod) error {
 
 // If the port is not specified, it implies all ports for that protocol
 func (gp *gressPolicy) addPortPolicy(portJSON *knet.NetworkPolicyPort) {
-	pp := &portPolicy{protocol: string(*portJSON.Protocol),
+	pp := &portPolicy{protocol: string(v1.ProtocolTCP),
 		port: 0,
 	}
+	if portJSON.Protocol != nil {
+		pp.protocol = string(*portJSON.Protocol)
+	}
 	if portJSON.Port != nil {
-		pp.port = portJSON.Port.IntVal
+		if portJSON.Port.Type == intstr.String {
+			pp.name = portJSON.Port.StrVal
+		} else {
+			pp.port = portJSON.Port.IntVal
+			if portJSON.EndPort != nil {
+				pp.endPort = *portJSON.EndPort
+			}
+		}
 	}
 	gp.portPolicies = append(gp.portPolicies, pp)
 }
 
+// hasNamedPorts returns true if any of the port policies refers to a named port
+func (gp *gressPolicy) hasNamedPorts() bool {
+	for _, pp := range gp.portPolicies {
+		if pp.name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// addNamedPortPods resolves the named ports of the gress policy against the
+// given pods and returns true if the resolved endpoints changed
+func (gp *gressPolicy) addNamedPortPods(pods ...*v1.Pod) bool {
+	changed := false
+	for _, pod := range pods {
+		podKey := pod.Namespace + "/" + pod.Name
+		ports := make(map[string]int32)
+		for _, pp := range gp.portPolicies {
+			if pp.name == "" {
+				continue
+			}
+			if port, ok := pp.resolveNamedPort(pod); ok {
+				ports[pp.namedPortKey()] = port
+			}
+		}
+		ips, err := util.GetAllPodIPs(pod)
+		if len(ports) == 0 || err != nil {
+			if _, ok := gp.namedPortPods[podKey]; ok {
+				delete(gp.namedPortPods, podKey)
+				changed = true
+			}
+			continue
+		}
+		endpoints := &namedPortEndpoints{ips: ips, ports: ports}
+		if old, ok := gp.namedPortPods[podKey]; ok && reflect.DeepEqual(old, endpoints) {
+			continue
+		}
+		gp.namedPortPods[podKey] = endpoints
+		changed = true
+	}
+	return changed
+}
+
+// deleteNamedPortPod stops resolving the named ports of the gress policy
+// against the pod and returns true if the resolved endpoints changed
+func (gp *gressPolicy) deleteNamedPortPod(pod *v1.Pod) bool {
+	podKey := pod.Namespace + "/" + pod.Name
+	if _, ok := gp.namedPortPods[podKey]; !ok {
+		return false
+	}
+	delete(gp.namedPortPods, podKey)
+	return true
+}
+
+// getNamedPortL4Match builds the L4 match of a named port policy out of the
+// IPs and port numbers of the pods it currently resolves to. Named ports are
+// always destination ports, so for ingress the local pods are matched and for
+// egress the peer pods.
+func (gp *gressPolicy) getNamedPortL4Match(pp *portPolicy) string {
+	proto := strings.ToLower(pp.protocol)
+	podKeys := make([]string, 0, len(gp.namedPortPods))
+	for podKey := range gp.namedPortPods {
+		podKeys = append(podKeys, podKey)
+	}
+	sort.Strings(podKeys)
+
+	var matches []string
+	for _, podKey := range podKeys {
+		endpoints := gp.namedPortPods[podKey]
+		port, ok := endpoints.ports[pp.namedPortKey()]
+		if !ok {
+			continue
+		}
+		for _, ip := range endpoints.ips {
+			ipVersion := "ip4"
+			if utilnet.IsIPv6(ip) {
+				ipVersion = "ip6"
+			}
+			matches = append(matches, fmt.Sprintf("(%s.dst == %s && %s.dst==%d)", ipVersion, ip, proto, port))
+		}
+	}
+	if len(matches) == 0 {
+		// the name does not resolve on any pod (yet), so nothing may match
+		return fmt.Sprintf("%s && 0", proto)
+	}
+	return fmt.Sprintf("%s && (%s)", proto, strings.Join(matches, " || "))
+}
+
 func (gp *gressPolicy) addIPBlock(ipblockJSON *knet.IPBlock) {
 	gp.ipBlock = append(gp.ipBlock, ipblockJSON)
 }
@@ -329,10 +477,16 @@ func (gp *gressPolicy) localPodSetACL(portGroupName, portGroupUUID string, aclLo
 		if err != nil {
 			continue
 		}
-		match := fmt.Sprintf("match=\"%s && %s && %s\"", l3Match, l4Match, lportMatch)
+		// For named ports l4Match only identifies the ACL, the match itself
+		// is made of the endpoints the name currently resolves to.
+		aclL4Match := l4Match
+		if port.name != "" {
+			aclL4Match = gp.getNamedPortL4Match(port)
+		}
+		match := fmt.Sprintf("match=\"%s && %s && %s\"", l3Match, aclL4Match, lportMatch)
 		if len(gp.ipBlock) > 0 {
 			// Add ACL allow rule for IPBlock CIDR
-			cidrMatches = gp.getMatchFromIPBlock(lportMatch, l4Match)
+			cidrMatches = gp.getMatchFromIPBlock(lportMatch, aclL4Match)
 			for i, cidrMatch := range cidrMatches {
 				if err := gp.addOrModifyACLAllow(cidrMatch, l4Match, portGroupUUID, i+1, aclLogging); err != nil {
 					klog.Warningf(err.Error())
@@ -1,9 +1,13 @@
 package ovn
 
 import (
+	"testing"
+
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	knet "k8s.io/api/networking/v1"
-	"testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestGetMatchFromIPBlock(t *testing.T) {
@@ -117,3 +121,92 @@ func TestGetMatchFromIPBlock(t *testing.T) {
 		assert.Equal(t, tc.expected, output)
 	}
 }
+
+func TestGetL4Match(t *testing.T) {
+	tcp := v1.ProtocolTCP
+	udp := v1.ProtocolUDP
+	port := intstr.FromInt(80)
+	namedPort := intstr.FromString("http")
+	endPort := int32(90)
+	testcases := []struct {
+		desc     string
+		port     knet.NetworkPolicyPort
+		expected string
+	}{
+		{
+			desc:     "protocol only",
+			port:     knet.NetworkPolicyPort{Protocol: &udp},
+			expected: "udp",
+		},
+		{
+			desc:     "single port",
+			port:     knet.NetworkPolicyPort{Protocol: &tcp, Port: &port},
+			expected: "tcp && tcp.dst==80",
+		},
+		{
+			desc:     "port range",
+			port:     knet.NetworkPolicyPort{Protocol: &tcp, Port: &port, EndPort: &endPort},
+			expected: "tcp && tcp.dst>=80 && tcp.dst<=90",
+		},
+		{
+			desc:     "named port",
+			port:     knet.NetworkPolicyPort{Protocol: &tcp, Port: &namedPort},
+			expected: "tcp && tcp.dst==http",
+		},
+		{
+			desc:     "default protocol",
+			port:     knet.NetworkPolicyPort{Port: &port},
+			expected: "tcp && tcp.dst==80",
+		},
+	}
+
+	for _, tc := range testcases {
+		gressPolicy := newGressPolicy(knet.PolicyTypeIngress, 5, "testing", "test")
+		gressPolicy.addPortPolicy(&tc.port)
+		output, err := gressPolicy.portPolicies[0].getL4Match()
+		assert.NoError(t, err, tc.desc)
+		assert.Equal(t, tc.expected, output, tc.desc)
+	}
+}
+
+func TestGetNamedPortL4Match(t *testing.T) {
+	newPod := func(name, ip string, ports ...v1.ContainerPort) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "testing"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "c", Ports: ports}},
+			},
+			Status: v1.PodStatus{PodIPs: []v1.PodIP{{IP: ip}}},
+		}
+	}
+
+	tcp := v1.ProtocolTCP
+	namedPort := intstr.FromString("http")
+	gressPolicy := newGressPolicy(knet.PolicyTypeIngress, 5, "testing", "test")
+	gressPolicy.addPortPolicy(&knet.NetworkPolicyPort{Protocol: &tcp, Port: &namedPort})
+	pp := gressPolicy.portPolicies[0]
+	assert.True(t, gressPolicy.hasNamedPorts())
+
+	// nothing resolves yet
+	assert.Equal(t, "tcp && 0", gressPolicy.getNamedPortL4Match(pp))
+
+	podA := newPod("a", "10.128.1.3", v1.ContainerPort{Name: "http", ContainerPort: 8080})
+	podB := newPod("b", "fd00:10:244::3", v1.ContainerPort{Name: "http", ContainerPort: 80, Protocol: v1.ProtocolTCP})
+	podC := newPod("c", "10.128.1.5", v1.ContainerPort{Name: "http", ContainerPort: 80, Protocol: v1.ProtocolUDP})
+	assert.True(t, gressPolicy.addNamedPortPods(podA, podB, podC))
+	assert.Equal(t, "tcp && ((ip4.dst == 10.128.1.3 && tcp.dst==8080) || (ip6.dst == fd00:10:244::3 && tcp.dst==80))",
+		gressPolicy.getNamedPortL4Match(pp))
+
+	// re-adding an unchanged pod is a no-op
+	assert.False(t, gressPolicy.addNamedPortPods(podA))
+
+	// a changed container spec is tracked
+	podA = newPod("a", "10.128.1.3", v1.ContainerPort{Name: "http", ContainerPort: 8081})
+	assert.True(t, gressPolicy.addNamedPortPods(podA))
+	assert.Equal(t, "tcp && ((ip4.dst == 10.128.1.3 && tcp.dst==8081) || (ip6.dst == fd00:10:244::3 && tcp.dst==80))",
+		gressPolicy.getNamedPortL4Match(pp))
+
+	assert.True(t, gressPolicy.deleteNamedPortPod(podB))
+	assert.False(t, gressPolicy.deleteNamedPortPod(podB))
+	assert.Equal(t, "tcp && ((ip4.dst == 10.128.1.3 && tcp.dst==8081))", gressPolicy.getNamedPortL4Match(pp))
+}
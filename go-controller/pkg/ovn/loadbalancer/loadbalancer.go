@@ -7,6 +7,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	globalconfig "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	"k8s.io/klog/v2"
@@ -93,12 +94,14 @@ func ensureLB(txn *util.NBTxn, lbCache *LBCache, lb *LB, existing *CachedLB) (st
 			"create", "load_balancer",
 		}
 		cmds = append(cmds, lbToColumns(lb)...)
+		cmds = append(cmds, lbHealthCheckArgs(lb, "hc", nil, false)...)
 		// note: load-balancer creation is not in the transaction
 		stdout, _, err := util.RunOVNNbctl(cmds...)
 		if err != nil {
 			return "", fmt.Errorf("failed to create load_balancer %s: %w", lb.Name, err)
 		}
-		uuid = stdout
+		// the uuids of the created health checks, if any, follow the load balancer's
+		uuid = strings.SplitN(stdout, "\n", 2)[0]
 		lb.UUID = uuid
 
 		// Since this short-cut the transation, immediately add it to the cache.
@@ -108,6 +111,17 @@ func ensureLB(txn *util.NBTxn, lbCache *LBCache, lb *LB, existing *CachedLB) (st
 			"set", "load_balancer", existing.UUID,
 		}
 		cmds = append(cmds, lbToColumns(lb)...)
+		var existingHealthChecks map[string]string
+		if len(lb.HealthChecks) > 0 {
+			var err error
+			existingHealthChecks, err = findLBHealthChecks(lb.Name)
+			if err != nil {
+				return "", fmt.Errorf("failed to update load_balancer %s: %w", lb.Name, err)
+			}
+		}
+		// the health check ids must be unique within the transaction
+		cmds = append(cmds, lbHealthCheckArgs(lb, "hc_"+strings.ReplaceAll(existing.UUID, "-", "_"),
+			existingHealthChecks, globalconfig.OVNKubernetesFeature.EnableLBHealthCheck)...)
 		_, _, err := txn.AddOrCommit(cmds)
 		if err != nil {
 			return "", fmt.Errorf("failed to update load_balancer %s: %w", lb.Name, err)
@@ -211,6 +225,58 @@ func lbToColumns(lb *LB) []string {
 	return out
 }
 
+// lbHealthCheckArgs returns the health_check and ip_port_mappings columns of the
+// load balancer, followed by the commands updating the health checks they refer to.
+// The existing health checks, by vip, are updated in place and the missing ones are
+// created, named after idPrefix. If clear is set, the columns are emptied when the
+// load balancer has no health checks; the health checks which are no longer referenced
+// are garbage collected by the database.
+func lbHealthCheckArgs(lb *LB, idPrefix string, existing map[string]string, clear bool) []string {
+	if len(lb.HealthChecks) == 0 {
+		if clear {
+			return []string{"health_check=[]", "ip_port_mappings={}"}
+		}
+		return nil
+	}
+
+	ids := make([]string, 0, len(lb.HealthChecks))
+	cmds := []string{}
+	for i, hc := range lb.HealthChecks {
+		options := []string{
+			fmt.Sprintf("options:interval=%d", hc.Interval),
+			fmt.Sprintf("options:timeout=%d", hc.Timeout),
+			fmt.Sprintf("options:success_count=%d", hc.SuccessCount),
+			fmt.Sprintf("options:failure_count=%d", hc.FailureCount),
+		}
+		if uuid, ok := existing[hc.VIP.String()]; ok {
+			ids = append(ids, uuid)
+			cmds = append(cmds, "--", "set", "load_balancer_health_check", uuid)
+			cmds = append(cmds, options...)
+			continue
+		}
+		id := fmt.Sprintf("@%s_%d", idPrefix, i)
+		ids = append(ids, id)
+		cmds = append(cmds, "--", "--id="+id, "create", "load_balancer_health_check",
+			fmt.Sprintf(`vip="%s"`, hc.VIP.String()),
+			fmt.Sprintf(`external_ids:%s="%s"`, lbHealthCheckOwnerKey, lb.Name),
+		)
+		cmds = append(cmds, options...)
+	}
+
+	mappings := make([]string, 0, len(lb.IPPortMappings))
+	for ip, mapping := range lb.IPPortMappings {
+		mappings = append(mappings, fmt.Sprintf(`"%s"="%s"`, ip, mapping))
+	}
+	// for unit testing - stable order
+	sort.Strings(mappings)
+
+	out := []string{
+		fmt.Sprintf("health_check=[%s]", strings.Join(ids, ",")),
+		fmt.Sprintf("ip_port_mappings={%s}", strings.Join(mappings, ",")),
+	}
+	return append(out, cmds...)
+}
+
 // Returns a nbctl column update string for this rule
 func (r *LBRule) nbctlString() string {
 	tgts := make([]string, 0, len(r.Targets))
@@ -299,3 +365,24 @@ func DeleteLoadBalancerVIPs(toRemove []DeleteVIPEntry) error {
 
 	return nil
 }
+
+// the external id of the health checks naming the load balancer they belong to
+const lbHealthCheckOwnerKey = "load_balancer"
+
+// findLBHealthChecks returns the uuids of the health checks of a load balancer, by vip
+func findLBHealthChecks(lbName string) (map[string]string, error) {
+	rows, err := util.RunOVNNbctlCSV([]string{"--data=bare", "--columns=_uuid,vip", "find", "load_balancer_health_check",
+		fmt.Sprintf(`external_ids:%s="%s"`, lbHealthCheckOwnerKey, lbName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the health checks of load_balancer %s: %w", lbName, err)
+	}
+
+	out := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if len(row) != 2 {
+			return nil, fmt.Errorf("invalid row returned when listing the health checks of load_balancer %s: %#v", lbName, row)
+		}
+		out[row[1]] = row[0]
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLBHealthCheckArgs(t *testing.T) {
+	lb := &LB{
+		Name: "Service_testns/foo_TCP_cluster",
+		HealthChecks: []LBHealthCheck{
+			{VIP: Addr{IP: "192.168.1.1", Port: 80}, Interval: 5, Timeout: 2, SuccessCount: 1, FailureCount: 4},
+		},
+		IPPortMappings: map[string]string{
+			"10.128.2.5": "testns_pod-b:10.128.2.254",
+			"10.128.1.5": "testns_pod-a:10.128.1.254",
+		},
+	}
+
+	assert.Equal(t, []string{
+		"health_check=[@hc_0]",
+		`ip_port_mappings={"10.128.1.5"="testns_pod-a:10.128.1.254","10.128.2.5"="testns_pod-b:10.128.2.254"}`,
+		"--", "--id=@hc_0", "create", "load_balancer_health_check", `vip="192.168.1.1:80"`,
+		`external_ids:load_balancer="Service_testns/foo_TCP_cluster"`,
+		"options:interval=5", "options:timeout=2", "options:success_count=1", "options:failure_count=4",
+	}, lbHealthCheckArgs(lb, "hc", nil, false))
+
+	// the existing health checks are reused, the missing ones created
+	lb.HealthChecks = append(lb.HealthChecks,
+		LBHealthCheck{VIP: Addr{IP: "192.168.1.1", Port: 443}, Interval: 10, Timeout: 1, SuccessCount: 1, FailureCount: 3})
+	existing := map[string]string{
+		"192.168.1.1:80":   "5d9f3a66-1c6e-4a5d-9b0e-6b0b7c3f0e01",
+		"192.168.1.2:8080": "8a3e1f27-93c4-4c1f-a0d7-2e4c9f1b7a02",
+	}
+	assert.Equal(t, []string{
+		"health_check=[5d9f3a66-1c6e-4a5d-9b0e-6b0b7c3f0e01,@hc_1]",
+		`ip_port_mappings={"10.128.1.5"="testns_pod-a:10.128.1.254","10.128.2.5"="testns_pod-b:10.128.2.254"}`,
+		"--", "set", "load_balancer_health_check", "5d9f3a66-1c6e-4a5d-9b0e-6b0b7c3f0e01",
+		"options:interval=5", "options:timeout=2", "options:success_count=1", "options:failure_count=4",
+		"--", "--id=@hc_1", "create", "load_balancer_health_check", `vip="192.168.1.1:443"`,
+		`external_ids:load_balancer="Service_testns/foo_TCP_cluster"`,
+		"options:interval=10", "options:timeout=1", "options:success_count=1", "options:failure_count=3",
+	}, lbHealthCheckArgs(lb, "hc", existing, false))
+
+	noHealthCheck := &LB{Name: "Service_testns/bar_TCP_cluster"}
+	assert.Empty(t, lbHealthCheckArgs(noHealthCheck, "hc", nil, false))
+	assert.Equal(t, []string{"health_check=[]", "ip_port_mappings={}"}, lbHealthCheckArgs(noHealthCheck, "hc", nil, true))
+}
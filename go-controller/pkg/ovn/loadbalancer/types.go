@@ -12,6 +12,12 @@ type LB struct {
 
 	Rules []LBRule
 
+	// the OVN health checks of the vips, only IPv4 vips can be health checked
+	HealthChecks []LBHealthCheck
+	// maps the IP of each backend probed by the health checks to
+	// "<logical port>:<probe source IP>"
+	IPPortMappings map[string]string
+
 	// the names of logical switches and routers that this LB should be attached to
 	Switches []string
 	Routers  []string
@@ -28,6 +34,20 @@ type LBOpts struct {
 	SkipSNAT bool
 }
 
+// LBHealthCheck configures OVN to probe the backends of a vip, removing
+// those which fail to answer from the load balancer.
+type LBHealthCheck struct {
+	VIP Addr
+	// seconds between two probes of a backend
+	Interval int32
+	// seconds to wait for the answer to a probe
+	Timeout int32
+	// number of successful probes after which a backend is considered online
+	SuccessCount int32
+	// number of failed probes after which a backend is considered offline
+	FailureCount int32
+}
+
 type Addr struct {
 	IP   string
 	Port int32
@@ -12,6 +12,7 @@ import (
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
 )
 
 // logicalSwitchInfo contains information corresponding to the node. It holds the
@@ -21,6 +22,9 @@ type logicalSwitchInfo struct {
 	hostSubnets  []*net.IPNet
 	ipams        []ipam.Interface
 	noHostSubnet bool
+	// noLBHealthCheckIP is set when the load balancer health check IP of the
+	// switch could not be reserved because it is already in use
+	noLBHealthCheckIP bool
 }
 
 type ipamFactoryFunc func(*net.IPNet) (ipam.Interface, error)
@@ -79,6 +83,25 @@ func reserveIPs(subnet *net.IPNet, ipam ipam.Interface) error {
 	return nil
 }
 
+// reserveLBHealthCheckIP reserves the last address of an IPv4 subnet as the source
+// of the load balancer health checks. It returns false, without failing, when the
+// address is already in use: a pod may hold it if the health checks were enabled
+// after the pod was created.
+func reserveLBHealthCheckIP(subnet *net.IPNet, subnetRange ipam.Interface) (bool, error) {
+	lbHealthCheckIfAddr := util.GetNodeLBHealthCheckIfAddr(subnet)
+	err := subnetRange.Allocate(lbHealthCheckIfAddr.IP)
+	if err == ipam.ErrAllocated {
+		klog.Warningf("Subnet's load balancer health check IP %s is already in use, "+
+			"load balancer health checks are disabled on the subnet", lbHealthCheckIfAddr.IP)
+		return false, nil
+	}
+	if err != nil {
+		klog.Errorf("Unable to allocate subnet's load balancer health check IP: %s", lbHealthCheckIfAddr.IP)
+		return false, err
+	}
+	return true, nil
+}
+
 // Initializes a new logical switch manager
 func NewLogicalSwitchManager() *LogicalSwitchManager {
 	return &LogicalSwitchManager{
@@ -98,18 +121,27 @@ func (manager *LogicalSwitchManager) AddNode(nodeName string, hostSubnets []*net
 			util.JoinIPNets(lsi.hostSubnets, ","), util.JoinIPNets(hostSubnets, ","))
 	}
 	var ipams []ipam.Interface
+	noLBHealthCheckIP := false
 	for _, subnet := range hostSubnets {
 		ipam, err := manager.ipamFunc(subnet)
 		if err != nil {
 			klog.Errorf("IPAM for subnet %s was not initialized for node %q", subnet, nodeName)
 			return err
 		}
+		if config.OVNKubernetesFeature.EnableLBHealthCheck && !utilnet.IsIPv6CIDR(subnet) {
+			reserved, err := reserveLBHealthCheckIP(subnet, ipam)
+			if err != nil {
+				return err
+			}
+			noLBHealthCheckIP = !reserved
+		}
 		ipams = append(ipams, ipam)
 	}
 	manager.cache[nodeName] = logicalSwitchInfo{
-		hostSubnets:  hostSubnets,
-		ipams:        ipams,
-		noHostSubnet: len(hostSubnets) == 0,
+		hostSubnets:       hostSubnets,
+		ipams:             ipams,
+		noHostSubnet:      len(hostSubnets) == 0,
+		noLBHealthCheckIP: noLBHealthCheckIP,
 	}
 
 	return nil
@@ -139,6 +171,15 @@ func (manager *LogicalSwitchManager) IsNonHostSubnetSwitch(nodeName string) bool
 	return ok && lsi.noHostSubnet
 }
 
+// HasLBHealthCheckIP returns false if the load balancer health check IP of the
+// switch could not be reserved, the backends on the switch must not be probed then
+func (manager *LogicalSwitchManager) HasLBHealthCheckIP(nodeName string) bool {
+	manager.RLock()
+	defer manager.RUnlock()
+	lsi, ok := manager.cache[nodeName]
+	return ok && !lsi.noLBHealthCheckIP
+}
+
 // Given a switch name, get all its host-subnets
 func (manager *LogicalSwitchManager) GetSwitchSubnets(nodeName string) []*net.IPNet {
 	manager.RLock()
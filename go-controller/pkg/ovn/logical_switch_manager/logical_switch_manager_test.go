@@ -1,10 +1,13 @@
 package logicalswitchmanager
 
 import (
+	"net"
+
 	"github.com/urfave/cli/v2"
 	"k8s.io/klog/v2"
 
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ipam "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
 	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
 
 	"github.com/onsi/ginkgo"
@@ -275,6 +278,35 @@ var _ = ginkgo.Describe("OVN Logical Switch Manager operations", func() {
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("does not fail when the load balancer health check IP is already in use", func() {
+			app.Action = func(ctx *cli.Context) error {
+				_, err := config.InitConfig(ctx, fexec, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				config.OVNKubernetesFeature.EnableLBHealthCheck = true
+
+				err = lsManager.AddNode("testNode1", ovntest.MustParseIPNets("10.1.1.0/24"))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lsManager.HasLBHealthCheckIP("testNode1")).To(gomega.BeTrue())
+				err = lsManager.AllocateIPs("testNode1", ovntest.MustParseIPNets("10.1.1.254/24"))
+				gomega.Expect(err).To(gomega.HaveOccurred())
+
+				// a pod holds the last address of the subnet of the second node
+				ipamFunc := lsManager.ipamFunc
+				lsManager.ipamFunc = func(subnet *net.IPNet) (ipam.Interface, error) {
+					subnetRange, err := ipamFunc(subnet)
+					if err != nil {
+						return nil, err
+					}
+					return subnetRange, subnetRange.Allocate(ovntest.MustParseIP("10.1.2.254"))
+				}
+				err = lsManager.AddNode("testNode2", ovntest.MustParseIPNets("10.1.2.0/24"))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lsManager.HasLBHealthCheckIP("testNode2")).To(gomega.BeFalse())
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 
 })
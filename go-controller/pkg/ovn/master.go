@@ -728,14 +728,18 @@ func (oc *Controller) ensureNodeLogicalNetwork(node *kapi.Node, hostSubnets []*n
 			)
 		} else {
 			v4Gateway = gwIfAddr.IP
-			excludeIPs := mgmtIfAddr.IP.String()
+			excludeIPs := []string{mgmtIfAddr.IP.String()}
 			if config.HybridOverlay.Enabled {
 				hybridOverlayIfAddr := util.GetNodeHybridOverlayIfAddr(hostSubnet)
-				excludeIPs += ".." + hybridOverlayIfAddr.IP.String()
+				excludeIPs[0] += ".." + hybridOverlayIfAddr.IP.String()
+			}
+			if config.OVNKubernetesFeature.EnableLBHealthCheck {
+				lbHealthCheckIfAddr := util.GetNodeLBHealthCheckIfAddr(hostSubnet)
+				excludeIPs = append(excludeIPs, lbHealthCheckIfAddr.IP.String())
 			}
 			lsArgs = append(lsArgs,
 				"other-config:subnet="+hostSubnet.String(),
-				"other-config:exclude_ips="+excludeIPs,
+				"other-config:exclude_ips=\""+strings.Join(excludeIPs, " ")+"\"",
 			)
 		}
 	}
@@ -840,7 +844,14 @@ func (oc *Controller) ensureNodeLogicalNetwork(node *kapi.Node, hostSubnets []*n
 	}
 
 	// Add the node to the logical switch cache
-	return oc.lsManager.AddNode(nodeName, hostSubnets)
+	if err = oc.lsManager.AddNode(nodeName, hostSubnets); err != nil {
+		return err
+	}
+
+	if config.OVNKubernetesFeature.EnableEgressQoS {
+		return oc.addEgressQoSToNodeSwitch(nodeName)
+	}
+	return nil
 }
 
 func (oc *Controller) addNodeAnnotations(node *kapi.Node, hostSubnets []*net.IPNet) error {
@@ -1123,6 +1134,10 @@ func (oc *Controller) deleteNode(nodeName string, hostSubnets []*net.IPNet, node
 		klog.Errorf("Error deleting node %s logical network: %v", nodeName, err)
 	}
 
+	if config.OVNKubernetesFeature.EnableMultiNetwork {
+		oc.deleteSecondaryNetworksNode(nodeName)
+	}
+
 	if err := gatewayCleanup(nodeName); err != nil {
 		klog.Errorf("Failed to clean up node %s gateway: (%v)", nodeName, err)
 	}
@@ -29,7 +29,9 @@ import (
 	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
-	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	adminnetworkpolicy "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	egressqos "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
 
 	utilnet "k8s.io/utils/net"
 
@@ -52,9 +54,8 @@ import (
 )
 
 const (
-	clusterPortGroupName             string        = "clusterPortGroup"
-	clusterRtrPortGroupName          string        = "clusterRtrPortGroup"
-	egressFirewallDNSDefaultDuration time.Duration = 30 * time.Minute
+	clusterPortGroupName    string = "clusterPortGroup"
+	clusterRtrPortGroupName string = "clusterRtrPortGroup"
 )
 
 // ACL logging severity levels
@@ -114,7 +115,14 @@ type Controller struct {
 	kube                  kube.Interface
 	watchFactory          *factory.WatchFactory
 	egressFirewallHandler *factory.Handler
-	stopChan              <-chan struct{}
+	// A handler for the cluster wide default egress firewall
+	clusterEgressFirewallHandler *factory.Handler
+	// A handler for the nodes selected by egress firewall nodeSelector rules
+	egressFirewallNodeHandler *factory.Handler
+	egressQoSHandler          *factory.Handler
+	adminNetworkPolicyHandler *factory.Handler
+	nadHandler                *factory.Handler
+	stopChan                  <-chan struct{}
 
 	// FIXME DUAL-STACK -  Make IP Allocators more dual-stack friendly
 	masterSubnetAllocator     *subnetallocator.SubnetAllocator
@@ -145,6 +153,22 @@ type Controller struct {
 	// egressFirewalls is a map of namespaces and the egressFirewall attached to it
 	egressFirewalls sync.Map
 
+	// egressQoSes is a map of namespaces and the egressQoS attached to it
+	egressQoSes sync.Map
+
+	// adminNetworkPolicies is a map of the names of the admin network policies and their state
+	adminNetworkPolicies sync.Map
+	// adminNetworkPolicyGenerations maps the names of the admin network policies found in
+	// the database on startup to the generation of their port group and address sets
+	adminNetworkPolicyGenerations sync.Map
+
+	// secondaryNetworks is a map of the names of the secondary OVN networks and
+	// their state, nadNetworks maps each NetworkAttachmentDefinition of these
+	// networks to its network. Both are protected by secondaryNetworksMutex.
+	secondaryNetworks      map[string]*secondaryNetwork
+	nadNetworks            map[string]*secondaryNetwork
+	secondaryNetworksMutex sync.Mutex
+
 	// An address set factory that creates address sets
 	addressSetFactory addressset.AddressSetFactory
 
@@ -209,6 +233,11 @@ type Controller struct {
 
 	// channel to indicate we need to retry pods immediately
 	retryPodsChan chan struct{}
+
+	// Addresses of the deleted StatefulSet pods kept for their replacement,
+	// keyed by the logical port name of the pods
+	stickyPodAddresses     map[string]*stickyPodAddresses
+	stickyPodAddressesLock sync.Mutex
 }
 
 type retryEntry struct {
@@ -251,19 +280,21 @@ func NewOvnController(ovnClient *util.OVNClientset, wf *factory.WatchFactory, st
 	if addressSetFactory == nil {
 		addressSetFactory = addressset.NewOvnAddressSetFactory(libovsdbOvnNBClient)
 	}
+	lsManager := lsm.NewLogicalSwitchManager()
 	return &Controller{
 		client: ovnClient.KubeClient,
 		kube: &kube.Kube{
 			KClient:              ovnClient.KubeClient,
 			EIPClient:            ovnClient.EgressIPClient,
 			EgressFirewallClient: ovnClient.EgressFirewallClient,
+			EgressQoSClient:      ovnClient.EgressQoSClient,
 		},
 		watchFactory:              wf,
 		stopChan:                  stopChan,
 		masterSubnetAllocator:     subnetallocator.NewSubnetAllocator(),
 		nodeLocalNatIPv4Allocator: &ipallocator.Range{},
 		nodeLocalNatIPv6Allocator: &ipallocator.Range{},
-		lsManager:                 lsm.NewLogicalSwitchManager(),
+		lsManager:                 lsManager,
 		logicalPortCache:          newPortCache(stopChan),
 		namespaces:                make(map[string]*namespaceInfo),
 		namespacesMutex:           sync.Mutex{},
@@ -271,6 +302,8 @@ func NewOvnController(ovnClient *util.OVNClientset, wf *factory.WatchFactory, st
 		lspIngressDenyCache:       make(map[string]int),
 		lspEgressDenyCache:        make(map[string]int),
 		lspMutex:                  &sync.Mutex{},
+		secondaryNetworks:         make(map[string]*secondaryNetwork),
+		nadNetworks:               make(map[string]*secondaryNetwork),
 		eIPC: egressIPController{
 			assignmentRetryMutex:  &sync.Mutex{},
 			assignmentRetry:       make(map[string]bool),
@@ -288,12 +321,13 @@ func NewOvnController(ovnClient *util.OVNClientset, wf *factory.WatchFactory, st
 		joinSwIPManager:          nil,
 		retryPods:                make(map[types.UID]*retryEntry),
 		retryPodsChan:            make(chan struct{}, 1),
+		stickyPodAddresses:       make(map[string]*stickyPodAddresses),
 		recorder:                 recorder,
 		ovnNBClient:              ovnNBClient,
 		ovnSBClient:              ovnSBClient,
 		nbClient:                 libovsdbOvnNBClient,
 		sbClient:                 libovsdbOvnSBClient,
-		svcController:            newServiceController(ovnClient.KubeClient, libovsdbOvnNBClient, stopChan),
+		svcController:            newServiceController(ovnClient.KubeClient, libovsdbOvnNBClient, lsManager.HasLBHealthCheckIP, stopChan),
 	}
 }
 
@@ -326,6 +360,11 @@ func (oc *Controller) Run(wg *sync.WaitGroup, nodeName string) error {
 		return err
 	}
 
+	// Secondary networks must be set up before the pods attached to them
+	if config.OVNKubernetesFeature.EnableMultiNetwork {
+		oc.nadHandler = oc.WatchNetworkAttachmentDefinitions()
+	}
+
 	oc.WatchPods()
 
 	// WatchNetworkPolicy depends on WatchPods and WatchNamespaces
@@ -338,13 +377,25 @@ func (oc *Controller) Run(wg *sync.WaitGroup, nodeName string) error {
 
 	if config.OVNKubernetesFeature.EnableEgressFirewall {
 		var err error
-		oc.egressFirewallDNS, err = NewEgressDNS(oc.addressSetFactory, oc.stopChan)
+		oc.egressFirewallDNS, err = NewEgressDNS(oc.addressSetFactory, oc.updateEgressFirewallStatusForNamespaces, oc.stopChan)
 		if err != nil {
 			return err
 		}
-		oc.egressFirewallDNS.Run(egressFirewallDNSDefaultDuration)
+		oc.egressFirewallDNS.Run(config.OVNKubernetesFeature.EgressFirewallDNSWorkers)
 		oc.egressFirewallHandler = oc.WatchEgressFirewall()
+		oc.clusterEgressFirewallHandler = oc.WatchClusterEgressFirewall()
+		oc.egressFirewallNodeHandler = oc.WatchEgressFirewallNodes()
+
+	}
+
+	if config.OVNKubernetesFeature.EnableEgressQoS {
+		oc.egressQoSHandler = oc.WatchEgressQoS()
+	}
 
+	// WatchAdminNetworkPolicy depends on WatchPods and WatchNamespaces, and must
+	// start after the network policies sync removed the stale address sets
+	if config.OVNKubernetesFeature.EnableAdminNetworkPolicy {
+		oc.adminNetworkPolicyHandler = oc.WatchAdminNetworkPolicy()
 	}
 
 	klog.Infof("Completing all the Watchers took %v", time.Since(start))
@@ -437,8 +488,12 @@ func (oc *Controller) recordPodEvent(addErr error, pod *kapi.Pod) {
 		klog.Errorf("Couldn't get a reference to pod %s/%s to post an event: '%v'",
 			pod.Namespace, pod.Name, err)
 	} else {
+		reason := "ErrorAddingLogicalPort"
+		if reqErr, ok := addErr.(*podAddressRequestError); ok {
+			reason = reqErr.reason
+		}
 		klog.V(5).Infof("Posting a %s event for Pod %s/%s", kapi.EventTypeWarning, pod.Namespace, pod.Name)
-		oc.recorder.Eventf(podRef, kapi.EventTypeWarning, "ErrorAddingLogicalPort", addErr.Error())
+		oc.recorder.Eventf(podRef, kapi.EventTypeWarning, reason, addErr.Error())
 	}
 }
 
@@ -705,6 +760,8 @@ func (oc *Controller) WatchEgressFirewall() *factory.Handler {
 					egressFirewall.Status.Status = egressFirewallAppliedCorrectly
 				}
 			}
+			egressFirewall.Status.Rules = oc.getEgressFirewallRuleStatuses(egressFirewall,
+				egressFirewall.Status.Status == egressFirewallAppliedCorrectly)
 
 			err := oc.updateEgressFirewallWithRetry(egressFirewall)
 			if err != nil {
@@ -715,21 +772,14 @@ func (oc *Controller) WatchEgressFirewall() *factory.Handler {
 			newEgressFirewall := newer.(*egressfirewall.EgressFirewall).DeepCopy()
 			oldEgressFirewall := old.(*egressfirewall.EgressFirewall)
 			if !reflect.DeepEqual(oldEgressFirewall.Spec, newEgressFirewall.Spec) {
-				txn := util.NewNBTxn()
-				errList := oc.updateEgressFirewall(oldEgressFirewall, newEgressFirewall, txn)
-				if errList != nil {
+				if err := oc.updateEgressFirewall(oldEgressFirewall, newEgressFirewall); err != nil {
 					newEgressFirewall.Status.Status = egressFirewallUpdateError
-					klog.Error(errList)
+					klog.Error(err)
 				} else {
-					_, stderr, err := txn.Commit()
-					if err != nil {
-						klog.Errorf("Failed to commit db changes for egressFirewall in namespace %s stderr: %q, err: %+v", newEgressFirewall.Namespace, stderr, err)
-						newEgressFirewall.Status.Status = egressFirewallUpdateError
-
-					} else {
-						newEgressFirewall.Status.Status = egressFirewallAppliedCorrectly
-					}
+					newEgressFirewall.Status.Status = egressFirewallAppliedCorrectly
 				}
+				newEgressFirewall.Status.Rules = oc.getEgressFirewallRuleStatuses(newEgressFirewall,
+					newEgressFirewall.Status.Status == egressFirewallAppliedCorrectly)
 				err := oc.updateEgressFirewallWithRetry(newEgressFirewall)
 				if err != nil {
 					klog.Error(err)
@@ -738,18 +788,184 @@ func (oc *Controller) WatchEgressFirewall() *factory.Handler {
 		},
 		DeleteFunc: func(obj interface{}) {
 			egressFirewall := obj.(*egressfirewall.EgressFirewall)
+			if err := oc.deleteEgressFirewall(egressFirewall); err != nil {
+				klog.Error(err)
+			}
+		},
+	}, oc.syncEgressFirewall)
+}
+
+// WatchClusterEgressFirewall starts the watching of clusteregressfirewall resource and calls
+// back the appropriate handler logic
+func (oc *Controller) WatchClusterEgressFirewall() *factory.Handler {
+	return oc.watchFactory.AddClusterEgressFirewallHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			clusterEgressFirewall := obj.(*egressfirewall.ClusterEgressFirewall).DeepCopy()
 			txn := util.NewNBTxn()
-			deleteErrors := oc.deleteEgressFirewall(egressFirewall, txn)
-			if deleteErrors != nil {
-				klog.Error(deleteErrors)
+			if err := oc.addClusterEgressFirewall(clusterEgressFirewall, txn); err != nil {
+				klog.Error(err)
+				clusterEgressFirewall.Status.Status = egressFirewallAddError
+			} else if _, stderr, err := txn.Commit(); err != nil {
+				klog.Errorf("Failed to commit db changes for clusterEgressFirewall %s stderr: %q, err: %+v", clusterEgressFirewall.Name, stderr, err)
+				clusterEgressFirewall.Status.Status = egressFirewallAddError
+			} else {
+				clusterEgressFirewall.Status.Status = egressFirewallAppliedCorrectly
+			}
+			clusterEgressFirewall.Status.Rules = oc.getClusterEgressFirewallRuleStatuses(clusterEgressFirewall,
+				clusterEgressFirewall.Status.Status == egressFirewallAppliedCorrectly)
+			if err := oc.updateClusterEgressFirewallWithRetry(clusterEgressFirewall); err != nil {
+				klog.Error(err)
+			}
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			newClusterEgressFirewall := newer.(*egressfirewall.ClusterEgressFirewall).DeepCopy()
+			oldClusterEgressFirewall := old.(*egressfirewall.ClusterEgressFirewall)
+			if reflect.DeepEqual(oldClusterEgressFirewall.Spec, newClusterEgressFirewall.Spec) {
+				return
+			}
+			if err := oc.updateClusterEgressFirewall(oldClusterEgressFirewall, newClusterEgressFirewall); err != nil {
+				klog.Error(err)
+				newClusterEgressFirewall.Status.Status = egressFirewallUpdateError
+			} else {
+				newClusterEgressFirewall.Status.Status = egressFirewallAppliedCorrectly
+			}
+			newClusterEgressFirewall.Status.Rules = oc.getClusterEgressFirewallRuleStatuses(newClusterEgressFirewall,
+				newClusterEgressFirewall.Status.Status == egressFirewallAppliedCorrectly)
+			if err := oc.updateClusterEgressFirewallWithRetry(newClusterEgressFirewall); err != nil {
+				klog.Error(err)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			clusterEgressFirewall := obj.(*egressfirewall.ClusterEgressFirewall)
+			if err := oc.deleteClusterEgressFirewall(clusterEgressFirewall); err != nil {
+				klog.Error(err)
+			}
+		},
+	}, oc.syncClusterEgressFirewall)
+}
+
+// WatchEgressQoS starts the watching of egressqos resource and calls
+// back the appropriate handler logic
+func (oc *Controller) WatchEgressQoS() *factory.Handler {
+	return oc.watchFactory.AddEgressQoSHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			egressQoS := obj.(*egressqos.EgressQoS).DeepCopy()
+			txn := util.NewNBTxn()
+			if err := oc.addEgressQoS(egressQoS, txn); err != nil {
+				klog.Error(err)
+				egressQoS.Status.Status = egressQoSAddError
+			} else if _, stderr, err := txn.Commit(); err != nil {
+				klog.Errorf("Failed to commit db changes for egressQoS in namespace %s stderr: %q, err: %+v", egressQoS.Namespace, stderr, err)
+				egressQoS.Status.Status = egressQoSAddError
+			} else {
+				egressQoS.Status.Status = egressQoSAppliedCorrectly
+			}
+			if err := oc.updateEgressQoSWithRetry(egressQoS); err != nil {
+				klog.Error(err)
+			}
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			newEgressQoS := newer.(*egressqos.EgressQoS).DeepCopy()
+			oldEgressQoS := old.(*egressqos.EgressQoS)
+			if reflect.DeepEqual(oldEgressQoS.Spec, newEgressQoS.Spec) {
 				return
 			}
+			txn := util.NewNBTxn()
+			if err := oc.updateEgressQoS(oldEgressQoS, newEgressQoS, txn); err != nil {
+				klog.Error(err)
+				newEgressQoS.Status.Status = egressQoSUpdateError
+			} else if _, stderr, err := txn.Commit(); err != nil {
+				klog.Errorf("Failed to commit db changes for egressQoS in namespace %s stderr: %q, err: %+v", newEgressQoS.Namespace, stderr, err)
+				newEgressQoS.Status.Status = egressQoSUpdateError
+			} else {
+				newEgressQoS.Status.Status = egressQoSAppliedCorrectly
+			}
+			if err := oc.updateEgressQoSWithRetry(newEgressQoS); err != nil {
+				klog.Error(err)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			egressQoS := obj.(*egressqos.EgressQoS)
+			txn := util.NewNBTxn()
+			// commit the removal of the QoS rules even if some address sets could not be deleted
+			if err := oc.deleteEgressQoS(egressQoS, txn); err != nil {
+				klog.Error(err)
+			}
 			stdout, stderr, err := txn.Commit()
 			if err != nil {
-				klog.Errorf("Failed to commit db changes for egressFirewall in namespace %s stdout: %q, stderr: %q, err: %+v", egressFirewall.Namespace, stdout, stderr, err)
+				klog.Errorf("Failed to commit db changes for egressQoS in namespace %s stdout: %q, stderr: %q, err: %+v", egressQoS.Namespace, stdout, stderr, err)
 			}
 		},
-	}, oc.syncEgressFirewall)
+	}, oc.syncEgressQoS)
+}
+
+// WatchAdminNetworkPolicy starts the watching of adminnetworkpolicy resource and calls
+// back the appropriate handler logic
+func (oc *Controller) WatchAdminNetworkPolicy() *factory.Handler {
+	return oc.watchFactory.AddAdminNetworkPolicyHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			policy := obj.(*adminnetworkpolicy.AdminNetworkPolicy)
+			if err := oc.addAdminNetworkPolicy(policy); err != nil {
+				klog.Error(err)
+				return
+			}
+			if err := oc.syncAdminNetworkPolicyACLs(); err != nil {
+				klog.Error(err)
+			}
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			oldPolicy := old.(*adminnetworkpolicy.AdminNetworkPolicy)
+			newPolicy := newer.(*adminnetworkpolicy.AdminNetworkPolicy)
+			if reflect.DeepEqual(oldPolicy.Spec, newPolicy.Spec) {
+				return
+			}
+			if err := oc.updateAdminNetworkPolicy(oldPolicy, newPolicy); err != nil {
+				klog.Error(err)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			policy := obj.(*adminnetworkpolicy.AdminNetworkPolicy)
+			oc.deleteAdminNetworkPolicy(policy)
+			if err := oc.syncAdminNetworkPolicyACLs(); err != nil {
+				klog.Error(err)
+			}
+		},
+	}, oc.syncAdminNetworkPolicies)
+}
+
+// WatchEgressFirewallNodes starts the watching of nodes so that the node IPs of
+// egress firewall nodeSelector rules are kept up to date
+func (oc *Controller) WatchEgressFirewallNodes() *factory.Handler {
+	return oc.watchFactory.AddNodeHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			node := obj.(*kapi.Node)
+			if oc.egressFirewallDNS != nil {
+				oc.egressFirewallDNS.SetSnoopedIPs(node.Name, getEgressFirewallDNSIPs(node))
+			}
+			oc.updateEgressFirewallForNode(nil, node)
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			oldNode := old.(*kapi.Node)
+			newNode := newer.(*kapi.Node)
+			if oc.egressFirewallDNS != nil {
+				if newDNSIPs := getEgressFirewallDNSIPs(newNode); !reflect.DeepEqual(getEgressFirewallDNSIPs(oldNode), newDNSIPs) {
+					oc.egressFirewallDNS.SetSnoopedIPs(newNode.Name, newDNSIPs)
+				}
+			}
+			if reflect.DeepEqual(oldNode.Labels, newNode.Labels) &&
+				reflect.DeepEqual(getEgressFirewallNodeIPs(oldNode), getEgressFirewallNodeIPs(newNode)) {
+				return
+			}
+			oc.updateEgressFirewallForNode(oldNode, newNode)
+		},
+		DeleteFunc: func(obj interface{}) {
+			node := obj.(*kapi.Node)
+			if oc.egressFirewallDNS != nil {
+				oc.egressFirewallDNS.SetSnoopedIPs(node.Name, nil)
+			}
+			oc.updateEgressFirewallForNode(node, nil)
+		},
+	}, nil)
 }
 
 // WatchEgressNodes starts the watching of egress assignable nodes and calls
@@ -784,8 +1000,11 @@ func (oc *Controller) WatchEgressNodes() {
 		UpdateFunc: func(old, new interface{}) {
 			oldNode := old.(*kapi.Node)
 			newNode := new.(*kapi.Node)
-			if err := oc.initEgressIPAllocator(newNode); err != nil {
-				klog.Error(err)
+			if util.NodeEgressIPConfigAnnotationChanged(oldNode, newNode) {
+				if err := oc.initEgressIPAllocator(newNode); err != nil {
+					klog.Error(err)
+				}
+				oc.enforceEgressIPCapacity(newNode.Name)
 			}
 			oldLabels := oldNode.GetLabels()
 			newLabels := newNode.GetLabels()
@@ -872,7 +1091,8 @@ func (oc *Controller) WatchEgressIP() {
 					klog.Error(err)
 				}
 				newEIP.Status = egressipv1.EgressIPStatus{
-					Items: []egressipv1.EgressIPStatusItem{},
+					Items:      []egressipv1.EgressIPStatusItem{},
+					Conditions: newEIP.Status.Conditions,
 				}
 				oc.eIPC.assignmentRetryMutex.Lock()
 				defer oc.eIPC.assignmentRetryMutex.Unlock()
@@ -1088,6 +1308,7 @@ func (oc *Controller) WatchNodes() {
 			dnatSnatIPs, _ := util.ParseNodeLocalNatIPAnnotation(node)
 			oc.deleteNode(node.Name, nodeSubnets, dnatSnatIPs)
 			oc.lsManager.DeleteNode(node.Name)
+			oc.forgetStickyPodAddressesOnNode(node.Name)
 			addNodeFailed.Delete(node.Name)
 			mgmtPortFailed.Delete(node.Name)
 			gatewaysFailed.Delete(node.Name)
@@ -1198,7 +1419,8 @@ func shouldUpdate(node, oldNode *kapi.Node) (bool, error) {
 	return true, nil
 }
 
-func newServiceController(client clientset.Interface, nbClient libovsdbclient.Client, stopChan <-chan struct{}) *svccontroller.Controller {
+func newServiceController(client clientset.Interface, nbClient libovsdbclient.Client, hasLBHealthCheckIP func(nodeName string) bool,
+	stopChan <-chan struct{}) *svccontroller.Controller {
 	// Create our own informers to start compartmentalizing the code
 	// filter server side the things we don't care about
 	noProxyName, err := labels.NewRequirement("service.kubernetes.io/service-proxy-name", selection.DoesNotExist, nil)
@@ -1225,6 +1447,8 @@ func newServiceController(client clientset.Interface, nbClient libovsdbclient.Cl
 		svcFactory.Core().V1().Services(),
 		svcFactory.Discovery().V1beta1().EndpointSlices(),
 		svcFactory.Core().V1().Nodes(),
+		svcFactory.Core().V1().Pods(),
+		hasLBHealthCheckIP,
 	)
 
 	svcFactory.Start(stopChan)
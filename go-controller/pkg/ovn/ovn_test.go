@@ -1,6 +1,7 @@
 package ovn
 
 import (
+	"context"
 	"sync"
 
 	goovn "github.com/ebay/go-ovn"
@@ -14,14 +15,22 @@ import (
 	util "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
 
 	"github.com/urfave/cli/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
 
-	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
-	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/fake"
+	adminnetworkpolicy "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1"
+	adminnetworkpolicyfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned/fake"
+	egressfirewall "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2"
+	egressfirewallfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/clientset/versioned/fake"
 	egressip "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1"
 	egressipfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned/fake"
+	egressqos "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1"
+	egressqosfake "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned/fake"
+
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	networkattchmentdeffake "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned/fake"
 )
 
 const (
@@ -67,12 +76,23 @@ func NewFakeOVN(fexec *ovntest.FakeExec) *FakeOVN {
 func (o *FakeOVN) start(ctx *cli.Context, objects ...runtime.Object) {
 	egressIPObjects := []runtime.Object{}
 	egressFirewallObjects := []runtime.Object{}
+	egressQoSObjects := []runtime.Object{}
+	adminNetworkPolicyObjects := []runtime.Object{}
+	nadObjects := []nettypes.NetworkAttachmentDefinition{}
 	v1Objects := []runtime.Object{}
 	for _, object := range objects {
 		if _, isEgressIPObject := object.(*egressip.EgressIPList); isEgressIPObject {
 			egressIPObjects = append(egressIPObjects, object)
 		} else if _, isEgressFirewallObject := object.(*egressfirewall.EgressFirewallList); isEgressFirewallObject {
 			egressFirewallObjects = append(egressFirewallObjects, object)
+		} else if _, isClusterEgressFirewallObject := object.(*egressfirewall.ClusterEgressFirewallList); isClusterEgressFirewallObject {
+			egressFirewallObjects = append(egressFirewallObjects, object)
+		} else if _, isEgressQoSObject := object.(*egressqos.EgressQoSList); isEgressQoSObject {
+			egressQoSObjects = append(egressQoSObjects, object)
+		} else if _, isAdminNetworkPolicyObject := object.(*adminnetworkpolicy.AdminNetworkPolicyList); isAdminNetworkPolicyObject {
+			adminNetworkPolicyObjects = append(adminNetworkPolicyObjects, object)
+		} else if nadList, isNADObject := object.(*nettypes.NetworkAttachmentDefinitionList); isNADObject {
+			nadObjects = append(nadObjects, nadList.Items...)
 		} else {
 			v1Objects = append(v1Objects, object)
 		}
@@ -80,9 +100,20 @@ func (o *FakeOVN) start(ctx *cli.Context, objects ...runtime.Object) {
 	_, err := config.InitConfig(ctx, o.fakeExec, nil)
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	o.fakeClient = &util.OVNClientset{
-		KubeClient:           fake.NewSimpleClientset(v1Objects...),
-		EgressIPClient:       egressipfake.NewSimpleClientset(egressIPObjects...),
-		EgressFirewallClient: egressfirewallfake.NewSimpleClientset(egressFirewallObjects...),
+		KubeClient:               fake.NewSimpleClientset(v1Objects...),
+		EgressIPClient:           egressipfake.NewSimpleClientset(egressIPObjects...),
+		EgressFirewallClient:     egressfirewallfake.NewSimpleClientset(egressFirewallObjects...),
+		EgressQoSClient:          egressqosfake.NewSimpleClientset(egressQoSObjects...),
+		AdminNetworkPolicyClient: adminnetworkpolicyfake.NewSimpleClientset(adminNetworkPolicyObjects...),
+		NetworkAttchDefClient:    networkattchmentdeffake.NewSimpleClientset(),
+	}
+	// the object tracker guesses the resource of the net-attach-defs passed to
+	// NewSimpleClientset as "networkattachmentdefinitions" while the client
+	// uses "network-attachment-definitions", create them through the client
+	for i := range nadObjects {
+		_, err = o.fakeClient.NetworkAttchDefClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(nadObjects[i].Namespace).Create(
+			context.TODO(), &nadObjects[i], metav1.CreateOptions{})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 	}
 	o.init()
 }
@@ -86,6 +86,10 @@ func (oc *Controller) deleteLogicalPort(pod *kapi.Pod) {
 	podDesc := pod.Namespace + "/" + pod.Name
 	klog.Infof("Deleting pod: %s", podDesc)
 
+	if config.OVNKubernetesFeature.EnableMultiNetwork {
+		oc.deletePodSecondaryNetworkPorts(pod)
+	}
+
 	logicalPort := util.GetLogicalPortName(pod.Namespace, pod.Name)
 	portInfo, err := oc.logicalPortCache.get(logicalPort)
 	if err != nil {
@@ -102,7 +106,7 @@ func (oc *Controller) deleteLogicalPort(pod *kapi.Pod) {
 		logicalSwitch := pod.Spec.NodeName
 		if logicalSwitch != "" {
 			annotation, err := util.UnmarshalPodAnnotation(pod.Annotations)
-			if err == nil {
+			if err == nil && !oc.holdStickyPodAddresses(pod, logicalSwitch, annotation.IPs, annotation.MAC) {
 				podIfAddrs := annotation.IPs
 				_ = oc.lsManager.ReleaseIPs(logicalSwitch, podIfAddrs)
 			}
@@ -121,8 +125,10 @@ func (oc *Controller) deleteLogicalPort(pod *kapi.Pod) {
 		klog.Errorf(err.Error())
 	}
 
-	if err := oc.lsManager.ReleaseIPs(portInfo.logicalSwitch, portInfo.ips); err != nil {
-		klog.Errorf(err.Error())
+	if !oc.holdStickyPodAddresses(pod, portInfo.logicalSwitch, portInfo.ips, portInfo.mac) {
+		if err := oc.lsManager.ReleaseIPs(portInfo.logicalSwitch, portInfo.ips); err != nil {
+			klog.Errorf(err.Error())
+		}
 	}
 
 	if config.Gateway.DisableSNATMultipleGWs {
@@ -247,6 +253,7 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 	var addresses []string
 	var cmd *goovn.OvnCommand
 	var releaseIPs bool
+	var stickyIPs bool
 	needsIP := true
 
 	// Check if the pod's logical switch port already exists. If it
@@ -296,6 +303,27 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 	}
 	cmds = append(cmds, cmd)
 
+	// reserve the addresses of the pod on its secondary networks, those
+	// allocated here are released if addLogicalPort fails
+	var secondaryPorts []*secondaryNetworkPort
+	if config.OVNKubernetesFeature.EnableMultiNetwork {
+		secondaryPorts, err = oc.allocatePodSecondaryNetworkPorts(pod)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				oc.releasePodSecondaryNetworkPorts(secondaryPorts)
+			}
+		}()
+	}
+	needsSecondaryAnnotation := false
+	for _, port := range secondaryPorts {
+		if port.allocated {
+			needsSecondaryAnnotation = true
+		}
+	}
+
 	annotation, err := util.UnmarshalPodAnnotation(pod.Annotations)
 
 	// the IPs we allocate in this function need to be released back to the
@@ -308,6 +336,10 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 
 	defer func() {
 		if releaseIPs && err != nil {
+			// addresses kept for the pod are kept again for its next attempt
+			if stickyIPs && oc.holdStickyPodAddresses(pod, logicalSwitch, podIfAddrs, podMac) {
+				return
+			}
 			if relErr := oc.lsManager.ReleaseIPs(logicalSwitch, podIfAddrs); relErr != nil {
 				klog.Errorf("Error when releasing IPs for node: %s, err: %q",
 					logicalSwitch, relErr)
@@ -338,47 +370,64 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 		}
 	}
 
+	var podAnnotation *util.PodAnnotation
 	if needsIP {
-		// try to get the IP from existing port in OVN first
-		podMac, podIfAddrs, err = oc.getPortAddresses(logicalSwitch, portName)
-		if err != nil {
-			return fmt.Errorf("failed to get pod addresses for pod %s on node: %s, err: %v",
-				portName, logicalSwitch, err)
-		}
-		needsNewAllocation := false
-		// ensure we have reserved the IPs found in OVN
-		if len(podIfAddrs) == 0 {
-			needsNewAllocation = true
-		} else if err = oc.lsManager.AllocateIPs(logicalSwitch, podIfAddrs); err != nil && err != ipallocator.ErrAllocated {
-			klog.Warningf("Unable to allocate IPs found on existing OVN port: %s, for pod %s on node: %s"+
-				" error: %v", util.JoinIPNetIPs(podIfAddrs, " "), portName, logicalSwitch, err)
-
-			needsNewAllocation = true
-		}
-		if needsNewAllocation {
-			// Previous attempts to use already configured IPs failed, need to assign new
-			podMac, podIfAddrs, err = oc.assignPodAddresses(logicalSwitch)
-			if err != nil {
-				return fmt.Errorf("failed to assign pod addresses for pod %s on node: %s, err: %v",
-					portName, logicalSwitch, err)
-			}
-		}
-
-		releaseIPs = true
 		var networks []*types.NetworkSelectionElement
 
 		networks, err = util.GetPodNetSelAnnotation(pod, util.DefNetworkAnnotation)
 		// handle error cases separately first to ensure binding to err, otherwise the
 		// defer will fail
 		if err != nil {
-			return fmt.Errorf("error while getting custom MAC config for port %q from "+
+			return fmt.Errorf("error while getting custom MAC/IP config for port %q from "+
 				"default-network's network-attachment: %v", portName, err)
 		} else if networks != nil && len(networks) != 1 {
-			err = fmt.Errorf("invalid network annotation size while getting custom MAC config"+
+			err = fmt.Errorf("invalid network annotation size while getting custom MAC/IP config"+
 				" for port %q", portName)
 			return err
 		}
 
+		if networks != nil && len(networks[0].IPRequest) > 0 {
+			// the IPs requested by the pod take precedence over those
+			// found on an existing port in OVN
+			klog.V(5).Infof("Pod %s/%s requested custom IPs: %v", pod.Namespace, pod.Name, networks[0].IPRequest)
+			podIfAddrs, err = oc.allocatePodRequestedIPs(pod, logicalSwitch, networks[0].IPRequest)
+			if err != nil {
+				return err
+			}
+			podMac = util.IPAddrToHWAddr(podIfAddrs[0].IP)
+		} else if podMac, podIfAddrs = oc.claimStickyPodAddresses(pod, logicalSwitch); len(podIfAddrs) > 0 {
+			// the pod replaces a deleted StatefulSet pod whose addresses
+			// were kept, they are still allocated
+			stickyIPs = true
+		} else {
+			// try to get the IP from existing port in OVN first
+			podMac, podIfAddrs, err = oc.getPortAddresses(logicalSwitch, portName)
+			if err != nil {
+				return fmt.Errorf("failed to get pod addresses for pod %s on node: %s, err: %v",
+					portName, logicalSwitch, err)
+			}
+			needsNewAllocation := false
+			// ensure we have reserved the IPs found in OVN
+			if len(podIfAddrs) == 0 {
+				needsNewAllocation = true
+			} else if err = oc.lsManager.AllocateIPs(logicalSwitch, podIfAddrs); err != nil && err != ipallocator.ErrAllocated {
+				klog.Warningf("Unable to allocate IPs found on existing OVN port: %s, for pod %s on node: %s"+
+					" error: %v", util.JoinIPNetIPs(podIfAddrs, " "), portName, logicalSwitch, err)
+
+				needsNewAllocation = true
+			}
+			if needsNewAllocation {
+				// Previous attempts to use already configured IPs failed, need to assign new
+				podMac, podIfAddrs, err = oc.assignPodAddresses(logicalSwitch)
+				if err != nil {
+					return fmt.Errorf("failed to assign pod addresses for pod %s on node: %s, err: %v",
+						portName, logicalSwitch, err)
+				}
+			}
+		}
+
+		releaseIPs = true
+
 		if networks != nil && networks[0].MacRequest != "" {
 			klog.V(5).Infof("Pod %s/%s requested custom MAC: %s", pod.Namespace, pod.Name, networks[0].MacRequest)
 			podMac, err = net.ParseMAC(networks[0].MacRequest)
@@ -387,7 +436,7 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 					networks[0].MacRequest, pod.Name, err)
 			}
 		}
-		podAnnotation := util.PodAnnotation{
+		podAnnotation = &util.PodAnnotation{
 			IPs: podIfAddrs,
 			MAC: podMac,
 		}
@@ -396,22 +445,39 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 			return fmt.Errorf("cannot retrieve subnet for assigning gateway routes for pod %s, node: %s",
 				pod.Name, logicalSwitch)
 		}
-		err = oc.addRoutesGatewayIP(pod, &podAnnotation, nodeSubnets)
+		err = oc.addRoutesGatewayIP(pod, podAnnotation, nodeSubnets)
 		if err != nil {
 			return err
 		}
+	}
+
+	if needsIP || needsSecondaryAnnotation {
+		// the networks of the pod share a single annotation, write them all
+		// at once
+		podAnnotations := map[string]*util.PodAnnotation{
+			util.OvnPodDefaultNetwork: podAnnotation,
+		}
+		if podAnnotation == nil {
+			podAnnotations[util.OvnPodDefaultNetwork] = annotation
+		}
+		for _, port := range secondaryPorts {
+			podAnnotations[port.nadName] = port.annotation
+		}
 		var marshalledAnnotation map[string]string
-		marshalledAnnotation, err = util.MarshalPodAnnotation(&podAnnotation)
+		marshalledAnnotation, err = util.MarshalPodAnnotations(podAnnotations)
 		if err != nil {
 			return fmt.Errorf("error creating pod network annotation: %v", err)
 		}
 
 		klog.V(5).Infof("Annotation values: ip=%v ; mac=%s ; gw=%s\nAnnotation=%s",
-			podIfAddrs, podMac, podAnnotation.Gateways, marshalledAnnotation)
+			podIfAddrs, podMac, podAnnotations[util.OvnPodDefaultNetwork].Gateways, marshalledAnnotation)
 		if err = oc.kube.SetAnnotationsOnPod(pod.Namespace, pod.Name, marshalledAnnotation); err != nil {
 			return fmt.Errorf("failed to set annotation on pod %s: %v", pod.Name, err)
 		}
 		releaseIPs = false
+		for _, port := range secondaryPorts {
+			port.allocated = false
+		}
 	}
 
 	// Ensure the namespace/nsInfo exists
@@ -483,6 +549,15 @@ func (oc *Controller) addLogicalPort(pod *kapi.Pod) (err error) {
 
 	cmds = append(cmds, cmd)
 
+	for _, port := range secondaryPorts {
+		var secondaryCmds []*goovn.OvnCommand
+		secondaryCmds, err = oc.getSecondaryNetworkPortCmds(pod, port)
+		if err != nil {
+			return err
+		}
+		cmds = append(cmds, secondaryCmds...)
+	}
+
 	// execute all the commands together. If a single operation fails, all commands will roll back =>
 	// for new Pod no LSP will be created
 	err = oc.ovnNBClient.Execute(cmds...)
@@ -534,6 +609,90 @@ func (oc *Controller) assignPodAddresses(nodeName string) (net.HardwareAddr, []*
 	return podMAC, podCIDRs, nil
 }
 
+// podAddressRequestError is returned when the addresses requested by a pod
+// cannot be assigned to it, its reason is the reason of the pod event
+type podAddressRequestError struct {
+	reason string
+	msg    string
+}
+
+func (e *podAddressRequestError) Error() string {
+	return e.msg
+}
+
+// allocatePodRequestedIPs reserves the IPs requested by a pod on the given node.
+// Exactly one IP must be requested in each of the subnets of the node, and
+// none of them may be in use already; the prefix length of the requested IPs
+// is ignored, that of the node subnets is used.
+func (oc *Controller) allocatePodRequestedIPs(pod *kapi.Pod, nodeName string, ipRequests []string) ([]*net.IPNet, error) {
+	nodeSubnets := oc.lsManager.GetSwitchSubnets(nodeName)
+	podIPs := make([]*net.IPNet, len(nodeSubnets))
+	for _, ipRequest := range ipRequests {
+		ip, _, err := net.ParseCIDR(ipRequest)
+		if err != nil {
+			ip = net.ParseIP(ipRequest)
+		}
+		if ip == nil {
+			return nil, &podAddressRequestError{
+				reason: "InvalidRequestedIP",
+				msg:    fmt.Sprintf("failed to parse IP %s requested by pod %s/%s", ipRequest, pod.Namespace, pod.Name),
+			}
+		}
+		found := false
+		for i, subnet := range nodeSubnets {
+			if !subnet.Contains(ip) {
+				continue
+			}
+			if podIPs[i] != nil {
+				return nil, &podAddressRequestError{
+					reason: "InvalidRequestedIP",
+					msg: fmt.Sprintf("pod %s/%s requested both IPs %s and %s in subnet %s of node %s",
+						pod.Namespace, pod.Name, podIPs[i].IP, ip, subnet, nodeName),
+				}
+			}
+			podIPs[i] = &net.IPNet{IP: ip, Mask: subnet.Mask}
+			found = true
+			break
+		}
+		if !found {
+			return nil, &podAddressRequestError{
+				reason: "InvalidRequestedIP",
+				msg: fmt.Sprintf("IP %s requested by pod %s/%s is not in the subnets %s of node %s",
+					ip, pod.Namespace, pod.Name, util.JoinIPNets(nodeSubnets, ","), nodeName),
+			}
+		}
+	}
+	for i, podIP := range podIPs {
+		if podIP == nil {
+			return nil, &podAddressRequestError{
+				reason: "InvalidRequestedIP",
+				msg: fmt.Sprintf("pod %s/%s did not request an IP in subnet %s of node %s",
+					pod.Namespace, pod.Name, nodeSubnets[i], nodeName),
+			}
+		}
+	}
+
+	if err := oc.lsManager.AllocateIPs(nodeName, podIPs); err != nil {
+		if err == ipallocator.ErrAllocated {
+			return nil, &podAddressRequestError{
+				reason: "RequestedIPInUse",
+				msg: fmt.Sprintf("IPs %s requested by pod %s/%s are already in use on node %s",
+					util.JoinIPNetIPs(podIPs, " "), pod.Namespace, pod.Name, nodeName),
+			}
+		}
+		if _, ok := err.(*ipallocator.ErrNotInRange); ok {
+			return nil, &podAddressRequestError{
+				reason: "InvalidRequestedIP",
+				msg: fmt.Sprintf("IPs %s requested by pod %s/%s cannot be assigned on node %s: %v",
+					util.JoinIPNetIPs(podIPs, " "), pod.Namespace, pod.Name, nodeName, err),
+			}
+		}
+		return nil, fmt.Errorf("failed to allocate IPs %s requested by pod %s/%s on node %s: %v",
+			util.JoinIPNetIPs(podIPs, " "), pod.Namespace, pod.Name, nodeName, err)
+	}
+	return podIPs, nil
+}
+
 // Given a pod and the node on which it is scheduled, get all addresses currently assigned
 // to it from the nbdb.
 func (oc *Controller) getPortAddresses(nodeName, portName string) (net.HardwareAddr, []*net.IPNet, error) {
@@ -68,6 +68,27 @@ func newPodWithLabels(namespace, name, node, podIP string, additionalLabels map[
 	}
 }
 
+func newStatefulSetPod(namespace, name, node, podIP string) *v1.Pod {
+	pod := newPod(namespace, name, node, podIP)
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "apps/v1",
+			Kind:       "StatefulSet",
+			Name:       "web",
+			UID:        types.UID("web"),
+			Controller: &controller,
+		},
+	}
+	return pod
+}
+
+func getStickyPodAddresses(oc *Controller, namespace, name string) *stickyPodAddresses {
+	oc.stickyPodAddressesLock.Lock()
+	defer oc.stickyPodAddressesLock.Unlock()
+	return oc.stickyPodAddresses[util.GetLogicalPortName(namespace, name)]
+}
+
 func newPod(namespace, name, node, podIP string) *v1.Pod {
 	podIPs := []v1.PodIP{}
 	if podIP != "" {
@@ -445,6 +466,241 @@ var _ = ginkgo.Describe("OVN Pod Operations", func() {
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+
+		ginkgo.It("assigns the IP and MAC requested by a new pod", func() {
+			app.Action = func(ctx *cli.Context) error {
+				namespaceT := *newNamespace("namespace1")
+				t := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.50",
+					"0a:58:0a:80:01:99",
+					namespaceT.Name,
+				)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespaceT,
+						},
+					},
+				)
+				t.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+
+				pod := newPod(t.namespace, t.podName, t.nodeName, t.podIP)
+				pod.Annotations = map[string]string{
+					util.DefNetworkAnnotation: `[{"namespace": "ovn-kubernetes", "name": "ovn-kubernetes", "ips": ["` + t.podIP + `/24"], "mac": "` + t.podMAC + `"}]`,
+				}
+				_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName) }, 2).Should(gomega.MatchJSON(`{"default": {"ip_addresses":["` + t.podIP + `/24"], "mac_address":"` + t.podMAC + `", "gateway_ips": ["` + t.nodeGWIP + `"], "ip_address":"` + t.podIP + `/24", "gateway_ip": "` + t.nodeGWIP + `"}}`))
+				lsp, err := fakeOvn.ovnNBClient.LSPGet(t.portName)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(lsp.Addresses).To(gomega.HaveLen(1))
+				gomega.Expect(lsp.Addresses[0]).To(gomega.Equal(t.podMAC + " " + t.podIP))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("refuses the IPs requested by a pod when they are in use or out of the node subnet", func() {
+			app.Action = func(ctx *cli.Context) error {
+				namespaceT := *newNamespace("namespace1")
+				t := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"myPod",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespaceT.Name,
+				)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespaceT,
+						},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{
+							*newPod(t.namespace, t.podName, t.nodeName, t.podIP),
+						},
+					},
+				)
+				t.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName) }, 2).ShouldNot(gomega.BeEmpty())
+
+				// the IP of myPod is in use
+				conflicting := newPod(t.namespace, "conflicting", t.nodeName, "")
+				conflicting.Annotations = map[string]string{
+					util.DefNetworkAnnotation: `[{"namespace": "ovn-kubernetes", "name": "ovn-kubernetes", "ips": ["` + t.podIP + `/24"]}]`,
+				}
+				_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), conflicting, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				var recordedEvent string
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.Receive(&recordedEvent))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("RequestedIPInUse"))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("IPs %s requested by pod %s/conflicting are already in use", t.podIP, t.namespace))
+
+				outOfSubnet := newPod(t.namespace, "outofsubnet", t.nodeName, "")
+				outOfSubnet.Annotations = map[string]string{
+					util.DefNetworkAnnotation: `[{"namespace": "ovn-kubernetes", "name": "ovn-kubernetes", "ips": ["10.128.2.3/24"]}]`,
+				}
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), outOfSubnet, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fakeOvn.fakeRecorder.Events).Should(gomega.Receive(&recordedEvent))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("InvalidRequestedIP"))
+				gomega.Expect(recordedEvent).To(gomega.ContainSubstring("IP 10.128.2.3 requested by pod %s/outofsubnet is not in the subnets 10.128.1.0/24 of node %s", t.namespace, t.nodeName))
+
+				gomega.Expect(getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, "conflicting")).To(gomega.BeEmpty())
+				gomega.Expect(getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, "outofsubnet")).To(gomega.BeEmpty())
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+		ginkgo.It("keeps the IPs of a deleted StatefulSet pod for its replacement on the same node", func() {
+			app.Action = func(ctx *cli.Context) error {
+				namespaceT := *newNamespace("namespace1")
+				namespaceT.Annotations[stickyPodIPsAnnotation] = "5m"
+				t := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"web-0",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespaceT.Name,
+				)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespaceT,
+						},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{
+							*newStatefulSetPod(t.namespace, t.podName, t.nodeName, t.podIP),
+						},
+					},
+				)
+				t.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				podAnnotation := `{"default": {"ip_addresses":["` + t.podIP + `/24"], "mac_address":"` + t.podMAC + `", "gateway_ips": ["` + t.nodeGWIP + `"], "ip_address":"` + t.podIP + `/24", "gateway_ip": "` + t.nodeGWIP + `"}}`
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName) }, 2).Should(gomega.MatchJSON(podAnnotation))
+
+				err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Delete(context.TODO(), t.podName, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() *stickyPodAddresses { return getStickyPodAddresses(fakeOvn.controller, t.namespace, t.podName) }, 2).ShouldNot(gomega.BeNil())
+
+				// the IP of web-0 is not given to another pod
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), newPod(t.namespace, "other", t.nodeName, ""), metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, "other") }, 2).Should(gomega.MatchJSON(`{"default": {"ip_addresses":["10.128.1.4/24"], "mac_address":"0a:58:0a:80:01:04", "gateway_ips": ["` + t.nodeGWIP + `"], "ip_address":"10.128.1.4/24", "gateway_ip": "` + t.nodeGWIP + `"}}`))
+
+				// but to its replacement
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), newStatefulSetPod(t.namespace, t.podName, t.nodeName, ""), metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName) }, 2).Should(gomega.MatchJSON(podAnnotation))
+				gomega.Expect(getStickyPodAddresses(fakeOvn.controller, t.namespace, t.podName)).To(gomega.BeNil())
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("releases the IPs kept for a StatefulSet pod when it moves to another node or after the grace period", func() {
+			app.Action = func(ctx *cli.Context) error {
+				namespaceT := *newNamespace("namespace1")
+				namespaceT.Annotations[stickyPodIPsAnnotation] = "500ms"
+				t := newTPod(
+					"node1",
+					"10.128.1.0/24",
+					"10.128.1.2",
+					"10.128.1.1",
+					"web-0",
+					"10.128.1.3",
+					"0a:58:0a:80:01:03",
+					namespaceT.Name,
+				)
+				t2 := newTPod(
+					"node2",
+					"10.128.2.0/24",
+					"10.128.2.2",
+					"10.128.2.1",
+					"web-1",
+					"10.128.1.4",
+					"0a:58:0a:80:01:04",
+					namespaceT.Name,
+				)
+
+				fakeOvn.start(ctx,
+					&v1.NamespaceList{
+						Items: []v1.Namespace{
+							namespaceT,
+						},
+					},
+					&v1.PodList{
+						Items: []v1.Pod{
+							*newStatefulSetPod(t.namespace, t.podName, t.nodeName, t.podIP),
+							*newStatefulSetPod(t2.namespace, t2.podName, t.nodeName, t2.podIP),
+						},
+					},
+				)
+				t.populateLogicalSwitchCache(fakeOvn)
+				t2.populateLogicalSwitchCache(fakeOvn)
+				fakeOvn.controller.WatchNamespaces()
+				fakeOvn.controller.WatchPods()
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName) }, 2).ShouldNot(gomega.BeEmpty())
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t2.namespace, t2.podName) }, 2).ShouldNot(gomega.BeEmpty())
+				// the pods are added in any order
+				podIPs := map[string][]*net.IPNet{}
+				for _, name := range []string{t.podName, t2.podName} {
+					pod, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Get(context.TODO(), name, metav1.GetOptions{})
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					annotation, err := util.UnmarshalPodAnnotation(pod.Annotations)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					podIPs[name] = annotation.IPs
+				}
+
+				// web-0 comes back on node2, its IP on node1 is released
+				err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Delete(context.TODO(), t.podName, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() *stickyPodAddresses { return getStickyPodAddresses(fakeOvn.controller, t.namespace, t.podName) }, 2).ShouldNot(gomega.BeNil())
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t.namespace).Create(context.TODO(), newStatefulSetPod(t.namespace, t.podName, t2.nodeName, ""), metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, t.namespace, t.podName) }, 2).Should(gomega.MatchJSON(`{"default": {"ip_addresses":["10.128.2.3/24"], "mac_address":"0a:58:0a:80:02:03", "gateway_ips": ["` + t2.nodeGWIP + `"], "ip_address":"10.128.2.3/24", "gateway_ip": "` + t2.nodeGWIP + `"}}`))
+				gomega.Expect(fakeOvn.controller.lsManager.AllocateIPs(t.nodeName, podIPs[t.podName])).To(gomega.Succeed())
+
+				// web-1 does not come back in time, its IP is released
+				err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(t2.namespace).Delete(context.TODO(), t2.podName, *metav1.NewDeleteOptions(0))
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(func() *stickyPodAddresses { return getStickyPodAddresses(fakeOvn.controller, t2.namespace, t2.podName) }, 2).ShouldNot(gomega.BeNil())
+				gomega.Eventually(func() *stickyPodAddresses { return getStickyPodAddresses(fakeOvn.controller, t2.namespace, t2.podName) }, 2).Should(gomega.BeNil())
+				gomega.Expect(fakeOvn.controller.lsManager.AllocateIPs(t.nodeName, podIPs[t2.podName])).To(gomega.Succeed())
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
 	})
 
 	ginkgo.Context("on startup", func() {
@@ -726,6 +726,11 @@ func (oc *Controller) handleLocalPodSelectorAddFunc(
 		return
 	}
 
+	// Named ports of ingress rules are resolved against the local pods
+	oc.handleNamedPortPodsUpdate(np, np.ingressPolicies, func(gp *gressPolicy) bool {
+		return gp.addNamedPortPods(pod)
+	})
+
 	// Get the logical port info
 	logicalPort := util.GetLogicalPortName(pod.Namespace, pod.Name)
 	portInfo, err := oc.logicalPortCache.get(logicalPort)
@@ -791,6 +796,14 @@ func (oc *Controller) handleLocalPodSelectorSetPods(
 			continue
 		}
 
+		// Named ports of ingress rules are resolved against the local pods.
+		// The ACLs are set once all handlers of the policy are in place.
+		for _, gp := range np.ingressPolicies {
+			if gp.hasNamedPorts() {
+				gp.addNamedPortPods(pod)
+			}
+		}
+
 		portInfo, err := oc.logicalPortCache.get(util.GetLogicalPortName(pod.Namespace, pod.Name))
 		// pod is not yet handled
 		// no big deal, we'll get the update when it is.
@@ -836,6 +849,10 @@ func (oc *Controller) handleLocalPodSelectorDelFunc(
 		return
 	}
 
+	oc.handleNamedPortPodsUpdate(np, np.ingressPolicies, func(gp *gressPolicy) bool {
+		return gp.deleteNamedPortPod(pod)
+	})
+
 	// Get the logical port info
 	logicalPort := util.GetLogicalPortName(pod.Namespace, pod.Name)
 	portInfo, err := oc.logicalPortCache.get(logicalPort)
@@ -963,6 +980,7 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 		podSelector       *metav1.LabelSelector
 	}
 	var policyHandlers []policyHandler
+	var namedPortGresses []*gressPolicy
 	// Go through each ingress rule.  For each ingress rule, create an
 	// addressSet for the peer pods.
 	for i, ingressJSON := range policy.Spec.Ingress {
@@ -1032,6 +1050,11 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 				podSelector:       toJSON.PodSelector,
 			})
 		}
+		if len(egressJSON.To) == 0 && egress.hasNamedPorts() {
+			// Egress to any destination still needs the pods
+			// that the named ports resolve on
+			namedPortGresses = append(namedPortGresses, egress)
+		}
 		np.egressPolicies = append(np.egressPolicies, egress)
 	}
 	np.Unlock()
@@ -1048,6 +1071,13 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 			oc.handlePeerNamespaceAndPodSelector(policy,
 				handler.namespaceSelector, handler.podSelector,
 				handler.gress, np)
+		} else if handler.namespaceSelector != nil && handler.gress.policyType == knet.PolicyTypeEgress &&
+			handler.gress.hasNamedPorts() {
+			// Named ports of egress rules have to be resolved against the
+			// peer pods, so watch all the pods of the selected namespaces
+			oc.handlePeerNamespaceAndPodSelector(policy,
+				handler.namespaceSelector, &metav1.LabelSelector{},
+				handler.gress, np)
 		} else if handler.namespaceSelector != nil {
 			// For each peer namespace selector, we create a watcher that
 			// populates ingress.peerAddressSets
@@ -1061,6 +1091,10 @@ func (oc *Controller) addNetworkPolicy(policy *knet.NetworkPolicy) {
 		}
 	}
 
+	for _, gress := range namedPortGresses {
+		oc.handleNamedPortPeerPods(gress, np)
+	}
+
 	// Finally, make sure that all ACLs are set
 	oc.addNetworkPolicyACL(np, nsInfo.aclLogging.Allow)
 }
@@ -1148,7 +1182,7 @@ func (oc *Controller) destroyNetworkPolicy(np *networkPolicy, nsInfo *namespaceI
 // handlePeerPodSelectorAddUpdate adds the IP address of a pod that has been
 // selected as a peer by a NetworkPolicy's ingress/egress section to that
 // ingress/egress address set
-func (oc *Controller) handlePeerPodSelectorAddUpdate(gp *gressPolicy, objs ...interface{}) {
+func (oc *Controller) handlePeerPodSelectorAddUpdate(np *networkPolicy, gp *gressPolicy, objs ...interface{}) {
 	pods := make([]*kapi.Pod, 0, len(objs))
 	for _, obj := range objs {
 		pod := obj.(*kapi.Pod)
@@ -1160,12 +1194,13 @@ func (oc *Controller) handlePeerPodSelectorAddUpdate(gp *gressPolicy, objs ...in
 	if err := gp.addPeerPods(pods...); err != nil {
 		klog.Errorf(err.Error())
 	}
+	oc.handleNamedPortPeerPodsAddUpdate(np, gp, pods...)
 }
 
 // handlePeerPodSelectorDelete removes the IP address of a pod that no longer
 // matches a NetworkPolicy ingress/egress section's selectors from that
 // ingress/egress address set
-func (oc *Controller) handlePeerPodSelectorDelete(gp *gressPolicy, obj interface{}) {
+func (oc *Controller) handlePeerPodSelectorDelete(np *networkPolicy, gp *gressPolicy, obj interface{}) {
 	pod := obj.(*kapi.Pod)
 	if pod.Spec.NodeName == "" {
 		return
@@ -1173,6 +1208,94 @@ func (oc *Controller) handlePeerPodSelectorDelete(gp *gressPolicy, obj interface
 	if err := gp.deletePeerPod(pod); err != nil {
 		klog.Errorf(err.Error())
 	}
+	oc.handleNamedPortPeerPodDelete(np, gp, pod)
+}
+
+// handleNamedPortPodsUpdate runs doUpdate on those of the given 'gress
+// policies that have named ports, and refreshes their ACLs when the
+// endpoints the named ports resolve to changed
+func (oc *Controller) handleNamedPortPodsUpdate(np *networkPolicy, gresses []*gressPolicy, doUpdate func(gp *gressPolicy) bool) {
+	namedPortGresses := make([]*gressPolicy, 0, len(gresses))
+	for _, gp := range gresses {
+		if gp.hasNamedPorts() {
+			namedPortGresses = append(namedPortGresses, gp)
+		}
+	}
+	if len(namedPortGresses) == 0 {
+		return
+	}
+
+	aclLoggingLevels := oc.GetNetworkPolicyACLLogging(np.namespace)
+	np.Lock()
+	defer np.Unlock()
+	// This needs to be a write lock because there's no locking around 'gress policies
+	if np.deleted {
+		return
+	}
+	for _, gp := range namedPortGresses {
+		if doUpdate(gp) && np.portGroupUUID != "" {
+			gp.localPodSetACL(np.portGroupName, np.portGroupUUID, aclLoggingLevels.Allow)
+		}
+	}
+}
+
+// handleNamedPortPeerPodsAddUpdate resolves the named ports of an egress
+// 'gress policy against its peer pods
+func (oc *Controller) handleNamedPortPeerPodsAddUpdate(np *networkPolicy, gp *gressPolicy, pods ...*kapi.Pod) {
+	if gp.policyType != knet.PolicyTypeEgress {
+		return
+	}
+	oc.handleNamedPortPodsUpdate(np, []*gressPolicy{gp}, func(gp *gressPolicy) bool {
+		return gp.addNamedPortPods(pods...)
+	})
+}
+
+// handleNamedPortPeerPodDelete stops resolving the named ports of an egress
+// 'gress policy against a peer pod
+func (oc *Controller) handleNamedPortPeerPodDelete(np *networkPolicy, gp *gressPolicy, pod *kapi.Pod) {
+	if gp.policyType != knet.PolicyTypeEgress {
+		return
+	}
+	oc.handleNamedPortPodsUpdate(np, []*gressPolicy{gp}, func(gp *gressPolicy) bool {
+		return gp.deleteNamedPortPod(pod)
+	})
+}
+
+// handleNamedPortPeerPods watches all pods of the cluster for an egress
+// 'gress policy that has named ports but no peers, i.e. that allows traffic
+// to the named ports of any pod
+func (oc *Controller) handleNamedPortPeerPods(gp *gressPolicy, np *networkPolicy) {
+	h := oc.watchFactory.AddPodHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				if pod.Spec.NodeName != "" {
+					oc.handleNamedPortPeerPodsAddUpdate(np, gp, pod)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				pod := obj.(*kapi.Pod)
+				if pod.Spec.NodeName != "" {
+					oc.handleNamedPortPeerPodDelete(np, gp, pod)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				pod := newObj.(*kapi.Pod)
+				if pod.Spec.NodeName != "" {
+					oc.handleNamedPortPeerPodsAddUpdate(np, gp, pod)
+				}
+			},
+		}, func(objs []interface{}) {
+			pods := make([]*kapi.Pod, 0, len(objs))
+			for _, obj := range objs {
+				pod := obj.(*kapi.Pod)
+				if pod.Spec.NodeName != "" {
+					pods = append(pods, pod)
+				}
+			}
+			oc.handleNamedPortPeerPodsAddUpdate(np, gp, pods...)
+		})
+	np.podHandlerList = append(np.podHandlerList, h)
 }
 
 // handlePeerServiceSelectorAddUpdate adds the VIP of a service that selects
@@ -1240,16 +1363,16 @@ func (oc *Controller) handlePeerPodSelector(
 	h := oc.watchFactory.AddFilteredPodHandler(policy.Namespace, sel,
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
-				oc.handlePeerPodSelectorAddUpdate(gp, obj)
+				oc.handlePeerPodSelectorAddUpdate(np, gp, obj)
 			},
 			DeleteFunc: func(obj interface{}) {
-				oc.handlePeerPodSelectorDelete(gp, obj)
+				oc.handlePeerPodSelectorDelete(np, gp, obj)
 			},
 			UpdateFunc: func(oldObj, newObj interface{}) {
-				oc.handlePeerPodSelectorAddUpdate(gp, newObj)
+				oc.handlePeerPodSelectorAddUpdate(np, gp, newObj)
 			},
 		}, func(objs []interface{}) {
-			oc.handlePeerPodSelectorAddUpdate(gp, objs...)
+			oc.handlePeerPodSelectorAddUpdate(np, gp, objs...)
 		})
 	np.podHandlerList = append(np.podHandlerList, h)
 }
@@ -1281,16 +1404,16 @@ func (oc *Controller) handlePeerNamespaceAndPodSelector(
 				podHandler := oc.watchFactory.AddFilteredPodHandler(namespace.Name, podSel,
 					cache.ResourceEventHandlerFuncs{
 						AddFunc: func(obj interface{}) {
-							oc.handlePeerPodSelectorAddUpdate(gp, obj)
+							oc.handlePeerPodSelectorAddUpdate(np, gp, obj)
 						},
 						DeleteFunc: func(obj interface{}) {
-							oc.handlePeerPodSelectorDelete(gp, obj)
+							oc.handlePeerPodSelectorDelete(np, gp, obj)
 						},
 						UpdateFunc: func(oldObj, newObj interface{}) {
-							oc.handlePeerPodSelectorAddUpdate(gp, newObj)
+							oc.handlePeerPodSelectorAddUpdate(np, gp, newObj)
 						},
 					}, func(objs []interface{}) {
-						oc.handlePeerPodSelectorAddUpdate(gp, objs...)
+						oc.handlePeerPodSelectorAddUpdate(np, gp, objs...)
 					})
 				np.Lock()
 				defer np.Unlock()
@@ -1307,7 +1430,7 @@ func (oc *Controller) handlePeerNamespaceAndPodSelector(
 				pods, _ := oc.watchFactory.GetPods(namespace.Name)
 
 				for _, pod := range pods {
-					oc.handlePeerPodSelectorDelete(gp, pod)
+					oc.handlePeerPodSelectorDelete(np, gp, pod)
 				}
 
 			},
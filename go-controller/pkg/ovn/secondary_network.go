@@ -0,0 +1,778 @@
+package ovn
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+
+	goovn "github.com/ebay/go-ovn"
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	nadutils "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/utils"
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/factory"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/ipallocator"
+	lsm "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/logical_switch_manager"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/ovn/subnetallocator"
+	ovntypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+)
+
+// secondaryNetwork is a secondary OVN network shared by the
+// NetworkAttachmentDefinitions whose CNI config has the same network name.
+//
+// A layer2 network is a single cluster-wide logical switch whose subnets are
+// used as a whole for the pods of all the nodes. A layer3 network is a logical
+// router connecting a logical switch per node, each of them getting a host
+// subnet allocated from the subnets of the network when the first pod of the
+// node is attached to the network.
+type secondaryNetwork struct {
+	sync.Mutex
+
+	netconf *ovncnitypes.NetConf
+	// the NetworkAttachmentDefinitions of the network
+	nads sets.String
+	// the subnets of the network
+	clusterSubnets []config.CIDRNetworkEntry
+	// the allocator of the node subnets of a layer3 network
+	subnetAllocator *subnetallocator.SubnetAllocator
+	// the IPAM of the logical switches of the network, keyed by switch name
+	lsManager *lsm.LogicalSwitchManager
+	// the logical switches of the nodes of a layer3 network, keyed by node name
+	nodeSwitches map[string]string
+}
+
+// secondaryNetworkPort is the logical port of a pod on a secondary network
+type secondaryNetworkPort struct {
+	network    *secondaryNetwork
+	nadName    string
+	switchName string
+	portName   string
+	annotation *util.PodAnnotation
+	// the addresses of the port were allocated by this call of addLogicalPort
+	// and must be released if it fails
+	allocated bool
+}
+
+func (n *secondaryNetwork) name() string {
+	return n.netconf.Name
+}
+
+func (n *secondaryNetwork) prefix() string {
+	return util.GetSecondaryNetworkPrefix(n.netconf.Name)
+}
+
+func (n *secondaryNetwork) routerName() string {
+	return n.prefix() + ovntypes.OVNClusterRouter
+}
+
+func (n *secondaryNetwork) layer2SwitchName() string {
+	return n.prefix() + ovntypes.OvnLayer2Switch
+}
+
+func (n *secondaryNetwork) nodeSwitchName(nodeName string) string {
+	return n.prefix() + nodeName
+}
+
+// switchNames returns all the logical switches of the network
+func (n *secondaryNetwork) switchNames() []string {
+	if n.netconf.Topology == ovntypes.Layer2Topology {
+		return []string{n.layer2SwitchName()}
+	}
+	switchNames := make([]string, 0, len(n.nodeSwitches))
+	for _, switchName := range n.nodeSwitches {
+		switchNames = append(switchNames, switchName)
+	}
+	return switchNames
+}
+
+// sameConfig returns whether the CNI config of a NetworkAttachmentDefinition
+// describes the network
+func (n *secondaryNetwork) sameConfig(netconf *ovncnitypes.NetConf) bool {
+	return n.netconf.Topology == netconf.Topology && n.netconf.Subnets == netconf.Subnets &&
+		n.netconf.MTU == netconf.MTU
+}
+
+// WatchNetworkAttachmentDefinitions starts the watching of the
+// NetworkAttachmentDefinitions and sets up the secondary networks they describe
+func (oc *Controller) WatchNetworkAttachmentDefinitions() *factory.Handler {
+	return oc.watchFactory.AddNetworkAttachmentDefinitionHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			nad := obj.(*nettypes.NetworkAttachmentDefinition)
+			if err := oc.addNetworkAttachmentDefinition(nad); err != nil {
+				klog.Error(err)
+			}
+		},
+		UpdateFunc: func(old, newer interface{}) {
+			oldNAD := old.(*nettypes.NetworkAttachmentDefinition)
+			newNAD := newer.(*nettypes.NetworkAttachmentDefinition)
+			if reflect.DeepEqual(oldNAD.Spec, newNAD.Spec) {
+				return
+			}
+			oc.deleteNetworkAttachmentDefinition(oldNAD)
+			if err := oc.addNetworkAttachmentDefinition(newNAD); err != nil {
+				klog.Error(err)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			nad := obj.(*nettypes.NetworkAttachmentDefinition)
+			oc.deleteNetworkAttachmentDefinition(nad)
+		},
+	}, oc.syncNetworkAttachmentDefinitions)
+}
+
+// syncNetworkAttachmentDefinitions removes the logical switches and routers of
+// the secondary networks which are not described by any
+// NetworkAttachmentDefinition anymore
+func (oc *Controller) syncNetworkAttachmentDefinitions(nads []interface{}) {
+	networks := sets.NewString()
+	for _, obj := range nads {
+		nad, ok := obj.(*nettypes.NetworkAttachmentDefinition)
+		if !ok {
+			klog.Errorf("Spurious object in syncNetworkAttachmentDefinitions: %v", obj)
+			continue
+		}
+		netconf, err := util.ParseNetConf(nad)
+		if err != nil {
+			continue
+		}
+		networks.Insert(netconf.Name)
+	}
+
+	var args []string
+	for _, table := range []string{"logical_switch", "logical_router"} {
+		rows, err := util.RunOVNNbctlCSV([]string{"--data=bare", "--columns=name,external_ids", "find", table})
+		if err != nil {
+			klog.Errorf("Cannot sync secondary networks, failed to list the %ss: %v", table, err)
+			return
+		}
+		for _, row := range rows {
+			if len(row) != 2 {
+				klog.Errorf("Invalid row returned when listing the %ss: %#v", table, row)
+				continue
+			}
+			externalIDs := parseBareExternalIDs(row[1])
+			netName, ok := externalIDs[ovntypes.NetworkNameExternalID]
+			if !ok || networks.Has(netName) {
+				continue
+			}
+			klog.Infof("Stale %s %s of secondary network %s found, it will be deleted", table, row[0], netName)
+			if table == "logical_switch" {
+				args = append(args, "--", "--if-exists", "ls-del", row[0])
+			} else {
+				args = append(args, "--", "--if-exists", "lr-del", row[0])
+			}
+		}
+	}
+	if len(args) == 0 {
+		return
+	}
+	if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+		klog.Errorf("Failed to delete the stale secondary networks, stderr: %q, error: %v", stderr, err)
+	}
+}
+
+// parseBareExternalIDs parses the external_ids column of a row listed with
+// --data=bare, i.e. space separated key=value pairs
+func parseBareExternalIDs(column string) map[string]string {
+	externalIDs := make(map[string]string)
+	for _, pair := range strings.Fields(column) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 {
+			externalIDs[kv[0]] = kv[1]
+		}
+	}
+	return externalIDs
+}
+
+// getSecondaryNetwork returns the secondary network of a
+// NetworkAttachmentDefinition, or nil if it is not an OVN network
+func (oc *Controller) getSecondaryNetwork(nadName string) *secondaryNetwork {
+	oc.secondaryNetworksMutex.Lock()
+	defer oc.secondaryNetworksMutex.Unlock()
+	return oc.nadNetworks[nadName]
+}
+
+func (oc *Controller) addNetworkAttachmentDefinition(nad *nettypes.NetworkAttachmentDefinition) error {
+	netconf, err := util.ParseNetConf(nad)
+	if err != nil {
+		if err == util.ErrorAttachDefNotOvnManaged {
+			return nil
+		}
+		return err
+	}
+	nadName := util.GetNADName(nad.Namespace, nad.Name)
+
+	oc.secondaryNetworksMutex.Lock()
+	defer oc.secondaryNetworksMutex.Unlock()
+	network, ok := oc.secondaryNetworks[netconf.Name]
+	if ok {
+		if !network.sameConfig(netconf) {
+			return fmt.Errorf("net-attach-def %s describes network %s with a config conflicting with net-attach-defs %v",
+				nadName, netconf.Name, network.nads.List())
+		}
+	} else {
+		network, err = oc.setupSecondaryNetwork(netconf)
+		if err != nil {
+			return fmt.Errorf("failed to set up network %s of net-attach-def %s: %v", netconf.Name, nadName, err)
+		}
+		oc.secondaryNetworks[netconf.Name] = network
+	}
+	klog.Infof("Adding net-attach-def %s to secondary network %s", nadName, netconf.Name)
+	network.Lock()
+	defer network.Unlock()
+	network.nads.Insert(nadName)
+	oc.nadNetworks[nadName] = network
+	oc.reserveSecondaryNetworkPodIPs(network, nadName)
+	return nil
+}
+
+func (oc *Controller) deleteNetworkAttachmentDefinition(nad *nettypes.NetworkAttachmentDefinition) {
+	nadName := util.GetNADName(nad.Namespace, nad.Name)
+
+	oc.secondaryNetworksMutex.Lock()
+	defer oc.secondaryNetworksMutex.Unlock()
+	network, ok := oc.nadNetworks[nadName]
+	if !ok {
+		return
+	}
+	klog.Infof("Deleting net-attach-def %s of secondary network %s", nadName, network.name())
+	delete(oc.nadNetworks, nadName)
+	network.Lock()
+	defer network.Unlock()
+	network.nads.Delete(nadName)
+	if network.nads.Len() > 0 {
+		return
+	}
+	if err := oc.teardownSecondaryNetwork(network); err != nil {
+		klog.Errorf("Failed to delete secondary network %s: %v", network.name(), err)
+	}
+	delete(oc.secondaryNetworks, network.name())
+}
+
+// setupSecondaryNetwork creates the logical entities of a secondary network and
+// recovers the state of the network from them and from the pod annotations
+func (oc *Controller) setupSecondaryNetwork(netconf *ovncnitypes.NetConf) (*secondaryNetwork, error) {
+	clusterSubnets, err := util.ParseNetworkSubnets(netconf)
+	if err != nil {
+		return nil, err
+	}
+	network := &secondaryNetwork{
+		netconf:        netconf,
+		nads:           sets.NewString(),
+		clusterSubnets: clusterSubnets,
+		lsManager:      lsm.NewLogicalSwitchManager(),
+		nodeSwitches:   make(map[string]string),
+	}
+	externalIDs := []string{
+		"external_ids:" + ovntypes.NetworkNameExternalID + "=" + netconf.Name,
+		"external_ids:" + ovntypes.NetworkTopoExternalID + "=" + netconf.Topology,
+	}
+
+	if netconf.Topology == ovntypes.Layer2Topology {
+		switchName := network.layer2SwitchName()
+		args := append([]string{"--may-exist", "ls-add", switchName, "--", "set", "logical_switch", switchName}, externalIDs...)
+		if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+			return nil, fmt.Errorf("failed to create logical switch %s, stderr: %q, error: %v", switchName, stderr, err)
+		}
+		var subnets []*net.IPNet
+		for _, clusterSubnet := range clusterSubnets {
+			subnets = append(subnets, clusterSubnet.CIDR)
+		}
+		if err := network.lsManager.AddNode(switchName, subnets); err != nil {
+			return nil, err
+		}
+	} else {
+		network.subnetAllocator = subnetallocator.NewSubnetAllocator()
+		for _, clusterSubnet := range clusterSubnets {
+			if err := network.subnetAllocator.AddNetworkRange(clusterSubnet.CIDR, clusterSubnet.HostSubnetLength); err != nil {
+				return nil, err
+			}
+		}
+		routerName := network.routerName()
+		args := append([]string{"--may-exist", "lr-add", routerName, "--", "set", "logical_router", routerName}, externalIDs...)
+		if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+			return nil, fmt.Errorf("failed to create logical router %s, stderr: %q, error: %v", routerName, stderr, err)
+		}
+		if err := oc.recoverSecondaryNetworkNodeSwitches(network); err != nil {
+			return nil, err
+		}
+	}
+
+	oc.deleteStaleSecondaryNetworkPorts(network)
+	return network, nil
+}
+
+// recoverSecondaryNetworkNodeSwitches restores the node subnets of a layer3
+// network from the external IDs of its node switches
+func (oc *Controller) recoverSecondaryNetworkNodeSwitches(network *secondaryNetwork) error {
+	rows, err := util.RunOVNNbctlCSV([]string{"--data=bare", "--columns=name,external_ids", "find", "logical_switch",
+		"external_ids:" + ovntypes.NetworkNameExternalID + "=" + network.name()})
+	if err != nil {
+		return fmt.Errorf("failed to list the logical switches of network %s: %v", network.name(), err)
+	}
+	for _, row := range rows {
+		if len(row) != 2 {
+			return fmt.Errorf("invalid row returned when listing the logical switches of network %s: %#v", network.name(), row)
+		}
+		externalIDs := parseBareExternalIDs(row[1])
+		nodeName := externalIDs[ovntypes.NodeNameExternalID]
+		var hostSubnets []*net.IPNet
+		for _, subnet := range strings.Split(externalIDs[ovntypes.NodeSubnetsExternalID], ",") {
+			_, hostSubnet, err := net.ParseCIDR(subnet)
+			if err != nil {
+				return fmt.Errorf("invalid subnet %q of logical switch %s: %v", subnet, row[0], err)
+			}
+			if err := network.subnetAllocator.MarkAllocatedNetwork(hostSubnet); err != nil {
+				return err
+			}
+			hostSubnets = append(hostSubnets, hostSubnet)
+		}
+		if err := network.lsManager.AddNode(row[0], hostSubnets); err != nil {
+			return err
+		}
+		network.nodeSwitches[nodeName] = row[0]
+	}
+	return nil
+}
+
+// deleteStaleSecondaryNetworkPorts deletes the logical ports of the pods which
+// are not attached to the network anymore
+func (oc *Controller) deleteStaleSecondaryNetworkPorts(network *secondaryNetwork) {
+	pods, err := oc.watchFactory.GetAllPods()
+	if err != nil {
+		klog.Errorf("Failed to get pods: %v", err)
+		return
+	}
+	expectedLogicalPorts := sets.NewString()
+	for _, pod := range pods {
+		if !util.PodScheduled(pod) || !util.PodWantsNetwork(pod) {
+			continue
+		}
+		podAnnotations, err := util.UnmarshalPodAnnotationAllNetworks(pod.Annotations)
+		if err != nil {
+			continue
+		}
+		for nadName := range podAnnotations {
+			if nadName != util.OvnPodDefaultNetwork {
+				expectedLogicalPorts.Insert(util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName))
+			}
+		}
+	}
+
+	for _, switchName := range network.switchNames() {
+		ports, err := oc.ovnNBClient.LSPList(switchName)
+		if err != nil {
+			klog.Errorf("Failed to list lsp for switch %s: error %v", switchName, err)
+			continue
+		}
+		for _, port := range ports {
+			if port.ExternalID["pod"] != "true" || expectedLogicalPorts.Has(port.Name) {
+				continue
+			}
+			klog.Infof("Stale logical port found: %s. This logical port will be deleted.", port.Name)
+			if err := util.OvnNBLSPDel(oc.ovnNBClient, port.Name); err != nil {
+				klog.Errorf(err.Error())
+			}
+		}
+	}
+}
+
+// reserveSecondaryNetworkPodIPs reserves the IPs of the pods already attached
+// to the network through a NetworkAttachmentDefinition. It must be called with
+// the network lock held.
+func (oc *Controller) reserveSecondaryNetworkPodIPs(network *secondaryNetwork, nadName string) {
+	pods, err := oc.watchFactory.GetAllPods()
+	if err != nil {
+		klog.Errorf("Failed to get pods: %v", err)
+		return
+	}
+	for _, pod := range pods {
+		if !util.PodScheduled(pod) || !util.PodWantsNetwork(pod) {
+			continue
+		}
+		annotation, err := util.UnmarshalPodAnnotationForNetwork(pod.Annotations, nadName)
+		if err != nil {
+			continue
+		}
+		switchName := network.layer2SwitchName()
+		if network.netconf.Topology == ovntypes.Layer3Topology {
+			if switchName = network.nodeSwitches[pod.Spec.NodeName]; switchName == "" {
+				continue
+			}
+		}
+		if err = network.lsManager.AllocateIPs(switchName, annotation.IPs); err != nil && err != ipallocator.ErrAllocated {
+			klog.Errorf("Couldn't allocate IPs: %s for pod: %s/%s on network: %s error: %v",
+				util.JoinIPNetIPs(annotation.IPs, " "), pod.Namespace, pod.Name, network.name(), err)
+		}
+	}
+}
+
+// teardownSecondaryNetwork deletes the logical entities of a secondary network.
+// It must be called with the network lock held.
+func (oc *Controller) teardownSecondaryNetwork(network *secondaryNetwork) error {
+	klog.Infof("Deleting secondary network %s", network.name())
+	var args []string
+	for _, switchName := range network.switchNames() {
+		args = append(args, "--", "--if-exists", "ls-del", switchName)
+	}
+	if network.netconf.Topology == ovntypes.Layer3Topology {
+		args = append(args, "--", "--if-exists", "lr-del", network.routerName())
+	}
+	if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+		return fmt.Errorf("failed to delete the logical entities, stderr: %q, error: %v", stderr, err)
+	}
+	return nil
+}
+
+// ensureSecondaryNetworkNodeSwitch returns the logical switch of a node on a
+// layer3 network, creating it and connecting it to the router of the network
+// if needed. It must be called with the network lock held.
+func (oc *Controller) ensureSecondaryNetworkNodeSwitch(network *secondaryNetwork, nodeName string) (switchName string, err error) {
+	if switchName, ok := network.nodeSwitches[nodeName]; ok {
+		return switchName, nil
+	}
+
+	hostSubnets, err := network.subnetAllocator.AllocateNetworks()
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate a subnet for node %s on network %s: %v", nodeName, network.name(), err)
+	}
+	defer func() {
+		if err != nil {
+			for _, hostSubnet := range hostSubnets {
+				if relErr := network.subnetAllocator.ReleaseNetwork(hostSubnet); relErr != nil {
+					klog.Errorf("Error releasing subnet %s of network %s: %v", hostSubnet, network.name(), relErr)
+				}
+			}
+		}
+	}()
+
+	// logical router port MAC is based on IPv4 subnet if there is one, else IPv6
+	var nodeLRPMAC net.HardwareAddr
+	for _, hostSubnet := range hostSubnets {
+		gwIfAddr := util.GetNodeGatewayIfAddr(hostSubnet)
+		nodeLRPMAC = util.IPAddrToHWAddr(gwIfAddr.IP)
+		if !utilnet.IsIPv6CIDR(hostSubnet) {
+			break
+		}
+	}
+
+	switchName = network.nodeSwitchName(nodeName)
+	lrpName := ovntypes.RouterToSwitchPrefix + switchName
+	lspName := ovntypes.SwitchToRouterPrefix + switchName
+	args := []string{
+		"--may-exist", "ls-add", switchName,
+		"--", "set", "logical_switch", switchName,
+		"external_ids:" + ovntypes.NetworkNameExternalID + "=" + network.name(),
+		"external_ids:" + ovntypes.NetworkTopoExternalID + "=" + network.netconf.Topology,
+		"external_ids:" + ovntypes.NodeNameExternalID + "=" + nodeName,
+		"external_ids:" + ovntypes.NodeSubnetsExternalID + "=" + util.JoinIPNets(hostSubnets, ","),
+		"--", "--may-exist", "lrp-add", network.routerName(), lrpName, nodeLRPMAC.String(),
+	}
+	for _, hostSubnet := range hostSubnets {
+		args = append(args, util.GetNodeGatewayIfAddr(hostSubnet).String())
+	}
+	args = append(args,
+		"--", "--may-exist", "lsp-add", switchName, lspName,
+		"--", "lsp-set-type", lspName, "router",
+		"--", "lsp-set-options", lspName, "router-port="+lrpName,
+		"--", "lsp-set-addresses", lspName, "router")
+	if _, stderr, err := util.RunOVNNbctl(args...); err != nil {
+		return "", fmt.Errorf("failed to create logical switch %s, stderr: %q, error: %v", switchName, stderr, err)
+	}
+
+	if err = network.lsManager.AddNode(switchName, hostSubnets); err != nil {
+		return "", err
+	}
+	network.nodeSwitches[nodeName] = switchName
+	return switchName, nil
+}
+
+// deleteSecondaryNetworksNode deletes the logical switches of a node on the
+// layer3 networks
+func (oc *Controller) deleteSecondaryNetworksNode(nodeName string) {
+	oc.secondaryNetworksMutex.Lock()
+	defer oc.secondaryNetworksMutex.Unlock()
+	for _, network := range oc.secondaryNetworks {
+		network.Lock()
+		switchName, ok := network.nodeSwitches[nodeName]
+		if !ok {
+			network.Unlock()
+			continue
+		}
+		if _, stderr, err := util.RunOVNNbctl("--if-exists", "ls-del", switchName,
+			"--", "--if-exists", "lrp-del", ovntypes.RouterToSwitchPrefix+switchName); err != nil {
+			klog.Errorf("Failed to delete logical switch %s of node %s on network %s, stderr: %q, error: %v",
+				switchName, nodeName, network.name(), stderr, err)
+			network.Unlock()
+			continue
+		}
+		for _, hostSubnet := range network.lsManager.GetSwitchSubnets(switchName) {
+			if err := network.subnetAllocator.ReleaseNetwork(hostSubnet); err != nil {
+				klog.Errorf("Error releasing subnet %s of network %s: %v", hostSubnet, network.name(), err)
+			}
+		}
+		network.lsManager.DeleteNode(switchName)
+		delete(network.nodeSwitches, nodeName)
+		network.Unlock()
+	}
+}
+
+// getPodSecondaryNetworks returns the NetworkAttachmentDefinitions of the
+// secondary OVN networks requested by the pod, the attachments to the other
+// networks are left to their own CNI plugins
+func (oc *Controller) getPodSecondaryNetworks(pod *kapi.Pod) ([]*nettypes.NetworkSelectionElement, error) {
+	if _, ok := pod.Annotations[nettypes.NetworkAttachmentAnnot]; !ok {
+		return nil, nil
+	}
+	networks, err := nadutils.ParsePodNetworkAnnotation(pod)
+	if err != nil {
+		return nil, err
+	}
+	var ovnNetworks []*nettypes.NetworkSelectionElement
+	nadNames := sets.NewString()
+	for _, network := range networks {
+		nadName := util.GetNADName(network.Namespace, network.Name)
+		if oc.getSecondaryNetwork(nadName) == nil {
+			nad, err := oc.watchFactory.GetNetworkAttachmentDefinition(network.Namespace, network.Name)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("net-attach-def %s requested by pod %s/%s not found", nadName, pod.Namespace, pod.Name)
+				}
+				return nil, err
+			}
+			if _, err := util.ParseNetConf(nad); err == util.ErrorAttachDefNotOvnManaged {
+				continue
+			}
+			return nil, fmt.Errorf("secondary network of net-attach-def %s requested by pod %s/%s is not ready",
+				nadName, pod.Namespace, pod.Name)
+		}
+		if nadNames.Has(nadName) {
+			klog.Warningf("Pod %s/%s requests net-attach-def %s more than once, only one interface is attached",
+				pod.Namespace, pod.Name, nadName)
+			continue
+		}
+		nadNames.Insert(nadName)
+		ovnNetworks = append(ovnNetworks, network)
+	}
+	return ovnNetworks, nil
+}
+
+// allocatePodSecondaryNetworkPorts reserves or allocates the addresses of the
+// pod on each of the secondary OVN networks it requests
+func (oc *Controller) allocatePodSecondaryNetworkPorts(pod *kapi.Pod) (ports []*secondaryNetworkPort, err error) {
+	networks, err := oc.getPodSecondaryNetworks(pod)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			oc.releasePodSecondaryNetworkPorts(ports)
+			ports = nil
+		}
+	}()
+	for _, element := range networks {
+		nadName := util.GetNADName(element.Namespace, element.Name)
+		network := oc.getSecondaryNetwork(nadName)
+		if network == nil {
+			return ports, fmt.Errorf("secondary network of net-attach-def %s was deleted", nadName)
+		}
+		port, err := oc.allocatePodSecondaryNetworkPort(pod, network, nadName, element)
+		if err != nil {
+			return ports, err
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+func (oc *Controller) allocatePodSecondaryNetworkPort(pod *kapi.Pod, network *secondaryNetwork, nadName string,
+	element *nettypes.NetworkSelectionElement) (*secondaryNetworkPort, error) {
+	network.Lock()
+	defer network.Unlock()
+
+	port := &secondaryNetworkPort{
+		network:    network,
+		nadName:    nadName,
+		switchName: network.layer2SwitchName(),
+		portName:   util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName),
+	}
+	if network.netconf.Topology == ovntypes.Layer3Topology {
+		var err error
+		if port.switchName, err = oc.ensureSecondaryNetworkNodeSwitch(network, pod.Spec.NodeName); err != nil {
+			return nil, err
+		}
+	}
+
+	annotation, err := util.UnmarshalPodAnnotationForNetwork(pod.Annotations, nadName)
+	if err == nil {
+		// ensure we have reserved the IPs in the annotation
+		if err = network.lsManager.AllocateIPs(port.switchName, annotation.IPs); err != nil && err != ipallocator.ErrAllocated {
+			return nil, fmt.Errorf("unable to ensure IPs allocated for already annotated pod: %s on network: %s, IPs: %s, error: %v",
+				pod.Name, network.name(), util.JoinIPNetIPs(annotation.IPs, " "), err)
+		}
+		port.annotation = annotation
+		return port, nil
+	}
+
+	annotation = &util.PodAnnotation{}
+	if len(element.IPRequest) > 0 {
+		for _, ipRequest := range element.IPRequest {
+			ip, ipNet, err := net.ParseCIDR(ipRequest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse IP %s requested in annotation for pod %s: %v", ipRequest, pod.Name, err)
+			}
+			ipNet.IP = ip
+			annotation.IPs = append(annotation.IPs, ipNet)
+		}
+		if err = network.lsManager.AllocateIPs(port.switchName, annotation.IPs); err != nil {
+			return nil, fmt.Errorf("failed to allocate IPs %s requested by pod %s on network %s: %v",
+				util.JoinIPNetIPs(annotation.IPs, " "), pod.Name, network.name(), err)
+		}
+	} else if annotation.IPs, err = network.lsManager.AllocateNextIPs(port.switchName); err != nil {
+		return nil, fmt.Errorf("failed to assign pod addresses for pod %s on network %s: %v", pod.Name, network.name(), err)
+	}
+	port.allocated = true
+
+	if element.MacRequest != "" {
+		klog.V(5).Infof("Pod %s/%s requested custom MAC: %s on network %s", pod.Namespace, pod.Name, element.MacRequest, network.name())
+		if annotation.MAC, err = net.ParseMAC(element.MacRequest); err != nil {
+			return port, fmt.Errorf("failed to parse mac %s requested in annotation for pod %s: Error %v",
+				element.MacRequest, pod.Name, err)
+		}
+	} else if len(annotation.IPs) > 0 {
+		annotation.MAC = util.IPAddrToHWAddr(annotation.IPs[0].IP)
+	}
+
+	// a layer3 network is only reachable through the gateway of the node
+	// switch, secondary networks never get the default route of the pod
+	if network.netconf.Topology == ovntypes.Layer3Topology {
+		for _, nodeSubnet := range network.lsManager.GetSwitchSubnets(port.switchName) {
+			gwIfAddr := util.GetNodeGatewayIfAddr(nodeSubnet)
+			for _, clusterSubnet := range network.clusterSubnets {
+				if utilnet.IsIPv6CIDR(clusterSubnet.CIDR) == utilnet.IsIPv6CIDR(nodeSubnet) {
+					annotation.Routes = append(annotation.Routes, util.PodRoute{
+						Dest:    clusterSubnet.CIDR,
+						NextHop: gwIfAddr.IP,
+					})
+				}
+			}
+		}
+	}
+	port.annotation = annotation
+	return port, nil
+}
+
+// releasePodSecondaryNetworkPorts releases the addresses allocated to the pod
+// on its secondary networks
+func (oc *Controller) releasePodSecondaryNetworkPorts(ports []*secondaryNetworkPort) {
+	for _, port := range ports {
+		if !port.allocated || port.annotation == nil {
+			continue
+		}
+		if err := port.network.lsManager.ReleaseIPs(port.switchName, port.annotation.IPs); err != nil {
+			klog.Errorf("Error when releasing IPs for switch: %s, err: %q", port.switchName, err)
+		}
+	}
+}
+
+// getSecondaryNetworkPortCmds returns the commands creating the logical port of
+// the pod on a secondary network
+func (oc *Controller) getSecondaryNetworkPortCmds(pod *kapi.Pod, port *secondaryNetworkPort) ([]*goovn.OvnCommand, error) {
+	var cmds []*goovn.OvnCommand
+
+	lsp, err := oc.ovnNBClient.LSPGet(port.portName)
+	if err != nil && err != goovn.ErrorNotFound && err != goovn.ErrorSchema {
+		return nil, fmt.Errorf("unable to get the lsp: %s from the nbdb: %s", port.portName, err)
+	}
+	opts := map[string]string{"requested-chassis": pod.Spec.NodeName}
+	if lsp == nil {
+		cmd, err := oc.ovnNBClient.LSPAdd(port.switchName, port.portName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the LSPAdd command for port: %s from the nbdb: %v", port.portName, err)
+		}
+		cmds = append(cmds, cmd)
+		opts["iface-id-ver"] = string(pod.UID)
+	} else if existingOpts, err := oc.ovnNBClient.LSPGetOptions(port.portName); err == nil && existingOpts != nil {
+		if ver, ok := existingOpts["iface-id-ver"]; ok {
+			opts["iface-id-ver"] = ver
+		}
+	}
+
+	cmd, err := oc.ovnNBClient.LSPSetOptions(port.portName, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the LSPSetOptions command for port: %s from the nbdb: %v", port.portName, err)
+	}
+	cmds = append(cmds, cmd)
+
+	addresses := []string{port.annotation.MAC.String()}
+	for _, podIfAddr := range port.annotation.IPs {
+		addresses = append(addresses, podIfAddr.IP.String())
+	}
+	cmd, err = oc.ovnNBClient.LSPSetAddress(port.portName, strings.Join(addresses, " "))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create LSPSetAddress command for port: %s", port.portName)
+	}
+	cmds = append(cmds, cmd)
+
+	extIds := map[string]string{
+		"namespace":                    pod.Namespace,
+		"pod":                          "true",
+		ovntypes.NetworkNameExternalID: port.network.name(),
+		ovntypes.NADNameExternalID:     port.nadName,
+	}
+	cmd, err = oc.ovnNBClient.LSPSetExternalIds(port.portName, extIds)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create LSPSetExternalIds command for port: %s", port.portName)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd, err = oc.ovnNBClient.LSPSetPortSecurity(port.portName, strings.Join(addresses, " "))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create LSPSetPortSecurity command for port: %s", port.portName)
+	}
+	cmds = append(cmds, cmd)
+	return cmds, nil
+}
+
+// deletePodSecondaryNetworkPorts deletes the logical ports of the pod on its
+// secondary networks and releases their addresses
+func (oc *Controller) deletePodSecondaryNetworkPorts(pod *kapi.Pod) {
+	podAnnotations, err := util.UnmarshalPodAnnotationAllNetworks(pod.Annotations)
+	if err != nil {
+		return
+	}
+	for nadName, annotation := range podAnnotations {
+		if nadName == util.OvnPodDefaultNetwork {
+			continue
+		}
+		portName := util.GetSecondaryNetworkLogicalPortName(pod.Namespace, pod.Name, nadName)
+		if err := util.OvnNBLSPDel(oc.ovnNBClient, portName); err != nil {
+			klog.Errorf(err.Error())
+		}
+		network := oc.getSecondaryNetwork(nadName)
+		if network == nil {
+			continue
+		}
+		network.Lock()
+		switchName := network.layer2SwitchName()
+		if network.netconf.Topology == ovntypes.Layer3Topology {
+			switchName = network.nodeSwitches[pod.Spec.NodeName]
+		}
+		if switchName != "" {
+			if err := network.lsManager.ReleaseIPs(switchName, annotation.IPs); err != nil {
+				klog.Errorf(err.Error())
+			}
+		}
+		network.Unlock()
+	}
+}
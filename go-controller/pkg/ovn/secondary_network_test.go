@@ -0,0 +1,212 @@
+package ovn
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	goovn "github.com/ebay/go-ovn"
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newNetworkAttachmentDefinition(namespace, name, netName, topology, subnets string) *nettypes.NetworkAttachmentDefinition {
+	return &nettypes.NetworkAttachmentDefinition{
+		ObjectMeta: newObjectMeta(name, namespace),
+		Spec: nettypes.NetworkAttachmentDefinitionSpec{
+			Config: fmt.Sprintf(`{"cniVersion": "0.4.0", "name": %q, "type": "ovn-k8s-cni-overlay", "topology": %q, "netAttachDefName": "%s/%s", "subnets": %q}`,
+				netName, topology, namespace, name, subnets),
+		},
+	}
+}
+
+func newPodWithNetworks(namespace, name, node, networks string) *v1.Pod {
+	pod := newPod(namespace, name, node, "")
+	pod.Annotations = map[string]string{nettypes.NetworkAttachmentAnnot: networks}
+	return pod
+}
+
+// secondaryNetworkSyncCmds adds the commands listing the secondary networks
+// logical switches and routers at startup
+func secondaryNetworkSyncCmds(fExec *ovntest.FakeExec, switches, routers string) {
+	fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+		Cmd:    "ovn-nbctl --timeout=15 --no-heading --format=csv --data=bare --columns=name,external_ids find logical_switch",
+		Output: switches,
+	})
+	fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+		Cmd:    "ovn-nbctl --timeout=15 --no-heading --format=csv --data=bare --columns=name,external_ids find logical_router",
+		Output: routers,
+	})
+}
+
+var _ = ginkgo.Describe("OVN secondary networks", func() {
+	var (
+		app     *cli.App
+		fakeOvn *FakeOVN
+		fExec   *ovntest.FakeExec
+	)
+
+	const (
+		namespaceName = "namespace1"
+		nodeName      = "node1"
+		podName       = "myPod"
+	)
+
+	ginkgo.BeforeEach(func() {
+		// Restore global default values before each testcase
+		config.PrepareTestConfig()
+		config.OVNKubernetesFeature.EnableMultiNetwork = true
+
+		app = cli.NewApp()
+		app.Name = "test"
+		app.Flags = config.Flags
+
+		fExec = ovntest.NewLooseCompareFakeExec()
+		fakeOvn = NewFakeOVN(fExec)
+	})
+
+	ginkgo.AfterEach(func() {
+		fakeOvn.shutdown()
+	})
+
+	startWithNAD := func(ctx *cli.Context, nads ...nettypes.NetworkAttachmentDefinition) {
+		namespaceT := *newNamespace(namespaceName)
+		fakeOvn.start(ctx,
+			&v1.NamespaceList{Items: []v1.Namespace{namespaceT}},
+			&v1.PodList{Items: []v1.Pod{}},
+			&nettypes.NetworkAttachmentDefinitionList{Items: nads},
+		)
+		fakeOvn.controller.lsManager.AddNode(nodeName, []*net.IPNet{ovntest.MustParseIPNet("10.128.1.0/24")})
+		fakeOvn.controller.WatchNamespaces()
+		fakeOvn.controller.nadHandler = fakeOvn.controller.WatchNetworkAttachmentDefinitions()
+		fakeOvn.controller.WatchPods()
+	}
+
+	ginkgo.It("attaches a pod to a layer2 network and detaches it on deletion", func() {
+		app.Action = func(ctx *cli.Context) error {
+			secondaryNetworkSyncCmds(fExec, "", "")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --may-exist ls-add l2-net_ovn_layer2_switch -- set logical_switch l2-net_ovn_layer2_switch " +
+					"external_ids:k8s-network=l2-net external_ids:k8s-topology=layer2",
+			})
+			startWithNAD(ctx, *newNetworkAttachmentDefinition(namespaceName, "l2", "l2-net", "layer2", "10.100.0.0/16"))
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(namespaceName).Create(context.TODO(),
+				newPodWithNetworks(namespaceName, podName, nodeName, "l2"), metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, namespaceName, podName) }, 2).Should(gomega.MatchJSON(
+				`{"default": {"ip_addresses":["10.128.1.3/24"], "mac_address":"0a:58:0a:80:01:03", "gateway_ips": ["10.128.1.1"], "ip_address":"10.128.1.3/24", "gateway_ip": "10.128.1.1"},` +
+					`"namespace1/l2": {"ip_addresses":["10.100.0.3/16"], "mac_address":"0a:58:0a:64:00:03", "ip_address":"10.100.0.3/16"}}`))
+
+			portName := util.GetSecondaryNetworkLogicalPortName(namespaceName, podName, "namespace1/l2")
+			gomega.Eventually(func() error {
+				_, err := fakeOvn.ovnNBClient.LSPGet(portName)
+				return err
+			}).ShouldNot(gomega.HaveOccurred())
+			lsp, err := fakeOvn.ovnNBClient.LSPGet(portName)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(lsp.Addresses).To(gomega.Equal([]string{"0a:58:0a:64:00:03 10.100.0.3"}))
+			gomega.Expect(lsp.ExternalID).To(gomega.HaveKeyWithValue("k8s-nad", "namespace1/l2"))
+
+			err = fakeOvn.fakeClient.KubeClient.CoreV1().Pods(namespaceName).Delete(context.TODO(), podName, *metav1.NewDeleteOptions(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(func() error {
+				_, err := fakeOvn.ovnNBClient.LSPGet(portName)
+				return err
+			}).Should(gomega.Equal(goovn.ErrorNotFound))
+
+			// the address of the deleted pod is available again
+			network := fakeOvn.controller.getSecondaryNetwork("namespace1/l2")
+			gomega.Expect(network).NotTo(gomega.BeNil())
+			err = network.lsManager.AllocateIPs("l2-net_ovn_layer2_switch", []*net.IPNet{ovntest.MustParseIPNet("10.100.0.3/16")})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("creates the node switch of a layer3 network for the first pod of the node", func() {
+		app.Action = func(ctx *cli.Context) error {
+			secondaryNetworkSyncCmds(fExec, "", "")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --may-exist lr-add l3-net_ovn_cluster_router -- set logical_router l3-net_ovn_cluster_router " +
+					"external_ids:k8s-network=l3-net external_ids:k8s-topology=layer3",
+			})
+			fExec.AddFakeCmd(&ovntest.ExpectedCmd{
+				Cmd: "ovn-nbctl --timeout=15 --no-heading --format=csv --data=bare --columns=name,external_ids find logical_switch external_ids:k8s-network=l3-net",
+			})
+			startWithNAD(ctx, *newNetworkAttachmentDefinition(namespaceName, "l3", "l3-net", "layer3", "10.200.0.0/16/24"))
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 --may-exist ls-add l3-net_node1 -- set logical_switch l3-net_node1 " +
+					"external_ids:k8s-network=l3-net external_ids:k8s-topology=layer3 external_ids:k8s-node=node1 external_ids:k8s-node-subnets=10.200.0.0/24 " +
+					"-- --may-exist lrp-add l3-net_ovn_cluster_router rtos-l3-net_node1 0a:58:0a:c8:00:01 10.200.0.1/24 " +
+					"-- --may-exist lsp-add l3-net_node1 stor-l3-net_node1 -- lsp-set-type stor-l3-net_node1 router " +
+					"-- lsp-set-options stor-l3-net_node1 router-port=rtos-l3-net_node1 -- lsp-set-addresses stor-l3-net_node1 router",
+			})
+			_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(namespaceName).Create(context.TODO(),
+				newPodWithNetworks(namespaceName, podName, nodeName, `[{"name": "l3", "mac": "0a:58:0a:c8:00:64"}]`), metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, namespaceName, podName) }, 2).Should(gomega.MatchJSON(
+				`{"default": {"ip_addresses":["10.128.1.3/24"], "mac_address":"0a:58:0a:80:01:03", "gateway_ips": ["10.128.1.1"], "ip_address":"10.128.1.3/24", "gateway_ip": "10.128.1.1"},` +
+					`"namespace1/l3": {"ip_addresses":["10.200.0.3/24"], "mac_address":"0a:58:0a:c8:00:64", "ip_address":"10.200.0.3/24", "routes":[{"dest":"10.200.0.0/16","nextHop":"10.200.0.1"}]}}`))
+
+			// deleting the last net-attach-def of the network deletes its logical entities
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 -- --if-exists ls-del l3-net_node1 -- --if-exists lr-del l3-net_ovn_cluster_router",
+			})
+			err = fakeOvn.fakeClient.NetworkAttchDefClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions(namespaceName).Delete(context.TODO(), "l3", *metav1.NewDeleteOptions(0))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+			gomega.Eventually(func() *secondaryNetwork { return fakeOvn.controller.getSecondaryNetwork("namespace1/l3") }).Should(gomega.BeNil())
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("ignores the net-attach-defs of other CNI plugins and removes stale networks at startup", func() {
+		app.Action = func(ctx *cli.Context) error {
+			secondaryNetworkSyncCmds(fExec,
+				"node1,\nold-net_ovn_layer2_switch,k8s-network=old-net k8s-topology=layer2\n",
+				"ovn_cluster_router,k8s-cluster-router=yes\n")
+			fExec.AddFakeCmdsNoOutputNoError([]string{
+				"ovn-nbctl --timeout=15 -- --if-exists ls-del old-net_ovn_layer2_switch",
+			})
+			macvlan := nettypes.NetworkAttachmentDefinition{
+				ObjectMeta: newObjectMeta("macvlan", namespaceName),
+				Spec:       nettypes.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion": "0.4.0", "name": "macvlan-net", "type": "macvlan"}`},
+			}
+			startWithNAD(ctx, macvlan)
+			gomega.Eventually(fExec.CalledMatchesExpected).Should(gomega.BeTrue(), fExec.ErrorDesc)
+
+			// the macvlan attachment is left to its own plugin
+			_, err := fakeOvn.fakeClient.KubeClient.CoreV1().Pods(namespaceName).Create(context.TODO(),
+				newPodWithNetworks(namespaceName, podName, nodeName, "macvlan"), metav1.CreateOptions{})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Eventually(func() string { return getPodAnnotations(fakeOvn.fakeClient.KubeClient, namespaceName, podName) }, 2).Should(gomega.MatchJSON(
+				`{"default": {"ip_addresses":["10.128.1.3/24"], "mac_address":"0a:58:0a:80:01:03", "gateway_ips": ["10.128.1.1"], "ip_address":"10.128.1.3/24", "gateway_ip": "10.128.1.1"}}`))
+			return nil
+		}
+
+		err := app.Run([]string{app.Name})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	})
+})
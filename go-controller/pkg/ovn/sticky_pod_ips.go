@@ -0,0 +1,145 @@
+package ovn
+
+import (
+	"net"
+	"time"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/util"
+
+	kapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Annotation used to keep the addresses of the deleted StatefulSet pods of a
+// namespace for their replacement, its value is the grace period during which
+// they are kept, e.g. "5m"
+const stickyPodIPsAnnotation = "k8s.ovn.org/sticky-pod-ips"
+
+// stickyPodAddresses are the addresses of a deleted pod kept allocated on
+// the switch of its node until its replacement claims them or timer fires
+type stickyPodAddresses struct {
+	nodeName string
+	ips      []*net.IPNet
+	mac      net.HardwareAddr
+	timer    *time.Timer
+}
+
+// getStickyPodIPsGracePeriod returns the grace period during which the
+// addresses of the deleted StatefulSet pods of a namespace are kept, or 0 when
+// the namespace did not opt in
+func getStickyPodIPsGracePeriod(annotations map[string]string) time.Duration {
+	annotation, ok := annotations[stickyPodIPsAnnotation]
+	if !ok {
+		return 0
+	}
+	gracePeriod, err := time.ParseDuration(annotation)
+	if err != nil || gracePeriod < 0 {
+		klog.Warningf("Invalid %s annotation %q, sticky pod IPs are disabled", stickyPodIPsAnnotation, annotation)
+		return 0
+	}
+	return gracePeriod
+}
+
+// isStatefulSetPod returns true if the pod is managed by a StatefulSet, whose
+// replacement pods keep the name of the pod
+func isStatefulSetPod(pod *kapi.Pod) bool {
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == "StatefulSet"
+}
+
+// holdStickyPodAddresses keeps the addresses of a deleted pod allocated for
+// its replacement if it is a StatefulSet pod of a namespace with sticky pod
+// IPs. It returns false when the addresses are not kept and must be released
+// by the caller.
+func (oc *Controller) holdStickyPodAddresses(pod *kapi.Pod, nodeName string, ips []*net.IPNet, mac net.HardwareAddr) bool {
+	if nodeName == "" || len(ips) == 0 || !isStatefulSetPod(pod) {
+		return false
+	}
+	ns, err := oc.watchFactory.GetNamespace(pod.Namespace)
+	if err != nil {
+		return false
+	}
+	gracePeriod := getStickyPodIPsGracePeriod(ns.Annotations)
+	if gracePeriod == 0 {
+		return false
+	}
+
+	key := util.GetLogicalPortName(pod.Namespace, pod.Name)
+	addresses := &stickyPodAddresses{
+		nodeName: nodeName,
+		ips:      ips,
+		mac:      mac,
+	}
+
+	oc.stickyPodAddressesLock.Lock()
+	defer oc.stickyPodAddressesLock.Unlock()
+	if old, ok := oc.stickyPodAddresses[key]; ok {
+		// should not happen as pods are deleted before being recreated
+		old.timer.Stop()
+		if util.JoinIPNetIPs(old.ips, " ") != util.JoinIPNetIPs(ips, " ") {
+			oc.releaseStickyPodAddressesLocked(key, old)
+		}
+	}
+	addresses.timer = time.AfterFunc(gracePeriod, func() {
+		oc.stickyPodAddressesLock.Lock()
+		defer oc.stickyPodAddressesLock.Unlock()
+		// the timer may fire while the addresses are being claimed
+		if oc.stickyPodAddresses[key] == addresses {
+			oc.releaseStickyPodAddressesLocked(key, addresses)
+		}
+	})
+	oc.stickyPodAddresses[key] = addresses
+	klog.Infof("Keeping IPs %s of pod %s/%s on node %s for %v", util.JoinIPNetIPs(ips, " "),
+		pod.Namespace, pod.Name, nodeName, gracePeriod)
+	return true
+}
+
+// releaseStickyPodAddressesLocked releases kept addresses back to the IPAM
+// pool, stickyPodAddressesLock must be held
+func (oc *Controller) releaseStickyPodAddressesLocked(key string, addresses *stickyPodAddresses) {
+	delete(oc.stickyPodAddresses, key)
+	if err := oc.lsManager.ReleaseIPs(addresses.nodeName, addresses.ips); err != nil {
+		klog.Errorf("Failed to release IPs %s kept for pod %s on node %s: %v",
+			util.JoinIPNetIPs(addresses.ips, " "), key, addresses.nodeName, err)
+		return
+	}
+	klog.Infof("Released IPs %s kept for pod %s on node %s", util.JoinIPNetIPs(addresses.ips, " "),
+		key, addresses.nodeName)
+}
+
+// claimStickyPodAddresses returns the addresses kept for a pod, which stay
+// allocated, if it comes back on the same node. Addresses kept on another node
+// are released.
+func (oc *Controller) claimStickyPodAddresses(pod *kapi.Pod, nodeName string) (net.HardwareAddr, []*net.IPNet) {
+	key := util.GetLogicalPortName(pod.Namespace, pod.Name)
+
+	oc.stickyPodAddressesLock.Lock()
+	defer oc.stickyPodAddressesLock.Unlock()
+	addresses, ok := oc.stickyPodAddresses[key]
+	if !ok {
+		return nil, nil
+	}
+	addresses.timer.Stop()
+	if addresses.nodeName != nodeName {
+		oc.releaseStickyPodAddressesLocked(key, addresses)
+		return nil, nil
+	}
+	delete(oc.stickyPodAddresses, key)
+	klog.Infof("Reusing IPs %s kept for pod %s/%s on node %s", util.JoinIPNetIPs(addresses.ips, " "),
+		pod.Namespace, pod.Name, nodeName)
+	return addresses.mac, addresses.ips
+}
+
+// forgetStickyPodAddressesOnNode drops the addresses kept on a deleted node,
+// they are released with the switch of the node
+func (oc *Controller) forgetStickyPodAddressesOnNode(nodeName string) {
+	oc.stickyPodAddressesLock.Lock()
+	defer oc.stickyPodAddressesLock.Unlock()
+	for key, addresses := range oc.stickyPodAddresses {
+		if addresses.nodeName == nodeName {
+			addresses.timer.Stop()
+			delete(oc.stickyPodAddresses, key)
+		}
+	}
+}
@@ -45,6 +45,12 @@ const (
 
 	// ACL Priorities
 
+	// Admin network policy acl rule priorities, above all the other acls so that the rules
+	// of the cluster admin always win. The acl priority of a rule decreases with the priority
+	// of its policy and with its index in the policy
+	AdminNetworkPolicyStartPriority = "30000"
+	// Load balancer source ranges drop acl rule priority, higher than network policies
+	LoadBalancerSourceRangesPriority = "1014"
 	// Default routed multicast allow acl rule priority
 	DefaultRoutedMcastAllowPriority = "1013"
 	// Default multicast allow acl rule priority
@@ -67,6 +73,15 @@ const (
 	DefaultNoRereoutePriority             = "101"
 	EgressIPReroutePriority               = "100"
 
+	// priorities of the ClusterEgressFirewall rules, below the ones of the namespace
+	// EgressFirewall rules and above the ones of all the other acls but the admin network policies
+	ClusterEgressFirewallStartPriority           = "1999"
+	MinimumReservedClusterEgressFirewallPriority = "1015"
+
+	// priority of the QoS rules of the first EgressQoS rule of a namespace, the priority of
+	// the following rules decreases with their index
+	EgressQoSStartPriority = "1000"
+
 	V6NodeLocalNATSubnet           = "fd99::/64"
 	V6NodeLocalNATSubnetPrefix     = 64
 	V6NodeLocalNATSubnetNextHop    = "fd99::1"
@@ -89,6 +104,9 @@ const (
 
 	OvnACLLoggingMeter = "acl-logging"
 
+	// EgressIPHealthCheckResponseOK is the response of a healthy node's egress IP health check server
+	EgressIPHealthCheckResponseOK = "OK"
+
 	// OVN-K8S Topology Versions
 	OvnSingleJoinSwitchTopoVersion = 1
 	OvnNamespacedDenyPGTopoVersion = 2
@@ -104,6 +122,21 @@ const (
 	// Monitoring constants
 	SFlowAgent = "ovn-k8s-mp0"
 
+	// DefaultNetworkName is the CNI network name of the default pod network
+	DefaultNetworkName = "ovn-kubernetes"
+
+	// Secondary network topologies
+	Layer2Topology = "layer2"
+	Layer3Topology = "layer3"
+
+	// Secondary network logical entities names and external IDs
+	OvnLayer2Switch       = "ovn_layer2_switch"
+	NetworkNameExternalID = "k8s-network"
+	NetworkTopoExternalID = "k8s-topology"
+	NodeNameExternalID    = "k8s-node"
+	NodeSubnetsExternalID = "k8s-node-subnets"
+	NADNameExternalID     = "k8s-nad"
+
 	// OVNKube-Node Node types
 	NodeModeFull         = "full"
 	NodeModeSmartNIC     = "smart-nic"
@@ -3,12 +3,12 @@ package util
 import (
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/miekg/dns"
 
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog"
 
@@ -16,8 +16,8 @@ import (
 )
 
 const (
-	// defaultTTL is used if an invalid or zero TTL is provided.
-	defaultTTL = 30 * time.Minute
+	// wildcardDNSNamePrefix starts the dnsNames matching all the subdomains of a domain
+	wildcardDNSNamePrefix = "*."
 )
 
 type dnsValue struct {
@@ -39,18 +39,28 @@ type DNS struct {
 	nameservers []string
 	// DNS port
 	port string
+
+	// bounds of the TTL after which a dns name is resolved again
+	minTTL time.Duration
+	maxTTL time.Duration
+	// time after which a dns name that failed to resolve is resolved again, when the
+	// responses do not carry a negative TTL
+	negativeTTL time.Duration
 }
 
 func NewDNS(resolverConfigFile string) (*DNS, error) {
-	config, err := dnsOps.ClientConfigFromFile(resolverConfigFile)
-	if err != nil || config == nil {
+	clientConfig, err := dnsOps.ClientConfigFromFile(resolverConfigFile)
+	if err != nil || clientConfig == nil {
 		return nil, fmt.Errorf("cannot initialize the resolver: %v", err)
 	}
 
 	return &DNS{
 		dnsMap:      map[string]dnsValue{},
-		nameservers: filterIPServers(config.Servers),
-		port:        config.Port,
+		nameservers: filterIPServers(clientConfig.Servers),
+		port:        clientConfig.Port,
+		minTTL:      time.Duration(config.OVNKubernetesFeature.EgressFirewallDNSMinTTL) * time.Second,
+		maxTTL:      time.Duration(config.OVNKubernetesFeature.EgressFirewallDNSMaxTTL) * time.Second,
+		negativeTTL: time.Duration(config.OVNKubernetesFeature.EgressFirewallDNSNegativeTTL) * time.Second,
 	}, nil
 }
 
@@ -75,15 +85,26 @@ func (d *DNS) GetIPs(dns string) []net.IP {
 	return data.ips
 }
 
-func (d *DNS) Add(dns string) error {
+// GetNextQueryTime returns the time at which dns must be resolved again, and false if
+// dns is unknown
+func (d *DNS) GetNextQueryTime(dns string) (time.Time, bool) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
-	d.dnsMap[dns] = dnsValue{}
-	_, err := d.updateOne(dns)
-	if err != nil {
-		delete(d.dnsMap, dns)
+	res, ok := d.dnsMap[dns]
+	return res.nextQueryTime, ok
+}
+
+// Add adds dns if it is unknown and resolves it. A dns name that fails to resolve is
+// kept, and keeps its previous IPs, so that it is resolved again after its negative TTL.
+func (d *DNS) Add(dns string) error {
+	d.lock.Lock()
+	if _, ok := d.dnsMap[dns]; !ok {
+		d.dnsMap[dns] = dnsValue{}
 	}
+	d.lock.Unlock()
+
+	_, err := d.Update(dns)
 	return err
 }
 
@@ -93,24 +114,31 @@ func (d *DNS) Delete(dns string) {
 	delete(d.dnsMap, dns)
 }
 
+// Update resolves dns again and returns true if its IPs changed. The lock is not held
+// while querying the nameservers, so that several dns names can be resolved concurrently.
 func (d *DNS) Update(dnsName string) (bool, error) {
 	d.lock.Lock()
-	defer d.lock.Unlock()
+	_, ok := d.dnsMap[dnsName]
+	d.lock.Unlock()
+	if !ok {
+		return false, fmt.Errorf("DNS value not found in dnsMap for domain: %q", dnsName)
+	}
 
-	return d.updateOne(dnsName)
-}
+	ips, ttl, err := d.getIPsAndMinTTL(dnsName)
 
-func (d *DNS) updateOne(dns string) (bool, error) {
-	res, ok := d.dnsMap[dns]
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	res, ok := d.dnsMap[dnsName]
 	if !ok {
-		// Should not happen, all operations on dnsMap are synchronized by d.lock
-		return false, fmt.Errorf("DNS value not found in dnsMap for domain: %q", dns)
+		// deleted while being resolved
+		return false, nil
 	}
-
-	ips, ttl, err := d.getIPsAndMinTTL(dns)
 	if err != nil {
-		res.nextQueryTime = time.Now().Add(defaultTTL)
-		d.dnsMap[dns] = res
+		if ttl == 0 {
+			ttl = d.negativeTTL
+		}
+		res.nextQueryTime = time.Now().Add(d.boundTTL(ttl))
+		d.dnsMap[dnsName] = res
 		return false, err
 	}
 
@@ -119,17 +147,32 @@ func (d *DNS) updateOne(dns string) (bool, error) {
 		changed = true
 	}
 	res.ips = ips
-	res.ttl = ttl
+	res.ttl = d.boundTTL(ttl)
 	res.nextQueryTime = time.Now().Add(res.ttl)
-	d.dnsMap[dns] = res
+	d.dnsMap[dnsName] = res
 	return changed, nil
 }
 
+// boundTTL returns ttl within the configured minimum and maximum TTL
+func (d *DNS) boundTTL(ttl time.Duration) time.Duration {
+	if ttl < d.minTTL {
+		ttl = d.minTTL
+	}
+	if d.maxTTL > 0 && ttl > d.maxTTL {
+		ttl = d.maxTTL
+	}
+	return ttl
+}
+
+// getIPsAndMinTTL returns the IPs of domain and the lowest TTL of the answers. When
+// domain does not resolve, the returned TTL is the negative TTL of the responses, or 0
+// if they carry none.
 func (d *DNS) getIPsAndMinTTL(domain string) ([]net.IP, time.Duration, error) {
 	ips := []net.IP{}
 	ttlSet := false
-	var ttlSeconds uint32
 	var minTTL uint32
+	negativeTTLSet := false
+	var negativeTTL uint32
 	var recordTypes []uint16
 
 	if config.IPv4Mode {
@@ -155,67 +198,58 @@ func (d *DNS) getIPsAndMinTTL(domain string) ([]net.IP, time.Duration, error) {
 				klog.Warningf("Failed to query nameserver: %s with address: %s for domain: %s, err: %v", server, dialServer, domain, err)
 				continue
 			}
-			if in != nil && in.Rcode != dns.RcodeSuccess {
+			if in == nil {
+				continue
+			}
+			if ttl, ok := getNegativeTTL(in); ok && (!negativeTTLSet || ttl < negativeTTL) {
+				negativeTTL = ttl
+				negativeTTLSet = true
+			}
+			if in.Rcode != dns.RcodeSuccess {
 				klog.Warningf("Failed to get a valid answer: %v from nameserver: %s for domain: %s", in.Rcode, server, domain)
 				continue
 			}
 
-			if in != nil && len(in.Answer) > 0 {
-				for _, a := range in.Answer {
-					if !ttlSet || a.Header().Ttl < ttlSeconds {
-						ttlSeconds = a.Header().Ttl
-						ttlSet = true
-						if minTTL == 0 {
-							minTTL = ttlSeconds
-						}
-					}
-
-					switch t := a.(type) {
-					case *dns.A:
-						ips = append(ips, t.A)
-					case *dns.AAAA:
-						ips = append(ips, t.AAAA)
-					}
+			for _, a := range in.Answer {
+				switch t := a.(type) {
+				case *dns.A:
+					ips = append(ips, t.A)
+				case *dns.AAAA:
+					ips = append(ips, t.AAAA)
+				default:
+					continue
 				}
-				if ttlSeconds < minTTL {
-					minTTL = ttlSeconds
+				if !ttlSet || a.Header().Ttl < minTTL {
+					minTTL = a.Header().Ttl
+					ttlSet = true
 				}
 			}
 		}
 	}
 
 	if !ttlSet || (len(ips) == 0) {
-		return nil, defaultTTL, fmt.Errorf("IPv4 or IPv6 addr not found for domain: %q, nameservers: %v", domain, d.nameservers)
-	}
-
-	ttl, err := time.ParseDuration(fmt.Sprintf("%ds", minTTL))
-	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("invalid TTL value for domain: %q, err: %v, defaulting ttl=%s", domain, err, defaultTTL.String()))
-		ttl = defaultTTL
-	}
-	if ttl == 0 {
-		ttl = defaultTTL
+		return nil, time.Duration(negativeTTL) * time.Second,
+			fmt.Errorf("IPv4 or IPv6 addr not found for domain: %q, nameservers: %v", domain, d.nameservers)
 	}
 
-	return removeDuplicateIPs(ips), ttl, nil
+	return removeDuplicateIPs(ips), time.Duration(minTTL) * time.Second, nil
 }
 
-func (d *DNS) GetNextQueryTime() (time.Time, string, bool) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-
-	timeSet := false
-	var minTime time.Time
-	var dns string
-
-	for dnsName, res := range d.dnsMap {
-		if !timeSet || res.nextQueryTime.Before(minTime) {
-			timeSet = true
-			minTime = res.nextQueryTime
-			dns = dnsName
+// getNegativeTTL returns the time a response without answers may be cached for, the
+// lowest of the TTL and of the minimum field of its SOA record (RFC 2308)
+func getNegativeTTL(msg *dns.Msg) (uint32, bool) {
+	if msg.Rcode != dns.RcodeNameError && (msg.Rcode != dns.RcodeSuccess || len(msg.Answer) > 0) {
+		return 0, false
+	}
+	for _, rr := range msg.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			if soa.Minttl < soa.Hdr.Ttl {
+				return soa.Minttl, true
+			}
+			return soa.Hdr.Ttl, true
 		}
 	}
-	return minTime, dns, timeSet
+	return 0, false
 }
 
 func ipsEqual(oldips, newips []net.IP) bool {
@@ -266,3 +300,21 @@ func removeDuplicateIPs(ips []net.IP) []net.IP {
 	}
 	return uniqueIPs
 }
+
+// IsWildcardDNSName returns true if dnsName is a wildcard such as *.example.com, which
+// cannot be resolved by querying it
+func IsWildcardDNSName(dnsName string) bool {
+	return strings.HasPrefix(dnsName, wildcardDNSNamePrefix)
+}
+
+// MatchWildcardDNSName returns true if name is a subdomain, at any depth, of the domain
+// of the wildcard dnsName, e.g. www.example.com and a.b.example.com for *.example.com.
+// The comparison is case insensitive and ignores the trailing dots.
+func MatchWildcardDNSName(wildcard, name string) bool {
+	if !IsWildcardDNSName(wildcard) {
+		return false
+	}
+	suffix := strings.ToLower(strings.TrimSuffix(wildcard[len(wildcardDNSNamePrefix)-1:], "."))
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	return len(name) > len(suffix) && strings.HasSuffix(name, suffix)
+}
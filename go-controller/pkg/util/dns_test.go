@@ -283,3 +283,138 @@ func TestAdd(t *testing.T) {
 	}
 
 }
+
+func TestUpdateNextQueryTime(t *testing.T) {
+	mockDNSOps := new(util_mocks.DNSOps)
+	SetDNSLibOpsMockInst(mockDNSOps)
+
+	dnsName := "www.testing.com"
+	newRR := func(record string) dns.RR {
+		rr, err := dns.NewRR(record)
+		assert.Nil(t, err)
+		return rr
+	}
+
+	tests := []struct {
+		desc     string
+		response *dns.Msg
+		errExp   bool
+		ttlExp   time.Duration
+	}{
+		{
+			desc:     "the lowest TTL of the answers is used",
+			response: &dns.Msg{Answer: []dns.RR{newRR(dnsName + ". 120 IN A 1.2.3.4"), newRR(dnsName + ". 60 IN A 1.2.3.5")}},
+			ttlExp:   60 * time.Second,
+		},
+		{
+			desc:     "the TTL is raised to the minimum TTL",
+			response: &dns.Msg{Answer: []dns.RR{newRR(dnsName + ". 0 IN A 1.2.3.4")}},
+			ttlExp:   5 * time.Second,
+		},
+		{
+			desc:     "the TTL is lowered to the maximum TTL",
+			response: &dns.Msg{Answer: []dns.RR{newRR(dnsName + ". 86400 IN A 1.2.3.4")}},
+			ttlExp:   time.Hour,
+		},
+		{
+			desc: "the negative TTL of the SOA record is used when the name does not exist",
+			response: &dns.Msg{MsgHdr: dns.MsgHdr{Rcode: dns.RcodeNameError},
+				Ns: []dns.RR{newRR("testing.com. 300 IN SOA ns.testing.com. admin.testing.com. 1 7200 3600 1209600 120")}},
+			errExp: true,
+			ttlExp: 120 * time.Second,
+		},
+		{
+			desc: "the negative TTL of the SOA record is used when the name has no address",
+			response: &dns.Msg{
+				Ns: []dns.RR{newRR("testing.com. 90 IN SOA ns.testing.com. admin.testing.com. 1 7200 3600 1209600 120")}},
+			errExp: true,
+			ttlExp: 90 * time.Second,
+		},
+		{
+			desc:     "the configured negative TTL is used without SOA record",
+			response: &dns.Msg{MsgHdr: dns.MsgHdr{Rcode: dns.RcodeServerFailure}},
+			errExp:   true,
+			ttlExp:   30 * time.Second,
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			mockDNSOps.On("Fqdn", mock.AnythingOfType("string")).Return(dnsName).Once()
+			mockDNSOps.On("SetQuestion", mock.AnythingOfType("*dns.Msg"), mock.AnythingOfType("string"), mock.AnythingOfType("uint16")).Return(&dns.Msg{}).Once()
+			mockDNSOps.On("Exchange", mock.AnythingOfType("*dns.Client"), mock.AnythingOfType("*dns.Msg"), mock.AnythingOfType("string")).Return(tc.response, 0*time.Second, nil).Once()
+			config.IPv4Mode = true
+			config.IPv6Mode = false
+
+			dns := DNS{
+				dnsMap:      map[string]dnsValue{dnsName: {}},
+				nameservers: []string{"1.1.1.1"},
+				port:        "1234",
+				minTTL:      5 * time.Second,
+				maxTTL:      time.Hour,
+				negativeTTL: 30 * time.Second,
+			}
+			before := time.Now()
+			_, err := dns.Update(dnsName)
+			if tc.errExp {
+				assert.Error(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			nextQueryTime, ok := dns.GetNextQueryTime(dnsName)
+			assert.True(t, ok)
+			assert.WithinDuration(t, before.Add(tc.ttlExp), nextQueryTime, time.Second)
+			mockDNSOps.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchWildcardDNSName(t *testing.T) {
+	tests := []struct {
+		desc     string
+		wildcard string
+		name     string
+		expected bool
+	}{
+		{
+			desc:     "subdomain matches",
+			wildcard: "*.example.com",
+			name:     "www.example.com",
+			expected: true,
+		},
+		{
+			desc:     "nested subdomain matches",
+			wildcard: "*.example.com",
+			name:     "a.b.example.com.",
+			expected: true,
+		},
+		{
+			desc:     "comparison is case insensitive",
+			wildcard: "*.Example.com.",
+			name:     "WWW.example.COM",
+			expected: true,
+		},
+		{
+			desc:     "domain itself does not match",
+			wildcard: "*.example.com",
+			name:     "example.com",
+			expected: false,
+		},
+		{
+			desc:     "domain sharing the suffix does not match",
+			wildcard: "*.example.com",
+			name:     "www.badexample.com",
+			expected: false,
+		},
+		{
+			desc:     "exact dnsName never matches",
+			wildcard: "www.example.com",
+			name:     "www.example.com",
+			expected: false,
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchWildcardDNSName(tc.wildcard, tc.name))
+		})
+	}
+}
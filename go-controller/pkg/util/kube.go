@@ -24,8 +24,12 @@ import (
 	"k8s.io/client-go/util/cert"
 	"k8s.io/klog/v2"
 
-	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned"
+	networkattchmentdefclientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+
+	adminnetworkpolicyclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/adminnetworkpolicy/v1/apis/clientset/versioned"
+	egressfirewallclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v2/apis/clientset/versioned"
 	egressipclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressip/v1/apis/clientset/versioned"
+	egressqosclientset "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
 
 	cnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
@@ -34,9 +38,12 @@ import (
 
 // OVNClientset is a wrapper around all clientsets used by OVN-Kubernetes
 type OVNClientset struct {
-	KubeClient           kubernetes.Interface
-	EgressIPClient       egressipclientset.Interface
-	EgressFirewallClient egressfirewallclientset.Interface
+	KubeClient               kubernetes.Interface
+	EgressIPClient           egressipclientset.Interface
+	EgressFirewallClient     egressfirewallclientset.Interface
+	EgressQoSClient          egressqosclientset.Interface
+	AdminNetworkPolicyClient adminnetworkpolicyclientset.Interface
+	NetworkAttchDefClient    networkattchmentdefclientset.Interface
 }
 
 func adjustCommit() string {
@@ -130,10 +137,25 @@ func NewOVNClientset(conf *config.KubernetesConfig) (*OVNClientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	egressQoSClientset, err := egressqosclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+	adminNetworkPolicyClientset, err := adminnetworkpolicyclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+	networkAttchmntDefClientset, err := networkattchmentdefclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
 	return &OVNClientset{
-		KubeClient:           kclientset,
-		EgressIPClient:       egressIPClientset,
-		EgressFirewallClient: egressFirewallClientset,
+		KubeClient:               kclientset,
+		EgressIPClient:           egressIPClientset,
+		EgressFirewallClient:     egressFirewallClientset,
+		EgressQoSClient:          egressQoSClientset,
+		AdminNetworkPolicyClient: adminNetworkPolicyClientset,
+		NetworkAttchDefClient:    networkAttchmntDefClientset,
 	}, nil
 }
 
@@ -284,12 +306,17 @@ type LbEndpoints struct {
 	V4IPs []string
 	V6IPs []string
 	Port  int32
+	// ZoneHints maps each endpoint IP to the zones it should be consumed from.
+	// It is nil unless all the endpoints have zone hints.
+	ZoneHints map[string][]string
 }
 
 // GetLbEndpoints return the endpoints that belong to the IPFamily as a slice of IPs
 func GetLbEndpoints(slices []*discovery.EndpointSlice, svcPort kapi.ServicePort) LbEndpoints {
 	v4ips := sets.NewString()
 	v6ips := sets.NewString()
+	zoneHints := map[string]sets.String{}
+	allHinted := true
 
 	out := LbEndpoints{}
 	// return an empty object so the caller don't have to check for nil and can use it as an iterator
@@ -324,7 +351,18 @@ func GetLbEndpoints(slices []*discovery.EndpointSlice, svcPort kapi.ServicePort)
 					klog.V(4).Infof("Slice endpoints Not Ready")
 					continue
 				}
+				if endpoint.Hints == nil || len(endpoint.Hints.ForZones) == 0 {
+					allHinted = false
+				}
 				for _, ip := range endpoint.Addresses {
+					if allHinted {
+						if _, ok := zoneHints[ip]; !ok {
+							zoneHints[ip] = sets.NewString()
+						}
+						for _, zone := range endpoint.Hints.ForZones {
+							zoneHints[ip].Insert(zone.Name)
+						}
+					}
 					klog.V(4).Infof("Adding slice %s endpoints: %v, port: %d", slice.Name, endpoint.Addresses, *port.Port)
 					switch slice.AddressType {
 					case discovery.AddressTypeIPv4:
@@ -341,6 +379,12 @@ func GetLbEndpoints(slices []*discovery.EndpointSlice, svcPort kapi.ServicePort)
 
 	out.V4IPs = v4ips.List()
 	out.V6IPs = v6ips.List()
+	if allHinted && len(zoneHints) > 0 {
+		out.ZoneHints = make(map[string][]string, len(zoneHints))
+		for ip, zones := range zoneHints {
+			out.ZoneHints[ip] = zones.List()
+		}
+	}
 	klog.V(4).Infof("LB Endpoints for %s/%s are: %v / %v on port: %d",
 		slices[0].Namespace, slices[0].Labels[discovery.LabelServiceName],
 		out.V4IPs, out.V6IPs, out.Port)
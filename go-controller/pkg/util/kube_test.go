@@ -509,7 +509,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{"10.0.0.2"}, []string{}, 80},
+			want: LbEndpoints{[]string{"10.0.0.2"}, []string{}, 80, nil},
 		},
 		{
 			name: "slices with different port name",
@@ -545,7 +545,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{}, []string{}, 0},
+			want: LbEndpoints{[]string{}, []string{}, 0, nil},
 		},
 		{
 			name: "slices and service without port name",
@@ -579,7 +579,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{"10.0.0.2"}, []string{}, 8080},
+			want: LbEndpoints{[]string{"10.0.0.2"}, []string{}, 8080, nil},
 		},
 		{
 			name: "slices with different IP family",
@@ -615,7 +615,7 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{}, []string{"2001:db2::2"}, 80},
+			want: LbEndpoints{[]string{}, []string{"2001:db2::2"}, 80, nil},
 		},
 		{
 			name: "multiples slices with duplicate endpoints",
@@ -674,7 +674,95 @@ func Test_getLbEndpoints(t *testing.T) {
 					Protocol:   v1.ProtocolTCP,
 				},
 			},
-			want: LbEndpoints{[]string{"10.0.0.2", "10.1.1.2", "10.2.2.2"}, []string{}, 80},
+			want: LbEndpoints{[]string{"10.0.0.2", "10.1.1.2", "10.2.2.2"}, []string{}, 80, nil},
+		},
+		{
+			name: "slice with zone hints",
+			args: args{
+				slices: []*discovery.EndpointSlice{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "svc-ab23",
+							Namespace: "ns",
+							Labels:    map[string]string{discovery.LabelServiceName: "svc"},
+						},
+						Ports: []discovery.EndpointPort{
+							{
+								Name:     utilpointer.StringPtr("tcp-example"),
+								Protocol: protoPtr(v1.ProtocolTCP),
+								Port:     utilpointer.Int32Ptr(int32(80)),
+							},
+						},
+						AddressType: discovery.AddressTypeIPv4,
+						Endpoints: []discovery.Endpoint{
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.BoolPtr(true),
+								},
+								Addresses: []string{"10.0.0.2"},
+								Hints:     &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: "zone-a"}}},
+							},
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.BoolPtr(true),
+								},
+								Addresses: []string{"10.1.1.2"},
+								Hints:     &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: "zone-b"}, {Name: "zone-c"}}},
+							},
+						},
+					},
+				},
+				svcPort: v1.ServicePort{
+					Name:       "tcp-example",
+					TargetPort: intstr.FromInt(80),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			want: LbEndpoints{[]string{"10.0.0.2", "10.1.1.2"}, []string{}, 80,
+				map[string][]string{"10.0.0.2": {"zone-a"}, "10.1.1.2": {"zone-b", "zone-c"}}},
+		},
+		{
+			name: "slice with incomplete zone hints",
+			args: args{
+				slices: []*discovery.EndpointSlice{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "svc-ab23",
+							Namespace: "ns",
+							Labels:    map[string]string{discovery.LabelServiceName: "svc"},
+						},
+						Ports: []discovery.EndpointPort{
+							{
+								Name:     utilpointer.StringPtr("tcp-example"),
+								Protocol: protoPtr(v1.ProtocolTCP),
+								Port:     utilpointer.Int32Ptr(int32(80)),
+							},
+						},
+						AddressType: discovery.AddressTypeIPv4,
+						Endpoints: []discovery.Endpoint{
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.BoolPtr(true),
+								},
+								Addresses: []string{"10.0.0.2"},
+								Hints:     &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: "zone-a"}}},
+							},
+							{
+								Conditions: discovery.EndpointConditions{
+									Ready: utilpointer.BoolPtr(true),
+								},
+								Addresses: []string{"10.1.1.2"},
+							},
+						},
+					},
+				},
+				svcPort: v1.ServicePort{
+					Name:       "tcp-example",
+					TargetPort: intstr.FromInt(80),
+					Protocol:   v1.ProtocolTCP,
+				},
+			},
+			want: LbEndpoints{[]string{"10.0.0.2", "10.1.1.2"}, []string{}, 80, nil},
 		},
 	}
 	for _, tt := range tests {
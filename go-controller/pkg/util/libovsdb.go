@@ -74,7 +74,26 @@ func NewSBClientWithConfig(cfg config.OvnAuthConfig, stopCh <-chan struct{}) (cl
 		return nil, err
 	}
 
-	return newClient(cfg, dbModel, stopCh)
+	c, err := newClient(cfg, dbModel, stopCh)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	// unlike the northbound database, the southbound database is too large to be
+	// monitored as a whole: only monitor the tables the master reads through libovsdb
+	_, err = c.Monitor(ctx, c.NewTableMonitor(&sbdb.ControllerEvent{}))
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return c, nil
 }
 
 // NewNBClient creates a new OVN Northbound Database client
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	ovncnitypes "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/cni/types"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/types"
+)
+
+// ErrorAttachDefNotOvnManaged is returned by ParseNetConf for the
+// NetworkAttachmentDefinitions which are not handled by ovn-kubernetes
+var ErrorAttachDefNotOvnManaged = errors.New("net-attach-def not managed by OVN")
+
+// ParseNetConf parses the CNI config of a NetworkAttachmentDefinition and
+// validates it describes a secondary OVN network
+func ParseNetConf(netattachdef *nettypes.NetworkAttachmentDefinition) (*ovncnitypes.NetConf, error) {
+	netconf := &ovncnitypes.NetConf{}
+	if err := json.Unmarshal([]byte(netattachdef.Spec.Config), netconf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config of net-attach-def %s/%s: %v",
+			netattachdef.Namespace, netattachdef.Name, err)
+	}
+	if netconf.Type != config.CNI.Plugin {
+		return nil, ErrorAttachDefNotOvnManaged
+	}
+
+	if netconf.Name == "" || netconf.Name == types.DefaultNetworkName {
+		return nil, fmt.Errorf("invalid network name %q in net-attach-def %s/%s",
+			netconf.Name, netattachdef.Namespace, netattachdef.Name)
+	}
+	if netconf.Topology != types.Layer2Topology && netconf.Topology != types.Layer3Topology {
+		return nil, fmt.Errorf("unsupported topology %q in net-attach-def %s/%s",
+			netconf.Topology, netattachdef.Namespace, netattachdef.Name)
+	}
+	nadName := GetNADName(netattachdef.Namespace, netattachdef.Name)
+	if netconf.NADName != nadName {
+		return nil, fmt.Errorf("net-attach-def name %q in the config of net-attach-def %s does not match",
+			netconf.NADName, nadName)
+	}
+	if _, err := ParseNetworkSubnets(netconf); err != nil {
+		return nil, fmt.Errorf("invalid subnets in net-attach-def %s: %v", nadName, err)
+	}
+	return netconf, nil
+}
+
+// ParseNetworkSubnets returns the subnets of a secondary network. The host
+// subnet length is only meaningful for the layer3 topology, the subnets of a
+// layer2 network are used as a whole by its single logical switch.
+func ParseNetworkSubnets(netconf *ovncnitypes.NetConf) ([]config.CIDRNetworkEntry, error) {
+	if netconf.Subnets == "" {
+		return nil, fmt.Errorf("no subnets specified")
+	}
+	if netconf.Topology == types.Layer3Topology {
+		return config.ParseClusterSubnetEntries(netconf.Subnets)
+	}
+
+	var subnets []config.CIDRNetworkEntry
+	for _, subnet := range strings.Split(netconf.Subnets, ",") {
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(subnet))
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, config.CIDRNetworkEntry{CIDR: cidr})
+	}
+	return subnets, nil
+}
+
+// GetNADName returns the key of a NetworkAttachmentDefinition, which is also
+// the key of the network in the pod-networks annotation
+func GetNADName(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// GetSecondaryNetworkPrefix returns the prefix of the names of the logical
+// entities of a secondary network
+func GetSecondaryNetworkPrefix(netName string) string {
+	return netName + "_"
+}
+
+// GetSecondaryNetworkLogicalPortName returns the name of the logical port of
+// a pod on the secondary network of a NetworkAttachmentDefinition. It is also
+// the iface-id of the OVS interface of the pod for that network.
+func GetSecondaryNetworkLogicalPortName(podNamespace, podName, nadName string) string {
+	return strings.ReplaceAll(nadName, "/", ".") + "_" + GetLogicalPortName(podNamespace, podName)
+}
@@ -0,0 +1,101 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	nettypes "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ovn-org/ovn-kubernetes/go-controller/pkg/config"
+	ovntest "github.com/ovn-org/ovn-kubernetes/go-controller/pkg/testing"
+)
+
+func TestParseNetConf(t *testing.T) {
+	tests := []struct {
+		desc             string
+		inpConfig        string
+		errMatch         error
+		expectedTopology string
+		expectedSubnets  []config.CIDRNetworkEntry
+	}{
+		{
+			desc:      "verify json unmarshal error",
+			inpConfig: `{"name": "l2-net", "type": "ovn-k8s-cni-overlay"`,
+			errMatch:  fmt.Errorf("failed to unmarshal config of net-attach-def ns1/nad1"),
+		},
+		{
+			desc:      "verify net-attach-def of another CNI plugin is not managed",
+			inpConfig: `{"name": "macvlan-net", "type": "macvlan", "topology": "layer2"}`,
+			errMatch:  ErrorAttachDefNotOvnManaged,
+		},
+		{
+			desc:      "verify the default network name is rejected",
+			inpConfig: `{"name": "ovn-kubernetes", "type": "ovn-k8s-cni-overlay", "topology": "layer2", "netAttachDefName": "ns1/nad1", "subnets": "10.100.0.0/16"}`,
+			errMatch:  fmt.Errorf("invalid network name \"ovn-kubernetes\""),
+		},
+		{
+			desc:      "verify unsupported topology is rejected",
+			inpConfig: `{"name": "l2-net", "type": "ovn-k8s-cni-overlay", "topology": "localnet", "netAttachDefName": "ns1/nad1", "subnets": "10.100.0.0/16"}`,
+			errMatch:  fmt.Errorf("unsupported topology \"localnet\""),
+		},
+		{
+			desc:      "verify mismatching net-attach-def name is rejected",
+			inpConfig: `{"name": "l2-net", "type": "ovn-k8s-cni-overlay", "topology": "layer2", "netAttachDefName": "ns1/nad2", "subnets": "10.100.0.0/16"}`,
+			errMatch:  fmt.Errorf("net-attach-def name \"ns1/nad2\" in the config of net-attach-def ns1/nad1 does not match"),
+		},
+		{
+			desc:      "verify missing subnets are rejected",
+			inpConfig: `{"name": "l2-net", "type": "ovn-k8s-cni-overlay", "topology": "layer2", "netAttachDefName": "ns1/nad1"}`,
+			errMatch:  fmt.Errorf("invalid subnets in net-attach-def ns1/nad1: no subnets specified"),
+		},
+		{
+			desc:      "verify layer3 subnets with a too long host subnet length are rejected",
+			inpConfig: `{"name": "l3-net", "type": "ovn-k8s-cni-overlay", "topology": "layer3", "netAttachDefName": "ns1/nad1", "subnets": "10.100.0.0/16/16"}`,
+			errMatch:  fmt.Errorf("invalid subnets in net-attach-def ns1/nad1: cannot use a host subnet length mask shorter than or equal to the cluster subnet mask"),
+		},
+		{
+			desc:             "verify successful parse of a layer2 network",
+			inpConfig:        `{"name": "l2-net", "type": "ovn-k8s-cni-overlay", "topology": "layer2", "netAttachDefName": "ns1/nad1", "subnets": "10.100.0.0/24, fd00:10:100::/64"}`,
+			expectedTopology: "layer2",
+			expectedSubnets: []config.CIDRNetworkEntry{
+				{CIDR: ovntest.MustParseIPNet("10.100.0.0/24")},
+				{CIDR: ovntest.MustParseIPNet("fd00:10:100::/64")},
+			},
+		},
+		{
+			desc:             "verify successful parse of a layer3 network",
+			inpConfig:        `{"name": "l3-net", "type": "ovn-k8s-cni-overlay", "topology": "layer3", "netAttachDefName": "ns1/nad1", "subnets": "10.100.0.0/16/24"}`,
+			expectedTopology: "layer3",
+			expectedSubnets: []config.CIDRNetworkEntry{
+				{CIDR: ovntest.MustParseIPNet("10.100.0.0/16"), HostSubnetLength: 24},
+			},
+		},
+	}
+	config.PrepareTestConfig()
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			nad := &nettypes.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "nad1"},
+				Spec:       nettypes.NetworkAttachmentDefinitionSpec{Config: tc.inpConfig},
+			}
+			res, e := ParseNetConf(nad)
+			if tc.errMatch != nil {
+				assert.Error(t, e)
+				assert.Contains(t, e.Error(), tc.errMatch.Error())
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, tc.expectedTopology, res.Topology)
+				subnets, err := ParseNetworkSubnets(res)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedSubnets, subnets)
+			}
+		})
+	}
+}
+
+func TestGetSecondaryNetworkLogicalPortName(t *testing.T) {
+	assert.Equal(t, "ns1.nad1_ns2_pod1", GetSecondaryNetworkLogicalPortName("ns2", "pod1", "ns1/nad1"))
+	assert.Equal(t, "l3-net_", GetSecondaryNetworkPrefix("l3-net"))
+}
@@ -133,6 +133,17 @@ func GetNodeHybridOverlayIfAddr(subnet *net.IPNet) *net.IPNet {
 	return &net.IPNet{IP: NextIP(mgmtIfAddr.IP), Mask: subnet.Mask}
 }
 
+// GetNodeLBHealthCheckIfAddr returns the node logical switch address used as
+// the source of OVN load balancer health checks (the last usable address)
+func GetNodeLBHealthCheckIfAddr(subnet *net.IPNet) *net.IPNet {
+	last := ipToInt(subnet.IP)
+	ones, bits := subnet.Mask.Size()
+	hostMask := big.NewInt(0).Sub(big.NewInt(0).Lsh(big.NewInt(1), uint(bits-ones)), big.NewInt(1))
+	last.Or(last, hostMask)
+	// step back from the broadcast address
+	return &net.IPNet{IP: intToIP(last.Sub(last, big.NewInt(1))), Mask: subnet.Mask}
+}
+
 // JoinHostPortInt32 is like net.JoinHostPort(), but with an int32 for the port
 func JoinHostPortInt32(host string, port int32) string {
 	return net.JoinHostPort(host, strconv.Itoa(int(port)))
@@ -61,6 +61,32 @@ func TestNextIP(t *testing.T) {
 	}
 }
 
+func TestGetNodeLBHealthCheckIfAddr(t *testing.T) {
+	tests := []struct {
+		desc      string
+		input     string
+		expOutput string
+	}{
+		{
+			desc:      "test /24 subnet",
+			input:     "10.128.1.0/24",
+			expOutput: "10.128.1.254/24",
+		},
+		{
+			desc:      "test /23 subnet",
+			input:     "10.128.2.0/23",
+			expOutput: "10.128.3.254/23",
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			res := GetNodeLBHealthCheckIfAddr(ovntest.MustParseIPNet(tc.input))
+			t.Log(res.String())
+			assert.Equal(t, tc.expOutput, res.String())
+		})
+	}
+}
+
 func TestGetPortAddresses(t *testing.T) {
 	mockOvnNBClient := new(goovn_mocks.Client)
 	tests := []struct {
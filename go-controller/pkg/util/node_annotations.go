@@ -61,6 +61,17 @@ const (
 
 	// ovnNodeHostAddresses is used to track the different host IP addresses on the node
 	ovnNodeHostAddresses = "k8s.ovn.org/host-addresses"
+
+	// ovnNodeEgressIPHealthCheckPort is the port on which the node answers egress IP reachability probes
+	ovnNodeEgressIPHealthCheckPort = "k8s.ovn.org/egress-ip-health-check-port"
+
+	// ovnNodeEgressIPCapacity is the maximum number of egress IPs the node's primary interface can host,
+	// set by the administrator or a cloud integration
+	ovnNodeEgressIPCapacity = "k8s.ovn.org/egress-ip-capacity"
+
+	// ovnNodeEgressFirewallDNSIPs holds the IPs seen by the node in the DNS responses for
+	// the wildcard dnsNames of the EgressFirewall rules
+	ovnNodeEgressFirewallDNSIPs = "k8s.ovn.org/egress-firewall-dns-ips"
 )
 
 type L3GatewayConfig struct {
@@ -350,3 +361,89 @@ func ParseNodeHostAddresses(node *kapi.Node) (sets.String, error) {
 
 	return sets.NewString(cfg...), nil
 }
+
+// SetNodeEgressIPHealthCheckPort advertises the port of the node's egress IP health check server
+func SetNodeEgressIPHealthCheckPort(nodeAnnotator kube.Annotator, port int) error {
+	return nodeAnnotator.Set(ovnNodeEgressIPHealthCheckPort, strconv.Itoa(port))
+}
+
+// DeleteNodeEgressIPHealthCheckPort stops advertising the node's egress IP health check server
+func DeleteNodeEgressIPHealthCheckPort(nodeAnnotator kube.Annotator) {
+	nodeAnnotator.Delete(ovnNodeEgressIPHealthCheckPort)
+}
+
+// ParseNodeEgressIPHealthCheckPort returns the port of the node's egress IP health check server
+func ParseNodeEgressIPHealthCheckPort(node *kapi.Node) (int, error) {
+	portAnnotation, ok := node.Annotations[ovnNodeEgressIPHealthCheckPort]
+	if !ok {
+		return 0, newAnnotationNotSetError("%s annotation not found for node %q", ovnNodeEgressIPHealthCheckPort, node.Name)
+	}
+	port, err := strconv.Atoi(portAnnotation)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid %s annotation %q for node %q", ovnNodeEgressIPHealthCheckPort, portAnnotation, node.Name)
+	}
+	return port, nil
+}
+
+// NodeEgressIPConfigAnnotationChanged returns true if any of the annotations the egress IP
+// assignment of the node is configured with differs between oldNode and newNode
+func NodeEgressIPConfigAnnotationChanged(oldNode, newNode *kapi.Node) bool {
+	for _, annotation := range []string{ovnNodeIfAddr, ovnNodeEgressIPHealthCheckPort, ovnNodeEgressIPCapacity} {
+		if oldNode.Annotations[annotation] != newNode.Annotations[annotation] {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNodeEgressIPCapacity returns the maximum number of egress IPs which can be assigned to the node
+func ParseNodeEgressIPCapacity(node *kapi.Node) (int, error) {
+	capacityAnnotation, ok := node.Annotations[ovnNodeEgressIPCapacity]
+	if !ok {
+		return 0, newAnnotationNotSetError("%s annotation not found for node %q", ovnNodeEgressIPCapacity, node.Name)
+	}
+	capacity, err := strconv.Atoi(capacityAnnotation)
+	if err != nil || capacity < 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q for node %q", ovnNodeEgressIPCapacity, capacityAnnotation, node.Name)
+	}
+	return capacity, nil
+}
+
+// SetNodeEgressFirewallDNSIPs advertises the IPs the wildcard dnsNames of the EgressFirewall
+// rules were seen resolving to on the node, the annotation is removed when there are none
+func SetNodeEgressFirewallDNSIPs(nodeAnnotator kube.Annotator, dnsIPs map[string][]string) error {
+	if len(dnsIPs) == 0 {
+		nodeAnnotator.Delete(ovnNodeEgressFirewallDNSIPs)
+		return nil
+	}
+	return nodeAnnotator.Set(ovnNodeEgressFirewallDNSIPs, dnsIPs)
+}
+
+// ParseNodeEgressFirewallDNSIPs returns the IPs the wildcard dnsNames of the EgressFirewall
+// rules were seen resolving to on the node
+func ParseNodeEgressFirewallDNSIPs(node *kapi.Node) (map[string][]net.IP, error) {
+	dnsAnnotation, ok := node.Annotations[ovnNodeEgressFirewallDNSIPs]
+	if !ok {
+		return nil, newAnnotationNotSetError("%s annotation not found for node %q", ovnNodeEgressFirewallDNSIPs, node.Name)
+	}
+
+	var cfg map[string][]string
+	if err := json.Unmarshal([]byte(dnsAnnotation), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal egress firewall DNS IPs annotation %s for node %q: %v",
+			dnsAnnotation, node.Name, err)
+	}
+	dnsIPs := make(map[string][]net.IP, len(cfg))
+	for dnsName, ipStrs := range cfg {
+		ips := make([]net.IP, 0, len(ipStrs))
+		for _, ipStr := range ipStrs {
+			ip := net.ParseIP(ipStr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %q for dnsName %s in egress firewall DNS IPs annotation for node %q",
+					ipStr, dnsName, node.Name)
+			}
+			ips = append(ips, ip)
+		}
+		dnsIPs[dnsName] = ips
+	}
+	return dnsIPs, nil
+}
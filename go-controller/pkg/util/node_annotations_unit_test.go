@@ -379,3 +379,95 @@ func TestParseNodeManagementPortMACAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNodeEgressFirewallDNSIPs(t *testing.T) {
+	tests := []struct {
+		desc        string
+		inpNode     v1.Node
+		errExpected bool
+		expOutput   map[string][]net.IP
+	}{
+		{
+			desc:        "annotation not found for node",
+			inpNode:     v1.Node{},
+			errExpected: true,
+		},
+		{
+			desc: "success: parse the IPs of the dnsNames",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/egress-firewall-dns-ips": `{"*.example.com":["1.1.1.1","2001::1"],"*.example.org":[]}`},
+				},
+			},
+			expOutput: map[string][]net.IP{
+				"*.example.com": {net.ParseIP("1.1.1.1"), net.ParseIP("2001::1")},
+				"*.example.org": {},
+			},
+		},
+		{
+			desc: "error: invalid IP",
+			inpNode: v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"k8s.ovn.org/egress-firewall-dns-ips": `{"*.example.com":["1.1.1"]}`},
+				},
+			},
+			errExpected: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			dnsIPs, e := ParseNodeEgressFirewallDNSIPs(&tc.inpNode)
+			if tc.errExpected {
+				t.Log(e)
+				assert.Error(t, e)
+				assert.Nil(t, dnsIPs)
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, tc.expOutput, dnsIPs)
+			}
+		})
+	}
+}
+
+func TestNodeEgressIPConfigAnnotationChanged(t *testing.T) {
+	node := func(annotations map[string]string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: annotations}}
+	}
+	tests := []struct {
+		desc    string
+		oldNode *v1.Node
+		newNode *v1.Node
+		changed bool
+	}{
+		{
+			desc:    "unrelated annotation changed",
+			oldNode: node(map[string]string{"k8s.ovn.org/egress-ip-capacity": "5"}),
+			newNode: node(map[string]string{"k8s.ovn.org/egress-ip-capacity": "5", "foo": "bar"}),
+		},
+		{
+			desc:    "capacity changed",
+			oldNode: node(map[string]string{"k8s.ovn.org/egress-ip-capacity": "5"}),
+			newNode: node(map[string]string{"k8s.ovn.org/egress-ip-capacity": "10"}),
+			changed: true,
+		},
+		{
+			desc:    "primary interface address set",
+			oldNode: node(nil),
+			newNode: node(map[string]string{"k8s.ovn.org/node-primary-ifaddr": `{"ipv4":"192.168.126.12/24"}`}),
+			changed: true,
+		},
+		{
+			desc:    "health check port removed",
+			oldNode: node(map[string]string{"k8s.ovn.org/egress-ip-health-check-port": "9107"}),
+			newNode: node(nil),
+			changed: true,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			assert.Equal(t, tc.changed, NodeEgressIPConfigAnnotationChanged(tc.oldNode, tc.newNode))
+		})
+	}
+}
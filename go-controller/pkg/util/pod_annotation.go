@@ -35,6 +35,9 @@ import (
 // additional network attachment that claims the default route, then the "default" network
 // will have explicit routes to the cluster and service subnets.)
 //
+// A pod attached to secondary OVN networks through NetworkAttachmentDefinitions
+// gets an additional entry for each of them, keyed by "<nad namespace>/<nad name>".
+//
 // The "ip_address" and "gateway_ip" fields are deprecated and will eventually go away.
 // (And they are not output when "ip_addresses" or "gateway_ips" contains multiple
 // values.)
@@ -88,6 +91,32 @@ type podRoute struct {
 // MarshalPodAnnotation returns a JSON-formatted annotation describing the pod's
 // network details
 func MarshalPodAnnotation(podInfo *PodAnnotation) (map[string]string, error) {
+	return MarshalPodAnnotations(map[string]*PodAnnotation{OvnPodDefaultNetwork: podInfo})
+}
+
+// MarshalPodAnnotations returns a JSON-formatted annotation describing the pod's
+// network details on each of the networks, keyed by the network name (the
+// "default" network or the namespace/name of a NetworkAttachmentDefinition)
+func MarshalPodAnnotations(podInfos map[string]*PodAnnotation) (map[string]string, error) {
+	podNetworks := make(map[string]podAnnotation, len(podInfos))
+	for nadName, podInfo := range podInfos {
+		pa, err := marshalPodNetwork(podInfo)
+		if err != nil {
+			return nil, err
+		}
+		podNetworks[nadName] = *pa
+	}
+	bytes, err := json.Marshal(podNetworks)
+	if err != nil {
+		klog.Errorf("Failed marshaling podNetworks map %v", podNetworks)
+		return nil, err
+	}
+	return map[string]string{
+		OvnPodAnnotationName: string(bytes),
+	}, nil
+}
+
+func marshalPodNetwork(podInfo *PodAnnotation) (*podAnnotation, error) {
 	pa := podAnnotation{
 		MAC: podInfo.MAC.String(),
 	}
@@ -121,21 +150,54 @@ func MarshalPodAnnotation(podInfo *PodAnnotation) (map[string]string, error) {
 		})
 	}
 
-	podNetworks := map[string]podAnnotation{
-		OvnPodDefaultNetwork: pa,
+	return &pa, nil
+}
+
+// UnmarshalPodAnnotation returns the default network info from pod.Annotations
+func UnmarshalPodAnnotation(annotations map[string]string) (*PodAnnotation, error) {
+	podNetworks, err := unmarshalPodNetworks(annotations)
+	if err != nil {
+		return nil, err
+	}
+	tempA := podNetworks[OvnPodDefaultNetwork]
+	return unmarshalPodNetwork(&tempA)
+}
+
+// UnmarshalPodAnnotationForNetwork returns the network info of the given
+// network (the "default" network or the namespace/name of a
+// NetworkAttachmentDefinition) from pod.Annotations
+func UnmarshalPodAnnotationForNetwork(annotations map[string]string, nadName string) (*PodAnnotation, error) {
+	podNetworks, err := unmarshalPodNetworks(annotations)
+	if err != nil {
+		return nil, err
 	}
-	bytes, err := json.Marshal(podNetworks)
+	a, ok := podNetworks[nadName]
+	if !ok {
+		return nil, newAnnotationNotSetError("could not find OVN pod annotation for network %s in %v", nadName, annotations)
+	}
+	return unmarshalPodNetwork(&a)
+}
+
+// UnmarshalPodAnnotationAllNetworks returns the info of all the networks of
+// pod.Annotations, keyed by the network name
+func UnmarshalPodAnnotationAllNetworks(annotations map[string]string) (map[string]*PodAnnotation, error) {
+	podNetworks, err := unmarshalPodNetworks(annotations)
 	if err != nil {
-		klog.Errorf("Failed marshaling podNetworks map %v", podNetworks)
 		return nil, err
 	}
-	return map[string]string{
-		OvnPodAnnotationName: string(bytes),
-	}, nil
+	podAnnotations := make(map[string]*PodAnnotation, len(podNetworks))
+	for nadName := range podNetworks {
+		a := podNetworks[nadName]
+		podAnnotation, err := unmarshalPodNetwork(&a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse network %s: %v", nadName, err)
+		}
+		podAnnotations[nadName] = podAnnotation
+	}
+	return podAnnotations, nil
 }
 
-// UnmarshalPodAnnotation returns the default network info from pod.Annotations
-func UnmarshalPodAnnotation(annotations map[string]string) (*PodAnnotation, error) {
+func unmarshalPodNetworks(annotations map[string]string) (map[string]podAnnotation, error) {
 	ovnAnnotation, ok := annotations[OvnPodAnnotationName]
 	if !ok {
 		return nil, newAnnotationNotSetError("could not find OVN pod annotation in %v", annotations)
@@ -146,9 +208,10 @@ func UnmarshalPodAnnotation(annotations map[string]string) (*PodAnnotation, erro
 		return nil, fmt.Errorf("failed to unmarshal ovn pod annotation %q: %v",
 			ovnAnnotation, err)
 	}
-	tempA := podNetworks[OvnPodDefaultNetwork]
-	a := &tempA
+	return podNetworks, nil
+}
 
+func unmarshalPodNetwork(a *podAnnotation) (*PodAnnotation, error) {
 	podAnnotation := &PodAnnotation{}
 	var err error
 
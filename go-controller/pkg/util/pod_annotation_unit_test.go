@@ -212,6 +212,84 @@ func TestUnmarshalPodAnnotation(t *testing.T) {
 	}
 }
 
+func TestMarshalPodAnnotations(t *testing.T) {
+	podInfos := map[string]*PodAnnotation{
+		OvnPodDefaultNetwork: {
+			IPs: []*net.IPNet{ovntest.MustParseIPNet("192.168.0.5/24")},
+			MAC: ovntest.MustParseMAC("0a:58:c0:a8:00:05"),
+		},
+		"ns1/l2-net": {
+			IPs: []*net.IPNet{ovntest.MustParseIPNet("10.100.0.3/16")},
+			MAC: ovntest.MustParseMAC("0a:58:0a:64:00:03"),
+		},
+	}
+	res, err := MarshalPodAnnotations(podInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"k8s.ovn.org/pod-networks": `{"default":{"ip_addresses":["192.168.0.5/24"],"mac_address":"0a:58:c0:a8:00:05","ip_address":"192.168.0.5/24"},` +
+		`"ns1/l2-net":{"ip_addresses":["10.100.0.3/16"],"mac_address":"0a:58:0a:64:00:03","ip_address":"10.100.0.3/16"}}`}, res)
+
+	podInfos["ns1/bad"] = &PodAnnotation{
+		IPs:      []*net.IPNet{ovntest.MustParseIPNet("192.168.0.5/24")},
+		Gateways: []net.IP{net.ParseIP("192.168.1.0"), net.ParseIP("fd01::1")},
+	}
+	_, err = MarshalPodAnnotations(podInfos)
+	assert.Error(t, err)
+}
+
+func TestUnmarshalPodAnnotationForNetwork(t *testing.T) {
+	annotations := map[string]string{"k8s.ovn.org/pod-networks": `{"default":{"ip_addresses":["192.168.0.5/24"],"mac_address":"0a:58:fd:98:00:01"},` +
+		`"ns1/l3-net":{"ip_addresses":["10.100.1.3/24"],"mac_address":"0a:58:0a:64:01:03","routes":[{"dest":"10.100.0.0/16","nextHop":"10.100.1.1"}]}}`}
+	tests := []struct {
+		desc        string
+		inpAnnotMap map[string]string
+		inpNADName  string
+		errMatch    error
+		expectedIPs []*net.IPNet
+	}{
+		{
+			desc:        "verify `OVN pod annotation not found` error thrown",
+			inpAnnotMap: nil,
+			inpNADName:  "ns1/l3-net",
+			errMatch:    fmt.Errorf("could not find OVN pod annotation in"),
+		},
+		{
+			desc:        "verify error thrown when the network is not in the annotation",
+			inpAnnotMap: annotations,
+			inpNADName:  "ns1/l2-net",
+			errMatch:    fmt.Errorf("could not find OVN pod annotation for network ns1/l2-net"),
+		},
+		{
+			desc:        "verify the secondary network is returned",
+			inpAnnotMap: annotations,
+			inpNADName:  "ns1/l3-net",
+			expectedIPs: []*net.IPNet{ovntest.MustParseIPNet("10.100.1.3/24")},
+		},
+		{
+			desc:        "verify the default network is returned",
+			inpAnnotMap: annotations,
+			inpNADName:  OvnPodDefaultNetwork,
+			expectedIPs: []*net.IPNet{ovntest.MustParseIPNet("192.168.0.5/24")},
+		},
+	}
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			res, e := UnmarshalPodAnnotationForNetwork(tc.inpAnnotMap, tc.inpNADName)
+			if tc.errMatch != nil {
+				assert.Contains(t, e.Error(), tc.errMatch.Error())
+				assert.True(t, IsAnnotationNotSetError(e))
+			} else {
+				assert.NoError(t, e)
+				assert.Equal(t, tc.expectedIPs, res.IPs)
+			}
+		})
+	}
+
+	all, err := UnmarshalPodAnnotationAllNetworks(annotations)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Len(t, all["ns1/l3-net"].Routes, 1)
+}
+
 func TestGetAllPodIPs(t *testing.T) {
 	tests := []struct {
 		desc      string